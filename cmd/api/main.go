@@ -14,6 +14,7 @@ import (
 
 	appaddress "github.com/ESG-Project/suassu-api/internal/app/address"
 	appenterprise "github.com/ESG-Project/suassu-api/internal/app/enterprise"
+	appequation "github.com/ESG-Project/suassu-api/internal/app/equation"
 	appfeatures "github.com/ESG-Project/suassu-api/internal/app/feature"
 	appphyto "github.com/ESG-Project/suassu-api/internal/app/phytoanalysis"
 	appspecies "github.com/ESG-Project/suassu-api/internal/app/species"
@@ -21,6 +22,7 @@ import (
 	appuser "github.com/ESG-Project/suassu-api/internal/app/user"
 	"github.com/ESG-Project/suassu-api/internal/config"
 	enterprisehttp "github.com/ESG-Project/suassu-api/internal/http/v1/enterprise"
+	equationhttp "github.com/ESG-Project/suassu-api/internal/http/v1/equation"
 	phytohttp "github.com/ESG-Project/suassu-api/internal/http/v1/phytoanalysis"
 	specieshttp "github.com/ESG-Project/suassu-api/internal/http/v1/species"
 	specimenhttp "github.com/ESG-Project/suassu-api/internal/http/v1/specimen"
@@ -70,9 +72,13 @@ func main() {
 	userSvc := appuser.NewServiceWithTx(userRepo, addressSvc, hasher, txm)
 	enterpriseSvc := appenterprise.NewService(enterpriseRepo, addressSvc, hasher)
 
+	// Equações volumétricas e alométricas
+	equationRepo := postgres.NewEquationRepo(db)
+	equationSvc := appequation.NewService(equationRepo)
+
 	// PhytoAnalysis
 	phytoRepo := postgres.NewPhytoAnalysisRepo(db)
-	phytoSvc := appphyto.NewService(phytoRepo, txm)
+	phytoSvc := appphyto.NewServiceWithEquations(phytoRepo, txm, equationSvc)
 
 	// Species
 	speciesRepo := postgres.NewSpeciesRepo(db)
@@ -80,7 +86,7 @@ func main() {
 
 	// Specimen
 	specimenRepo := postgres.NewSpecimenRepo(db)
-	specimenSvc := appspecimen.NewService(specimenRepo)
+	specimenSvc := appspecimen.NewServiceWithIndicators(specimenRepo, txm, phytoSvc)
 
 	// Refresh Tokens
 	refreshTokenRepo := postgres.NewRefreshTokenRepo(db)
@@ -135,6 +141,7 @@ func main() {
 			priv.Mount("/phyto-analyses", phytohttp.Routes(phytoSvc))
 			priv.Mount("/specimens", specimenhttp.Routes(specimenSvc))
 			priv.Mount("/species", specieshttp.Routes(speciesSvc))
+			priv.Mount("/equations", equationhttp.Routes(equationSvc))
 		})
 
 		v1.Mount("/", openapi.Routes())
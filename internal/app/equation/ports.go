@@ -0,0 +1,16 @@
+package equation
+
+import (
+	"context"
+
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+)
+
+// Repo define a interface do repositório do cadastro de equações
+type Repo interface {
+	Create(ctx context.Context, eq *types.EquationData) error
+	GetByID(ctx context.Context, id string) (*types.EquationData, error)
+	List(ctx context.Context) ([]*types.EquationData, error)
+	Update(ctx context.Context, eq *types.EquationData) error
+	Delete(ctx context.Context, id string) error
+}
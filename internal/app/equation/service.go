@@ -0,0 +1,192 @@
+package equation
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ESG-Project/suassu-api/internal/app/phytometrics"
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+	"github.com/ESG-Project/suassu-api/internal/apperr"
+	"github.com/google/uuid"
+)
+
+type ServiceInterface interface {
+	Create(ctx context.Context, in Input) (*types.EquationData, error)
+	GetByID(ctx context.Context, id string) (*types.EquationData, error)
+	List(ctx context.Context, f types.EquationFilter) ([]*types.EquationData, error)
+	Update(ctx context.Context, id string, in Input) (*types.EquationData, error)
+	Delete(ctx context.Context, id string) error
+	Templates() []phytometrics.EquationTemplate
+}
+
+// Service mantém o cadastro de equações: as embutidas no motor (somente leitura) e as cadastradas
+type Service struct {
+	repo Repo
+}
+
+func NewService(r Repo) *Service {
+	return &Service{repo: r}
+}
+
+// Input representa os dados de criação e alteração de uma equação
+type Input struct {
+	Name          string
+	Kind          string // ignorado na alteração
+	Expression    string
+	Coefficients  map[string]float64
+	Applicability types.EquationApplicability
+	Reference     *string
+}
+
+func (s *Service) Create(ctx context.Context, in Input) (*types.EquationData, error) {
+	now := time.Now()
+	eq := &types.EquationData{
+		ID:        uuid.NewString(),
+		Kind:      strings.TrimSpace(in.Kind),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := apply(eq, in); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, eq); err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeInternal, "failed to create equation")
+	}
+	return eq, nil
+}
+
+// GetByID busca a equação embutida ou cadastrada
+func (s *Service) GetByID(ctx context.Context, id string) (*types.EquationData, error) {
+	if builtin, ok := phytometrics.BuiltinEquation(id); ok {
+		eq := builtin.Data
+		return &eq, nil
+	}
+
+	eq, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeNotFound, "equation not found")
+	}
+	return eq, nil
+}
+
+// List lista as equações que atendem ao filtro: por tipo, embutidas primeiro e depois por nome
+func (s *Service) List(ctx context.Context, f types.EquationFilter) ([]*types.EquationData, error) {
+	custom, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*types.EquationData, 0, len(custom))
+	for _, eq := range phytometrics.BuiltinEquations("") {
+		if phytometrics.EquationMatches(eq, f) {
+			eq := eq
+			out = append(out, &eq)
+		}
+	}
+	for _, eq := range custom {
+		if phytometrics.EquationMatches(*eq, f) {
+			out = append(out, eq)
+		}
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Kind != out[j].Kind {
+			return out[i].Kind > out[j].Kind // volume antes de biomass
+		}
+		if out[i].BuiltIn != out[j].BuiltIn {
+			return out[i].BuiltIn
+		}
+		return out[i].Name < out[j].Name
+	})
+
+	return out, nil
+}
+
+// Update altera uma equação cadastrada. Análises que já a selecionaram mantêm a definição
+// copiada na seleção até selecioná-la novamente.
+func (s *Service) Update(ctx context.Context, id string, in Input) (*types.EquationData, error) {
+	if _, ok := phytometrics.BuiltinEquation(id); ok {
+		return nil, apperr.New(apperr.CodeInvalid, "built-in equations are read-only")
+	}
+
+	eq, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeNotFound, "equation not found")
+	}
+	if err := apply(eq, in); err != nil {
+		return nil, err
+	}
+	eq.UpdatedAt = time.Now()
+
+	if err := s.repo.Update(ctx, eq); err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeInternal, "failed to update equation")
+	}
+	return eq, nil
+}
+
+// Delete remove uma equação cadastrada (as análises que a selecionaram mantêm a cópia)
+func (s *Service) Delete(ctx context.Context, id string) error {
+	if _, ok := phytometrics.BuiltinEquation(id); ok {
+		return apperr.New(apperr.CodeInvalid, "built-in equations are read-only")
+	}
+
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return apperr.Wrap(err, apperr.CodeNotFound, "equation not found")
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// Templates lista os modelos usuais (Schumacher-Hall, Spurr...) para cadastro com coeficientes
+func (s *Service) Templates() []phytometrics.EquationTemplate {
+	return phytometrics.EquationTemplates()
+}
+
+// apply normaliza a entrada, valida a equação compilando a expressão e copia os dados
+func apply(eq *types.EquationData, in Input) error {
+	eq.Name = strings.TrimSpace(in.Name)
+	eq.Expression = strings.TrimSpace(in.Expression)
+	eq.Coefficients = in.Coefficients
+	eq.Applicability = normalizeApplicability(in.Applicability)
+	eq.Reference = in.Reference
+
+	if _, err := phytometrics.CompileEquation(*eq); err != nil {
+		return apperr.WithFields(
+			apperr.Wrap(err, apperr.CodeInvalid, "invalid equation"),
+			map[string]any{"reason": err.Error()},
+		)
+	}
+	return nil
+}
+
+func normalizeApplicability(a types.EquationApplicability) types.EquationApplicability {
+	return types.EquationApplicability{
+		States:          normalizeList(a.States, strings.ToUpper),
+		VegetationTypes: normalizeList(a.VegetationTypes, nil),
+		Habits:          normalizeList(a.Habits, strings.ToUpper),
+		Families:        normalizeList(a.Families, nil),
+		SpeciesIDs:      normalizeList(a.SpeciesIDs, nil),
+	}
+}
+
+func normalizeList(list []string, transform func(string) string) []string {
+	out := make([]string, 0, len(list))
+	seen := make(map[string]bool, len(list))
+	for _, v := range list {
+		v = strings.TrimSpace(v)
+		if transform != nil {
+			v = transform(v)
+		}
+		if v == "" || seen[v] {
+			continue
+		}
+		seen[v] = true
+		out = append(out, v)
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
@@ -0,0 +1,102 @@
+package equation_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ESG-Project/suassu-api/internal/app/equation"
+	"github.com/ESG-Project/suassu-api/internal/app/phytometrics"
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+	"github.com/ESG-Project/suassu-api/internal/apperr"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRepo struct {
+	items map[string]*types.EquationData
+}
+
+func (f *fakeRepo) Create(ctx context.Context, eq *types.EquationData) error {
+	f.items[eq.ID] = eq
+	return nil
+}
+
+func (f *fakeRepo) GetByID(ctx context.Context, id string) (*types.EquationData, error) {
+	if eq, ok := f.items[id]; ok {
+		return eq, nil
+	}
+	return nil, apperr.New(apperr.CodeNotFound, "equation not found")
+}
+
+func (f *fakeRepo) List(ctx context.Context) ([]*types.EquationData, error) {
+	out := make([]*types.EquationData, 0, len(f.items))
+	for _, eq := range f.items {
+		out = append(out, eq)
+	}
+	return out, nil
+}
+
+func (f *fakeRepo) Update(ctx context.Context, eq *types.EquationData) error {
+	f.items[eq.ID] = eq
+	return nil
+}
+
+func (f *fakeRepo) Delete(ctx context.Context, id string) error {
+	delete(f.items, id)
+	return nil
+}
+
+func TestEquationService(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	spurr := equation.Input{
+		Name:          " Spurr MG ",
+		Kind:          types.EquationKindVolume,
+		Expression:    "b0 + b1 * dap^2 * h",
+		Coefficients:  map[string]float64{"b0": 0.01, "b1": 0.00004},
+		Applicability: types.EquationApplicability{States: []string{" mg", "MG"}},
+	}
+
+	t.Run("create normalizes and lists with built-ins", func(t *testing.T) {
+		svc := equation.NewService(&fakeRepo{items: map[string]*types.EquationData{}})
+
+		eq, err := svc.Create(ctx, spurr)
+		require.NoError(t, err)
+		require.Equal(t, "Spurr MG", eq.Name)
+		require.Equal(t, []string{"MG"}, eq.Applicability.States)
+		require.False(t, eq.BuiltIn)
+
+		list, err := svc.List(ctx, types.EquationFilter{Kind: types.EquationKindVolume, State: "MG"})
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+		require.Equal(t, phytometrics.FormFactorEquationID, list[0].ID) // embutidas primeiro
+		require.Equal(t, eq.ID, list[1].ID)
+
+		list, err = svc.List(ctx, types.EquationFilter{Kind: types.EquationKindVolume, State: "SP"})
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+	})
+
+	t.Run("error - invalid expression", func(t *testing.T) {
+		svc := equation.NewService(&fakeRepo{items: map[string]*types.EquationData{}})
+
+		in := spurr
+		in.Expression = "b0 + b2 * dap"
+		_, err := svc.Create(ctx, in)
+
+		require.Error(t, err)
+		require.Equal(t, apperr.CodeInvalid, apperr.CodeOf(err))
+	})
+
+	t.Run("built-in equations are read-only", func(t *testing.T) {
+		svc := equation.NewService(&fakeRepo{items: map[string]*types.EquationData{}})
+
+		eq, err := svc.GetByID(ctx, phytometrics.DefaultBiomassEquationID)
+		require.NoError(t, err)
+		require.True(t, eq.BuiltIn)
+
+		_, err = svc.Update(ctx, phytometrics.DefaultBiomassEquationID, spurr)
+		require.Equal(t, apperr.CodeInvalid, apperr.CodeOf(err))
+		require.Equal(t, apperr.CodeInvalid, apperr.CodeOf(svc.Delete(ctx, phytometrics.DefaultBiomassEquationID)))
+	})
+}
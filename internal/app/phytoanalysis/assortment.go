@@ -0,0 +1,67 @@
+package phytoanalysis
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ESG-Project/suassu-api/internal/app/phytometrics"
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+	"github.com/ESG-Project/suassu-api/internal/apperr"
+)
+
+// normalizeAssortmentRules aplica os padrões do motor nos valores não informados
+func normalizeAssortmentRules(r types.AssortmentRules) types.AssortmentRules {
+	if r.LogMinDbhCm == 0 {
+		r.LogMinDbhCm = phytometrics.DefaultLogMinDbhCm
+	}
+	if r.StackingFactor == 0 {
+		r.StackingFactor = phytometrics.StackingFactor
+	}
+	return r
+}
+
+// validateAssortmentRules lista os problemas nas regras (já normalizadas)
+func validateAssortmentRules(r types.AssortmentRules) []string {
+	errs := make([]string, 0)
+	if r.LogMinDbhCm <= 0 {
+		errs = append(errs, "log minimum DBH must be positive")
+	}
+	if r.StackingFactor <= 0 || r.StackingFactor > 1 {
+		errs = append(errs, "stacking factor must be greater than 0 and at most 1")
+	}
+	return errs
+}
+
+// SetAssortment define as regras de classificação da madeira suprimida (tora e lenha) e recalcula
+// os indicadores, já que o fator de empilhamento entra no volume em metros estéreos
+func (s *Service) SetAssortment(ctx context.Context, id string, rules types.AssortmentRules) (*phytometrics.Snapshot, error) {
+	if strings.TrimSpace(id) == "" {
+		return nil, apperr.New(apperr.CodeInvalid, "missing required fields")
+	}
+
+	rules = normalizeAssortmentRules(rules)
+	if errs := validateAssortmentRules(rules); len(errs) > 0 {
+		return nil, apperr.New(apperr.CodeInvalid, errs[0])
+	}
+	if _, err := s.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.changeAndSnapshot(ctx, id, phytometrics.ReasonAssortmentChanged, func(repo Repo) error {
+		return repo.SetAssortment(ctx, id, rules)
+	})
+}
+
+// ClearAssortment remove as regras da análise; a análise volta às regras padrão do motor
+func (s *Service) ClearAssortment(ctx context.Context, id string) (*phytometrics.Snapshot, error) {
+	if strings.TrimSpace(id) == "" {
+		return nil, apperr.New(apperr.CodeInvalid, "missing required fields")
+	}
+	if _, err := s.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.changeAndSnapshot(ctx, id, phytometrics.ReasonAssortmentChanged, func(repo Repo) error {
+		return repo.DeleteAssortment(ctx, id)
+	})
+}
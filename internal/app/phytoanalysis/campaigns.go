@@ -0,0 +1,250 @@
+package phytoanalysis
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/ESG-Project/suassu-api/internal/app/phytometrics"
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+	"github.com/ESG-Project/suassu-api/internal/apperr"
+	domainphyto "github.com/ESG-Project/suassu-api/internal/domain/phytoanalysis"
+	postgres "github.com/ESG-Project/suassu-api/internal/infra/db/postgres"
+	"github.com/google/uuid"
+)
+
+// CampaignInput representa uma nova campanha de remedição das parcelas permanentes de uma análise
+type CampaignInput struct {
+	Title       string
+	InitialDate time.Time
+	Description *string
+}
+
+// tagSet retorna o conjunto das plaquetas informadas
+func tagSet(tags []string) map[string]bool {
+	out := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		out[tag] = true
+	}
+	return out
+}
+
+// specimenTags retorna as plaquetas dos espécimes
+func specimenTags(specimens []*types.SpecimenWithSpecies) map[string]bool {
+	out := make(map[string]bool)
+	for _, s := range specimens {
+		if s.Tag != nil {
+			out[*s.Tag] = true
+		}
+	}
+	return out
+}
+
+// CreateCampaign cria a remedição da análise: uma nova análise do mesmo projeto com o protocolo de
+// medição, as equações, os estratos e as parcelas da campanha anterior, sem espécimes. As árvores
+// são ligadas entre campanhas pela plaqueta informada na importação.
+func (s *Service) CreateCampaign(ctx context.Context, id string, in CampaignInput) (string, error) {
+	if strings.TrimSpace(id) == "" || strings.TrimSpace(in.Title) == "" || in.InitialDate.IsZero() {
+		return "", apperr.New(apperr.CodeInvalid, "missing required fields")
+	}
+	if s.txm == nil {
+		return "", apperr.New(apperr.CodeInvalid, "transaction manager required")
+	}
+
+	campaignID := uuid.NewString()
+
+	err := s.txm.RunInTx(ctx, func(repos postgres.Repos) error {
+		previous, err := repos.PhytoAnalyses().GetComplete(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		next, err := repos.PhytoAnalyses().GetNextCampaignID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if next != nil {
+			return apperr.WithFields(
+				apperr.New(apperr.CodeConflict, "analysis already has a remeasurement campaign"),
+				map[string]any{"campaignId": *next},
+			)
+		}
+		if !in.InitialDate.After(previous.InitialDate) {
+			return apperr.New(apperr.CodeInvalid, "campaign date must be after the previous campaign")
+		}
+
+		phyto := domainphyto.NewPhytoAnalysis(
+			campaignID,
+			strings.TrimSpace(in.Title),
+			in.InitialDate,
+			previous.PortionQuantity,
+			previous.PortionArea,
+			previous.TotalArea,
+			previous.SampledArea,
+			previous.ProjectID,
+		)
+		phyto.SetDescription(in.Description)
+		phyto.SetDefaultFormFactor(previous.DefaultFormFactor)
+		m := previous.Measurement
+		phyto.SetMeasurementProtocol(m.Mode, m.Unit, m.InclusionMin, m.InclusionPolicy, m.ExcludeDead)
+
+		if err := phyto.Validate(); err != nil {
+			return apperr.Wrap(err, apperr.CodeInvalid, "invalid phyto analysis data")
+		}
+		if err := repos.PhytoAnalyses().Create(ctx, phyto); err != nil {
+			return err
+		}
+
+		strata := make(map[string]string, len(previous.Strata)) // estrato anterior -> novo
+		for _, st := range previous.Strata {
+			stratum, err := buildStratum(uuid.NewString(), campaignID, StratumInput{
+				Code:        st.Code,
+				Name:        st.Name,
+				AreaHa:      st.AreaHa,
+				Description: st.Description,
+			})
+			if err != nil {
+				return err
+			}
+			if err := repos.Strata().Create(ctx, stratum); err != nil {
+				return err
+			}
+			strata[st.ID] = stratum.ID
+		}
+
+		for _, pl := range previous.Plots {
+			in := plotInputFromData(pl)
+			if pl.StratumID != nil {
+				stratumID := strata[*pl.StratumID]
+				in.StratumID = &stratumID
+			}
+			plot, err := buildPlot(uuid.NewString(), campaignID, in)
+			if err != nil {
+				return err
+			}
+			if err := repos.Plots().Create(ctx, plot); err != nil {
+				return err
+			}
+		}
+
+		for _, eq := range []*types.EquationData{previous.VolumeEquation, previous.BiomassEquation} {
+			if eq == nil {
+				continue
+			}
+			if err := repos.PhytoAnalyses().SetEquation(ctx, campaignID, eq); err != nil {
+				return err
+			}
+		}
+
+		if err := repos.PhytoAnalyses().CreateCampaign(ctx, campaignID, id); err != nil {
+			return err
+		}
+
+		_, err = snapshotIndicators(ctx, repos.PhytoAnalyses(), campaignID, phytometrics.ReasonCreated)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return campaignID, nil
+}
+
+// plotInputFromData converte a parcela cadastrada nos dados de cadastro (cópia entre campanhas)
+func plotInputFromData(pl *types.PlotData) PlotInput {
+	area := pl.AreaM2
+	return PlotInput{
+		Code:            pl.Code,
+		AreaM2:          &area,
+		Shape:           pl.Shape,
+		WidthM:          pl.WidthM,
+		LengthM:         pl.LengthM,
+		RadiusM:         pl.RadiusM,
+		Center:          pl.Center,
+		Vertices:        pl.Vertices,
+		VegetationNotes: pl.VegetationNotes,
+	}
+}
+
+// ListCampaigns retorna as campanhas da série de remedições da análise, da primeira à última
+func (s *Service) ListCampaigns(ctx context.Context, id string) ([]*types.PhytoAnalysisWithProject, error) {
+	if strings.TrimSpace(id) == "" {
+		return nil, apperr.New(apperr.CodeInvalid, "missing required fields")
+	}
+	if _, err := s.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	ids := []string{id}
+	seen := map[string]bool{id: true}
+	for current := id; ; {
+		previous, err := s.repo.GetPreviousCampaignID(ctx, current)
+		if err != nil {
+			return nil, err
+		}
+		if previous == nil || seen[*previous] {
+			break
+		}
+		seen[*previous] = true
+		ids = append([]string{*previous}, ids...)
+		current = *previous
+	}
+	for current := id; ; {
+		next, err := s.repo.GetNextCampaignID(ctx, current)
+		if err != nil {
+			return nil, err
+		}
+		if next == nil || seen[*next] {
+			break
+		}
+		seen[*next] = true
+		ids = append(ids, *next)
+		current = *next
+	}
+
+	out := make([]*types.PhytoAnalysisWithProject, 0, len(ids))
+	for _, campaignID := range ids {
+		phyto, err := s.GetByID(ctx, campaignID)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, phyto)
+	}
+	return out, nil
+}
+
+// GetDynamics calcula a dinâmica entre a análise e a campanha anterior (previousID vazio = campanha
+// remedida pela análise). As duas campanhas devem ser do mesmo projeto.
+func (s *Service) GetDynamics(ctx context.Context, id string, previousID string) (*phytometrics.Dynamics, error) {
+	if strings.TrimSpace(id) == "" {
+		return nil, apperr.New(apperr.CodeInvalid, "missing required fields")
+	}
+
+	current, err := s.GetWithSpecimens(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(previousID) == "" {
+		if current.PreviousAnalysisID == nil {
+			return nil, apperr.New(apperr.CodeInvalid, "analysis has no previous campaign")
+		}
+		previousID = *current.PreviousAnalysisID
+	}
+	if previousID == id {
+		return nil, apperr.New(apperr.CodeInvalid, "previous campaign must be another analysis")
+	}
+
+	previous, err := s.GetWithSpecimens(ctx, previousID)
+	if err != nil {
+		return nil, err
+	}
+	if previous.ProjectID != current.ProjectID {
+		return nil, apperr.New(apperr.CodeInvalid, "campaigns must belong to the same project")
+	}
+	if !current.InitialDate.After(previous.InitialDate) {
+		return nil, apperr.New(apperr.CodeInvalid, "campaign date must be after the previous campaign")
+	}
+
+	return phytometrics.ComputeDynamics(previous, current), nil
+}
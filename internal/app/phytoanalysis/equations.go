@@ -0,0 +1,130 @@
+package phytoanalysis
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/ESG-Project/suassu-api/internal/app/phytometrics"
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+	"github.com/ESG-Project/suassu-api/internal/apperr"
+	postgres "github.com/ESG-Project/suassu-api/internal/infra/db/postgres"
+)
+
+// GetEquation busca uma equação do cadastro (embutida ou cadastrada)
+func (s *Service) GetEquation(ctx context.Context, equationID string) (*types.EquationData, error) {
+	if s.equations == nil {
+		return nil, apperr.New(apperr.CodeInternal, "equation registry required")
+	}
+	return s.equations.GetByID(ctx, equationID)
+}
+
+// SuggestEquations lista as equações aplicáveis à análise (pelo estado do projeto e pelo filtro),
+// das mais específicas para as genéricas
+func (s *Service) SuggestEquations(ctx context.Context, id string, f types.EquationFilter) ([]*types.EquationData, error) {
+	if s.equations == nil {
+		return nil, apperr.New(apperr.CodeInternal, "equation registry required")
+	}
+
+	phyto, err := s.GetComplete(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if f.State == "" && phyto.ProjectState != nil {
+		f.State = *phyto.ProjectState
+	}
+
+	list, err := s.equations.List(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].Kind != list[j].Kind {
+			return list[i].Kind > list[j].Kind
+		}
+		si, sj := phytometrics.EquationSpecificity(*list[i]), phytometrics.EquationSpecificity(*list[j])
+		if si != sj {
+			return si > sj
+		}
+		return list[i].Name < list[j].Name
+	})
+	return list, nil
+}
+
+// SelectEquation seleciona a equação de volume ou biomassa da análise. A definição é copiada na
+// seleção: alterações posteriores no cadastro não mudam análises já calculadas.
+func (s *Service) SelectEquation(ctx context.Context, id string, equationID string) (*phytometrics.Snapshot, error) {
+	if strings.TrimSpace(id) == "" || strings.TrimSpace(equationID) == "" {
+		return nil, apperr.New(apperr.CodeInvalid, "missing required fields")
+	}
+
+	eq, err := s.GetEquation(ctx, equationID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := phytometrics.CompileEquation(*eq); err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeInvalid, "invalid equation")
+	}
+
+	phyto, err := s.GetComplete(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if phyto.ProjectState != nil && !phytometrics.EquationMatches(*eq, types.EquationFilter{State: *phyto.ProjectState}) {
+		return nil, apperr.WithFields(
+			apperr.New(apperr.CodeInvalid, "equation not applicable to project state"),
+			map[string]any{"state": *phyto.ProjectState, "states": eq.Applicability.States},
+		)
+	}
+
+	return s.changeAndSnapshot(ctx, id, phytometrics.ReasonEquationChanged, func(repo Repo) error {
+		return repo.SetEquation(ctx, id, eq)
+	})
+}
+
+// ClearEquation remove a equação selecionada; a análise volta a usar o cálculo padrão
+func (s *Service) ClearEquation(ctx context.Context, id string, kind string) (*phytometrics.Snapshot, error) {
+	if strings.TrimSpace(id) == "" {
+		return nil, apperr.New(apperr.CodeInvalid, "missing required fields")
+	}
+	if kind != types.EquationKindVolume && kind != types.EquationKindBiomass {
+		return nil, apperr.New(apperr.CodeInvalid, "invalid equation kind")
+	}
+	if _, err := s.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.changeAndSnapshot(ctx, id, phytometrics.ReasonEquationChanged, func(repo Repo) error {
+		return repo.DeleteEquation(ctx, id, kind)
+	})
+}
+
+// changeAndSnapshot aplica a alteração (seleção de equação, regras de classificação) e recalcula
+// os indicadores na mesma transação
+func (s *Service) changeAndSnapshot(ctx context.Context, id string, reason string, change func(repo Repo) error) (*phytometrics.Snapshot, error) {
+	if s.txm == nil {
+		if err := change(s.repo); err != nil {
+			return nil, err
+		}
+		rec, err := snapshotIndicators(ctx, s.repo, id, reason)
+		if err != nil {
+			return nil, err
+		}
+		return rec.Current, nil
+	}
+
+	var rec *phytometrics.Recomputation
+	err := s.txm.RunInTx(ctx, func(repos postgres.Repos) error {
+		if err := change(repos.PhytoAnalyses()); err != nil {
+			return err
+		}
+		var err error
+		rec, err = snapshotIndicators(ctx, repos.PhytoAnalyses(), id, reason)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rec.Current, nil
+}
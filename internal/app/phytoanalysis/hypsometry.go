@@ -0,0 +1,42 @@
+package phytoanalysis
+
+import (
+	"context"
+
+	"github.com/ESG-Project/suassu-api/internal/app/phytometrics"
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+)
+
+// GetHypsometry retorna a relação hipsométrica do snapshot mais recente: os modelos ajustados
+// com as árvores de altura medida, o selecionado e as contagens de alturas estimadas
+func (s *Service) GetHypsometry(ctx context.Context, id string) (*phytometrics.Hypsometry, error) {
+	snapshot, err := s.GetIndicators(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if snapshot.Result == nil || snapshot.Result.Hypsometry == nil {
+		return &phytometrics.Hypsometry{Fits: []phytometrics.HypsometricFit{}}, nil
+	}
+	return snapshot.Result.Hypsometry, nil
+}
+
+// heightEstimator preenche a altura dos espécimes sem medição. A listagem paginada não carrega
+// a análise inteira, então usa o modelo gravado no snapshot, buscado apenas quando necessário.
+func (s *Service) heightEstimator(ctx context.Context, id string) func(*types.SpecimenWithSpecies) error {
+	var fit *phytometrics.HypsometricFit
+	loaded := false
+	return func(sp *types.SpecimenWithSpecies) error {
+		if sp.Height > 0 || phytometrics.ABI(sp) <= 0 {
+			return nil // altura medida, ou sem DAP para estimar
+		}
+		if !loaded {
+			h, err := s.GetHypsometry(ctx, id)
+			if err != nil {
+				return err
+			}
+			fit, loaded = h.Selected, true
+		}
+		phytometrics.FillHeight(sp, fit)
+		return nil
+	}
+}
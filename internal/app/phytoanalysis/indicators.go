@@ -0,0 +1,166 @@
+package phytoanalysis
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/ESG-Project/suassu-api/internal/app/phytometrics"
+	"github.com/ESG-Project/suassu-api/internal/apperr"
+	postgres "github.com/ESG-Project/suassu-api/internal/infra/db/postgres"
+	"github.com/google/uuid"
+)
+
+// GetIndicators retorna o snapshot mais recente dos indicadores da análise.
+// Análises ainda sem snapshot (anteriores ao motor) e snapshots de uma versão anterior do motor
+// têm o resultado calculado na leitura, sem gravar: a nova versão só é gravada por uma alteração
+// ou pelo recálculo explícito.
+func (s *Service) GetIndicators(ctx context.Context, id string) (*phytometrics.Snapshot, error) {
+	latest, err := latestIndicatorSnapshot(ctx, s.repo, id)
+	if err != nil {
+		return nil, err
+	}
+	if latest != nil && !latest.Outdated() {
+		return latest, nil
+	}
+
+	phyto, err := s.repo.GetWithSpecimens(ctx, id)
+	if err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeNotFound, "phyto analysis not found")
+	}
+
+	reason := phytometrics.ReasonInitial
+	if latest != nil {
+		reason = phytometrics.ReasonEngineUpgraded
+	}
+	result := phytometrics.Compute(phyto)
+	return &phytometrics.Snapshot{
+		PhytoAnalysisID: id,
+		EngineVersion:   phytometrics.EngineVersion,
+		Reason:          reason,
+		SpecimensCount:  len(phyto.Specimens),
+		CreatedAt:       time.Now(),
+		Result:          result,
+	}, nil
+}
+
+// GetIndicatorSnapshot retorna uma versão específica dos indicadores da análise
+func (s *Service) GetIndicatorSnapshot(ctx context.Context, id string, version int) (*phytometrics.Snapshot, error) {
+	if version <= 0 {
+		return nil, apperr.New(apperr.CodeInvalid, "invalid version")
+	}
+
+	snapshot, err := s.repo.GetIndicatorSnapshot(ctx, id, version)
+	if err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeNotFound, "indicator snapshot not found")
+	}
+	return snapshot, nil
+}
+
+// ListIndicatorSnapshots lista as versões dos indicadores da análise (mais recente primeiro)
+func (s *Service) ListIndicatorSnapshots(ctx context.Context, id string) ([]*phytometrics.Snapshot, error) {
+	if _, err := s.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.ListIndicatorSnapshots(ctx, id)
+}
+
+// RecomputeIndicators recalcula os indicadores com os dados atuais e compara com o snapshot anterior.
+// Uma nova versão só é gravada quando o resultado muda ou o snapshot anterior é de outra versão do motor.
+func (s *Service) RecomputeIndicators(ctx context.Context, id string) (*phytometrics.Recomputation, error) {
+	return s.snapshotIndicatorsInTx(ctx, id, phytometrics.ReasonRecomputed)
+}
+
+// RefreshIndicatorsInTx atualiza o snapshot após alterações feitas fora deste serviço (ex.: espécimes
+// avulsos), na transação da alteração, para que os dois sejam gravados juntos
+func (s *Service) RefreshIndicatorsInTx(ctx context.Context, repos postgres.Repos, id string, reason string) error {
+	_, err := snapshotIndicators(ctx, repos.PhytoAnalyses(), id, reason)
+	return err
+}
+
+func (s *Service) snapshotIndicatorsInTx(ctx context.Context, id string, reason string) (*phytometrics.Recomputation, error) {
+	if strings.TrimSpace(id) == "" {
+		return nil, apperr.New(apperr.CodeInvalid, "missing required fields")
+	}
+
+	if s.txm == nil {
+		return snapshotIndicators(ctx, s.repo, id, reason)
+	}
+
+	var rec *phytometrics.Recomputation
+	err := s.txm.RunInTx(ctx, func(repos postgres.Repos) error {
+		var err error
+		rec, err = snapshotIndicators(ctx, repos.PhytoAnalyses(), id, reason)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+// snapshotIndicators calcula os indicadores da análise e grava uma nova versão quando o
+// resultado ou as equações usadas diferem do snapshot anterior
+func snapshotIndicators(ctx context.Context, repo Repo, id string, reason string) (*phytometrics.Recomputation, error) {
+	// serializa gravações concorrentes na mesma análise (versão = anterior + 1)
+	if err := repo.LockForSnapshot(ctx, id); err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeNotFound, "phyto analysis not found")
+	}
+
+	phyto, err := repo.GetWithSpecimens(ctx, id)
+	if err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeNotFound, "phyto analysis not found")
+	}
+
+	previous, err := latestIndicatorSnapshot(ctx, repo, id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := phytometrics.Compute(phyto)
+
+	rec := &phytometrics.Recomputation{Previous: previous}
+	if previous != nil {
+		rec.Changes = phytometrics.Diff(previous.Result, result)
+		if len(rec.Changes) == 0 && !previous.Outdated() && !phytometrics.EquationsChanged(previous.Result, result) {
+			rec.Current = previous
+			return rec, nil
+		}
+	} else {
+		rec.Changes = phytometrics.Diff(nil, result)
+	}
+
+	version := 1
+	if previous != nil {
+		version = previous.Version + 1
+	}
+
+	rec.Current = &phytometrics.Snapshot{
+		ID:              uuid.NewString(),
+		PhytoAnalysisID: id,
+		Version:         version,
+		EngineVersion:   phytometrics.EngineVersion,
+		Reason:          reason,
+		SpecimensCount:  len(phyto.Specimens),
+		CreatedAt:       time.Now(),
+		Result:          result,
+	}
+	if err := repo.CreateIndicatorSnapshot(ctx, rec.Current); err != nil {
+		return nil, err
+	}
+	rec.Created = true
+
+	return rec, nil
+}
+
+// latestIndicatorSnapshot retorna o snapshot mais recente ou nil quando a análise não tem nenhum
+func latestIndicatorSnapshot(ctx context.Context, repo Repo, id string) (*phytometrics.Snapshot, error) {
+	snapshot, err := repo.GetLatestIndicatorSnapshot(ctx, id)
+	if err != nil {
+		if apperr.CodeOf(err) == apperr.CodeNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return snapshot, nil
+}
@@ -0,0 +1,99 @@
+package phytoanalysis
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+)
+
+// normalizeMeasurementProtocol aplica os padrões do protocolo (CAP em cm, sem critério, rejeitar)
+func normalizeMeasurementProtocol(m types.MeasurementProtocol) types.MeasurementProtocol {
+	m.Mode = strings.ToLower(strings.TrimSpace(m.Mode))
+	if m.Mode == "" {
+		m.Mode = types.MeasurementModeCAP
+	}
+	m.Unit = strings.ToLower(strings.TrimSpace(m.Unit))
+	if m.Unit == "" {
+		m.Unit = types.MeasurementUnitCm
+	}
+	m.InclusionPolicy = strings.ToLower(strings.TrimSpace(m.InclusionPolicy))
+	if m.InclusionPolicy == "" {
+		m.InclusionPolicy = types.InclusionReject
+	}
+	return m
+}
+
+// validateMeasurementProtocol lista os problemas no protocolo (já normalizado)
+func validateMeasurementProtocol(m types.MeasurementProtocol) []string {
+	errs := make([]string, 0)
+	if m.Mode != types.MeasurementModeCAP && m.Mode != types.MeasurementModeDAP {
+		errs = append(errs, "measurement mode must be cap or dap")
+	}
+	if m.Unit != types.MeasurementUnitCm && m.Unit != types.MeasurementUnitMm {
+		errs = append(errs, "measurement unit must be cm or mm")
+	}
+	if m.InclusionMin != nil && *m.InclusionMin <= 0 {
+		errs = append(errs, "inclusion minimum must be positive")
+	}
+	if m.InclusionPolicy != types.InclusionReject && m.InclusionPolicy != types.InclusionFlag {
+		errs = append(errs, "inclusion policy must be reject or flag")
+	}
+	return errs
+}
+
+// stemToCapCm converte a medida de um fuste no protocolo para CAP em cm (formato gravado)
+func stemToCapCm(v float64, m types.MeasurementProtocol) float64 {
+	if m.Unit == types.MeasurementUnitMm {
+		v /= 10
+	}
+	if m.Mode == types.MeasurementModeDAP {
+		v *= math.Pi
+	}
+	return v
+}
+
+// applyMeasurementProtocol confere o critério de inclusão de cada fuste (na unidade informada)
+// e converte as medidas para CAP em cm. Retorna as mensagens dos fustes abaixo do critério.
+func applyMeasurementProtocol(sp *SpecimenInput, m types.MeasurementProtocol) []string {
+	stems := []struct {
+		name  string
+		value *float64
+	}{
+		{"cap1", &sp.Cap1},
+		{"cap2", sp.Cap2},
+		{"cap3", sp.Cap3},
+		{"cap4", sp.Cap4},
+		{"cap5", sp.Cap5},
+		{"cap6", sp.Cap6},
+	}
+
+	below := make([]string, 0)
+	for _, stem := range stems {
+		if stem.value == nil || *stem.value <= 0 {
+			continue
+		}
+		if m.InclusionMin != nil && *stem.value < *m.InclusionMin {
+			below = append(below, fmt.Sprintf("%s below inclusion criterion (%s >= %g %s)",
+				stem.name, strings.ToUpper(m.Mode), *m.InclusionMin, m.Unit))
+		}
+	}
+
+	// ponteiros novos: a entrada original não é alterada
+	convert := func(v *float64) *float64 {
+		if v == nil {
+			return nil
+		}
+		c := stemToCapCm(*v, m)
+		return &c
+	}
+	sp.Cap1 = stemToCapCm(sp.Cap1, m)
+	sp.Cap2 = convert(sp.Cap2)
+	sp.Cap3 = convert(sp.Cap3)
+	sp.Cap4 = convert(sp.Cap4)
+	sp.Cap5 = convert(sp.Cap5)
+	sp.Cap6 = convert(sp.Cap6)
+
+	return below
+}
@@ -0,0 +1,358 @@
+package phytoanalysis
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/ESG-Project/suassu-api/internal/app/phytometrics"
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+	"github.com/ESG-Project/suassu-api/internal/apperr"
+	domainmorphospecies "github.com/ESG-Project/suassu-api/internal/domain/morphospecies"
+	postgres "github.com/ESG-Project/suassu-api/internal/infra/db/postgres"
+	"github.com/google/uuid"
+)
+
+// MorphospeciesInput representa uma morfoespécie da análise (cadastro, edição ou declaração na importação)
+type MorphospeciesInput struct {
+	Name   string
+	Family *string // opcional: família já determinada
+	Genus  *string // opcional: gênero já determinado
+}
+
+// buildMorphospecies monta e valida a entidade da morfoespécie
+func buildMorphospecies(id, phytoID string, in MorphospeciesInput) (*domainmorphospecies.Morphospecies, error) {
+	m := domainmorphospecies.NewMorphospecies(id, phytoID, in.Name)
+	m.SetTaxonomy(in.Family, in.Genus)
+
+	if err := m.Validate(); err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeInvalid, "invalid morphospecies data")
+	}
+	return m, nil
+}
+
+// morphospeciesKey normaliza o nome da morfoespécie para comparação (caixa e espaços)
+func morphospeciesKey(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// prefixos (em minúsculas) usados em campo para indivíduos não identificados
+var indeterminatePrefixes = map[string]bool{
+	"sp":            true,
+	"spp":           true,
+	"indet":         true,
+	"indeterminada": true,
+	"indeterminado": true,
+	"ni":            true,
+	"morfo":         true,
+	"morfoespecie":  true,
+	"morfoespécie":  true,
+}
+
+// nomes de família sem a terminação -aceae (nomes conservados)
+var conservedFamilyNames = map[string]bool{
+	"compositae":   true,
+	"cruciferae":   true,
+	"gramineae":    true,
+	"guttiferae":   true,
+	"labiatae":     true,
+	"leguminosae":  true,
+	"palmae":       true,
+	"umbelliferae": true,
+}
+
+// isMorphospeciesQualifier indica se a palavra apenas numera ou qualifica a morfoespécie
+// ("sp.", "spp.", "1", "A")
+func isMorphospeciesQualifier(word string) bool {
+	w := strings.ToLower(strings.TrimSuffix(word, "."))
+	if w == "sp" || w == "spp" {
+		return true
+	}
+	if len([]rune(w)) == 1 {
+		return true
+	}
+	for _, r := range w {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return w != ""
+}
+
+// detectMorphospecies reconhece os nomes de determinação incompleta usados em campo e retorna
+// a família ou o gênero já determinados:
+//
+//	"Indet.", "Indet. 3", "NI 4", "Morfo 1", "sp. 2" -> sem família e gênero
+//	"Myrcia", "Myrcia sp.", "Myrcia sp. 2"           -> gênero Myrcia
+//	"Myrtaceae", "Myrtaceae sp. 1", "Leguminosae 2"  -> família
+//
+// Binômios ("Myrcia splendens") não são reconhecidos: seguem como espécie não encontrada.
+func detectMorphospecies(name string) (family, genus *string, ok bool) {
+	words := strings.Fields(name)
+	if len(words) == 0 {
+		return nil, nil, false
+	}
+	for _, w := range words[1:] {
+		if !isMorphospeciesQualifier(w) {
+			return nil, nil, false
+		}
+	}
+
+	first := strings.TrimSuffix(words[0], ".")
+	if indeterminatePrefixes[strings.ToLower(first)] {
+		return nil, nil, true
+	}
+
+	runes := []rune(first)
+	if len(runes) < 2 || !unicode.IsUpper(runes[0]) {
+		return nil, nil, false
+	}
+	for _, r := range runes {
+		if !unicode.IsLetter(r) {
+			return nil, nil, false
+		}
+	}
+
+	lower := strings.ToLower(first)
+	if strings.HasSuffix(lower, "aceae") || conservedFamilyNames[lower] {
+		return &first, nil, true
+	}
+	return nil, &first, true
+}
+
+// resolveMorphospecies associa os nomes não encontrados no catálogo a morfoespécies da análise,
+// nesta ordem: morfoespécie já cadastrada com o mesmo nome, morfoespécie declarada na importação
+// e nome reconhecido como determinação incompleta. As morfoespécies novas retornam para serem
+// cadastradas junto com os espécimes; os demais nomes seguem sem resolução.
+func resolveMorphospecies(ctx context.Context, repos postgres.Repos, phytoID string, names []string, declared []MorphospeciesInput) (map[string]resolvedSpecies, []*domainmorphospecies.Morphospecies, error) {
+	resolved := make(map[string]resolvedSpecies, len(names))
+	if len(names) == 0 {
+		return resolved, nil, nil
+	}
+
+	available := make(map[string]resolvedSpecies)
+	if phytoID != "" {
+		existing, err := repos.Morphospecies().ListByPhytoAnalysis(ctx, phytoID)
+		if err != nil {
+			return nil, nil, apperr.Wrap(err, apperr.CodeInternal, "failed to fetch morphospecies")
+		}
+		for _, m := range existing {
+			available[morphospeciesKey(m.Name)] = resolvedSpecies{ID: m.ID, ScientificName: m.Name}
+		}
+	}
+
+	declaredByKey := make(map[string]MorphospeciesInput, len(declared))
+	for _, d := range declared {
+		if key := morphospeciesKey(d.Name); key != "" {
+			declaredByKey[key] = d
+		}
+	}
+
+	created := make([]*domainmorphospecies.Morphospecies, 0)
+	for _, name := range names {
+		key := morphospeciesKey(name)
+		if m, ok := available[key]; ok {
+			resolved[name] = m
+			continue
+		}
+
+		in, ok := declaredByKey[key]
+		if !ok {
+			family, genus, detected := detectMorphospecies(name)
+			if !detected {
+				continue
+			}
+			in = MorphospeciesInput{Name: name, Family: family, Genus: genus}
+		}
+
+		m, err := buildMorphospecies(uuid.NewString(), phytoID, in)
+		if err != nil {
+			return nil, nil, err
+		}
+		created = append(created, m)
+		available[key] = resolvedSpecies{ID: m.ID, ScientificName: m.Name}
+		resolved[name] = available[key]
+	}
+
+	return resolved, created, nil
+}
+
+// createMorphospecies cadastra as morfoespécies novas de uma importação
+func createMorphospecies(ctx context.Context, repos postgres.Repos, list []*domainmorphospecies.Morphospecies) error {
+	for _, m := range list {
+		if err := repos.Morphospecies().Create(ctx, m); err != nil {
+			return apperr.Wrap(err, apperr.CodeInvalid, "failed to create morphospecies")
+		}
+	}
+	return nil
+}
+
+// morphospeciesNameTaken indica se outra morfoespécie da análise já usa o nome
+func morphospeciesNameTaken(list []*types.MorphospeciesData, name, exceptID string) bool {
+	key := morphospeciesKey(name)
+	for _, m := range list {
+		if morphospeciesKey(m.Name) == key && m.ID != exceptID {
+			return true
+		}
+	}
+	return false
+}
+
+// ListMorphospecies lista as morfoespécies da análise com a quantidade de espécimes de cada uma
+func (s *Service) ListMorphospecies(ctx context.Context, id string) ([]*types.MorphospeciesData, error) {
+	if strings.TrimSpace(id) == "" {
+		return nil, apperr.New(apperr.CodeInvalid, "missing required fields")
+	}
+	if _, err := s.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.ListMorphospecies(ctx, id)
+}
+
+// CreateMorphospecies cadastra uma morfoespécie na análise
+func (s *Service) CreateMorphospecies(ctx context.Context, id string, in MorphospeciesInput) (string, error) {
+	if strings.TrimSpace(id) == "" {
+		return "", apperr.New(apperr.CodeInvalid, "missing required fields")
+	}
+	if s.txm == nil {
+		return "", apperr.New(apperr.CodeInvalid, "transaction manager required")
+	}
+
+	m, err := buildMorphospecies(uuid.NewString(), id, in)
+	if err != nil {
+		return "", err
+	}
+
+	err = s.txm.RunInTx(ctx, func(repos postgres.Repos) error {
+		if _, err := repos.PhytoAnalyses().GetByID(ctx, id); err != nil {
+			return err
+		}
+
+		existing, err := repos.Morphospecies().ListByPhytoAnalysis(ctx, id)
+		if err != nil {
+			return err
+		}
+		if morphospeciesNameTaken(existing, m.Name, "") {
+			return apperr.New(apperr.CodeConflict, "morphospecies name already exists")
+		}
+
+		return repos.Morphospecies().Create(ctx, m)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return m.ID, nil
+}
+
+// UpdateMorphospecies atualiza o nome e a taxonomia da morfoespécie; os indicadores são recalculados
+// porque o nome identifica a morfoespécie na estrutura e na diversidade
+func (s *Service) UpdateMorphospecies(ctx context.Context, id string, morphospeciesID string, in MorphospeciesInput) error {
+	if strings.TrimSpace(id) == "" || strings.TrimSpace(morphospeciesID) == "" {
+		return apperr.New(apperr.CodeInvalid, "missing required fields")
+	}
+	if s.txm == nil {
+		return apperr.New(apperr.CodeInvalid, "transaction manager required")
+	}
+
+	m, err := buildMorphospecies(morphospeciesID, id, in)
+	if err != nil {
+		return err
+	}
+
+	return s.txm.RunInTx(ctx, func(repos postgres.Repos) error {
+		current, err := repos.Morphospecies().GetByID(ctx, id, morphospeciesID)
+		if err != nil {
+			return err
+		}
+
+		existing, err := repos.Morphospecies().ListByPhytoAnalysis(ctx, id)
+		if err != nil {
+			return err
+		}
+		if morphospeciesNameTaken(existing, m.Name, morphospeciesID) {
+			return apperr.New(apperr.CodeConflict, "morphospecies name already exists")
+		}
+
+		m.CreatedAt = current.CreatedAt
+		m.UpdatedAt = time.Now()
+		if err := repos.Morphospecies().Update(ctx, m); err != nil {
+			return err
+		}
+
+		_, err = snapshotIndicators(ctx, repos.PhytoAnalyses(), id, phytometrics.ReasonMorphospeciesChanged)
+		return err
+	})
+}
+
+// DeleteMorphospecies remove a morfoespécie; morfoespécies com espécimes não podem ser removidas
+func (s *Service) DeleteMorphospecies(ctx context.Context, id string, morphospeciesID string) error {
+	if strings.TrimSpace(id) == "" || strings.TrimSpace(morphospeciesID) == "" {
+		return apperr.New(apperr.CodeInvalid, "missing required fields")
+	}
+	if s.txm == nil {
+		return apperr.New(apperr.CodeInvalid, "transaction manager required")
+	}
+
+	return s.txm.RunInTx(ctx, func(repos postgres.Repos) error {
+		if _, err := repos.Morphospecies().GetByID(ctx, id, morphospeciesID); err != nil {
+			return err
+		}
+
+		count, err := repos.Morphospecies().CountSpecimens(ctx, morphospeciesID)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return apperr.WithFields(
+				apperr.New(apperr.CodeConflict, "morphospecies has specimens"),
+				map[string]any{"specimens": count},
+			)
+		}
+
+		return repos.Morphospecies().Delete(ctx, id, morphospeciesID)
+	})
+}
+
+// ResolveMorphospecies determina a morfoespécie como uma espécie do cadastro: todos os seus
+// espécimes passam a apontar para a espécie e a morfoespécie é removida, na mesma transação.
+// Retorna a quantidade de espécimes reassociados.
+func (s *Service) ResolveMorphospecies(ctx context.Context, id string, morphospeciesID string, speciesID string) (int64, error) {
+	if strings.TrimSpace(id) == "" || strings.TrimSpace(morphospeciesID) == "" || strings.TrimSpace(speciesID) == "" {
+		return 0, apperr.New(apperr.CodeInvalid, "missing required fields")
+	}
+	if s.txm == nil {
+		return 0, apperr.New(apperr.CodeInvalid, "transaction manager required")
+	}
+
+	var relinked int64
+	err := s.txm.RunInTx(ctx, func(repos postgres.Repos) error {
+		if _, err := repos.Morphospecies().GetByID(ctx, id, morphospeciesID); err != nil {
+			return err
+		}
+		if _, err := repos.Species().GetByID(ctx, speciesID); err != nil {
+			return apperr.Wrap(err, apperr.CodeNotFound, "species not found")
+		}
+
+		count, err := repos.Morphospecies().ResolveSpecimens(ctx, morphospeciesID, speciesID)
+		if err != nil {
+			return err
+		}
+		if err := repos.Morphospecies().Delete(ctx, id, morphospeciesID); err != nil {
+			return err
+		}
+
+		if _, err := snapshotIndicators(ctx, repos.PhytoAnalyses(), id, phytometrics.ReasonMorphospeciesChanged); err != nil {
+			return err
+		}
+
+		relinked = count
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return relinked, nil
+}
@@ -0,0 +1,298 @@
+package phytoanalysis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ESG-Project/suassu-api/internal/app/phytometrics"
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+	"github.com/ESG-Project/suassu-api/internal/apperr"
+	domainplot "github.com/ESG-Project/suassu-api/internal/domain/plot"
+	postgres "github.com/ESG-Project/suassu-api/internal/infra/db/postgres"
+	"github.com/google/uuid"
+)
+
+// PlotInput representa os dados de uma parcela da análise
+type PlotInput struct {
+	Code            string
+	AreaM2          *float64 // omitida = calculada pelas dimensões (retangular ou circular)
+	Shape           string   // rectangle, circle ou irregular
+	WidthM          *float64
+	LengthM         *float64
+	RadiusM         *float64
+	Center          *types.Coordinate
+	Vertices        []types.Coordinate
+	VegetationNotes *string
+	StratumID       *string // estrato da análise (nil = sem estrato)
+}
+
+// newPlot monta e valida a entidade da parcela
+func newPlot(id, phytoID string, in PlotInput) (*domainplot.Plot, error) {
+	area := 0.0
+	if in.AreaM2 != nil {
+		area = *in.AreaM2
+		if area <= 0 {
+			return nil, errors.New("area must be positive")
+		}
+	}
+
+	p := domainplot.NewPlot(id, phytoID, strings.TrimSpace(in.Code), strings.ToLower(strings.TrimSpace(in.Shape)), area)
+	p.SetDimensions(in.WidthM, in.LengthM, in.RadiusM)
+
+	var center *domainplot.Coordinate
+	if in.Center != nil {
+		center = &domainplot.Coordinate{Latitude: in.Center.Latitude, Longitude: in.Center.Longitude}
+	}
+	vertices := make([]domainplot.Coordinate, 0, len(in.Vertices))
+	for _, v := range in.Vertices {
+		vertices = append(vertices, domainplot.Coordinate{Latitude: v.Latitude, Longitude: v.Longitude})
+	}
+	p.SetLocation(center, vertices)
+	p.SetVegetationNotes(in.VegetationNotes)
+	if in.StratumID != nil && strings.TrimSpace(*in.StratumID) != "" {
+		stratumID := strings.TrimSpace(*in.StratumID)
+		p.SetStratum(&stratumID)
+	}
+	p.DeriveArea()
+
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func buildPlot(id, phytoID string, in PlotInput) (*domainplot.Plot, error) {
+	p, err := newPlot(id, phytoID, in)
+	if err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeInvalid, "invalid plot data")
+	}
+	return p, nil
+}
+
+// buildCreatePlots monta as parcelas informadas na criação da análise e, quando houver,
+// deriva delas o número de parcelas e a área por parcela. Retorna os erros de todas as parcelas.
+func buildCreatePlots(phytoID string, in *CreateInput) ([]*domainplot.Plot, []string) {
+	plots := make([]*domainplot.Plot, 0, len(in.Plots))
+	errs := make([]string, 0)
+	seen := make(map[string]bool, len(in.Plots))
+	for i, pi := range in.Plots {
+		p, err := newPlot(uuid.NewString(), phytoID, pi)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("plot %d: %s", i+1, err.Error()))
+			continue
+		}
+		if seen[p.Code] {
+			errs = append(errs, fmt.Sprintf("plot %d: duplicated code %s", i+1, p.Code))
+			continue
+		}
+		if p.StratumID != nil {
+			// os estratos são cadastrados depois da análise
+			errs = append(errs, fmt.Sprintf("plot %d: stratum not found", i+1))
+			continue
+		}
+		seen[p.Code] = true
+		plots = append(plots, p)
+	}
+
+	if len(errs) == 0 && len(plots) > 0 {
+		in.PortionQuantity, in.PortionArea, _ = phytometrics.PlotSampling(toPlotsData(plots))
+	}
+	return plots, errs
+}
+
+// toPlotsData converte as entidades para o formato usado no cálculo (prévia e derivação das áreas)
+func toPlotsData(plots []*domainplot.Plot) []*types.PlotData {
+	out := make([]*types.PlotData, 0, len(plots))
+	for _, p := range plots {
+		pd := &types.PlotData{
+			ID:              p.ID,
+			PhytoAnalysisID: p.PhytoAnalysisID,
+			Code:            p.Code,
+			AreaM2:          p.AreaM2,
+			Shape:           p.Shape,
+			WidthM:          p.WidthM,
+			LengthM:         p.LengthM,
+			RadiusM:         p.RadiusM,
+			VegetationNotes: p.VegetationNotes,
+			StratumID:       p.StratumID,
+			CreatedAt:       p.CreatedAt,
+			UpdatedAt:       p.UpdatedAt,
+		}
+		if p.Center != nil {
+			pd.Center = &types.Coordinate{Latitude: p.Center.Latitude, Longitude: p.Center.Longitude}
+		}
+		for _, v := range p.Vertices {
+			pd.Vertices = append(pd.Vertices, types.Coordinate{Latitude: v.Latitude, Longitude: v.Longitude})
+		}
+		out = append(out, pd)
+	}
+	return out
+}
+
+// plotCodes retorna os códigos das parcelas cadastradas (nil quando não há cadastro)
+func plotCodes(plots []*types.PlotData) map[string]bool {
+	if len(plots) == 0 {
+		return nil
+	}
+	codes := make(map[string]bool, len(plots))
+	for _, pl := range plots {
+		codes[pl.Code] = true
+	}
+	return codes
+}
+
+// checkPlotStratum verifica se o estrato informado na parcela pertence à análise
+func checkPlotStratum(ctx context.Context, repos postgres.Repos, phytoID string, stratumID *string) error {
+	if stratumID == nil {
+		return nil
+	}
+	if _, err := repos.Strata().GetByID(ctx, phytoID, *stratumID); err != nil {
+		if apperr.CodeOf(err) == apperr.CodeNotFound {
+			return apperr.New(apperr.CodeInvalid, "stratum not found")
+		}
+		return err
+	}
+	return nil
+}
+
+// syncPlotSampling deriva o número de parcelas e as áreas da análise das parcelas cadastradas
+// e recalcula os indicadores
+func syncPlotSampling(ctx context.Context, repos postgres.Repos, id string) error {
+	plots, err := repos.Plots().ListByPhytoAnalysis(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if quantity, meanArea, sampledArea := phytometrics.PlotSampling(plots); quantity > 0 {
+		if err := repos.PhytoAnalyses().UpdateSampling(ctx, id, quantity, meanArea, sampledArea); err != nil {
+			return err
+		}
+	}
+
+	_, err = snapshotIndicators(ctx, repos.PhytoAnalyses(), id, phytometrics.ReasonPlotsChanged)
+	return err
+}
+
+// CreatePlot cadastra uma parcela na análise; espécimes com o mesmo código passam a pertencer a ela
+func (s *Service) CreatePlot(ctx context.Context, id string, in PlotInput) (string, error) {
+	if strings.TrimSpace(id) == "" {
+		return "", apperr.New(apperr.CodeInvalid, "missing required fields")
+	}
+	if s.txm == nil {
+		return "", apperr.New(apperr.CodeInvalid, "transaction manager required")
+	}
+
+	plot, err := buildPlot(uuid.NewString(), id, in)
+	if err != nil {
+		return "", err
+	}
+
+	err = s.txm.RunInTx(ctx, func(repos postgres.Repos) error {
+		if _, err := repos.PhytoAnalyses().GetByID(ctx, id); err != nil {
+			return err
+		}
+
+		existing, err := repos.Plots().ListByPhytoAnalysis(ctx, id)
+		if err != nil {
+			return err
+		}
+		if plotCodes(existing)[plot.Code] {
+			return apperr.New(apperr.CodeConflict, "plot code already exists")
+		}
+		if err := checkPlotStratum(ctx, repos, id, plot.StratumID); err != nil {
+			return err
+		}
+
+		if err := repos.Plots().Create(ctx, plot); err != nil {
+			return err
+		}
+		return syncPlotSampling(ctx, repos, id)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return plot.ID, nil
+}
+
+// UpdatePlot atualiza a parcela; a troca de código é aplicada também aos espécimes da parcela
+func (s *Service) UpdatePlot(ctx context.Context, id string, plotID string, in PlotInput) error {
+	if strings.TrimSpace(id) == "" || strings.TrimSpace(plotID) == "" {
+		return apperr.New(apperr.CodeInvalid, "missing required fields")
+	}
+	if s.txm == nil {
+		return apperr.New(apperr.CodeInvalid, "transaction manager required")
+	}
+
+	plot, err := buildPlot(plotID, id, in)
+	if err != nil {
+		return err
+	}
+
+	return s.txm.RunInTx(ctx, func(repos postgres.Repos) error {
+		current, err := repos.Plots().GetByID(ctx, id, plotID)
+		if err != nil {
+			return err
+		}
+
+		if plot.Code != current.Code {
+			existing, err := repos.Plots().ListByPhytoAnalysis(ctx, id)
+			if err != nil {
+				return err
+			}
+			if plotCodes(existing)[plot.Code] {
+				return apperr.New(apperr.CodeConflict, "plot code already exists")
+			}
+			if err := repos.Plots().RenameSpecimensPortion(ctx, id, current.Code, plot.Code); err != nil {
+				return err
+			}
+		}
+
+		if err := checkPlotStratum(ctx, repos, id, plot.StratumID); err != nil {
+			return err
+		}
+
+		plot.CreatedAt = current.CreatedAt
+		plot.UpdatedAt = time.Now()
+		if err := repos.Plots().Update(ctx, plot); err != nil {
+			return err
+		}
+		return syncPlotSampling(ctx, repos, id)
+	})
+}
+
+// DeletePlot remove a parcela; parcelas com espécimes não podem ser removidas
+func (s *Service) DeletePlot(ctx context.Context, id string, plotID string) error {
+	if strings.TrimSpace(id) == "" || strings.TrimSpace(plotID) == "" {
+		return apperr.New(apperr.CodeInvalid, "missing required fields")
+	}
+	if s.txm == nil {
+		return apperr.New(apperr.CodeInvalid, "transaction manager required")
+	}
+
+	return s.txm.RunInTx(ctx, func(repos postgres.Repos) error {
+		current, err := repos.Plots().GetByID(ctx, id, plotID)
+		if err != nil {
+			return err
+		}
+
+		count, err := repos.Plots().CountSpecimens(ctx, id, current.Code)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return apperr.WithFields(
+				apperr.New(apperr.CodeConflict, "plot has specimens"),
+				map[string]any{"specimens": count},
+			)
+		}
+
+		if err := repos.Plots().Delete(ctx, id, plotID); err != nil {
+			return err
+		}
+		return syncPlotSampling(ctx, repos, id)
+	})
+}
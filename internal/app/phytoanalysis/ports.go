@@ -3,6 +3,7 @@ package phytoanalysis
 import (
 	"context"
 
+	"github.com/ESG-Project/suassu-api/internal/app/phytometrics"
 	"github.com/ESG-Project/suassu-api/internal/app/types"
 	domainphyto "github.com/ESG-Project/suassu-api/internal/domain/phytoanalysis"
 )
@@ -17,4 +18,35 @@ type Repo interface {
 	Update(ctx context.Context, p *domainphyto.PhytoAnalysis) error
 	Delete(ctx context.Context, id string) error
 	GetWithSpecimens(ctx context.Context, id string) (*types.PhytoAnalysisComplete, error)
+	GetComplete(ctx context.Context, id string) (*types.PhytoAnalysisComplete, error)
+	ListSpecimens(ctx context.Context, phytoAnalysisID string, f types.SpecimenFilter, limit int32, after *types.SpecimenCursorKey) ([]*types.SpecimenWithSpecies, types.SpecimenPageInfo, error)
+	StreamSpecimens(ctx context.Context, phytoAnalysisID string, f types.SpecimenFilter, fn func(*types.SpecimenWithSpecies) error) error
+	FilterIDsByEnterprise(ctx context.Context, enterpriseID string, ids []string) ([]string, error)
+
+	// Snapshots dos indicadores
+	LockForSnapshot(ctx context.Context, phytoAnalysisID string) error
+	CreateIndicatorSnapshot(ctx context.Context, s *phytometrics.Snapshot) error
+	GetLatestIndicatorSnapshot(ctx context.Context, phytoAnalysisID string) (*phytometrics.Snapshot, error)
+	GetIndicatorSnapshot(ctx context.Context, phytoAnalysisID string, version int) (*phytometrics.Snapshot, error)
+	ListIndicatorSnapshots(ctx context.Context, phytoAnalysisID string) ([]*phytometrics.Snapshot, error)
+	DeleteIndicatorSnapshots(ctx context.Context, phytoAnalysisID string) error
+
+	// Equações selecionadas (uma por tipo)
+	SetEquation(ctx context.Context, phytoAnalysisID string, eq *types.EquationData) error
+	DeleteEquation(ctx context.Context, phytoAnalysisID string, kind string) error
+	SetAssortment(ctx context.Context, phytoAnalysisID string, rules types.AssortmentRules) error
+	DeleteAssortment(ctx context.Context, phytoAnalysisID string) error
+
+	// Campanhas de remedição (parcelas permanentes)
+	GetPreviousCampaignID(ctx context.Context, phytoAnalysisID string) (*string, error)
+	GetNextCampaignID(ctx context.Context, phytoAnalysisID string) (*string, error)
+
+	// Morfoespécies da análise (com a quantidade de espécimes)
+	ListMorphospecies(ctx context.Context, phytoAnalysisID string) ([]*types.MorphospeciesData, error)
+}
+
+// EquationResolver resolve equações do cadastro (embutidas ou cadastradas)
+type EquationResolver interface {
+	GetByID(ctx context.Context, id string) (*types.EquationData, error)
+	List(ctx context.Context, f types.EquationFilter) ([]*types.EquationData, error)
 }
@@ -2,11 +2,14 @@ package phytoanalysis
 
 import (
 	"context"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/ESG-Project/suassu-api/internal/app/phytometrics"
 	"github.com/ESG-Project/suassu-api/internal/app/types"
 	"github.com/ESG-Project/suassu-api/internal/apperr"
+	domainmorphospecies "github.com/ESG-Project/suassu-api/internal/domain/morphospecies"
 	domainphyto "github.com/ESG-Project/suassu-api/internal/domain/phytoanalysis"
 	domainspecimen "github.com/ESG-Project/suassu-api/internal/domain/specimen"
 	postgres "github.com/ESG-Project/suassu-api/internal/infra/db/postgres"
@@ -17,16 +20,50 @@ type ServiceInterface interface {
 	Create(ctx context.Context, in CreateInput) (string, error)
 	GetByID(ctx context.Context, id string) (*types.PhytoAnalysisWithProject, error)
 	GetWithSpecimens(ctx context.Context, id string) (*types.PhytoAnalysisComplete, error)
+	GetForIndicators(ctx context.Context, id string) (*types.PhytoAnalysisComplete, error)
+	GetComplete(ctx context.Context, id string) (*types.PhytoAnalysisComplete, error)
+	ListSpecimens(ctx context.Context, id string, f types.SpecimenFilter, limit int32, after *types.SpecimenCursorKey) ([]*types.SpecimenWithSpecies, *types.SpecimenPageInfo, error)
+	StreamSpecimens(ctx context.Context, id string, f types.SpecimenFilter, fn func(*types.SpecimenWithSpecies) error) error
+	ListWithSpecimens(ctx context.Context, enterpriseID string, ids []string) ([]*types.PhytoAnalysisComplete, error)
+	AddSpecimens(ctx context.Context, id string, in AddSpecimensInput) (int, error)
+	ValidateCreate(ctx context.Context, in CreateInput) (*types.SpecimenImportReport, error)
+	ValidateSpecimens(ctx context.Context, id string, in AddSpecimensInput) (*types.SpecimenImportReport, error)
 	ListByProject(ctx context.Context, projectID string) ([]*types.PhytoAnalysisWithProject, error)
 	ListByEnterprise(ctx context.Context, enterpriseID string) ([]*types.PhytoAnalysisWithProject, error)
 	ListAll(ctx context.Context, limit, offset int32) ([]*types.PhytoAnalysisWithProject, error)
 	Update(ctx context.Context, id string, in UpdateInput) error
 	Delete(ctx context.Context, id string) error
+	GetIndicators(ctx context.Context, id string) (*phytometrics.Snapshot, error)
+	GetIndicatorSnapshot(ctx context.Context, id string, version int) (*phytometrics.Snapshot, error)
+	ListIndicatorSnapshots(ctx context.Context, id string) ([]*phytometrics.Snapshot, error)
+	RecomputeIndicators(ctx context.Context, id string) (*phytometrics.Recomputation, error)
+	GetEquation(ctx context.Context, equationID string) (*types.EquationData, error)
+	SuggestEquations(ctx context.Context, id string, f types.EquationFilter) ([]*types.EquationData, error)
+	SelectEquation(ctx context.Context, id string, equationID string) (*phytometrics.Snapshot, error)
+	ClearEquation(ctx context.Context, id string, kind string) (*phytometrics.Snapshot, error)
+	SetAssortment(ctx context.Context, id string, rules types.AssortmentRules) (*phytometrics.Snapshot, error)
+	ClearAssortment(ctx context.Context, id string) (*phytometrics.Snapshot, error)
+	GetHypsometry(ctx context.Context, id string) (*phytometrics.Hypsometry, error)
+	CreatePlot(ctx context.Context, id string, in PlotInput) (string, error)
+	UpdatePlot(ctx context.Context, id string, plotID string, in PlotInput) error
+	DeletePlot(ctx context.Context, id string, plotID string) error
+	CreateStratum(ctx context.Context, id string, in StratumInput) (string, error)
+	UpdateStratum(ctx context.Context, id string, stratumID string, in StratumInput) error
+	DeleteStratum(ctx context.Context, id string, stratumID string) error
+	CreateCampaign(ctx context.Context, id string, in CampaignInput) (string, error)
+	ListCampaigns(ctx context.Context, id string) ([]*types.PhytoAnalysisWithProject, error)
+	GetDynamics(ctx context.Context, id string, previousID string) (*phytometrics.Dynamics, error)
+	ListMorphospecies(ctx context.Context, id string) ([]*types.MorphospeciesData, error)
+	CreateMorphospecies(ctx context.Context, id string, in MorphospeciesInput) (string, error)
+	UpdateMorphospecies(ctx context.Context, id string, morphospeciesID string, in MorphospeciesInput) error
+	DeleteMorphospecies(ctx context.Context, id string, morphospeciesID string) error
+	ResolveMorphospecies(ctx context.Context, id string, morphospeciesID string, speciesID string) (int64, error)
 }
 
 type Service struct {
-	repo Repo
-	txm  postgres.TxManagerInterface
+	repo      Repo
+	txm       postgres.TxManagerInterface
+	equations EquationResolver
 }
 
 func NewService(r Repo, txm postgres.TxManagerInterface) *Service {
@@ -36,6 +73,15 @@ func NewService(r Repo, txm postgres.TxManagerInterface) *Service {
 	}
 }
 
+// NewServiceWithEquations cria o serviço com o cadastro de equações (seleção por análise)
+func NewServiceWithEquations(r Repo, txm postgres.TxManagerInterface, equations EquationResolver) *Service {
+	return &Service{
+		repo:      r,
+		txm:       txm,
+		equations: equations,
+	}
+}
+
 type CreateInput struct {
 	Title           string
 	InitialDate     time.Time
@@ -44,30 +90,69 @@ type CreateInput struct {
 	TotalArea       float64
 	Description     *string
 	ProjectID       string
-	Specimens       []SpecimenInput
+	// Fator de forma para espécies sem legislação aplicável (nil = cilíndrico)
+	DefaultFormFactor *float64
+	// Protocolo de medição dos fustes e critério de inclusão (vazio = CAP em cm, sem critério)
+	Measurement types.MeasurementProtocol
+	// Parcelas (opcional): quando informadas, definem PortionQuantity e PortionArea
+	Plots     []PlotInput
+	Specimens []SpecimenInput
+	// Mapeamento opcional nome científico (como informado) -> speciesID, usado para
+	// resolver nomes não encontrados no catálogo (ex.: a partir das sugestões do relatório)
+	SpeciesMapping map[string]string
+	// Morfoespécies declaradas (opcional): nomes sem determinação no catálogo, com família/gênero
+	Morphospecies []MorphospeciesInput
+}
+
+// AddSpecimensInput representa uma importação de espécimes em uma análise existente
+type AddSpecimensInput struct {
+	Specimens      []SpecimenInput
+	SpeciesMapping map[string]string    // nome científico (como informado) -> speciesID
+	Morphospecies  []MorphospeciesInput // morfoespécies declaradas (opcional)
 }
 
 type SpecimenInput struct {
 	Portion      string
-	Height       float64
-	Cap1         float64
+	Height       *float64 // opcional: sem medição, é estimada pela relação hipsométrica
+	Cap1         float64  // medidas dos fustes no protocolo da análise (CAP ou DAP, cm ou mm)
 	Cap2         *float64
 	Cap3         *float64
 	Cap4         *float64
 	Cap5         *float64
 	Cap6         *float64
 	RegisterDate time.Time
+	Latitude     *float64 // opcional: localização do indivíduo (WGS84)
+	Longitude    *float64
+	Tag          *string // opcional: plaqueta, identidade da árvore entre campanhas
+	// Situação (types.SpecimenStatus*; vazia = viva), condição fitossanitária e observações de campo
+	Status           string
+	Phytosanitary    *string // opcional: types.Phytosanitary*
+	BifurcationNotes *string
+	Observations     *string
 	// Dados da espécie - buscar pelo nome científico
 	ScientificName string // Nome científico da espécie (obrigatório)
+	// Origem da linha (opcional) - usado em importações de planilha
+	RowNumber   int      // Número da linha na origem; quando 0, usa a posição na lista (1-based)
+	InputErrors []string // Erros de conversão detectados na leitura da origem
 }
 
 type UpdateInput struct {
-	Title           string
-	InitialDate     time.Time
-	PortionQuantity int
-	PortionArea     float64
-	TotalArea       float64
-	Description     *string
+	Title             string
+	InitialDate       time.Time
+	PortionQuantity   int
+	PortionArea       float64
+	TotalArea         float64
+	Description       *string
+	DefaultFormFactor *float64
+	// Vale para as próximas importações; espécimes já gravados não são convertidos
+	Measurement types.MeasurementProtocol
+}
+
+// importRules reúne as regras da análise aplicadas às linhas importadas
+type importRules struct {
+	Measurement types.MeasurementProtocol
+	PlotCodes   map[string]bool // parcelas cadastradas (nil = qualquer código de parcela)
+	UsedTags    map[string]bool // plaquetas já cadastradas na análise
 }
 
 type specimenRow struct {
@@ -75,30 +160,94 @@ type specimenRow struct {
 	Specimen  SpecimenInput
 }
 
-type invalidSpecimenRow struct {
-	RowNumber int      `json:"rowNumber"`
-	Errors    []string `json:"errors"`
-}
-
 func isBlankSpecimenInput(sp SpecimenInput) bool {
 	return strings.TrimSpace(sp.Portion) == "" &&
 		strings.TrimSpace(sp.ScientificName) == "" &&
-		sp.Height == 0 &&
+		sp.Height == nil &&
 		sp.Cap1 == 0 &&
 		sp.RegisterDate.IsZero() &&
 		sp.Cap2 == nil &&
 		sp.Cap3 == nil &&
 		sp.Cap4 == nil &&
 		sp.Cap5 == nil &&
-		sp.Cap6 == nil
+		sp.Cap6 == nil &&
+		sp.Latitude == nil &&
+		sp.Longitude == nil &&
+		(sp.Tag == nil || strings.TrimSpace(*sp.Tag) == "") &&
+		strings.TrimSpace(sp.Status) == "" &&
+		(sp.Phytosanitary == nil || strings.TrimSpace(*sp.Phytosanitary) == "") &&
+		(sp.BifurcationNotes == nil || strings.TrimSpace(*sp.BifurcationNotes) == "") &&
+		(sp.Observations == nil || strings.TrimSpace(*sp.Observations) == "") &&
+		len(sp.InputErrors) == 0
+}
+
+// specimenLocationError valida a localização opcional do indivíduo (as duas coordenadas juntas, em WGS84)
+func specimenLocationError(latitude, longitude *float64) string {
+	if latitude == nil && longitude == nil {
+		return ""
+	}
+	if latitude == nil || longitude == nil {
+		return "latitude and longitude must be informed together"
+	}
+	if *latitude < -90 || *latitude > 90 || *longitude < -180 || *longitude > 180 {
+		return "invalid coordinate"
+	}
+	return ""
+}
+
+func validSpecimenStatus(status string) bool {
+	switch status {
+	case types.SpecimenStatusAlive, types.SpecimenStatusDeadStanding, types.SpecimenStatusFallen, types.SpecimenStatusResprouting:
+		return true
+	}
+	return false
+}
+
+func validPhytosanitary(condition string) bool {
+	switch condition {
+	case types.PhytosanitaryGood, types.PhytosanitaryFair, types.PhytosanitaryPoor:
+		return true
+	}
+	return false
+}
+
+// normalizeSpecimenCondition aplica caixa baixa à situação (vazia = viva) e à condição fitossanitária
+func normalizeSpecimenCondition(status string, phytosanitary *string) (string, *string) {
+	status = strings.ToLower(strings.TrimSpace(status))
+	if status == "" {
+		status = types.SpecimenStatusAlive
+	}
+	if phytosanitary != nil {
+		v := strings.ToLower(strings.TrimSpace(*phytosanitary))
+		if v == "" {
+			return status, nil
+		}
+		phytosanitary = &v
+	}
+	return status, phytosanitary
+}
+
+// specimenRowNumber retorna o número da linha na origem ou a posição (1-based) na lista
+func specimenRowNumber(i int, sp SpecimenInput) int {
+	if sp.RowNumber > 0 {
+		return sp.RowNumber
+	}
+	return i + 1
 }
 
-func normalizeAndValidateSpecimens(specimens []SpecimenInput) ([]specimenRow, []invalidSpecimenRow) {
+// normalizeAndValidateSpecimens valida os campos de cada linha e converte os fustes do protocolo
+// de medição para CAP em cm. Fustes abaixo do critério de inclusão invalidam a linha ou, com a
+// política "flag", geram avisos. Com parcelas cadastradas, a parcela da linha deve existir.
+// As plaquetas não podem se repetir na planilha nem na análise.
+func normalizeAndValidateSpecimens(specimens []SpecimenInput, rules importRules) ([]specimenRow, []types.InvalidSpecimenRow, []types.FlaggedSpecimenRow) {
 	rows := make([]specimenRow, 0, len(specimens))
-	invalidRows := make([]invalidSpecimenRow, 0)
+	invalidRows := make([]types.InvalidSpecimenRow, 0)
+	flaggedRows := make([]types.FlaggedSpecimenRow, 0)
+	protocol := normalizeMeasurementProtocol(rules.Measurement)
+	tags := make(map[string]bool)
 
 	for i, sp := range specimens {
-		rowNumber := i + 1
+		rowNumber := specimenRowNumber(i, sp)
 		if isBlankSpecimenInput(sp) {
 			continue
 		}
@@ -106,12 +255,21 @@ func normalizeAndValidateSpecimens(specimens []SpecimenInput) ([]specimenRow, []
 		normalized := sp
 		normalized.Portion = strings.TrimSpace(sp.Portion)
 		normalized.ScientificName = strings.TrimSpace(sp.ScientificName)
+		normalized.Tag = nil
+		if sp.Tag != nil && strings.TrimSpace(*sp.Tag) != "" {
+			tag := strings.TrimSpace(*sp.Tag)
+			normalized.Tag = &tag
+		}
+		normalized.Status, normalized.Phytosanitary = normalizeSpecimenCondition(sp.Status, sp.Phytosanitary)
 
-		errorsByRow := make([]string, 0, 5)
+		errorsByRow := make([]string, 0, 5+len(sp.InputErrors))
+		errorsByRow = append(errorsByRow, sp.InputErrors...)
 		if normalized.Portion == "" {
 			errorsByRow = append(errorsByRow, "portion is required")
+		} else if rules.PlotCodes != nil && !rules.PlotCodes[normalized.Portion] {
+			errorsByRow = append(errorsByRow, "plot not found")
 		}
-		if normalized.Height <= 0 {
+		if normalized.Height != nil && *normalized.Height <= 0 {
 			errorsByRow = append(errorsByRow, "height must be positive")
 		}
 		if normalized.Cap1 <= 0 {
@@ -123,19 +281,420 @@ func normalizeAndValidateSpecimens(specimens []SpecimenInput) ([]specimenRow, []
 		if normalized.ScientificName == "" {
 			errorsByRow = append(errorsByRow, "scientific name is required")
 		}
+		if msg := specimenLocationError(normalized.Latitude, normalized.Longitude); msg != "" {
+			errorsByRow = append(errorsByRow, msg)
+		}
+		if !validSpecimenStatus(normalized.Status) {
+			errorsByRow = append(errorsByRow, "status must be alive, dead_standing, fallen or resprouting")
+		}
+		if normalized.Phytosanitary != nil && !validPhytosanitary(*normalized.Phytosanitary) {
+			errorsByRow = append(errorsByRow, "phytosanitary condition must be good, fair or poor")
+		}
+		if normalized.Tag != nil {
+			switch tag := *normalized.Tag; {
+			case rules.UsedTags[tag]:
+				errorsByRow = append(errorsByRow, "tag already used in the analysis")
+			case tags[tag]:
+				errorsByRow = append(errorsByRow, "duplicated tag "+tag)
+			}
+			tags[*normalized.Tag] = true
+		}
+
+		below := applyMeasurementProtocol(&normalized, protocol)
+		if protocol.InclusionPolicy == types.InclusionReject {
+			errorsByRow = append(errorsByRow, below...)
+		}
 
 		if len(errorsByRow) > 0 {
-			invalidRows = append(invalidRows, invalidSpecimenRow{
+			invalidRows = append(invalidRows, types.InvalidSpecimenRow{
 				RowNumber: rowNumber,
 				Errors:    errorsByRow,
 			})
 			continue
 		}
 
+		if len(below) > 0 {
+			flaggedRows = append(flaggedRows, types.FlaggedSpecimenRow{RowNumber: rowNumber, Warnings: below})
+		}
 		rows = append(rows, specimenRow{RowNumber: rowNumber, Specimen: normalized})
 	}
 
-	return rows, invalidRows
+	return rows, invalidRows, flaggedRows
+}
+
+func invalidRowsError(invalidRows []types.InvalidSpecimenRow) error {
+	return apperr.WithFields(
+		apperr.New(apperr.CodeInvalid, "invalid specimen rows"),
+		map[string]any{"invalidRows": invalidRows},
+	)
+}
+
+// preparedSpecimens é o resultado do pipeline de validação de uma importação
+type preparedSpecimens struct {
+	TotalRows    int
+	Specimens    []*domainspecimen.Specimen
+	SpeciesNames map[string]string  // specieID -> nome científico do catálogo
+	FormFactors  map[string]float64 // specieID -> fator de forma (carregado apenas para a prévia)
+	// Morfoespécies novas, cadastradas junto com os espécimes
+	Morphospecies      []*domainmorphospecies.Morphospecies
+	MorphospeciesNames map[string]string // morphospeciesID -> nome
+	InvalidRows        []types.InvalidSpecimenRow
+	FlaggedRows        []types.FlaggedSpecimenRow // linhas válidas com fustes abaixo do critério de inclusão
+}
+
+// prepareSpecimens executa todo o pipeline de importação (linhas em branco, campos,
+// busca das espécies e morfoespécies e validação da entidade) acumulando os erros de todas
+// as etapas por linha, para que o relatório traga todos os problemas de uma vez.
+func prepareSpecimens(ctx context.Context, repos postgres.Repos, phytoID string, rules importRules, specimens []SpecimenInput, speciesMapping map[string]string, declaredMorphospecies []MorphospeciesInput) (*preparedSpecimens, error) {
+	rows, invalidRows, flaggedRows := normalizeAndValidateSpecimens(specimens, rules)
+
+	errorsByRow := make(map[int][]string, len(invalidRows))
+	for _, ir := range invalidRows {
+		errorsByRow[ir.RowNumber] = append(errorsByRow[ir.RowNumber], ir.Errors...)
+	}
+
+	// Batch: coletar nomes científicos únicos (trim) de todas as linhas preenchidas,
+	// inclusive as que já falharam na checagem de campos, e buscar todos de uma vez
+	totalRows := 0
+	namesByRow := make(map[int]string, len(specimens))
+	uniqueNames := make([]string, 0, len(specimens))
+	seen := make(map[string]bool, len(specimens))
+	for i, sp := range specimens {
+		if isBlankSpecimenInput(sp) {
+			continue
+		}
+		totalRows++
+
+		name := strings.TrimSpace(sp.ScientificName)
+		if name == "" {
+			continue
+		}
+		namesByRow[specimenRowNumber(i, sp)] = name
+		if !seen[name] {
+			seen[name] = true
+			uniqueNames = append(uniqueNames, name)
+		}
+	}
+
+	resolved, unresolved, err := resolveSpecies(ctx, repos, uniqueNames, speciesMapping)
+	if err != nil {
+		return nil, err
+	}
+
+	// Nomes fora do catálogo: morfoespécies da análise (declaradas ou reconhecidas pelo nome)
+	unresolvedNames := make([]string, 0, len(unresolved))
+	for _, name := range uniqueNames {
+		if _, ok := unresolved[name]; ok {
+			unresolvedNames = append(unresolvedNames, name)
+		}
+	}
+	morphospecies, newMorphospecies, err := resolveMorphospecies(ctx, repos, phytoID, unresolvedNames, declaredMorphospecies)
+	if err != nil {
+		return nil, err
+	}
+	for name := range morphospecies {
+		delete(unresolved, name)
+	}
+
+	suggestionsByRow := make(map[int][]types.SpeciesMatch)
+	addSpeciesError := func(rowNumber int, name string) {
+		u := unresolved[name]
+		errorsByRow[rowNumber] = append(errorsByRow[rowNumber], u.Error)
+		if len(u.Suggestions) > 0 {
+			suggestionsByRow[rowNumber] = u.Suggestions
+		}
+	}
+
+	for _, ir := range invalidRows {
+		name, ok := namesByRow[ir.RowNumber]
+		if !ok {
+			continue
+		}
+		if _, found := unresolved[name]; found {
+			addSpeciesError(ir.RowNumber, name)
+		}
+	}
+
+	// Batch: construir todas as entidades de specimen para inserir de uma vez
+	domainSpecimens := make([]*domainspecimen.Specimen, 0, len(rows))
+	speciesNames := make(map[string]string, len(resolved))
+	morphospeciesNames := make(map[string]string, len(morphospecies))
+	for _, row := range rows {
+		sp := row.Specimen
+		if _, found := unresolved[sp.ScientificName]; found {
+			addSpeciesError(row.RowNumber, sp.ScientificName)
+			continue
+		}
+		species := resolved[sp.ScientificName] // vazio para morfoespécies
+		specieID := species.ID
+
+		s := domainspecimen.NewSpecimen(
+			uuid.NewString(),
+			sp.Portion,
+			sp.Height,
+			sp.Cap1,
+			sp.RegisterDate,
+			phytoID,
+			specieID,
+		)
+		s.SetOptionalCaps(sp.Cap2, sp.Cap3, sp.Cap4, sp.Cap5, sp.Cap6)
+		s.SetLocation(sp.Latitude, sp.Longitude)
+		s.SetTag(sp.Tag)
+		s.SetCondition(sp.Status, sp.Phytosanitary, sp.BifurcationNotes, sp.Observations)
+		if m, ok := morphospecies[sp.ScientificName]; ok {
+			s.SetMorphospecies(&m.ID)
+		}
+
+		if err := s.Validate(); err != nil {
+			errorsByRow[row.RowNumber] = append(errorsByRow[row.RowNumber], err.Error())
+			continue
+		}
+
+		if s.MorphospeciesID != nil {
+			morphospeciesNames[*s.MorphospeciesID] = morphospecies[sp.ScientificName].ScientificName
+		} else {
+			speciesNames[specieID] = species.ScientificName
+		}
+		domainSpecimens = append(domainSpecimens, s)
+	}
+
+	rowNumbers := make([]int, 0, len(errorsByRow))
+	for rowNumber := range errorsByRow {
+		rowNumbers = append(rowNumbers, rowNumber)
+	}
+	sort.Ints(rowNumbers)
+
+	allInvalidRows := make([]types.InvalidSpecimenRow, 0, len(rowNumbers))
+	for _, rowNumber := range rowNumbers {
+		allInvalidRows = append(allInvalidRows, types.InvalidSpecimenRow{
+			RowNumber:   rowNumber,
+			Errors:      errorsByRow[rowNumber],
+			Suggestions: suggestionsByRow[rowNumber],
+		})
+	}
+
+	validFlaggedRows := make([]types.FlaggedSpecimenRow, 0, len(flaggedRows))
+	for _, fr := range flaggedRows {
+		if _, invalid := errorsByRow[fr.RowNumber]; !invalid {
+			validFlaggedRows = append(validFlaggedRows, fr)
+		}
+	}
+
+	// Apenas as morfoespécies novas usadas por alguma linha válida são cadastradas
+	usedMorphospecies := make([]*domainmorphospecies.Morphospecies, 0, len(newMorphospecies))
+	for _, m := range newMorphospecies {
+		if _, used := morphospeciesNames[m.ID]; used {
+			usedMorphospecies = append(usedMorphospecies, m)
+		}
+	}
+
+	return &preparedSpecimens{
+		TotalRows:          totalRows,
+		Specimens:          domainSpecimens,
+		SpeciesNames:       speciesNames,
+		Morphospecies:      usedMorphospecies,
+		MorphospeciesNames: morphospeciesNames,
+		InvalidRows:        allInvalidRows,
+		FlaggedRows:        validFlaggedRows,
+	}, nil
+}
+
+// maxSpeciesSuggestions limita as sugestões de espécies por linha inválida
+const maxSpeciesSuggestions = 5
+
+type resolvedSpecies struct {
+	ID             string
+	ScientificName string // nome do catálogo
+}
+
+type unresolvedSpecies struct {
+	Error       string
+	Suggestions []types.SpeciesMatch
+}
+
+// resolveSpecies resolve os nomes científicos para espécies do catálogo, nesta ordem:
+// mapeamento enviado pelo cliente (nome -> speciesID), nome exato e nome normalizado
+// (caixa, acentos e autoria) quando há um único candidato. Os demais nomes retornam
+// como não resolvidos, com sugestões ordenadas por similaridade.
+func resolveSpecies(ctx context.Context, repos postgres.Repos, names []string, speciesMapping map[string]string) (map[string]resolvedSpecies, map[string]unresolvedSpecies, error) {
+	resolved := make(map[string]resolvedSpecies, len(names))
+	unresolved := make(map[string]unresolvedSpecies)
+	if len(names) == 0 {
+		return resolved, unresolved, nil
+	}
+
+	mapping := make(map[string]string, len(speciesMapping))
+	for name, id := range speciesMapping {
+		if name = strings.TrimSpace(name); name != "" && strings.TrimSpace(id) != "" {
+			mapping[name] = strings.TrimSpace(id)
+		}
+	}
+
+	mappedIDs := make([]string, 0, len(mapping))
+	for _, name := range names {
+		if id, ok := mapping[name]; ok {
+			mappedIDs = append(mappedIDs, id)
+		}
+	}
+	if len(mappedIDs) > 0 {
+		namesByID, err := repos.Species().GetNamesByIDs(ctx, mappedIDs)
+		if err != nil {
+			return nil, nil, apperr.Wrap(err, apperr.CodeInternal, "failed to fetch species")
+		}
+		for _, name := range names {
+			id, ok := mapping[name]
+			if !ok {
+				continue
+			}
+			if scientificName, found := namesByID[id]; found {
+				resolved[name] = resolvedSpecies{ID: id, ScientificName: scientificName}
+			} else {
+				unresolved[name] = unresolvedSpecies{Error: "species mapping references unknown species ID: " + id}
+			}
+		}
+	}
+
+	pending := make([]string, 0, len(names))
+	for _, name := range names {
+		if _, ok := mapping[name]; !ok {
+			pending = append(pending, name)
+		}
+	}
+	if len(pending) == 0 {
+		return resolved, unresolved, nil
+	}
+
+	exact, err := repos.Species().GetMapByScientificNames(ctx, pending)
+	if err != nil {
+		return nil, nil, apperr.Wrap(err, apperr.CodeInternal, "failed to fetch species")
+	}
+
+	notFound := make([]string, 0)
+	for _, name := range pending {
+		if id, ok := exact[name]; ok {
+			resolved[name] = resolvedSpecies{ID: id, ScientificName: name}
+			continue
+		}
+		notFound = append(notFound, name)
+	}
+	if len(notFound) == 0 {
+		return resolved, unresolved, nil
+	}
+
+	matches, err := repos.Species().MatchScientificNames(ctx, notFound, maxSpeciesSuggestions)
+	if err != nil {
+		return nil, nil, apperr.Wrap(err, apperr.CodeInternal, "failed to match species")
+	}
+
+	for _, name := range notFound {
+		candidates := matches[name]
+		exactMatches := 0
+		for _, c := range candidates {
+			if c.Exact {
+				exactMatches++
+			}
+		}
+
+		switch {
+		case exactMatches == 1:
+			resolved[name] = resolvedSpecies{ID: candidates[0].SpeciesID, ScientificName: candidates[0].ScientificName}
+		case exactMatches > 1:
+			unresolved[name] = unresolvedSpecies{Error: "species name is ambiguous: " + name, Suggestions: candidates}
+		default:
+			unresolved[name] = unresolvedSpecies{Error: "species not found with scientific name: " + name, Suggestions: candidates}
+		}
+	}
+
+	return resolved, unresolved, nil
+}
+
+// toSpecimensWithSpecies converte os espécimes preparados para o formato usado no cálculo dos indicadores
+func (p *preparedSpecimens) toSpecimensWithSpecies() []*types.SpecimenWithSpecies {
+	out := make([]*types.SpecimenWithSpecies, 0, len(p.Specimens))
+	for _, s := range p.Specimens {
+		sw := &types.SpecimenWithSpecies{
+			ID:               s.ID,
+			Portion:          s.Portion,
+			Cap1:             s.Cap1,
+			Cap2:             s.Cap2,
+			Cap3:             s.Cap3,
+			Cap4:             s.Cap4,
+			Cap5:             s.Cap5,
+			Cap6:             s.Cap6,
+			RegisterDate:     s.RegisterDate,
+			PhytoAnalysisID:  s.PhytoAnalysisID,
+			SpecieID:         s.SpecieID,
+			MorphospeciesID:  s.MorphospeciesID,
+			CreatedAt:        s.CreatedAt,
+			UpdatedAt:        s.UpdatedAt,
+			Latitude:         s.Latitude,
+			Longitude:        s.Longitude,
+			Tag:              s.Tag,
+			Status:           s.Status,
+			Phytosanitary:    s.Phytosanitary,
+			BifurcationNotes: s.BifurcationNotes,
+			Observations:     s.Observations,
+			ScientificName:   p.SpeciesNames[s.SpecieID],
+		}
+		if s.MorphospeciesID != nil {
+			sw.ScientificName = p.MorphospeciesNames[*s.MorphospeciesID]
+		}
+		if s.Height != nil {
+			sw.Height = *s.Height
+		}
+		if ff, ok := p.FormFactors[s.SpecieID]; ok {
+			sw.FormFactor = &ff
+		}
+		out = append(out, sw)
+	}
+	return out
+}
+
+// loadFormFactors busca o fator de forma das espécies válidas, usado no volume da prévia
+func (p *preparedSpecimens) loadFormFactors(ctx context.Context, repos postgres.Repos) error {
+	if len(p.SpeciesNames) == 0 {
+		return nil
+	}
+
+	speciesIDs := make([]string, 0, len(p.SpeciesNames))
+	for id := range p.SpeciesNames {
+		speciesIDs = append(speciesIDs, id)
+	}
+
+	formFactors, err := repos.Species().GetFormFactorsBySpeciesIDs(ctx, speciesIDs)
+	if err != nil {
+		return apperr.Wrap(err, apperr.CodeInternal, "failed to fetch species form factors")
+	}
+	p.FormFactors = formFactors
+	return nil
+}
+
+// validateCreateInput lista todos os problemas nos dados da análise (usado no dry-run)
+func validateCreateInput(in CreateInput) []string {
+	errs := make([]string, 0)
+	if strings.TrimSpace(in.Title) == "" {
+		errs = append(errs, "title is required")
+	}
+	if strings.TrimSpace(in.ProjectID) == "" {
+		errs = append(errs, "project ID is required")
+	}
+	if in.InitialDate.IsZero() {
+		errs = append(errs, "initial date is required")
+	}
+	if in.PortionQuantity <= 0 {
+		errs = append(errs, "portion quantity must be positive")
+	}
+	if in.PortionArea <= 0 {
+		errs = append(errs, "portion area must be positive")
+	}
+	if in.TotalArea <= 0 {
+		errs = append(errs, "total area must be positive")
+	}
+	if in.DefaultFormFactor != nil && *in.DefaultFormFactor <= 0 {
+		errs = append(errs, "default form factor must be positive")
+	}
+	errs = append(errs, validateMeasurementProtocol(normalizeMeasurementProtocol(in.Measurement))...)
+	return errs
 }
 
 func calcSampledAreaHa(portionArea float64, portionQuantity int) float64 {
@@ -157,18 +716,32 @@ func (s *Service) Create(ctx context.Context, in CreateInput) (string, error) {
 		return "", apperr.New(apperr.CodeInvalid, "transaction manager required")
 	}
 
+	plots, plotErrs := buildCreatePlots(phytoID, &in)
+	if len(plotErrs) > 0 {
+		return "", apperr.WithFields(
+			apperr.New(apperr.CodeInvalid, "invalid plots"),
+			map[string]any{"errors": plotErrs},
+		)
+	}
+
 	err := s.txm.RunInTx(ctx, func(repos postgres.Repos) error {
 		sampledAreaHa := calcSampledAreaHa(in.PortionArea, in.PortionQuantity)
 		if sampledAreaHa <= 0 {
 			return apperr.New(apperr.CodeInvalid, "sampled area must be positive")
 		}
 
-		rows, invalidRows := normalizeAndValidateSpecimens(in.Specimens)
-		if len(invalidRows) > 0 {
-			return apperr.WithFields(
-				apperr.New(apperr.CodeInvalid, "invalid specimen rows"),
-				map[string]any{"invalidRows": invalidRows},
-			)
+		measurement := normalizeMeasurementProtocol(in.Measurement)
+		if errs := validateMeasurementProtocol(measurement); len(errs) > 0 {
+			return apperr.New(apperr.CodeInvalid, errs[0])
+		}
+
+		rules := importRules{Measurement: measurement, PlotCodes: plotCodes(toPlotsData(plots))}
+		prepared, err := prepareSpecimens(ctx, repos, phytoID, rules, in.Specimens, in.SpeciesMapping, in.Morphospecies)
+		if err != nil {
+			return err
+		}
+		if len(prepared.InvalidRows) > 0 {
+			return invalidRowsError(prepared.InvalidRows)
 		}
 
 		phyto := domainphyto.NewPhytoAnalysis(
@@ -185,6 +758,8 @@ func (s *Service) Create(ctx context.Context, in CreateInput) (string, error) {
 		if in.Description != nil {
 			phyto.SetDescription(in.Description)
 		}
+		phyto.SetDefaultFormFactor(in.DefaultFormFactor)
+		phyto.SetMeasurementProtocol(measurement.Mode, measurement.Unit, measurement.InclusionMin, measurement.InclusionPolicy, measurement.ExcludeDead)
 
 		if err := phyto.Validate(); err != nil {
 			return apperr.Wrap(err, apperr.CodeInvalid, "invalid phyto analysis data")
@@ -194,81 +769,24 @@ func (s *Service) Create(ctx context.Context, in CreateInput) (string, error) {
 			return err
 		}
 
-		if len(rows) == 0 {
-			return nil
-		}
-
-		// Batch: coletar nomes científicos únicos (trim) e buscar todos de uma vez
-		uniqueNames := make([]string, 0, len(rows))
-		seen := make(map[string]bool, len(rows))
-		for _, row := range rows {
-			name := row.Specimen.ScientificName
-			if !seen[name] {
-				seen[name] = true
-				uniqueNames = append(uniqueNames, name)
+		for _, pl := range plots {
+			if err := repos.Plots().Create(ctx, pl); err != nil {
+				return err
 			}
 		}
 
-		speciesMap, err := repos.Species().GetMapByScientificNames(ctx, uniqueNames)
-		if err != nil {
-			return apperr.Wrap(err, apperr.CodeInternal, "failed to fetch species")
-		}
-
-		missingSpeciesRows := make([]invalidSpecimenRow, 0)
-		for _, row := range rows {
-			name := row.Specimen.ScientificName
-			if _, ok := speciesMap[name]; !ok {
-				missingSpeciesRows = append(missingSpeciesRows, invalidSpecimenRow{
-					RowNumber: row.RowNumber,
-					Errors:    []string{"species not found with scientific name: " + name},
-				})
-			}
+		if err := createMorphospecies(ctx, repos, prepared.Morphospecies); err != nil {
+			return err
 		}
 
-		if len(missingSpeciesRows) > 0 {
-			return apperr.WithFields(
-				apperr.New(apperr.CodeInvalid, "invalid specimen rows"),
-				map[string]any{"invalidRows": missingSpeciesRows},
-			)
-		}
-
-		// Batch: construir todas as entidades de specimen e inserir de uma vez
-		domainSpecimens := make([]*domainspecimen.Specimen, 0, len(rows))
-		for _, row := range rows {
-			sp := row.Specimen
-			specieID := speciesMap[sp.ScientificName]
-
-			s := domainspecimen.NewSpecimen(
-				uuid.NewString(),
-				sp.Portion,
-				sp.Height,
-				sp.Cap1,
-				sp.RegisterDate,
-				phytoID,
-				specieID,
-			)
-			s.SetOptionalCaps(sp.Cap2, sp.Cap3, sp.Cap4, sp.Cap5, sp.Cap6)
-
-			if err := s.Validate(); err != nil {
-				return apperr.WithFields(
-					apperr.New(apperr.CodeInvalid, "invalid specimen rows"),
-					map[string]any{
-						"invalidRows": []invalidSpecimenRow{{
-							RowNumber: row.RowNumber,
-							Errors:    []string{err.Error()},
-						}},
-					},
-				)
+		if len(prepared.Specimens) > 0 {
+			if err := repos.Specimens().CreateBatch(ctx, prepared.Specimens); err != nil {
+				return apperr.Wrap(err, apperr.CodeInvalid, "failed to create specimens")
 			}
-
-			domainSpecimens = append(domainSpecimens, s)
-		}
-
-		if err := repos.Specimens().CreateBatch(ctx, domainSpecimens); err != nil {
-			return apperr.Wrap(err, apperr.CodeInvalid, "failed to create specimens")
 		}
 
-		return nil
+		_, err = snapshotIndicators(ctx, repos.PhytoAnalyses(), phytoID, phytometrics.ReasonCreated)
+		return err
 	})
 
 	if err != nil {
@@ -286,14 +804,331 @@ func (s *Service) GetByID(ctx context.Context, id string) (*types.PhytoAnalysisW
 	return phyto, nil
 }
 
+// GetWithSpecimens retorna a análise com todos os espécimes, inclusive os mortos; alturas não
+// medidas vêm estimadas pela relação hipsométrica (HeightEstimated)
 func (s *Service) GetWithSpecimens(ctx context.Context, id string) (*types.PhytoAnalysisComplete, error) {
 	phyto, err := s.repo.GetWithSpecimens(ctx, id)
 	if err != nil {
 		return nil, apperr.Wrap(err, apperr.CodeNotFound, "phyto analysis not found")
 	}
+	phytometrics.EstimateHeights(phyto)
 	return phyto, nil
 }
 
+// GetForIndicators retorna a análise com os espécimes usados nos indicadores (sem os mortos quando
+// o protocolo os exclui)
+func (s *Service) GetForIndicators(ctx context.Context, id string) (*types.PhytoAnalysisComplete, error) {
+	phyto, err := s.GetWithSpecimens(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return phytometrics.WithoutDead(phyto), nil
+}
+
+// GetComplete retorna a análise com projeto e endereço, sem carregar os espécimes
+func (s *Service) GetComplete(ctx context.Context, id string) (*types.PhytoAnalysisComplete, error) {
+	phyto, err := s.repo.GetComplete(ctx, id)
+	if err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeNotFound, "phyto analysis not found")
+	}
+	return phyto, nil
+}
+
+// Tamanho da página da listagem de espécimes
+const (
+	DefaultSpecimenPageSize = 100
+	MaxSpecimenPageSize     = 1000
+)
+
+func validateSpecimenFilter(f types.SpecimenFilter) error {
+	switch f.SortBy {
+	case "", types.SpecimenSortPortion, types.SpecimenSortScientificName, types.SpecimenSortFamily,
+		types.SpecimenSortDbh, types.SpecimenSortMeasuredHeight, types.SpecimenSortRegisterDate:
+	default:
+		return apperr.New(apperr.CodeInvalid, "invalid sort field")
+	}
+	if f.Status != "" && !validSpecimenStatus(f.Status) {
+		return apperr.New(apperr.CodeInvalid, "invalid status")
+	}
+	if f.Phytosanitary != "" && !validPhytosanitary(f.Phytosanitary) {
+		return apperr.New(apperr.CodeInvalid, "invalid phytosanitary condition")
+	}
+	if f.MinDbhCm != nil && f.MaxDbhCm != nil && *f.MinDbhCm > *f.MaxDbhCm {
+		return apperr.New(apperr.CodeInvalid, "invalid dbh range")
+	}
+	if f.MinMeasuredHeight != nil && f.MaxMeasuredHeight != nil && *f.MinMeasuredHeight > *f.MaxMeasuredHeight {
+		return apperr.New(apperr.CodeInvalid, "invalid height range")
+	}
+	return nil
+}
+
+// ListSpecimens lista uma página dos espécimes da análise com filtros e ordenação
+func (s *Service) ListSpecimens(ctx context.Context, id string, f types.SpecimenFilter, limit int32, after *types.SpecimenCursorKey) ([]*types.SpecimenWithSpecies, *types.SpecimenPageInfo, error) {
+	if err := validateSpecimenFilter(f); err != nil {
+		return nil, nil, err
+	}
+	if limit <= 0 {
+		limit = DefaultSpecimenPageSize
+	}
+	if limit > MaxSpecimenPageSize {
+		limit = MaxSpecimenPageSize
+	}
+
+	if _, err := s.GetByID(ctx, id); err != nil {
+		return nil, nil, err
+	}
+
+	specimens, pageInfo, err := s.repo.ListSpecimens(ctx, id, f, limit, after)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	estimate := s.heightEstimator(ctx, id)
+	for _, sp := range specimens {
+		if err := estimate(sp); err != nil {
+			return nil, nil, err
+		}
+	}
+	return specimens, &pageInfo, nil
+}
+
+// StreamSpecimens percorre todos os espécimes filtrados da análise, um a um
+func (s *Service) StreamSpecimens(ctx context.Context, id string, f types.SpecimenFilter, fn func(*types.SpecimenWithSpecies) error) error {
+	if err := validateSpecimenFilter(f); err != nil {
+		return err
+	}
+
+	if _, err := s.GetByID(ctx, id); err != nil {
+		return err
+	}
+
+	estimate := s.heightEstimator(ctx, id)
+	return s.repo.StreamSpecimens(ctx, id, f, func(sp *types.SpecimenWithSpecies) error {
+		if err := estimate(sp); err != nil {
+			return err
+		}
+		return fn(sp)
+	})
+}
+
+// Limites de análises comparadas em uma mesma consulta
+const (
+	minComparedAnalyses = 2
+	maxComparedAnalyses = 20
+)
+
+// ListWithSpecimens carrega as análises completas informadas (espécimes dos indicadores), restritas à empresa.
+// Análises de outra empresa são tratadas como inexistentes.
+func (s *Service) ListWithSpecimens(ctx context.Context, enterpriseID string, ids []string) ([]*types.PhytoAnalysisComplete, error) {
+	unique := make([]string, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		unique = append(unique, id)
+	}
+
+	if len(unique) < minComparedAnalyses || len(unique) > maxComparedAnalyses {
+		return nil, apperr.WithFields(
+			apperr.New(apperr.CodeInvalid, "invalid number of phyto analyses"),
+			map[string]any{"min": minComparedAnalyses, "max": maxComparedAnalyses},
+		)
+	}
+
+	owned, err := s.repo.FilterIDsByEnterprise(ctx, enterpriseID, unique)
+	if err != nil {
+		return nil, err
+	}
+	ownedSet := make(map[string]bool, len(owned))
+	for _, id := range owned {
+		ownedSet[id] = true
+	}
+	for _, id := range unique {
+		if !ownedSet[id] {
+			return nil, apperr.New(apperr.CodeNotFound, "phyto analysis not found: "+id)
+		}
+	}
+
+	out := make([]*types.PhytoAnalysisComplete, 0, len(unique))
+	for _, id := range unique {
+		phyto, err := s.GetForIndicators(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, phyto)
+	}
+	return out, nil
+}
+
+// AddSpecimens importa espécimes em uma análise existente.
+// Retorna a quantidade de espécimes criados.
+func (s *Service) AddSpecimens(ctx context.Context, id string, in AddSpecimensInput) (int, error) {
+	if strings.TrimSpace(id) == "" {
+		return 0, apperr.New(apperr.CodeInvalid, "missing required fields")
+	}
+
+	if s.txm == nil {
+		return 0, apperr.New(apperr.CodeInvalid, "transaction manager required")
+	}
+
+	rows, invalidRows, _ := normalizeAndValidateSpecimens(in.Specimens, importRules{})
+	if len(rows) == 0 && len(invalidRows) == 0 {
+		return 0, apperr.New(apperr.CodeInvalid, "no specimens to import")
+	}
+
+	created := 0
+	err := s.txm.RunInTx(ctx, func(repos postgres.Repos) error {
+		phyto, err := repos.PhytoAnalyses().GetComplete(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		usedTags, err := repos.Specimens().ListTagsByPhytoAnalysis(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		rules := importRules{Measurement: phyto.Measurement, PlotCodes: plotCodes(phyto.Plots), UsedTags: tagSet(usedTags)}
+		prepared, err := prepareSpecimens(ctx, repos, id, rules, in.Specimens, in.SpeciesMapping, in.Morphospecies)
+		if err != nil {
+			return err
+		}
+		if len(prepared.InvalidRows) > 0 {
+			return invalidRowsError(prepared.InvalidRows)
+		}
+
+		if err := createMorphospecies(ctx, repos, prepared.Morphospecies); err != nil {
+			return err
+		}
+
+		if err := repos.Specimens().CreateBatch(ctx, prepared.Specimens); err != nil {
+			return apperr.Wrap(err, apperr.CodeInvalid, "failed to create specimens")
+		}
+
+		if _, err := snapshotIndicators(ctx, repos.PhytoAnalyses(), id, phytometrics.ReasonSpecimensImported); err != nil {
+			return err
+		}
+
+		created = len(prepared.Specimens)
+		return nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return created, nil
+}
+
+// ValidateCreate executa o pipeline completo de criação sem persistir nada (dry-run)
+// e retorna todas as linhas inválidas e a prévia da análise com os espécimes válidos.
+func (s *Service) ValidateCreate(ctx context.Context, in CreateInput) (*types.SpecimenImportReport, error) {
+	if s.txm == nil {
+		return nil, apperr.New(apperr.CodeInvalid, "transaction manager required")
+	}
+
+	plots, plotErrs := buildCreatePlots("", &in)
+	report := &types.SpecimenImportReport{
+		AnalysisErrors: append(validateCreateInput(in), plotErrs...),
+	}
+
+	err := s.txm.RunInTx(ctx, func(repos postgres.Repos) error {
+		measurement := normalizeMeasurementProtocol(in.Measurement)
+		plotsData := toPlotsData(plots)
+		rules := importRules{Measurement: measurement, PlotCodes: plotCodes(plotsData)}
+		prepared, err := prepareSpecimens(ctx, repos, "", rules, in.Specimens, in.SpeciesMapping, in.Morphospecies)
+		if err != nil {
+			return err
+		}
+		if err := prepared.loadFormFactors(ctx, repos); err != nil {
+			return err
+		}
+
+		fillImportReport(report, prepared, &types.PhytoAnalysisComplete{
+			Title:             in.Title,
+			InitialDate:       in.InitialDate,
+			PortionQuantity:   in.PortionQuantity,
+			PortionArea:       in.PortionArea,
+			TotalArea:         in.TotalArea,
+			SampledArea:       calcSampledAreaHa(in.PortionArea, in.PortionQuantity),
+			Description:       in.Description,
+			ProjectID:         in.ProjectID,
+			DefaultFormFactor: in.DefaultFormFactor,
+			Measurement:       measurement,
+			Plots:             plotsData,
+		})
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// ValidateSpecimens executa o pipeline de importação em uma análise existente sem persistir nada (dry-run).
+// A prévia considera os espécimes já cadastrados somados às linhas válidas.
+func (s *Service) ValidateSpecimens(ctx context.Context, id string, in AddSpecimensInput) (*types.SpecimenImportReport, error) {
+	if strings.TrimSpace(id) == "" {
+		return nil, apperr.New(apperr.CodeInvalid, "missing required fields")
+	}
+
+	if s.txm == nil {
+		return nil, apperr.New(apperr.CodeInvalid, "transaction manager required")
+	}
+
+	report := &types.SpecimenImportReport{}
+
+	err := s.txm.RunInTx(ctx, func(repos postgres.Repos) error {
+		existing, err := repos.PhytoAnalyses().GetWithSpecimens(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		rules := importRules{
+			Measurement: existing.Measurement,
+			PlotCodes:   plotCodes(existing.Plots),
+			UsedTags:    specimenTags(existing.Specimens),
+		}
+		prepared, err := prepareSpecimens(ctx, repos, id, rules, in.Specimens, in.SpeciesMapping, in.Morphospecies)
+		if err != nil {
+			return err
+		}
+		if err := prepared.loadFormFactors(ctx, repos); err != nil {
+			return err
+		}
+
+		fillImportReport(report, prepared, existing)
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+func fillImportReport(report *types.SpecimenImportReport, prepared *preparedSpecimens, preview *types.PhytoAnalysisComplete) {
+	report.TotalRows = prepared.TotalRows
+	report.ValidRows = len(prepared.Specimens)
+	report.InvalidRows = prepared.InvalidRows
+	report.FlaggedRows = prepared.FlaggedRows
+	report.NewMorphospecies = make([]string, 0, len(prepared.Morphospecies))
+	for _, m := range prepared.Morphospecies {
+		report.NewMorphospecies = append(report.NewMorphospecies, m.Name)
+	}
+
+	preview.Specimens = append(preview.Specimens, prepared.toSpecimensWithSpecies()...)
+	phytometrics.ExcludeDead(preview)
+	phytometrics.EstimateHeights(preview)
+	report.Preview = preview
+}
+
 func (s *Service) ListByProject(ctx context.Context, projectID string) ([]*types.PhytoAnalysisWithProject, error) {
 	return s.repo.ListByProject(ctx, projectID)
 }
@@ -326,6 +1161,13 @@ func (s *Service) Update(ctx context.Context, id string, in UpdateInput) error {
 	if in.TotalArea <= 0 {
 		return apperr.New(apperr.CodeInvalid, "total area must be positive")
 	}
+	if in.DefaultFormFactor != nil && *in.DefaultFormFactor <= 0 {
+		return apperr.New(apperr.CodeInvalid, "default form factor must be positive")
+	}
+	measurement := normalizeMeasurementProtocol(in.Measurement)
+	if errs := validateMeasurementProtocol(measurement); len(errs) > 0 {
+		return apperr.New(apperr.CodeInvalid, errs[0])
+	}
 
 	sampledAreaHa := calcSampledAreaHa(in.PortionArea, in.PortionQuantity)
 	if sampledAreaHa <= 0 {
@@ -333,18 +1175,49 @@ func (s *Service) Update(ctx context.Context, id string, in UpdateInput) error {
 	}
 
 	phyto := &domainphyto.PhytoAnalysis{
-		ID:              id,
-		Title:           in.Title,
-		InitialDate:     in.InitialDate,
-		PortionQuantity: in.PortionQuantity,
-		PortionArea:     in.PortionArea,
-		TotalArea:       in.TotalArea,
-		SampledArea:     sampledAreaHa,
-		Description:     in.Description,
-		UpdatedAt:       time.Now(),
+		ID:                id,
+		Title:             in.Title,
+		InitialDate:       in.InitialDate,
+		PortionQuantity:   in.PortionQuantity,
+		PortionArea:       in.PortionArea,
+		TotalArea:         in.TotalArea,
+		SampledArea:       sampledAreaHa,
+		Description:       in.Description,
+		DefaultFormFactor: in.DefaultFormFactor,
+		UpdatedAt:         time.Now(),
+	}
+	phyto.SetMeasurementProtocol(measurement.Mode, measurement.Unit, measurement.InclusionMin, measurement.InclusionPolicy, measurement.ExcludeDead)
+
+	if s.txm == nil {
+		return s.repo.Update(ctx, phyto)
 	}
 
-	return s.repo.Update(ctx, phyto)
+	// áreas e fator de forma alteram os indicadores
+	return s.txm.RunInTx(ctx, func(repos postgres.Repos) error {
+		// com parcelas cadastradas, o número de parcelas e as áreas vêm delas
+		plots, err := repos.Plots().ListByPhytoAnalysis(ctx, id)
+		if err != nil {
+			return err
+		}
+		if quantity, meanArea, sampledArea := phytometrics.PlotSampling(plots); quantity > 0 {
+			phyto.PortionQuantity, phyto.PortionArea, phyto.SampledArea = quantity, meanArea, sampledArea
+		}
+		// com estratos cadastrados, a área total é a soma dos estratos
+		strata, err := repos.Strata().ListByPhytoAnalysis(ctx, id)
+		if err != nil {
+			return err
+		}
+		if len(strata) > 0 {
+			phyto.TotalArea = strataTotalArea(strata)
+		}
+
+		if err := repos.PhytoAnalyses().Update(ctx, phyto); err != nil {
+			return err
+		}
+
+		_, err = snapshotIndicators(ctx, repos.PhytoAnalyses(), id, phytometrics.ReasonUpdated)
+		return err
+	})
 }
 
 func (s *Service) Delete(ctx context.Context, id string) error {
@@ -353,6 +1226,10 @@ func (s *Service) Delete(ctx context.Context, id string) error {
 	}
 
 	return s.txm.RunInTx(ctx, func(repos postgres.Repos) error {
+		if err := repos.PhytoAnalyses().DeleteIndicatorSnapshots(ctx, id); err != nil {
+			return err
+		}
+
 		if err := repos.Specimens().DeleteByPhytoAnalysis(ctx, id); err != nil {
 			return err
 		}
@@ -2,9 +2,11 @@ package phytoanalysis
 
 import (
 	"context"
+	"math"
 	"testing"
 	"time"
 
+	"github.com/ESG-Project/suassu-api/internal/app/phytometrics"
 	"github.com/ESG-Project/suassu-api/internal/app/types"
 	"github.com/ESG-Project/suassu-api/internal/apperr"
 	domainphyto "github.com/ESG-Project/suassu-api/internal/domain/phytoanalysis"
@@ -46,6 +48,74 @@ func (n *noopRepo) GetWithSpecimens(ctx context.Context, id string) (*types.Phyt
 	return nil, nil
 }
 
+func (n *noopRepo) FilterIDsByEnterprise(ctx context.Context, enterpriseID string, ids []string) ([]string, error) {
+	return ids, nil
+}
+
+func (n *noopRepo) GetComplete(ctx context.Context, id string) (*types.PhytoAnalysisComplete, error) {
+	return nil, nil
+}
+
+func (n *noopRepo) ListSpecimens(ctx context.Context, phytoAnalysisID string, f types.SpecimenFilter, limit int32, after *types.SpecimenCursorKey) ([]*types.SpecimenWithSpecies, types.SpecimenPageInfo, error) {
+	return nil, types.SpecimenPageInfo{}, nil
+}
+
+func (n *noopRepo) StreamSpecimens(ctx context.Context, phytoAnalysisID string, f types.SpecimenFilter, fn func(*types.SpecimenWithSpecies) error) error {
+	return nil
+}
+
+func (n *noopRepo) LockForSnapshot(ctx context.Context, phytoAnalysisID string) error {
+	return nil
+}
+
+func (n *noopRepo) CreateIndicatorSnapshot(ctx context.Context, s *phytometrics.Snapshot) error {
+	return nil
+}
+
+func (n *noopRepo) GetLatestIndicatorSnapshot(ctx context.Context, phytoAnalysisID string) (*phytometrics.Snapshot, error) {
+	return nil, apperr.New(apperr.CodeNotFound, "not found")
+}
+
+func (n *noopRepo) GetIndicatorSnapshot(ctx context.Context, phytoAnalysisID string, version int) (*phytometrics.Snapshot, error) {
+	return nil, apperr.New(apperr.CodeNotFound, "not found")
+}
+
+func (n *noopRepo) ListIndicatorSnapshots(ctx context.Context, phytoAnalysisID string) ([]*phytometrics.Snapshot, error) {
+	return nil, nil
+}
+
+func (n *noopRepo) DeleteIndicatorSnapshots(ctx context.Context, phytoAnalysisID string) error {
+	return nil
+}
+
+func (n *noopRepo) SetEquation(ctx context.Context, phytoAnalysisID string, eq *types.EquationData) error {
+	return nil
+}
+
+func (n *noopRepo) DeleteEquation(ctx context.Context, phytoAnalysisID string, kind string) error {
+	return nil
+}
+
+func (n *noopRepo) SetAssortment(ctx context.Context, phytoAnalysisID string, rules types.AssortmentRules) error {
+	return nil
+}
+
+func (n *noopRepo) DeleteAssortment(ctx context.Context, phytoAnalysisID string) error {
+	return nil
+}
+
+func (n *noopRepo) GetPreviousCampaignID(ctx context.Context, phytoAnalysisID string) (*string, error) {
+	return nil, nil
+}
+
+func (n *noopRepo) GetNextCampaignID(ctx context.Context, phytoAnalysisID string) (*string, error) {
+	return nil, nil
+}
+
+func (n *noopRepo) ListMorphospecies(ctx context.Context, phytoAnalysisID string) ([]*types.MorphospeciesData, error) {
+	return nil, nil
+}
+
 type mockTxManager struct {
 	runInTxFunc func(ctx context.Context, fn func(postgres.Repos) error) error
 }
@@ -61,17 +131,17 @@ func TestNormalizeAndValidateSpecimens_IgnoresBlankRows(t *testing.T) {
 	t.Parallel()
 	registerDate := time.Date(2026, time.January, 10, 0, 0, 0, 0, time.UTC)
 
-	rows, invalidRows := normalizeAndValidateSpecimens([]SpecimenInput{
+	rows, invalidRows, _ := normalizeAndValidateSpecimens([]SpecimenInput{
 		{},
 		{
 			Portion:        " A1 ",
-			Height:         12,
+			Height:         floatPtr(12),
 			Cap1:           22,
 			RegisterDate:   registerDate,
 			ScientificName: "  Copaifera langsdorffii  ",
 		},
 		{},
-	})
+	}, importRules{})
 
 	require.Empty(t, invalidRows)
 	require.Len(t, rows, 1)
@@ -83,17 +153,23 @@ func TestNormalizeAndValidateSpecimens_IgnoresBlankRows(t *testing.T) {
 func TestNormalizeAndValidateSpecimens_ReportsPartialRows(t *testing.T) {
 	t.Parallel()
 
-	rows, invalidRows := normalizeAndValidateSpecimens([]SpecimenInput{{Portion: "A1"}})
+	rows, invalidRows, _ := normalizeAndValidateSpecimens([]SpecimenInput{
+		{Portion: "A1"},
+		{Portion: "A1", Height: floatPtr(0)},
+	}, importRules{})
 
 	require.Empty(t, rows)
-	require.Len(t, invalidRows, 1)
+	require.Len(t, invalidRows, 2)
 	require.Equal(t, 1, invalidRows[0].RowNumber)
-	require.Contains(t, invalidRows[0].Errors, "height must be positive")
+	require.NotContains(t, invalidRows[0].Errors, "height must be positive") // altura é opcional
 	require.Contains(t, invalidRows[0].Errors, "cap1 must be positive")
 	require.Contains(t, invalidRows[0].Errors, "register date is required")
 	require.Contains(t, invalidRows[0].Errors, "scientific name is required")
+	require.Contains(t, invalidRows[1].Errors, "height must be positive")
 }
 
+func floatPtr(v float64) *float64 { return &v }
+
 func TestCreate_ReturnsInvalidRowsDetails(t *testing.T) {
 	t.Parallel()
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
@@ -128,9 +204,318 @@ func TestCreate_ReturnsInvalidRowsDetails(t *testing.T) {
 	invalidRowsRaw, exists := appErr.Fields["invalidRows"]
 	require.True(t, exists)
 
-	invalidRows, ok := invalidRowsRaw.([]invalidSpecimenRow)
+	invalidRows, ok := invalidRowsRaw.([]types.InvalidSpecimenRow)
 	require.True(t, ok)
 	require.Len(t, invalidRows, 1)
 	require.Equal(t, 2, invalidRows[0].RowNumber)
-	require.Contains(t, invalidRows[0].Errors, "height must be positive")
+	require.Contains(t, invalidRows[0].Errors, "cap1 must be positive")
+}
+
+func TestNormalizeAndValidateSpecimens_UsesSourceRowNumbers(t *testing.T) {
+	t.Parallel()
+
+	rows, invalidRows, _ := normalizeAndValidateSpecimens([]SpecimenInput{
+		{
+			RowNumber:      9,
+			Portion:        "1",
+			Height:         floatPtr(8),
+			Cap1:           30,
+			RegisterDate:   time.Date(2026, time.January, 10, 0, 0, 0, 0, time.UTC),
+			ScientificName: "Cedrela fissilis",
+		},
+		{
+			RowNumber:      10,
+			Portion:        "1",
+			Cap1:           25,
+			RegisterDate:   time.Date(2026, time.January, 10, 0, 0, 0, 0, time.UTC),
+			ScientificName: "Cedrela fissilis",
+			InputErrors:    []string{"height must be a number"},
+		},
+	}, importRules{})
+
+	require.Len(t, rows, 1)
+	require.Equal(t, 9, rows[0].RowNumber)
+	require.Len(t, invalidRows, 1)
+	require.Equal(t, 10, invalidRows[0].RowNumber)
+	require.Equal(t, "height must be a number", invalidRows[0].Errors[0])
+}
+
+func TestAddSpecimens_RequiresSpecimens(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	called := false
+	svc := NewService(&noopRepo{}, &mockTxManager{
+		runInTxFunc: func(ctx context.Context, fn func(postgres.Repos) error) error {
+			called = true
+			return fn(postgres.Repos{})
+		},
+	})
+
+	_, err := svc.AddSpecimens(ctx, "phyto-1", AddSpecimensInput{Specimens: []SpecimenInput{{}, {}}})
+
+	require.Error(t, err)
+	require.False(t, called)
+	require.Equal(t, apperr.CodeInvalid, apperr.CodeOf(err))
+}
+
+func TestValidateCreate_ReportsAllProblems(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	svc := NewService(&noopRepo{}, &mockTxManager{})
+
+	report, err := svc.ValidateCreate(ctx, CreateInput{
+		Title:           "",
+		InitialDate:     time.Now(),
+		PortionQuantity: 2,
+		PortionArea:     100,
+		TotalArea:       10,
+		ProjectID:       "proj-1",
+		Specimens: []SpecimenInput{
+			{Portion: "A1"},
+			{},
+			{Portion: "A2", Cap1: 10},
+		},
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, []string{"title is required"}, report.AnalysisErrors)
+	require.Equal(t, 2, report.TotalRows)
+	require.Equal(t, 0, report.ValidRows)
+	require.Len(t, report.InvalidRows, 2)
+	require.Equal(t, 1, report.InvalidRows[0].RowNumber)
+	require.Equal(t, 3, report.InvalidRows[1].RowNumber)
+	require.NotContains(t, report.InvalidRows[1].Errors, "cap1 must be positive")
+	require.NotNil(t, report.Preview)
+	require.InDelta(t, 0.02, report.Preview.SampledArea, 0.000001)
+	require.Empty(t, report.Preview.Specimens)
+}
+
+func TestNormalizeAndValidateSpecimens_MeasurementProtocol(t *testing.T) {
+	t.Parallel()
+	registerDate := time.Date(2026, time.January, 10, 0, 0, 0, 0, time.UTC)
+
+	// DAP em mm, critério de 50 mm (DAP >= 5 cm)
+	specimens := []SpecimenInput{
+		{Portion: "A1", Cap1: 100, Cap2: floatPtr(60), RegisterDate: registerDate, ScientificName: "A a"},
+		{Portion: "A1", Cap1: 80, Cap2: floatPtr(30), RegisterDate: registerDate, ScientificName: "A a"},
+	}
+	protocol := types.MeasurementProtocol{
+		Mode:         types.MeasurementModeDAP,
+		Unit:         types.MeasurementUnitMm,
+		InclusionMin: floatPtr(50),
+	}
+
+	rows, invalidRows, flaggedRows := normalizeAndValidateSpecimens(specimens, importRules{Measurement: protocol})
+
+	require.Len(t, rows, 1)
+	require.Empty(t, flaggedRows)
+	require.InDelta(t, 10*math.Pi, rows[0].Specimen.Cap1, 1e-9) // gravado como CAP em cm
+	require.InDelta(t, 6*math.Pi, *rows[0].Specimen.Cap2, 1e-9)
+	require.Equal(t, 60.0, *specimens[0].Cap2) // a entrada não é alterada
+	require.Len(t, invalidRows, 1)
+	require.Equal(t, 2, invalidRows[0].RowNumber)
+	require.Equal(t, []string{"cap2 below inclusion criterion (DAP >= 50 mm)"}, invalidRows[0].Errors)
+
+	// Com a política "flag" a linha é aceita e sinalizada
+	protocol.InclusionPolicy = types.InclusionFlag
+	rows, invalidRows, flaggedRows = normalizeAndValidateSpecimens(specimens, importRules{Measurement: protocol})
+
+	require.Len(t, rows, 2)
+	require.Empty(t, invalidRows)
+	require.Equal(t, []types.FlaggedSpecimenRow{
+		{RowNumber: 2, Warnings: []string{"cap2 below inclusion criterion (DAP >= 50 mm)"}},
+	}, flaggedRows)
+	require.InDelta(t, 3*math.Pi, *rows[1].Specimen.Cap2, 1e-9)
+}
+
+func TestValidateMeasurementProtocol(t *testing.T) {
+	t.Parallel()
+
+	require.Empty(t, validateMeasurementProtocol(normalizeMeasurementProtocol(types.MeasurementProtocol{})))
+	require.Equal(t, []string{
+		"measurement mode must be cap or dap",
+		"measurement unit must be cm or mm",
+		"inclusion minimum must be positive",
+		"inclusion policy must be reject or flag",
+	}, validateMeasurementProtocol(normalizeMeasurementProtocol(types.MeasurementProtocol{
+		Mode:            "diameter",
+		Unit:            "m",
+		InclusionMin:    floatPtr(0),
+		InclusionPolicy: "skip",
+	})))
+}
+
+func TestNormalizeAndValidateSpecimens_RegisteredPlots(t *testing.T) {
+	t.Parallel()
+	registerDate := time.Date(2026, time.January, 10, 0, 0, 0, 0, time.UTC)
+
+	rows, invalidRows, _ := normalizeAndValidateSpecimens([]SpecimenInput{
+		{Portion: "P1", Cap1: 30, RegisterDate: registerDate, ScientificName: "A a"},
+		{Portion: "P9", Cap1: 30, RegisterDate: registerDate, ScientificName: "A a"},
+	}, importRules{PlotCodes: map[string]bool{"P1": true}})
+
+	require.Len(t, rows, 1)
+	require.Len(t, invalidRows, 1)
+	require.Equal(t, 2, invalidRows[0].RowNumber)
+	require.Equal(t, []string{"plot not found"}, invalidRows[0].Errors)
+}
+
+func TestBuildCreatePlots(t *testing.T) {
+	t.Parallel()
+
+	in := CreateInput{
+		PortionQuantity: 99,
+		PortionArea:     1,
+		Plots: []PlotInput{
+			{Code: " P1 ", Shape: "Rectangle", WidthM: floatPtr(10), LengthM: floatPtr(20)},
+			{Code: "P2", Shape: "circle", RadiusM: floatPtr(10)},
+		},
+	}
+
+	plots, errs := buildCreatePlots("phyto-1", &in)
+
+	require.Empty(t, errs)
+	require.Len(t, plots, 2)
+	require.Equal(t, "P1", plots[0].Code)
+	require.Equal(t, 200.0, plots[0].AreaM2)
+	require.InDelta(t, 100*math.Pi, plots[1].AreaM2, 1e-9)
+	require.Equal(t, 2, in.PortionQuantity)
+	require.InDelta(t, (200+100*math.Pi)/2, in.PortionArea, 1e-9)
+
+	in = CreateInput{Plots: []PlotInput{
+		{Code: "P1", Shape: "rectangle", WidthM: floatPtr(10)},
+		{Code: "P2", Shape: "irregular", AreaM2: floatPtr(100), Vertices: []types.Coordinate{{Latitude: -15, Longitude: -47}}},
+		{Code: "P3", Shape: "irregular", AreaM2: floatPtr(100)},
+		{Code: "P3", Shape: "irregular", AreaM2: floatPtr(100)},
+	}}
+
+	_, errs = buildCreatePlots("phyto-1", &in)
+
+	require.Equal(t, []string{
+		"plot 1: rectangle plot requires width and length",
+		"plot 2: plot polygon requires at least 3 vertices",
+		"plot 4: duplicated code P3",
+	}, errs)
+	require.Zero(t, in.PortionQuantity) // não derivado com parcelas inválidas
+}
+
+func TestNormalizeAndValidateSpecimens_Location(t *testing.T) {
+	t.Parallel()
+	registerDate := time.Date(2026, time.January, 10, 0, 0, 0, 0, time.UTC)
+
+	rows, invalidRows, _ := normalizeAndValidateSpecimens([]SpecimenInput{
+		{Portion: "A1", Cap1: 30, RegisterDate: registerDate, ScientificName: "A a", Latitude: floatPtr(-15.78), Longitude: floatPtr(-47.93)},
+		{Portion: "A1", Cap1: 30, RegisterDate: registerDate, ScientificName: "A a", Latitude: floatPtr(-15.78)},
+		{Portion: "A1", Cap1: 30, RegisterDate: registerDate, ScientificName: "A a", Latitude: floatPtr(-95), Longitude: floatPtr(-47.93)},
+	}, importRules{})
+
+	require.Len(t, rows, 1)
+	require.Equal(t, -15.78, *rows[0].Specimen.Latitude)
+	require.Len(t, invalidRows, 2)
+	require.Equal(t, []string{"latitude and longitude must be informed together"}, invalidRows[0].Errors)
+	require.Equal(t, []string{"invalid coordinate"}, invalidRows[1].Errors)
+}
+
+func TestNormalizeAndValidateSpecimens_Tags(t *testing.T) {
+	t.Parallel()
+	registerDate := time.Date(2026, time.January, 10, 0, 0, 0, 0, time.UTC)
+	tag := func(v string) *string { return &v }
+
+	rows, invalidRows, _ := normalizeAndValidateSpecimens([]SpecimenInput{
+		{Portion: "A1", Cap1: 30, RegisterDate: registerDate, ScientificName: "A a", Tag: tag(" T2 ")},
+		{Portion: "A1", Cap1: 30, RegisterDate: registerDate, ScientificName: "A a", Tag: tag("T2")},
+		{Portion: "A1", Cap1: 30, RegisterDate: registerDate, ScientificName: "A a", Tag: tag("T1")},
+		{Portion: "A1", Cap1: 30, RegisterDate: registerDate, ScientificName: "A a", Tag: tag(" ")},
+	}, importRules{UsedTags: map[string]bool{"T1": true}})
+
+	require.Len(t, rows, 2)
+	require.Equal(t, "T2", *rows[0].Specimen.Tag)
+	require.Nil(t, rows[1].Specimen.Tag)
+	require.Len(t, invalidRows, 2)
+	require.Equal(t, []string{"duplicated tag T2"}, invalidRows[0].Errors)
+	require.Equal(t, []string{"tag already used in the analysis"}, invalidRows[1].Errors)
+}
+
+func TestNormalizeAndValidateSpecimens_Condition(t *testing.T) {
+	t.Parallel()
+	registerDate := time.Date(2026, time.January, 10, 0, 0, 0, 0, time.UTC)
+	condition := func(v string) *string { return &v }
+
+	rows, invalidRows, _ := normalizeAndValidateSpecimens([]SpecimenInput{
+		{Portion: "A1", Cap1: 30, RegisterDate: registerDate, ScientificName: "A a"},
+		{Portion: "A1", Cap1: 30, RegisterDate: registerDate, ScientificName: "A a", Status: " Fallen ", Phytosanitary: condition("POOR")},
+		{Portion: "A1", Cap1: 30, RegisterDate: registerDate, ScientificName: "A a", Status: "sleeping", Phytosanitary: condition("bad")},
+	}, importRules{})
+
+	require.Len(t, rows, 2)
+	require.Equal(t, types.SpecimenStatusAlive, rows[0].Specimen.Status)
+	require.Nil(t, rows[0].Specimen.Phytosanitary)
+	require.Equal(t, types.SpecimenStatusFallen, rows[1].Specimen.Status)
+	require.Equal(t, types.PhytosanitaryPoor, *rows[1].Specimen.Phytosanitary)
+	require.Len(t, invalidRows, 1)
+	require.Equal(t, []string{
+		"status must be alive, dead_standing, fallen or resprouting",
+		"phytosanitary condition must be good, fair or poor",
+	}, invalidRows[0].Errors)
+}
+
+func TestDetectMorphospecies(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name   string
+		ok     bool
+		family string
+		genus  string
+	}{
+		{name: "Indet. 3", ok: true},
+		{name: "NI 4", ok: true},
+		{name: "sp. 2", ok: true},
+		{name: "Myrcia sp. 2", ok: true, genus: "Myrcia"},
+		{name: "Myrcia", ok: true, genus: "Myrcia"},
+		{name: "Myrtaceae sp. 1", ok: true, family: "Myrtaceae"},
+		{name: "Leguminosae 2", ok: true, family: "Leguminosae"},
+		{name: "Myrcia splendens", ok: false},
+		{name: "myrcia sp.", ok: false},
+		{name: "", ok: false},
+	}
+
+	for _, tc := range cases {
+		family, genus, ok := detectMorphospecies(tc.name)
+		require.Equal(t, tc.ok, ok, tc.name)
+		if tc.family == "" {
+			require.Nil(t, family, tc.name)
+		} else {
+			require.Equal(t, tc.family, *family, tc.name)
+		}
+		if tc.genus == "" {
+			require.Nil(t, genus, tc.name)
+		} else {
+			require.Equal(t, tc.genus, *genus, tc.name)
+		}
+	}
+}
+
+func TestResolveMorphospecies_DeclaredAndDetected(t *testing.T) {
+	t.Parallel()
+	family := "Lauraceae"
+
+	resolved, created, err := resolveMorphospecies(context.Background(), postgres.Repos{}, "",
+		[]string{"Morfo A", "Ocotea sp. 1", "ocotea  SP. 1", "Ocotea odorifera"},
+		[]MorphospeciesInput{{Name: "morfo a", Family: &family}},
+	)
+
+	require.NoError(t, err)
+	require.Len(t, created, 2)
+	require.Equal(t, "morfo a", created[0].Name)
+	require.Equal(t, family, *created[0].Family)
+	require.Equal(t, "Ocotea", *created[1].Genus)
+
+	// nomes iguais após a normalização compartilham a morfoespécie
+	require.Equal(t, resolved["Ocotea sp. 1"].ID, resolved["ocotea  SP. 1"].ID)
+	require.NotContains(t, resolved, "Ocotea odorifera")
 }
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/ESG-Project/suassu-api/internal/app/phytoanalysis"
+	"github.com/ESG-Project/suassu-api/internal/app/phytometrics"
 	"github.com/ESG-Project/suassu-api/internal/app/types"
 	"github.com/ESG-Project/suassu-api/internal/apperr"
 	domainphyto "github.com/ESG-Project/suassu-api/internal/domain/phytoanalysis"
@@ -14,10 +15,14 @@ import (
 
 // Mock repositories
 type fakePhytoRepo struct {
-	saved    *domainphyto.PhytoAnalysis
-	err      error
-	phytos   []*types.PhytoAnalysisWithProject
-	complete *types.PhytoAnalysisComplete
+	saved     *domainphyto.PhytoAnalysis
+	err       error
+	phytos    []*types.PhytoAnalysisWithProject
+	complete  *types.PhytoAnalysisComplete
+	owned     map[string]bool // nil = todas as análises pertencem à empresa
+	snapshots []*phytometrics.Snapshot
+	specimens []*types.SpecimenWithSpecies
+	listLimit int32
 }
 
 func (f *fakePhytoRepo) Create(ctx context.Context, p *domainphyto.PhytoAnalysis) error {
@@ -81,6 +86,170 @@ func (f *fakePhytoRepo) GetWithSpecimens(ctx context.Context, id string) (*types
 	return nil, apperr.New(apperr.CodeNotFound, "not found")
 }
 
+func (f *fakePhytoRepo) FilterIDsByEnterprise(ctx context.Context, enterpriseID string, ids []string) ([]string, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if f.owned == nil || f.owned[id] {
+			out = append(out, id)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakePhytoRepo) GetComplete(ctx context.Context, id string) (*types.PhytoAnalysisComplete, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	if f.complete != nil {
+		return f.complete, nil
+	}
+	return nil, apperr.New(apperr.CodeNotFound, "not found")
+}
+
+func (f *fakePhytoRepo) ListSpecimens(ctx context.Context, phytoAnalysisID string, filter types.SpecimenFilter, limit int32, after *types.SpecimenCursorKey) ([]*types.SpecimenWithSpecies, types.SpecimenPageInfo, error) {
+	if f.err != nil {
+		return nil, types.SpecimenPageInfo{}, f.err
+	}
+	f.listLimit = limit
+	return f.specimens, types.SpecimenPageInfo{}, nil
+}
+
+func (f *fakePhytoRepo) StreamSpecimens(ctx context.Context, phytoAnalysisID string, filter types.SpecimenFilter, fn func(*types.SpecimenWithSpecies) error) error {
+	if f.err != nil {
+		return f.err
+	}
+	for _, s := range f.specimens {
+		if err := fn(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *fakePhytoRepo) LockForSnapshot(ctx context.Context, phytoAnalysisID string) error {
+	return f.err
+}
+
+func (f *fakePhytoRepo) CreateIndicatorSnapshot(ctx context.Context, s *phytometrics.Snapshot) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.snapshots = append(f.snapshots, s)
+	return nil
+}
+
+func (f *fakePhytoRepo) GetLatestIndicatorSnapshot(ctx context.Context, phytoAnalysisID string) (*phytometrics.Snapshot, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	if len(f.snapshots) == 0 {
+		return nil, apperr.New(apperr.CodeNotFound, "not found")
+	}
+	return f.snapshots[len(f.snapshots)-1], nil
+}
+
+func (f *fakePhytoRepo) GetIndicatorSnapshot(ctx context.Context, phytoAnalysisID string, version int) (*phytometrics.Snapshot, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	for _, s := range f.snapshots {
+		if s.Version == version {
+			return s, nil
+		}
+	}
+	return nil, apperr.New(apperr.CodeNotFound, "not found")
+}
+
+func (f *fakePhytoRepo) ListIndicatorSnapshots(ctx context.Context, phytoAnalysisID string) ([]*phytometrics.Snapshot, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.snapshots, nil
+}
+
+func (f *fakePhytoRepo) DeleteIndicatorSnapshots(ctx context.Context, phytoAnalysisID string) error {
+	return f.err
+}
+
+func (f *fakePhytoRepo) SetEquation(ctx context.Context, phytoAnalysisID string, eq *types.EquationData) error {
+	if f.err != nil {
+		return f.err
+	}
+	if eq.Kind == types.EquationKindVolume {
+		f.complete.VolumeEquation = eq
+	} else {
+		f.complete.BiomassEquation = eq
+	}
+	return nil
+}
+
+func (f *fakePhytoRepo) DeleteEquation(ctx context.Context, phytoAnalysisID string, kind string) error {
+	if f.err != nil {
+		return f.err
+	}
+	if kind == types.EquationKindVolume {
+		f.complete.VolumeEquation = nil
+	} else {
+		f.complete.BiomassEquation = nil
+	}
+	return nil
+}
+
+func (f *fakePhytoRepo) SetAssortment(ctx context.Context, phytoAnalysisID string, rules types.AssortmentRules) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.complete.Assortment = &rules
+	return nil
+}
+
+func (f *fakePhytoRepo) DeleteAssortment(ctx context.Context, phytoAnalysisID string) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.complete.Assortment = nil
+	return nil
+}
+
+func (f *fakePhytoRepo) GetPreviousCampaignID(ctx context.Context, phytoAnalysisID string) (*string, error) {
+	return nil, f.err
+}
+
+func (f *fakePhytoRepo) GetNextCampaignID(ctx context.Context, phytoAnalysisID string) (*string, error) {
+	return nil, f.err
+}
+
+func (f *fakePhytoRepo) ListMorphospecies(ctx context.Context, phytoAnalysisID string) ([]*types.MorphospeciesData, error) {
+	return nil, f.err
+}
+
+// fakeEquations resolve equações de uma lista fixa
+type fakeEquations struct {
+	list []*types.EquationData
+}
+
+func (f *fakeEquations) GetByID(ctx context.Context, id string) (*types.EquationData, error) {
+	for _, eq := range f.list {
+		if eq.ID == id {
+			return eq, nil
+		}
+	}
+	return nil, apperr.New(apperr.CodeNotFound, "equation not found")
+}
+
+func (f *fakeEquations) List(ctx context.Context, filter types.EquationFilter) ([]*types.EquationData, error) {
+	out := make([]*types.EquationData, 0, len(f.list))
+	for _, eq := range f.list {
+		if phytometrics.EquationMatches(*eq, filter) {
+			out = append(out, eq)
+		}
+	}
+	return out, nil
+}
+
 func TestPhytoAnalysisService_Create_NeedsTxManager(t *testing.T) {
 	t.Parallel()
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
@@ -320,3 +489,405 @@ func TestPhytoAnalysisService_Delete(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+func TestPhytoAnalysisService_ListWithSpecimens(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("error - fewer than two analyses", func(t *testing.T) {
+		svc := phytoanalysis.NewService(&fakePhytoRepo{}, nil)
+
+		_, err := svc.ListWithSpecimens(ctx, "ent-1", []string{"a", " a ", ""})
+
+		require.Error(t, err)
+		require.Equal(t, apperr.CodeInvalid, apperr.CodeOf(err))
+	})
+
+	t.Run("error - analysis from another enterprise", func(t *testing.T) {
+		repo := &fakePhytoRepo{
+			owned:    map[string]bool{"a": true},
+			complete: &types.PhytoAnalysisComplete{ID: "a"},
+		}
+		svc := phytoanalysis.NewService(repo, nil)
+
+		_, err := svc.ListWithSpecimens(ctx, "ent-1", []string{"a", "b"})
+
+		require.Error(t, err)
+		require.Equal(t, apperr.CodeNotFound, apperr.CodeOf(err))
+		require.Contains(t, err.Error(), "phyto analysis not found: b")
+	})
+
+	t.Run("success", func(t *testing.T) {
+		repo := &fakePhytoRepo{complete: &types.PhytoAnalysisComplete{ID: "a"}}
+		svc := phytoanalysis.NewService(repo, nil)
+
+		out, err := svc.ListWithSpecimens(ctx, "ent-1", []string{"a", "b", "a"})
+
+		require.NoError(t, err)
+		require.Len(t, out, 2)
+	})
+}
+
+func TestPhytoAnalysisService_Indicators(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	newComplete := func() *types.PhytoAnalysisComplete {
+		return &types.PhytoAnalysisComplete{
+			ID:              "phyto-1",
+			PortionQuantity: 1,
+			PortionArea:     10000,
+			SampledArea:     1,
+			Specimens: []*types.SpecimenWithSpecies{
+				{ID: "s1", Portion: "1", Height: 10, Cap1: 100, ScientificName: "A a"},
+			},
+		}
+	}
+
+	t.Run("read without snapshot computes without persisting", func(t *testing.T) {
+		repo := &fakePhytoRepo{complete: newComplete()}
+		svc := phytoanalysis.NewService(repo, nil)
+
+		snapshot, err := svc.GetIndicators(ctx, "phyto-1")
+
+		require.NoError(t, err)
+		require.Equal(t, 0, snapshot.Version)
+		require.Equal(t, phytometrics.ReasonInitial, snapshot.Reason)
+		require.Equal(t, 1, snapshot.SpecimensCount)
+		require.Equal(t, 1, snapshot.Result.Summary.IndividualsCount)
+		require.Empty(t, repo.snapshots)
+
+		// após o recálculo explícito, as leituras usam o snapshot gravado
+		rec, err := svc.RecomputeIndicators(ctx, "phyto-1")
+		require.NoError(t, err)
+		require.Equal(t, 1, rec.Current.Version)
+		again, err := svc.GetIndicators(ctx, "phyto-1")
+		require.NoError(t, err)
+		require.Same(t, rec.Current, again)
+		require.Len(t, repo.snapshots, 1)
+	})
+
+	t.Run("recompute without changes keeps the version", func(t *testing.T) {
+		repo := &fakePhytoRepo{complete: newComplete()}
+		svc := phytoanalysis.NewService(repo, nil)
+
+		_, err := svc.RecomputeIndicators(ctx, "phyto-1")
+		require.NoError(t, err)
+
+		rec, err := svc.RecomputeIndicators(ctx, "phyto-1")
+
+		require.NoError(t, err)
+		require.False(t, rec.Created)
+		require.Empty(t, rec.Changes)
+		require.Equal(t, 1, rec.Current.Version)
+		require.Len(t, repo.snapshots, 1)
+	})
+
+	t.Run("recompute after specimen change creates a new version with diff", func(t *testing.T) {
+		repo := &fakePhytoRepo{complete: newComplete()}
+		svc := phytoanalysis.NewService(repo, nil)
+
+		_, err := svc.RecomputeIndicators(ctx, "phyto-1")
+		require.NoError(t, err)
+
+		repo.complete.Specimens = append(repo.complete.Specimens,
+			&types.SpecimenWithSpecies{ID: "s2", Portion: "1", Height: 8, Cap1: 50, ScientificName: "B b"})
+
+		rec, err := svc.RecomputeIndicators(ctx, "phyto-1")
+
+		require.NoError(t, err)
+		require.True(t, rec.Created)
+		require.Equal(t, 1, rec.Previous.Version)
+		require.Equal(t, 2, rec.Current.Version)
+		require.Equal(t, phytometrics.ReasonRecomputed, rec.Current.Reason)
+		require.NotEmpty(t, rec.Changes)
+
+		snapshot, err := svc.GetIndicatorSnapshot(ctx, "phyto-1", 1)
+		require.NoError(t, err)
+		require.Equal(t, 1, snapshot.Result.Summary.IndividualsCount)
+	})
+
+	t.Run("outdated engine version forces a new snapshot", func(t *testing.T) {
+		repo := &fakePhytoRepo{complete: newComplete()}
+		svc := phytoanalysis.NewService(repo, nil)
+
+		initial, err := svc.RecomputeIndicators(ctx, "phyto-1")
+		require.NoError(t, err)
+		snapshot := initial.Current
+		snapshot.EngineVersion = phytometrics.EngineVersion - 1
+		require.True(t, snapshot.Outdated())
+
+		rec, err := svc.RecomputeIndicators(ctx, "phyto-1")
+
+		require.NoError(t, err)
+		require.True(t, rec.Created)
+		require.Empty(t, rec.Changes)
+		require.False(t, rec.Current.Outdated())
+	})
+
+	t.Run("reading an outdated snapshot recomputes without persisting", func(t *testing.T) {
+		repo := &fakePhytoRepo{complete: newComplete()}
+		svc := phytoanalysis.NewService(repo, nil)
+
+		initial, err := svc.RecomputeIndicators(ctx, "phyto-1")
+		require.NoError(t, err)
+		initial.Current.EngineVersion = phytometrics.EngineVersion - 1
+
+		current, err := svc.GetIndicators(ctx, "phyto-1")
+
+		require.NoError(t, err)
+		require.Equal(t, 0, current.Version)
+		require.False(t, current.Outdated())
+		require.Equal(t, phytometrics.ReasonEngineUpgraded, current.Reason)
+		require.NotNil(t, current.Result.Biomass)
+		require.Len(t, repo.snapshots, 1)
+	})
+
+	t.Run("error - snapshot version not found", func(t *testing.T) {
+		svc := phytoanalysis.NewService(&fakePhytoRepo{}, nil)
+
+		_, err := svc.GetIndicatorSnapshot(ctx, "phyto-1", 7)
+
+		require.Error(t, err)
+		require.Equal(t, apperr.CodeNotFound, apperr.CodeOf(err))
+	})
+}
+
+func TestPhytoAnalysisService_Equations(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	state := "MG"
+	newComplete := func() *types.PhytoAnalysisComplete {
+		return &types.PhytoAnalysisComplete{
+			ID:              "phyto-1",
+			PortionQuantity: 1,
+			PortionArea:     10000,
+			SampledArea:     1,
+			ProjectState:    &state,
+			Specimens: []*types.SpecimenWithSpecies{
+				{ID: "s1", Portion: "1", Height: 10, Cap1: 100, ScientificName: "A a"},
+			},
+		}
+	}
+	equations := &fakeEquations{list: []*types.EquationData{
+		{
+			ID:           "spurr-br",
+			Name:         "Spurr",
+			Kind:         types.EquationKindVolume,
+			Expression:   "b0 + b1 * dap^2 * h",
+			Coefficients: map[string]float64{"b0": 0.01, "b1": 0.00004},
+		},
+		{
+			ID:            "spurr-mg",
+			Name:          "Spurr MG",
+			Kind:          types.EquationKindVolume,
+			Expression:    "b0 + b1 * dap^2 * h",
+			Coefficients:  map[string]float64{"b0": 0.02, "b1": 0.00005},
+			Applicability: types.EquationApplicability{States: []string{"MG"}},
+		},
+		{
+			ID:            "spurr-sp",
+			Name:          "Spurr SP",
+			Kind:          types.EquationKindVolume,
+			Expression:    "b0 + b1 * dap^2 * h",
+			Coefficients:  map[string]float64{"b0": 0.02, "b1": 0.00005},
+			Applicability: types.EquationApplicability{States: []string{"SP"}},
+		},
+	}}
+
+	t.Run("suggestions follow the project state, most specific first", func(t *testing.T) {
+		repo := &fakePhytoRepo{complete: newComplete()}
+		svc := phytoanalysis.NewServiceWithEquations(repo, nil, equations)
+
+		list, err := svc.SuggestEquations(ctx, "phyto-1", types.EquationFilter{})
+
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+		require.Equal(t, "spurr-mg", list[0].ID)
+		require.Equal(t, "spurr-br", list[1].ID)
+	})
+
+	t.Run("selecting an equation creates a new snapshot version", func(t *testing.T) {
+		repo := &fakePhytoRepo{complete: newComplete(), phytos: []*types.PhytoAnalysisWithProject{{ID: "phyto-1"}}}
+		svc := phytoanalysis.NewServiceWithEquations(repo, nil, equations)
+
+		initial, err := svc.GetIndicators(ctx, "phyto-1")
+		require.NoError(t, err)
+		require.Equal(t, phytometrics.FormFactorEquationID, initial.Result.Equations.Volume.ID)
+
+		snapshot, err := svc.SelectEquation(ctx, "phyto-1", "spurr-mg")
+
+		require.NoError(t, err)
+		require.Equal(t, 1, snapshot.Version) // a leitura inicial não grava snapshot
+		require.Equal(t, phytometrics.ReasonEquationChanged, snapshot.Reason)
+		require.Equal(t, "spurr-mg", snapshot.Result.Equations.Volume.ID)
+		require.NotEqual(t, initial.Result.Summary.VolumeTotalM3, snapshot.Result.Summary.VolumeTotalM3)
+
+		cleared, err := svc.ClearEquation(ctx, "phyto-1", types.EquationKindVolume)
+
+		require.NoError(t, err)
+		require.Equal(t, 2, cleared.Version)
+		require.Equal(t, phytometrics.FormFactorEquationID, cleared.Result.Equations.Volume.ID)
+		require.InDelta(t, initial.Result.Summary.VolumeTotalM3, cleared.Result.Summary.VolumeTotalM3, 1e-12)
+	})
+
+	t.Run("error - equation restricted to another state", func(t *testing.T) {
+		repo := &fakePhytoRepo{complete: newComplete()}
+		svc := phytoanalysis.NewServiceWithEquations(repo, nil, equations)
+
+		_, err := svc.SelectEquation(ctx, "phyto-1", "spurr-sp")
+
+		require.Error(t, err)
+		require.Equal(t, apperr.CodeInvalid, apperr.CodeOf(err))
+		require.Nil(t, repo.complete.VolumeEquation)
+	})
+
+	t.Run("error - unknown equation", func(t *testing.T) {
+		repo := &fakePhytoRepo{complete: newComplete()}
+		svc := phytoanalysis.NewServiceWithEquations(repo, nil, equations)
+
+		_, err := svc.SelectEquation(ctx, "phyto-1", "unknown")
+
+		require.Error(t, err)
+		require.Equal(t, apperr.CodeNotFound, apperr.CodeOf(err))
+	})
+
+	t.Run("error - invalid kind", func(t *testing.T) {
+		svc := phytoanalysis.NewServiceWithEquations(&fakePhytoRepo{complete: newComplete()}, nil, equations)
+
+		_, err := svc.ClearEquation(ctx, "phyto-1", "height")
+
+		require.Error(t, err)
+		require.Equal(t, apperr.CodeInvalid, apperr.CodeOf(err))
+	})
+}
+
+func TestPhytoAnalysisService_ListSpecimens(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	analysis := []*types.PhytoAnalysisWithProject{{ID: "phyto-1"}}
+
+	t.Run("clamps page size", func(t *testing.T) {
+		repo := &fakePhytoRepo{phytos: analysis}
+		svc := phytoanalysis.NewService(repo, nil)
+
+		_, _, err := svc.ListSpecimens(ctx, "phyto-1", types.SpecimenFilter{}, 0, nil)
+		require.NoError(t, err)
+		require.EqualValues(t, phytoanalysis.DefaultSpecimenPageSize, repo.listLimit)
+
+		_, _, err = svc.ListSpecimens(ctx, "phyto-1", types.SpecimenFilter{}, 50000, nil)
+		require.NoError(t, err)
+		require.EqualValues(t, phytoanalysis.MaxSpecimenPageSize, repo.listLimit)
+	})
+
+	t.Run("error - invalid sort field", func(t *testing.T) {
+		svc := phytoanalysis.NewService(&fakePhytoRepo{phytos: analysis}, nil)
+
+		_, _, err := svc.ListSpecimens(ctx, "phyto-1", types.SpecimenFilter{SortBy: "cap9"}, 10, nil)
+
+		require.Error(t, err)
+		require.Equal(t, apperr.CodeInvalid, apperr.CodeOf(err))
+	})
+
+	t.Run("error - sort by estimated height", func(t *testing.T) {
+		svc := phytoanalysis.NewService(&fakePhytoRepo{phytos: analysis}, nil)
+
+		// só a altura medida é ordenável; a estimada é preenchida depois da consulta
+		_, _, err := svc.ListSpecimens(ctx, "phyto-1", types.SpecimenFilter{SortBy: "height"}, 10, nil)
+
+		require.Error(t, err)
+		require.Equal(t, apperr.CodeInvalid, apperr.CodeOf(err))
+	})
+
+	t.Run("error - inverted dbh range", func(t *testing.T) {
+		minDbh, maxDbh := 30.0, 10.0
+		svc := phytoanalysis.NewService(&fakePhytoRepo{phytos: analysis}, nil)
+
+		err := svc.StreamSpecimens(ctx, "phyto-1", types.SpecimenFilter{MinDbhCm: &minDbh, MaxDbhCm: &maxDbh}, func(*types.SpecimenWithSpecies) error {
+			return nil
+		})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid dbh range")
+	})
+
+	t.Run("error - analysis not found", func(t *testing.T) {
+		svc := phytoanalysis.NewService(&fakePhytoRepo{}, nil)
+
+		_, _, err := svc.ListSpecimens(ctx, "phyto-999", types.SpecimenFilter{}, 10, nil)
+
+		require.Error(t, err)
+		require.Equal(t, apperr.CodeNotFound, apperr.CodeOf(err))
+	})
+
+	t.Run("stream visits every specimen", func(t *testing.T) {
+		repo := &fakePhytoRepo{
+			phytos:    analysis,
+			specimens: []*types.SpecimenWithSpecies{{ID: "s1"}, {ID: "s2"}, {ID: "s3"}},
+		}
+		svc := phytoanalysis.NewService(repo, nil)
+
+		visited := make([]string, 0)
+		err := svc.StreamSpecimens(ctx, "phyto-1", types.SpecimenFilter{}, func(s *types.SpecimenWithSpecies) error {
+			visited = append(visited, s.ID)
+			return nil
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, []string{"s1", "s2", "s3"}, visited)
+	})
+}
+
+func TestPhytoAnalysisService_Assortment(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	newRepo := func() *fakePhytoRepo {
+		return &fakePhytoRepo{
+			phytos: []*types.PhytoAnalysisWithProject{{ID: "phyto-1"}},
+			complete: &types.PhytoAnalysisComplete{
+				ID:              "phyto-1",
+				PortionQuantity: 1,
+				PortionArea:     10000,
+				SampledArea:     1,
+				Specimens: []*types.SpecimenWithSpecies{
+					{ID: "s1", Portion: "1", Height: 10, Cap1: 100, ScientificName: "A a"},
+				},
+			},
+		}
+	}
+
+	t.Run("stacking factor of the analysis changes the stereo volume", func(t *testing.T) {
+		repo := newRepo()
+		svc := phytoanalysis.NewService(repo, nil)
+
+		initial, err := svc.GetIndicators(ctx, "phyto-1")
+		require.NoError(t, err)
+
+		snapshot, err := svc.SetAssortment(ctx, "phyto-1", types.AssortmentRules{StackingFactor: 0.5})
+
+		require.NoError(t, err)
+		require.Equal(t, 1, snapshot.Version) // a leitura inicial não grava snapshot
+		require.Equal(t, phytometrics.ReasonAssortmentChanged, snapshot.Reason)
+		require.Equal(t, phytometrics.DefaultLogMinDbhCm, repo.complete.Assortment.LogMinDbhCm)
+		require.InDelta(t, snapshot.Result.Summary.VolumeTotalM3/0.5, snapshot.Result.Summary.VolumeTotalMst, 1e-12)
+
+		cleared, err := svc.ClearAssortment(ctx, "phyto-1")
+
+		require.NoError(t, err)
+		require.Nil(t, repo.complete.Assortment)
+		require.InDelta(t, initial.Result.Summary.VolumeTotalMst, cleared.Result.Summary.VolumeTotalMst, 1e-12)
+	})
+
+	t.Run("error - stacking factor above 1", func(t *testing.T) {
+		repo := newRepo()
+		svc := phytoanalysis.NewService(repo, nil)
+
+		_, err := svc.SetAssortment(ctx, "phyto-1", types.AssortmentRules{StackingFactor: 1.4})
+
+		require.Error(t, err)
+		require.Equal(t, apperr.CodeInvalid, apperr.CodeOf(err))
+		require.Nil(t, repo.complete.Assortment)
+	})
+}
@@ -0,0 +1,177 @@
+package phytoanalysis
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+	"github.com/ESG-Project/suassu-api/internal/apperr"
+	domainstratum "github.com/ESG-Project/suassu-api/internal/domain/stratum"
+	postgres "github.com/ESG-Project/suassu-api/internal/infra/db/postgres"
+	"github.com/google/uuid"
+)
+
+// StratumInput representa os dados de um estrato da análise
+type StratumInput struct {
+	Code        string
+	Name        string // omitido = código
+	AreaHa      float64
+	Description *string
+}
+
+// buildStratum monta e valida a entidade do estrato
+func buildStratum(id, phytoID string, in StratumInput) (*domainstratum.Stratum, error) {
+	code := strings.TrimSpace(in.Code)
+	name := strings.TrimSpace(in.Name)
+	if name == "" {
+		name = code
+	}
+
+	s := domainstratum.NewStratum(id, phytoID, code, name, in.AreaHa)
+	s.SetDescription(in.Description)
+
+	if err := s.Validate(); err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeInvalid, "invalid stratum data")
+	}
+	return s, nil
+}
+
+// strataTotalArea retorna a soma das áreas dos estratos (ha)
+func strataTotalArea(strata []*types.StratumData) float64 {
+	var total float64
+	for _, s := range strata {
+		total += s.AreaHa
+	}
+	return total
+}
+
+// syncStrataArea deriva a área total da análise dos estratos cadastrados
+func syncStrataArea(ctx context.Context, repos postgres.Repos, id string) error {
+	strata, err := repos.Strata().ListByPhytoAnalysis(ctx, id)
+	if err != nil {
+		return err
+	}
+	if len(strata) == 0 {
+		return nil
+	}
+	return repos.PhytoAnalyses().UpdateTotalArea(ctx, id, strataTotalArea(strata))
+}
+
+// stratumCodeTaken indica se outro estrato da análise já usa o código
+func stratumCodeTaken(strata []*types.StratumData, code, exceptID string) bool {
+	for _, s := range strata {
+		if s.Code == code && s.ID != exceptID {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateStratum cadastra um estrato; a área total da análise passa a ser a soma dos estratos
+func (s *Service) CreateStratum(ctx context.Context, id string, in StratumInput) (string, error) {
+	if strings.TrimSpace(id) == "" {
+		return "", apperr.New(apperr.CodeInvalid, "missing required fields")
+	}
+	if s.txm == nil {
+		return "", apperr.New(apperr.CodeInvalid, "transaction manager required")
+	}
+
+	stratum, err := buildStratum(uuid.NewString(), id, in)
+	if err != nil {
+		return "", err
+	}
+
+	err = s.txm.RunInTx(ctx, func(repos postgres.Repos) error {
+		if _, err := repos.PhytoAnalyses().GetByID(ctx, id); err != nil {
+			return err
+		}
+
+		existing, err := repos.Strata().ListByPhytoAnalysis(ctx, id)
+		if err != nil {
+			return err
+		}
+		if stratumCodeTaken(existing, stratum.Code, "") {
+			return apperr.New(apperr.CodeConflict, "stratum code already exists")
+		}
+
+		if err := repos.Strata().Create(ctx, stratum); err != nil {
+			return err
+		}
+		return syncStrataArea(ctx, repos, id)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return stratum.ID, nil
+}
+
+// UpdateStratum atualiza o estrato e a área total da análise
+func (s *Service) UpdateStratum(ctx context.Context, id string, stratumID string, in StratumInput) error {
+	if strings.TrimSpace(id) == "" || strings.TrimSpace(stratumID) == "" {
+		return apperr.New(apperr.CodeInvalid, "missing required fields")
+	}
+	if s.txm == nil {
+		return apperr.New(apperr.CodeInvalid, "transaction manager required")
+	}
+
+	stratum, err := buildStratum(stratumID, id, in)
+	if err != nil {
+		return err
+	}
+
+	return s.txm.RunInTx(ctx, func(repos postgres.Repos) error {
+		current, err := repos.Strata().GetByID(ctx, id, stratumID)
+		if err != nil {
+			return err
+		}
+
+		existing, err := repos.Strata().ListByPhytoAnalysis(ctx, id)
+		if err != nil {
+			return err
+		}
+		if stratumCodeTaken(existing, stratum.Code, stratumID) {
+			return apperr.New(apperr.CodeConflict, "stratum code already exists")
+		}
+
+		stratum.CreatedAt = current.CreatedAt
+		stratum.UpdatedAt = time.Now()
+		if err := repos.Strata().Update(ctx, stratum); err != nil {
+			return err
+		}
+		return syncStrataArea(ctx, repos, id)
+	})
+}
+
+// DeleteStratum remove o estrato; estratos com parcelas não podem ser removidos
+func (s *Service) DeleteStratum(ctx context.Context, id string, stratumID string) error {
+	if strings.TrimSpace(id) == "" || strings.TrimSpace(stratumID) == "" {
+		return apperr.New(apperr.CodeInvalid, "missing required fields")
+	}
+	if s.txm == nil {
+		return apperr.New(apperr.CodeInvalid, "transaction manager required")
+	}
+
+	return s.txm.RunInTx(ctx, func(repos postgres.Repos) error {
+		if _, err := repos.Strata().GetByID(ctx, id, stratumID); err != nil {
+			return err
+		}
+
+		count, err := repos.Strata().CountPlots(ctx, stratumID)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return apperr.WithFields(
+				apperr.New(apperr.CodeConflict, "stratum has plots"),
+				map[string]any{"plots": count},
+			)
+		}
+
+		if err := repos.Strata().Delete(ctx, id, stratumID); err != nil {
+			return err
+		}
+		return syncStrataArea(ctx, repos, id)
+	})
+}
@@ -0,0 +1,211 @@
+package phytometrics
+
+import (
+	"sort"
+
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+)
+
+const (
+	// DefaultCarbonFraction é a fração de carbono na biomassa seca (IPCC, 2006)
+	DefaultCarbonFraction = 0.47
+	// DefaultRootShootRatio é a razão raiz:parte aérea para florestas tropicais (IPCC, 2006)
+	DefaultRootShootRatio = 0.24
+	// DefaultWoodDensity é a densidade básica (g/cm³) usada para espécies sem valor cadastrado
+	DefaultWoodDensity = 0.6
+	// CO2PerCarbon converte carbono em CO2 equivalente (44/12)
+	CO2PerCarbon = 44.0 / 12.0
+
+	// DefaultBiomassEquationID é a equação alométrica usada quando a análise não seleciona outra
+	DefaultBiomassEquationID = "chave2014"
+)
+
+// Origem da densidade da madeira usada no cálculo
+const (
+	WoodDensitySourceSpecies = "species"
+	WoodDensitySourceDefault = "default"
+)
+
+// BiomassEquation retorna a equação alométrica da análise (a padrão quando não há seleção)
+func BiomassEquation(p *types.PhytoAnalysisComplete) *CompiledEquation {
+	if eq := compileOrNil(p.BiomassEquation); eq != nil {
+		return eq
+	}
+	eq, _ := BuiltinEquation(DefaultBiomassEquationID)
+	return eq
+}
+
+// ComputeDefaultBiomass estima biomassa e carbono com a equação da análise e os fatores padrão
+func ComputeDefaultBiomass(p *types.PhytoAnalysisComplete) *Biomass {
+	return ComputeBiomass(p, DefaultBiomassOptions(), BiomassEquation(p))
+}
+
+// BiomassOptions representa os parâmetros da estimativa de biomassa e carbono
+type BiomassOptions struct {
+	CarbonFraction     float64 // fração de carbono na biomassa seca
+	RootShootRatio     float64 // biomassa abaixo do solo / acima do solo
+	DefaultWoodDensity float64 // g/cm³, para espécies sem densidade cadastrada
+}
+
+// DefaultBiomassOptions retorna os fatores padrão do IPCC
+func DefaultBiomassOptions() BiomassOptions {
+	return BiomassOptions{
+		CarbonFraction:     DefaultCarbonFraction,
+		RootShootRatio:     DefaultRootShootRatio,
+		DefaultWoodDensity: DefaultWoodDensity,
+	}
+}
+
+// SpeciesBiomass representa a contribuição de uma espécie para a biomassa e o carbono
+type SpeciesBiomass struct {
+	ScientificName      string  `json:"scientificName"`
+	Family              string  `json:"family"`
+	Individuals         int     `json:"individuals"`
+	WoodDensity         float64 `json:"woodDensity"`       // g/cm³
+	WoodDensitySource   string  `json:"woodDensitySource"` // species | default
+	AbovegroundBiomassT float64 `json:"abovegroundBiomassT"`
+	TotalBiomassT       float64 `json:"totalBiomassT"`
+	CarbonT             float64 `json:"carbonT"`
+	CO2eT               float64 `json:"co2eT"`
+	CarbonShare         float64 `json:"carbonShare"` // % do carbono total
+}
+
+// Biomass representa a estimativa de biomassa e estoque de carbono da análise
+type Biomass struct {
+	EquationID         string  `json:"equationId"`
+	CarbonFraction     float64 `json:"carbonFraction"`
+	RootShootRatio     float64 `json:"rootShootRatio"`
+	DefaultWoodDensity float64 `json:"defaultWoodDensity"`
+
+	AbovegroundBiomassT float64 `json:"abovegroundBiomassT"` // Biomassa acima do solo (t)
+	BelowgroundBiomassT float64 `json:"belowgroundBiomassT"` // Biomassa abaixo do solo (t)
+	TotalBiomassT       float64 `json:"totalBiomassT"`       // Biomassa total (t)
+	CarbonT             float64 `json:"carbonT"`             // Estoque de carbono (t C)
+	CO2eT               float64 `json:"co2eT"`               // CO2 equivalente (t CO2e)
+
+	AbovegroundBiomassPerHa float64 `json:"abovegroundBiomassPerHa"` // t/ha
+	TotalBiomassPerHa       float64 `json:"totalBiomassPerHa"`       // t/ha
+	CarbonPerHa             float64 `json:"carbonPerHa"`             // t C/ha
+	CO2ePerHa               float64 `json:"co2ePerHa"`               // t CO2e/ha
+
+	// Estoque extrapolado para a área total (valores por hectare × área total)
+	TotalAreaCarbonT float64 `json:"totalAreaCarbonT"`
+	TotalAreaCO2eT   float64 `json:"totalAreaCo2eT"`
+
+	DefaultDensityIndividuals  int              `json:"defaultDensityIndividuals"`  // Indivíduos calculados com a densidade padrão
+	DefaultEquationIndividuals int              `json:"defaultEquationIndividuals"` // Indivíduos fora da aplicabilidade da equação (calculados com a padrão)
+	FailedEquationIndividuals  int              `json:"failedEquationIndividuals"`  // Indivíduos em que a equação não gerou valor válido (calculados com a padrão)
+	Species                    []SpeciesBiomass `json:"species"`                    // Ordenado por carbono (desc)
+}
+
+// ComputeBiomass estima biomassa e carbono dos espécimes com a equação informada.
+// Usa o DAP equivalente dos fustes (a partir da ABI); espécimes sem DAP são ignorados, e
+// espécimes fora da aplicabilidade da equação, ou em que ela não gera valor válido, usam a
+// equação padrão.
+func ComputeBiomass(p *types.PhytoAnalysisComplete, opts BiomassOptions, eq *CompiledEquation) *Biomass {
+	fallback, _ := BuiltinEquation(DefaultBiomassEquationID)
+	if eq == nil {
+		eq = fallback
+	}
+
+	out := &Biomass{
+		EquationID:         eq.Data.ID,
+		CarbonFraction:     opts.CarbonFraction,
+		RootShootRatio:     opts.RootShootRatio,
+		DefaultWoodDensity: opts.DefaultWoodDensity,
+		Species:            make([]SpeciesBiomass, 0),
+	}
+
+	bySpecies := make(map[string]*SpeciesBiomass)
+	for _, s := range p.Specimens {
+		dbh, g := DbhAndBasalFromABI(ABI(s))
+		if dbh <= 0 {
+			continue
+		}
+
+		specimenEq := eq
+		if !eq.AppliesToSpecimen(s) {
+			specimenEq = fallback
+			out.DefaultEquationIndividuals++
+		}
+
+		density, source := opts.DefaultWoodDensity, WoodDensitySourceDefault
+		if s.WoodDensity != nil && *s.WoodDensity > 0 {
+			density, source = *s.WoodDensity, WoodDensitySourceSpecies
+		}
+
+		in := EquationInput{
+			DbhCm:       dbh,
+			HeightM:     s.Height,
+			BasalAreaM2: g,
+			WoodDensity: density,
+			FormFactor:  ResolveFormFactor(s, p.DefaultFormFactor),
+		}
+		agbKg, err := specimenEq.Evaluate(in)
+		if (err != nil || agbKg < 0) && specimenEq != fallback {
+			specimenEq = fallback
+			out.FailedEquationIndividuals++
+			agbKg, err = specimenEq.Evaluate(in)
+		}
+		if source == WoodDensitySourceDefault && specimenEq.Uses(VarWoodDensity) {
+			out.DefaultDensityIndividuals++
+		}
+
+		agbT := agbKg / 1000
+		if err != nil || agbT < 0 {
+			agbT = 0
+		}
+
+		name := s.ScientificName
+		sp, ok := bySpecies[name]
+		if !ok {
+			family := s.Family
+			if family == "" {
+				family = UnknownFamily
+			}
+			sp = &SpeciesBiomass{ScientificName: name, Family: family, WoodDensity: density, WoodDensitySource: source}
+			bySpecies[name] = sp
+		}
+		sp.Individuals++
+		sp.AbovegroundBiomassT += agbT
+	}
+
+	names := make([]string, 0, len(bySpecies))
+	for name := range bySpecies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		sp := bySpecies[name]
+		sp.TotalBiomassT = sp.AbovegroundBiomassT * (1 + opts.RootShootRatio)
+		sp.CarbonT = sp.TotalBiomassT * opts.CarbonFraction
+		sp.CO2eT = sp.CarbonT * CO2PerCarbon
+
+		out.AbovegroundBiomassT += sp.AbovegroundBiomassT
+		out.TotalBiomassT += sp.TotalBiomassT
+		out.CarbonT += sp.CarbonT
+	}
+	out.BelowgroundBiomassT = out.TotalBiomassT - out.AbovegroundBiomassT
+	out.CO2eT = out.CarbonT * CO2PerCarbon
+
+	for _, name := range names {
+		sp := bySpecies[name]
+		if out.CarbonT > 0 {
+			sp.CarbonShare = sp.CarbonT / out.CarbonT * 100
+		}
+		out.Species = append(out.Species, *sp)
+	}
+	sort.SliceStable(out.Species, func(i, j int) bool { return out.Species[i].CarbonT > out.Species[j].CarbonT })
+
+	if p.SampledArea > 0 {
+		out.AbovegroundBiomassPerHa = out.AbovegroundBiomassT / p.SampledArea
+		out.TotalBiomassPerHa = out.TotalBiomassT / p.SampledArea
+		out.CarbonPerHa = out.CarbonT / p.SampledArea
+		out.CO2ePerHa = out.CO2eT / p.SampledArea
+		out.TotalAreaCarbonT = out.CarbonPerHa * p.TotalArea
+		out.TotalAreaCO2eT = out.CO2ePerHa * p.TotalArea
+	}
+
+	return out
+}
@@ -0,0 +1,165 @@
+package phytometrics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuiltinBiomassEquations(t *testing.T) {
+	t.Parallel()
+
+	eqs := BuiltinEquations(types.EquationKindBiomass)
+	require.GreaterOrEqual(t, len(eqs), 3)
+	for i := 1; i < len(eqs); i++ {
+		require.Less(t, eqs[i-1].ID, eqs[i].ID)
+		require.True(t, eqs[i].BuiltIn)
+	}
+
+	eq, ok := BuiltinEquation(DefaultBiomassEquationID)
+	require.True(t, ok)
+	require.True(t, eq.Uses(VarHeight))
+	require.True(t, eq.Uses(VarWoodDensity))
+
+	// Chave 2014: D = 20 cm, H = 15 m, ρ = 0,6 → ~199 kg
+	agb, err := eq.Evaluate(EquationInput{DbhCm: 20, HeightM: 15, WoodDensity: 0.6})
+	require.NoError(t, err)
+	require.InDelta(t, 0.0673*math.Pow(0.6*400*15, 0.976), agb, 1e-9)
+	require.InDelta(t, 199, agb, 1)
+
+	_, ok = BuiltinEquation("unknown")
+	require.False(t, ok)
+}
+
+func evalKg(t *testing.T, eq *CompiledEquation, in EquationInput) float64 {
+	t.Helper()
+	v, err := eq.Evaluate(in)
+	require.NoError(t, err)
+	return v
+}
+
+func TestComputeBiomass(t *testing.T) {
+	t.Parallel()
+
+	density := 0.8
+	// CAP = 20π cm → DAP = 20 cm
+	p := &types.PhytoAnalysisComplete{
+		SampledArea: 0.5,
+		TotalArea:   10,
+		Specimens: []*types.SpecimenWithSpecies{
+			{Portion: "1", Cap1: 20 * math.Pi, Height: 10, ScientificName: "A a", Family: "Fabaceae", WoodDensity: &density},
+			{Portion: "1", Cap1: 20 * math.Pi, Height: 10, ScientificName: "A a", Family: "Fabaceae", WoodDensity: &density},
+			{Portion: "2", Cap1: 20 * math.Pi, Height: 10, ScientificName: "B b"},
+			{Portion: "2", Cap1: 0, Height: 10, ScientificName: "C c"},
+		},
+	}
+
+	eq, _ := BuiltinEquation(DefaultBiomassEquationID)
+	b := ComputeBiomass(p, DefaultBiomassOptions(), nil)
+
+	agbA := 2 * evalKg(t, eq, EquationInput{DbhCm: 20, HeightM: 10, WoodDensity: 0.8}) / 1000
+	agbB := evalKg(t, eq, EquationInput{DbhCm: 20, HeightM: 10, WoodDensity: DefaultWoodDensity}) / 1000
+
+	require.Equal(t, DefaultBiomassEquationID, b.EquationID)
+	require.Equal(t, 1, b.DefaultDensityIndividuals)
+	require.InDelta(t, agbA+agbB, b.AbovegroundBiomassT, 1e-12)
+	require.InDelta(t, (agbA+agbB)*DefaultRootShootRatio, b.BelowgroundBiomassT, 1e-12)
+	require.InDelta(t, (agbA+agbB)*(1+DefaultRootShootRatio), b.TotalBiomassT, 1e-12)
+	require.InDelta(t, b.TotalBiomassT*DefaultCarbonFraction, b.CarbonT, 1e-12)
+	require.InDelta(t, b.CarbonT*44/12, b.CO2eT, 1e-12)
+	require.InDelta(t, b.CarbonT/0.5, b.CarbonPerHa, 1e-12)
+	require.InDelta(t, b.CarbonPerHa*10, b.TotalAreaCarbonT, 1e-12)
+
+	// Espécime sem CAP não entra; espécies ordenadas por carbono
+	require.Len(t, b.Species, 2)
+	require.Equal(t, "A a", b.Species[0].ScientificName)
+	require.Equal(t, 2, b.Species[0].Individuals)
+	require.Equal(t, WoodDensitySourceSpecies, b.Species[0].WoodDensitySource)
+	require.Equal(t, "B b", b.Species[1].ScientificName)
+	require.Equal(t, UnknownFamily, b.Species[1].Family)
+	require.Equal(t, WoodDensitySourceDefault, b.Species[1].WoodDensitySource)
+	require.InDelta(t, 100, b.Species[0].CarbonShare+b.Species[1].CarbonShare, 1e-9)
+}
+
+func TestComputeBiomass_EquationWithoutDensity(t *testing.T) {
+	t.Parallel()
+
+	p := &types.PhytoAnalysisComplete{
+		SampledArea: 1,
+		Specimens: []*types.SpecimenWithSpecies{
+			{Portion: "1", Cap1: 30 * math.Pi, Height: 12, ScientificName: "A a"},
+		},
+	}
+
+	eq, ok := BuiltinEquation("brown1997-moist")
+	require.True(t, ok)
+	b := ComputeBiomass(p, DefaultBiomassOptions(), eq)
+
+	require.Zero(t, b.DefaultDensityIndividuals)
+	require.InDelta(t, math.Exp(-2.134+2.530*math.Log(30))/1000, b.AbovegroundBiomassT, 1e-12)
+}
+
+func TestComputeBiomass_ApplicabilityFallback(t *testing.T) {
+	t.Parallel()
+
+	habit := "ARVORE"
+	p := &types.PhytoAnalysisComplete{
+		SampledArea: 1,
+		Specimens: []*types.SpecimenWithSpecies{
+			{Portion: "1", Cap1: 20 * math.Pi, Height: 10, ScientificName: "A a", Family: "Arecaceae"},
+			{Portion: "1", Cap1: 20 * math.Pi, Height: 10, ScientificName: "B b", Family: "Fabaceae", Habit: &habit},
+		},
+	}
+
+	palms, err := CompileEquation(types.EquationData{
+		ID:            "palms",
+		Name:          "Palmeiras",
+		Kind:          types.EquationKindBiomass,
+		Expression:    "b0 * h",
+		Coefficients:  map[string]float64{"b0": 10},
+		Applicability: types.EquationApplicability{Families: []string{"arecaceae"}},
+	})
+	require.NoError(t, err)
+
+	b := ComputeBiomass(p, DefaultBiomassOptions(), palms)
+
+	fallback, _ := BuiltinEquation(DefaultBiomassEquationID)
+	want := 100 + evalKg(t, fallback, EquationInput{DbhCm: 20, HeightM: 10, WoodDensity: DefaultWoodDensity})
+	require.Equal(t, "palms", b.EquationID)
+	require.Equal(t, 1, b.DefaultEquationIndividuals)
+	require.Equal(t, 1, b.DefaultDensityIndividuals) // apenas o indivíduo da equação padrão usa densidade
+	require.InDelta(t, want/1000, b.AbovegroundBiomassT, 1e-12)
+}
+
+func TestComputeBiomass_FailedEquationFallback(t *testing.T) {
+	t.Parallel()
+
+	p := &types.PhytoAnalysisComplete{
+		SampledArea: 1,
+		Specimens: []*types.SpecimenWithSpecies{
+			{Portion: "1", Cap1: 20 * math.Pi, Height: 10, ScientificName: "A a"},
+			{Portion: "1", Cap1: 20 * math.Pi, Height: 0, ScientificName: "B b"}, // ln(0): resultado não finito
+		},
+	}
+
+	logHeight, err := CompileEquation(types.EquationData{
+		ID:           "log-height",
+		Name:         "Logarítmica na altura",
+		Kind:         types.EquationKindBiomass,
+		Expression:   "b0 * ln(h)",
+		Coefficients: map[string]float64{"b0": 100},
+	})
+	require.NoError(t, err)
+
+	b := ComputeBiomass(p, DefaultBiomassOptions(), logHeight)
+
+	fallback, _ := BuiltinEquation(DefaultBiomassEquationID)
+	want := 100*math.Log(10) + evalKg(t, fallback, EquationInput{DbhCm: 20, HeightM: 0, WoodDensity: DefaultWoodDensity})
+	require.Equal(t, "log-height", b.EquationID)
+	require.Equal(t, 1, b.FailedEquationIndividuals)
+	require.Zero(t, b.DefaultEquationIndividuals)
+	require.Equal(t, 1, b.DefaultDensityIndividuals) // apenas o indivíduo da equação padrão usa densidade
+	require.InDelta(t, want/1000, b.AbovegroundBiomassT, 1e-12)
+}
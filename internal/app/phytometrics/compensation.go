@@ -0,0 +1,211 @@
+package phytometrics
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+)
+
+func boolPtr(v bool) *bool { return &v }
+
+// DefaultCompensationRules é o conjunto de regras usado quando a requisição não informa outro.
+// As regras são avaliadas em ordem: vale a primeira que atende a cada legislação da espécie.
+func DefaultCompensationRules() []types.CompensationRule {
+	return []types.CompensationRule{
+		{
+			ID:             "threatened-cr-en",
+			Description:    "Espécie criticamente em perigo (CR) ou em perigo (EN): 20 mudas por indivíduo suprimido",
+			ThreatStatuses: []string{"CR", "EN"},
+			Ratio:          20,
+		},
+		{
+			ID:             "threatened-vu",
+			Description:    "Espécie vulnerável (VU): 10 mudas por indivíduo suprimido",
+			ThreatStatuses: []string{"VU"},
+			Ratio:          10,
+		},
+		{
+			ID:          "protected",
+			Description: "Espécie protegida ou imune de corte: 5 mudas por indivíduo suprimido",
+			Protected:   boolPtr(true),
+			Ratio:       5,
+		},
+	}
+}
+
+// MaxCompensationRatio limita a razão de mudas por indivíduo suprimido
+const MaxCompensationRatio = 1000
+
+// ValidateCompensationRules confere identificação, critérios e razão de cada regra
+func ValidateCompensationRules(rules []types.CompensationRule) error {
+	if len(rules) == 0 {
+		return errors.New("at least one compensation rule is required")
+	}
+	seen := make(map[string]bool, len(rules))
+	for _, r := range rules {
+		if r.ID == "" {
+			return errors.New("compensation rule ID is required")
+		}
+		if seen[r.ID] {
+			return fmt.Errorf("duplicated compensation rule %q", r.ID)
+		}
+		seen[r.ID] = true
+		if r.Ratio <= 0 {
+			return fmt.Errorf("compensation rule %q: ratio must be positive", r.ID)
+		}
+		if r.Ratio > MaxCompensationRatio {
+			return fmt.Errorf("compensation rule %q: ratio must be at most %d", r.ID, MaxCompensationRatio)
+		}
+		switch r.LawScope {
+		case "", "FEDERAL", "STATE", "MUNICIPAL":
+		default:
+			return fmt.Errorf("compensation rule %q: law scope must be FEDERAL, STATE or MUNICIPAL", r.ID)
+		}
+		for _, status := range r.ThreatStatuses {
+			if _, ok := threatSeverityOrder[status]; !ok {
+				return fmt.Errorf("compensation rule %q: invalid threat status %q", r.ID, status)
+			}
+		}
+	}
+	return nil
+}
+
+// threatSeverityOrder lista os graus de ameaça aceitos nas regras
+var threatSeverityOrder = map[string]int{"CR": 0, "EN": 1, "VU": 2, "NT": 3, "LC": 4}
+
+// CompensationRuleMatches indica se a regra se aplica à legislação
+func CompensationRuleMatches(r types.CompensationRule, l types.SpeciesLegislationStatus) bool {
+	if r.LawScope != "" && r.LawScope != l.LawScope {
+		return false
+	}
+	if r.Protected != nil && *r.Protected != l.Protected {
+		return false
+	}
+	if len(r.ThreatStatuses) > 0 && !containsFold(r.ThreatStatuses, l.ThreatStatus) {
+		return false
+	}
+	return true
+}
+
+// matchCompensation retorna a regra de maior razão entre as legislações da espécie (a primeira
+// regra que atende a cada legislação) e a legislação que a acionou
+func matchCompensation(rules []types.CompensationRule, legislations []types.SpeciesLegislationStatus) (*types.CompensationRule, *types.SpeciesLegislationStatus) {
+	var rule *types.CompensationRule
+	var legislation *types.SpeciesLegislationStatus
+	for i := range legislations {
+		for j := range rules {
+			if !CompensationRuleMatches(rules[j], legislations[i]) {
+				continue
+			}
+			if rule == nil || rules[j].Ratio > rule.Ratio {
+				rule, legislation = &rules[j], &legislations[i]
+			}
+			break
+		}
+	}
+	return rule, legislation
+}
+
+// CompensationLine representa o plantio compensatório exigido para uma espécie suprimida
+type CompensationLine struct {
+	SpeciesID      string  `json:"speciesId"`
+	ScientificName string  `json:"scientificName"`
+	Family         string  `json:"family"`
+	PopularName    *string `json:"popularName,omitempty"`
+	Individuals    int     `json:"individuals"` // indivíduos vivos suprimidos na amostra
+	Ratio          float64 `json:"ratio"`       // mudas por indivíduo
+	Seedlings      int     `json:"seedlings"`   // mudas exigidas (arredondado para cima)
+	// Estimativa para a área total da análise (omitida sem área amostrada ou total)
+	EstimatedIndividuals *float64 `json:"estimatedIndividuals,omitempty"`
+	EstimatedSeedlings   *int     `json:"estimatedSeedlings,omitempty"`
+	// Regra e legislação que acionaram a compensação (para citação no relatório)
+	Rule        types.CompensationRule         `json:"rule"`
+	Legislation types.SpeciesLegislationStatus `json:"legislation"`
+}
+
+// Compensation representa o plantio compensatório exigido pela supressão da análise
+type Compensation struct {
+	Rules              []types.CompensationRule `json:"rules"`
+	Lines              []CompensationLine       `json:"lines"` // por mudas exigidas (desc)
+	TotalIndividuals   int                      `json:"totalIndividuals"`
+	TotalSeedlings     int                      `json:"totalSeedlings"`
+	EstimatedSeedlings *int                     `json:"estimatedSeedlings,omitempty"`
+	DeadIgnored        int                      `json:"deadIgnored"` // indivíduos mortos não entram na compensação
+}
+
+// ComputeCompensation calcula as mudas exigidas por espécie e no total. Cada espécie usa a regra
+// de maior razão entre as suas legislações vigentes; espécies sem regra aplicável não geram linha.
+func ComputeCompensation(p *types.PhytoAnalysisComplete, rules []types.CompensationRule) *Compensation {
+	out := &Compensation{Rules: rules, Lines: make([]CompensationLine, 0)}
+
+	byKey := make(map[string]*CompensationLine)
+	order := make([]string, 0)
+	for _, s := range p.Specimens {
+		if IsDead(s) {
+			out.DeadIgnored++
+			continue
+		}
+		rule, legislation := matchCompensation(rules, s.Legislations)
+		if rule == nil {
+			continue
+		}
+
+		key := speciesKey(s)
+		line, ok := byKey[key]
+		if !ok {
+			line = &CompensationLine{
+				SpeciesID:      s.SpecieID,
+				ScientificName: s.ScientificName,
+				Family:         s.Family,
+				PopularName:    s.PopularName,
+				Ratio:          rule.Ratio,
+				Rule:           *rule,
+				Legislation:    *legislation,
+			}
+			byKey[key] = line
+			order = append(order, key)
+		}
+		line.Individuals++
+	}
+
+	var expansion float64
+	if p.SampledArea > 0 && p.TotalArea > 0 {
+		expansion = p.TotalArea / p.SampledArea
+	}
+
+	estimatedTotal := 0
+	for _, key := range order {
+		line := byKey[key]
+		line.Seedlings = seedlings(float64(line.Individuals), line.Ratio)
+		out.TotalIndividuals += line.Individuals
+		out.TotalSeedlings += line.Seedlings
+		if expansion > 0 {
+			individuals := float64(line.Individuals) * expansion
+			estimated := seedlings(individuals, line.Ratio)
+			line.EstimatedIndividuals = &individuals
+			line.EstimatedSeedlings = &estimated
+			estimatedTotal += estimated
+		}
+		out.Lines = append(out.Lines, *line)
+	}
+	if expansion > 0 {
+		out.EstimatedSeedlings = &estimatedTotal
+	}
+
+	sort.SliceStable(out.Lines, func(i, j int) bool {
+		if out.Lines[i].Seedlings != out.Lines[j].Seedlings {
+			return out.Lines[i].Seedlings > out.Lines[j].Seedlings
+		}
+		return out.Lines[i].ScientificName < out.Lines[j].ScientificName
+	})
+
+	return out
+}
+
+// seedlings arredonda para cima as mudas exigidas (tolerância para erros de ponto flutuante)
+func seedlings(individuals, ratio float64) int {
+	return int(math.Ceil(individuals*ratio - 1e-9))
+}
@@ -0,0 +1,90 @@
+package phytometrics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeCompensation(t *testing.T) {
+	t.Parallel()
+
+	law := "Lei 20.308/2012"
+	federalEN := types.SpeciesLegislationStatus{LawScope: "FEDERAL", ThreatStatus: "EN"}
+	stateProtected := types.SpeciesLegislationStatus{LawScope: "STATE", LawID: &law, Protected: true, ThreatStatus: "LC"}
+	stateVU := types.SpeciesLegislationStatus{LawScope: "STATE", ThreatStatus: "VU"}
+	common := types.SpeciesLegislationStatus{LawScope: "FEDERAL", ThreatStatus: "LC"}
+
+	specimen := func(name, status string, legislations ...types.SpeciesLegislationStatus) *types.SpecimenWithSpecies {
+		return &types.SpecimenWithSpecies{
+			Portion: "1", Cap1: 20 * math.Pi, Height: 10, ScientificName: name, Status: status,
+			Legislations: legislations,
+		}
+	}
+
+	p := &types.PhytoAnalysisComplete{
+		SampledArea: 0.5,
+		TotalArea:   1,
+		Specimens: []*types.SpecimenWithSpecies{
+			// protegida no estado e ameaçada (EN) na lista federal: vale a maior razão
+			specimen("Handroanthus serratifolius", "alive", stateProtected, federalEN),
+			specimen("Handroanthus serratifolius", "alive", stateProtected, federalEN),
+			specimen("Handroanthus serratifolius", "dead_standing", stateProtected, federalEN),
+			specimen("Ocotea odorifera", "alive", stateVU),
+			specimen("Ipê comum", "alive", stateProtected),
+			specimen("Sem restrição", "alive", common),
+			specimen("Sem legislação", "alive"),
+		},
+	}
+
+	out := ComputeCompensation(p, DefaultCompensationRules())
+
+	require.Equal(t, 1, out.DeadIgnored)
+	require.Len(t, out.Lines, 3)
+
+	first := out.Lines[0]
+	require.Equal(t, "Handroanthus serratifolius", first.ScientificName)
+	require.Equal(t, 2, first.Individuals)
+	require.Equal(t, 40, first.Seedlings)
+	require.Equal(t, "threatened-cr-en", first.Rule.ID)
+	require.Equal(t, "FEDERAL", first.Legislation.LawScope)
+	require.Equal(t, 80, *first.EstimatedSeedlings)
+
+	require.Equal(t, "threatened-vu", out.Lines[1].Rule.ID)
+	require.Equal(t, 10, out.Lines[1].Seedlings)
+
+	require.Equal(t, "protected", out.Lines[2].Rule.ID)
+	require.Equal(t, &law, out.Lines[2].Legislation.LawID)
+
+	require.Equal(t, 4, out.TotalIndividuals)
+	require.Equal(t, 55, out.TotalSeedlings)
+	require.Equal(t, 110, *out.EstimatedSeedlings)
+}
+
+func TestCompensationRules_ScopeAndValidation(t *testing.T) {
+	t.Parallel()
+
+	rules := []types.CompensationRule{
+		{ID: "municipal", Description: "Imune de corte municipal", LawScope: "MUNICIPAL", Protected: boolPtr(true), Ratio: 1.5},
+	}
+	require.NoError(t, ValidateCompensationRules(rules))
+
+	p := &types.PhytoAnalysisComplete{Specimens: []*types.SpecimenWithSpecies{
+		{ScientificName: "A a", Status: "alive", Legislations: []types.SpeciesLegislationStatus{{LawScope: "MUNICIPAL", Protected: true, ThreatStatus: "LC"}}},
+		{ScientificName: "B b", Status: "alive", Legislations: []types.SpeciesLegislationStatus{{LawScope: "STATE", Protected: true, ThreatStatus: "LC"}}},
+	}}
+	out := ComputeCompensation(p, rules)
+	require.Len(t, out.Lines, 1)
+	require.Equal(t, 2, out.Lines[0].Seedlings) // 1,5 muda arredondada para cima
+	require.Nil(t, out.EstimatedSeedlings)
+
+	require.Error(t, ValidateCompensationRules(nil))
+	require.Error(t, ValidateCompensationRules([]types.CompensationRule{{ID: "a", Ratio: 0}}))
+	require.Error(t, ValidateCompensationRules([]types.CompensationRule{{ID: "a", Ratio: MaxCompensationRatio + 1}}))
+	require.Error(t, ValidateCompensationRules([]types.CompensationRule{{ID: "a", Ratio: math.Inf(1)}}))
+	require.Error(t, ValidateCompensationRules([]types.CompensationRule{{ID: "a", Ratio: 1, LawScope: "COUNTY"}}))
+	require.Error(t, ValidateCompensationRules([]types.CompensationRule{{ID: "a", Ratio: 1, ThreatStatuses: []string{"XX"}}}))
+	require.Error(t, ValidateCompensationRules([]types.CompensationRule{{ID: "a", Ratio: 1}, {ID: "a", Ratio: 2}}))
+}
@@ -0,0 +1,218 @@
+package phytometrics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+)
+
+// Padrões das classes de distribuição (DAP em cm, altura em m)
+const (
+	DefaultDbhClassWidth    = 5.0
+	DefaultDbhMin           = 5.0
+	DefaultHeightClassWidth = 2.0
+	DefaultHeightMin        = 0.0
+)
+
+// Limites das classes: a amplitude mínima e o número máximo de classes por distribuição
+// (da primeira classe até a do maior valor observado)
+const (
+	MinClassWidth          = 0.1
+	MaxDistributionClasses = 500
+)
+
+// ClassDistributionOptions define a amplitude e o limite inferior das classes
+type ClassDistributionOptions struct {
+	DbhClassWidth    float64 // amplitude da classe de DAP (cm)
+	DbhMin           float64 // limite inferior da primeira classe de DAP (cm)
+	HeightClassWidth float64 // amplitude da classe de altura (m)
+	HeightMin        float64 // limite inferior da primeira classe de altura (m)
+}
+
+// DefaultClassDistributionOptions retorna as opções padrão das distribuições
+func DefaultClassDistributionOptions() ClassDistributionOptions {
+	return ClassDistributionOptions{
+		DbhClassWidth:    DefaultDbhClassWidth,
+		DbhMin:           DefaultDbhMin,
+		HeightClassWidth: DefaultHeightClassWidth,
+		HeightMin:        DefaultHeightMin,
+	}
+}
+
+// SpeciesClassData representa a participação de uma espécie em uma classe
+type SpeciesClassData struct {
+	ScientificName string  `json:"scientificName"`
+	Individuals    int     `json:"individuals"`    // Número de indivíduos na classe
+	DensityIndHa   float64 `json:"densityIndHa"`   // Indivíduos por hectare
+	BasalAreaPerHa float64 `json:"basalAreaPerHa"` // Área basal (m²/ha)
+}
+
+// DistributionClass representa uma classe [LowerLimit, UpperLimit)
+type DistributionClass struct {
+	LowerLimit     float64            `json:"lowerLimit"`
+	UpperLimit     float64            `json:"upperLimit"`
+	Center         float64            `json:"center"`         // Centro de classe
+	Individuals    int                `json:"individuals"`    // Número de indivíduos na classe
+	DensityIndHa   float64            `json:"densityIndHa"`   // Indivíduos por hectare
+	BasalAreaM2    float64            `json:"basalAreaM2"`    // Área basal amostrada (m²)
+	BasalAreaPerHa float64            `json:"basalAreaPerHa"` // Área basal (m²/ha)
+	Species        []SpeciesClassData `json:"species"`        // Distribuição por espécie na classe
+}
+
+// ClassDistribution representa a distribuição dos indivíduos em classes de um atributo
+type ClassDistribution struct {
+	ClassWidth   float64             `json:"classWidth"`
+	MinValue     float64             `json:"minValue"`
+	BelowMinimum int                 `json:"belowMinimum"` // Indivíduos abaixo do limite inferior (fora das classes)
+	Classes      []DistributionClass `json:"classes"`
+}
+
+// ClassDistributions representa as distribuições diamétrica e hipsométrica da análise
+type ClassDistributions struct {
+	SampledAreaHa float64           `json:"sampledAreaHa"`
+	Dbh           ClassDistribution `json:"dbh"`    // Distribuição diamétrica (cm)
+	Height        ClassDistribution `json:"height"` // Distribuição de altura (m)
+}
+
+// classItem é um indivíduo com o valor usado na classificação
+type classItem struct {
+	value          float64
+	basalM2        float64
+	scientificName string
+}
+
+// ComputeClassDistributions calcula as distribuições de DAP e altura da análise. Retorna erro
+// quando uma distribuição passaria de MaxDistributionClasses classes.
+func ComputeClassDistributions(p *types.PhytoAnalysisComplete, opts ClassDistributionOptions) (*ClassDistributions, error) {
+	dbhItems := make([]classItem, 0, len(p.Specimens))
+	heightItems := make([]classItem, 0, len(p.Specimens))
+
+	for _, s := range p.Specimens {
+		abi := ABI(s)
+		if abi <= 0 {
+			continue
+		}
+		dbhCm, basalM2 := DbhAndBasalFromABI(abi)
+
+		dbhItems = append(dbhItems, classItem{value: dbhCm, basalM2: basalM2, scientificName: s.ScientificName})
+		if s.Height > 0 {
+			heightItems = append(heightItems, classItem{value: s.Height, basalM2: basalM2, scientificName: s.ScientificName})
+		}
+	}
+
+	if err := checkClassCount("dbh", dbhItems, opts.DbhClassWidth, opts.DbhMin); err != nil {
+		return nil, err
+	}
+	if err := checkClassCount("height", heightItems, opts.HeightClassWidth, opts.HeightMin); err != nil {
+		return nil, err
+	}
+
+	return &ClassDistributions{
+		SampledAreaHa: p.SampledArea,
+		Dbh:           buildClassDistribution(dbhItems, opts.DbhClassWidth, opts.DbhMin, p.SampledArea),
+		Height:        buildClassDistribution(heightItems, opts.HeightClassWidth, opts.HeightMin, p.SampledArea),
+	}, nil
+}
+
+// checkClassCount verifica se a distribuição cabe em MaxDistributionClasses classes
+func checkClassCount(name string, items []classItem, width, minValue float64) error {
+	if width <= 0 {
+		return nil
+	}
+	maxValue := math.Inf(-1)
+	for _, it := range items {
+		maxValue = math.Max(maxValue, it.value)
+	}
+	if maxValue < minValue {
+		return nil
+	}
+	if math.Floor((maxValue-minValue)/width) >= MaxDistributionClasses {
+		return fmt.Errorf("%s distribution exceeds %d classes: increase the class width or the minimum", name, MaxDistributionClasses)
+	}
+	return nil
+}
+
+// buildClassDistribution agrupa os itens em classes contínuas de amplitude fixa, a partir de minValue
+// até a classe do maior valor observado (classes vazias são mantidas para o gráfico)
+func buildClassDistribution(items []classItem, width, minValue, sampledAreaHa float64) ClassDistribution {
+	out := ClassDistribution{
+		ClassWidth: width,
+		MinValue:   minValue,
+		Classes:    make([]DistributionClass, 0),
+	}
+	if width <= 0 {
+		return out
+	}
+
+	type speciesAcc struct {
+		individuals int
+		basalM2     float64
+	}
+	type classAcc struct {
+		individuals int
+		basalM2     float64
+		species     map[string]*speciesAcc
+	}
+
+	accs := make([]*classAcc, 0)
+	for _, it := range items {
+		if it.value < minValue {
+			out.BelowMinimum++
+			continue
+		}
+
+		idx := int(math.Floor((it.value - minValue) / width))
+		for len(accs) <= idx {
+			accs = append(accs, &classAcc{species: make(map[string]*speciesAcc)})
+		}
+
+		acc := accs[idx]
+		acc.individuals++
+		acc.basalM2 += it.basalM2
+
+		sp, ok := acc.species[it.scientificName]
+		if !ok {
+			sp = &speciesAcc{}
+			acc.species[it.scientificName] = sp
+		}
+		sp.individuals++
+		sp.basalM2 += it.basalM2
+	}
+
+	for i, acc := range accs {
+		lower := minValue + float64(i)*width
+		class := DistributionClass{
+			LowerLimit:  lower,
+			UpperLimit:  lower + width,
+			Center:      lower + width/2,
+			Individuals: acc.individuals,
+			BasalAreaM2: acc.basalM2,
+			Species:     make([]SpeciesClassData, 0, len(acc.species)),
+		}
+		if sampledAreaHa > 0 {
+			class.DensityIndHa = float64(acc.individuals) / sampledAreaHa
+			class.BasalAreaPerHa = acc.basalM2 / sampledAreaHa
+		}
+
+		for name, sp := range acc.species {
+			data := SpeciesClassData{ScientificName: name, Individuals: sp.individuals}
+			if sampledAreaHa > 0 {
+				data.DensityIndHa = float64(sp.individuals) / sampledAreaHa
+				data.BasalAreaPerHa = sp.basalM2 / sampledAreaHa
+			}
+			class.Species = append(class.Species, data)
+		}
+		sort.Slice(class.Species, func(a, b int) bool {
+			if class.Species[a].Individuals != class.Species[b].Individuals {
+				return class.Species[a].Individuals > class.Species[b].Individuals
+			}
+			return class.Species[a].ScientificName < class.Species[b].ScientificName
+		})
+
+		out.Classes = append(out.Classes, class)
+	}
+
+	return out
+}
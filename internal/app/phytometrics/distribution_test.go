@@ -0,0 +1,74 @@
+package phytometrics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+	"github.com/stretchr/testify/require"
+)
+
+// capForDbh retorna o CAP (cm) correspondente a um DAP (cm)
+func capForDbh(dbh float64) float64 { return dbh * math.Pi }
+
+func TestComputeClassDistributions(t *testing.T) {
+	t.Parallel()
+
+	p := &types.PhytoAnalysisComplete{
+		SampledArea: 0.5,
+		Specimens: []*types.SpecimenWithSpecies{
+			{Cap1: capForDbh(3), Height: 2, ScientificName: "A a"},  // abaixo do mínimo de DAP
+			{Cap1: capForDbh(6), Height: 4, ScientificName: "A a"},  // classe 5-10
+			{Cap1: capForDbh(7), Height: 5, ScientificName: "B b"},  // classe 5-10
+			{Cap1: capForDbh(22), Height: 9, ScientificName: "A a"}, // classe 20-25
+		},
+	}
+
+	out, err := ComputeClassDistributions(p, DefaultClassDistributionOptions())
+	require.NoError(t, err)
+
+	require.Equal(t, 1, out.Dbh.BelowMinimum)
+	require.Len(t, out.Dbh.Classes, 4) // 5-10, 10-15 (vazia), 15-20 (vazia), 20-25
+	first := out.Dbh.Classes[0]
+	require.InDelta(t, 5, first.LowerLimit, 1e-9)
+	require.InDelta(t, 10, first.UpperLimit, 1e-9)
+	require.InDelta(t, 7.5, first.Center, 1e-9)
+	require.Equal(t, 2, first.Individuals)
+	require.InDelta(t, 4, first.DensityIndHa, 1e-9)
+	require.Len(t, first.Species, 2)
+	require.Equal(t, 0, out.Dbh.Classes[1].Individuals)
+	require.Equal(t, 1, out.Dbh.Classes[3].Individuals)
+
+	g22 := math.Pi * 22 * 22 / 4 / 10000
+	require.InDelta(t, g22/0.5, out.Dbh.Classes[3].BasalAreaPerHa, 1e-9)
+
+	// altura: classes de 2 m a partir de 0 -> 2-4, 4-6 (2 ind.), 8-10
+	require.Equal(t, 0, out.Height.BelowMinimum)
+	require.Len(t, out.Height.Classes, 5)
+	require.Equal(t, 0, out.Height.Classes[0].Individuals)
+	require.Equal(t, 1, out.Height.Classes[1].Individuals)
+	require.Equal(t, 2, out.Height.Classes[2].Individuals)
+	require.Equal(t, 1, out.Height.Classes[4].Individuals)
+}
+
+func TestComputeClassDistributions_TooManyClasses(t *testing.T) {
+	t.Parallel()
+
+	p := &types.PhytoAnalysisComplete{
+		SampledArea: 1,
+		Specimens: []*types.SpecimenWithSpecies{
+			{Cap1: capForDbh(10), Height: 5, ScientificName: "A a"},
+			{Cap1: capForDbh(5000), Height: 5, ScientificName: "A a"}, // valor discrepante
+		},
+	}
+
+	opts := DefaultClassDistributionOptions()
+	opts.DbhClassWidth = MinClassWidth
+	_, err := ComputeClassDistributions(p, opts)
+	require.Error(t, err)
+
+	opts.DbhClassWidth = 50
+	out, err := ComputeClassDistributions(p, opts)
+	require.NoError(t, err)
+	require.LessOrEqual(t, len(out.Dbh.Classes), MaxDistributionClasses)
+}
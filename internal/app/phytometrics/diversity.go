@@ -0,0 +1,322 @@
+package phytometrics
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+)
+
+// Diversity agrega os índices calculados a partir das abundâncias por espécie
+type Diversity struct {
+	Individuals int
+	Species     int
+	Shannon     float64 // H' = -Σ(p_i × ln(p_i))
+	SumPLn2     float64 // Σ(p_i × ln²(p_i)), usado na variância de Hutcheson
+	Simpson     float64 // D = Σ(p_i²)
+}
+
+// SpeciesAbundances conta os indivíduos por espécie, ignorando espécimes sem espécie
+func SpeciesAbundances(specimens []*types.SpecimenWithSpecies) map[string]int {
+	counts := make(map[string]int)
+	for _, s := range specimens {
+		if s.ScientificName != "" {
+			counts[s.ScientificName]++
+		}
+	}
+	return counts
+}
+
+// SortedSpeciesNames retorna as espécies em ordem alfabética
+func SortedSpeciesNames(counts map[string]int) []string {
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ComputeDiversity calcula os índices a partir das abundâncias
+func ComputeDiversity(counts map[string]int) Diversity {
+	d := Diversity{Species: len(counts)}
+	for _, n := range counts {
+		d.Individuals += n
+	}
+	if d.Individuals == 0 {
+		return d
+	}
+
+	// soma em ordem estável para que o resultado não dependa da iteração do map
+	total := float64(d.Individuals)
+	for _, name := range SortedSpeciesNames(counts) {
+		n := counts[name]
+		if n <= 0 {
+			continue
+		}
+		p := float64(n) / total
+		lnP := math.Log(p)
+		d.Shannon -= p * lnP
+		d.SumPLn2 += p * lnP * lnP
+		d.Simpson += p * p
+	}
+	return d
+}
+
+// ShannonVariance retorna a variância de H' segundo Hutcheson (1970)
+//
+//	Var(H') = [Σ p_i ln²p_i - (Σ p_i ln p_i)²] / N + (S - 1) / 2N²
+func (d Diversity) ShannonVariance() float64 {
+	if d.Individuals == 0 {
+		return 0
+	}
+	n := float64(d.Individuals)
+	return (d.SumPLn2-d.Shannon*d.Shannon)/n + float64(d.Species-1)/(2*n*n)
+}
+
+// Pielou retorna J' = H'/ln(S), indefinido para menos de duas espécies
+func (d Diversity) Pielou() *float64 {
+	if d.Species <= 1 {
+		return nil
+	}
+	v := d.Shannon / math.Log(float64(d.Species))
+	return &v
+}
+
+// InverseSimpson retorna 1/D
+func (d Diversity) InverseSimpson() *float64 {
+	if d.Simpson <= 0 {
+		return nil
+	}
+	v := 1 / d.Simpson
+	return &v
+}
+
+// Margalef retorna (S-1)/ln(N)
+func (d Diversity) Margalef() *float64 {
+	if d.Individuals <= 1 {
+		return nil
+	}
+	v := float64(d.Species-1) / math.Log(float64(d.Individuals))
+	return &v
+}
+
+// Menhinick retorna S/√N
+func (d Diversity) Menhinick() *float64 {
+	if d.Individuals == 0 {
+		return nil
+	}
+	v := float64(d.Species) / math.Sqrt(float64(d.Individuals))
+	return &v
+}
+
+// Padrões das estatísticas de diversidade
+const (
+	DefaultDiversityProbability = 0.95
+	DefaultBootstrapIterations  = 1000
+	MaxBootstrapIterations      = 10000
+)
+
+// DiversityOptions define o nível de confiança e o bootstrap do H'
+type DiversityOptions struct {
+	Probability         float64 // ex.: 0.95
+	BootstrapIterations int
+	Seed                int64 // mesma semente e mesmos dados geram o mesmo intervalo
+}
+
+// ShannonEstimate representa o H' com variância e intervalos de confiança
+type ShannonEstimate struct {
+	Value             float64 `json:"value"`             // H' (ln)
+	Variance          float64 `json:"variance"`          // Variância analítica (Hutcheson)
+	StdError          float64 `json:"stdError"`          // Erro padrão analítico
+	Lower             float64 `json:"lower"`             // IC analítico (limite inferior)
+	Upper             float64 `json:"upper"`             // IC analítico (limite superior)
+	BootstrapStdError float64 `json:"bootstrapStdError"` // Erro padrão entre as reamostragens
+	BootstrapLower    float64 `json:"bootstrapLower"`    // IC por percentis do bootstrap (limite inferior)
+	BootstrapUpper    float64 `json:"bootstrapUpper"`    // IC por percentis do bootstrap (limite superior)
+}
+
+// HillNumbers representa a diversidade verdadeira de ordem q (número efetivo de espécies)
+type HillNumbers struct {
+	Q0 float64 `json:"q0"` // riqueza
+	Q1 float64 `json:"q1"` // exp(H')
+	Q2 float64 `json:"q2"` // 1/D
+}
+
+// DiversityStatistics representa os índices de diversidade da análise e suas incertezas
+type DiversityStatistics struct {
+	Probability         float64 `json:"probability"`
+	BootstrapIterations int     `json:"bootstrapIterations"`
+	Seed                int64   `json:"seed"`
+	IndividualsCount    int     `json:"individualsCount"`
+	SpeciesCount        int     `json:"speciesCount"`
+
+	Shannon        ShannonEstimate `json:"shannon"`
+	Simpson        float64         `json:"simpson"`                  // D = Σ(p_i²)
+	InverseSimpson *float64        `json:"inverseSimpson,omitempty"` // 1/D
+	Pielou         *float64        `json:"pielou,omitempty"`         // J' = H'/ln(S)
+	Margalef       *float64        `json:"margalef,omitempty"`       // (S-1)/ln(N)
+	Menhinick      *float64        `json:"menhinick,omitempty"`      // S/√N
+	Hill           HillNumbers     `json:"hill"`
+}
+
+// DiversitySample representa os dados de uma análise usados no teste de Hutcheson
+type DiversitySample struct {
+	AnalysisID       string  `json:"analysisId"`
+	Title            string  `json:"title"`
+	IndividualsCount int     `json:"individualsCount"`
+	SpeciesCount     int     `json:"speciesCount"`
+	Shannon          float64 `json:"shannon"`
+	ShannonVariance  float64 `json:"shannonVariance"`
+}
+
+// DiversityComparison representa o teste t de Hutcheson entre os H' de duas análises
+type DiversityComparison struct {
+	Probability      float64         `json:"probability"`
+	First            DiversitySample `json:"first"`
+	Second           DiversitySample `json:"second"`
+	Difference       float64         `json:"difference"`       // H'1 - H'2
+	TStatistic       float64         `json:"tStatistic"`       // t calculado
+	DegreesOfFreedom float64         `json:"degreesOfFreedom"` // gl de Hutcheson
+	CriticalT        float64         `json:"criticalT"`        // t tabelado bicaudal
+	PValue           float64         `json:"pValue"`           // bicaudal
+	Significant      bool            `json:"significant"`      // diferença significativa ao nível informado
+}
+
+// ComputeDiversityStatistics calcula os índices de diversidade com o IC analítico e por bootstrap do H'
+func ComputeDiversityStatistics(p *types.PhytoAnalysisComplete, opts DiversityOptions) *DiversityStatistics {
+	counts := SpeciesAbundances(p.Specimens)
+	d := ComputeDiversity(counts)
+
+	out := &DiversityStatistics{
+		Probability:         opts.Probability,
+		BootstrapIterations: opts.BootstrapIterations,
+		Seed:                opts.Seed,
+		IndividualsCount:    d.Individuals,
+		SpeciesCount:        d.Species,
+		Simpson:             d.Simpson,
+		InverseSimpson:      d.InverseSimpson(),
+		Pielou:              d.Pielou(),
+		Margalef:            d.Margalef(),
+		Menhinick:           d.Menhinick(),
+		Hill: HillNumbers{
+			Q0: float64(d.Species),
+			Q1: math.Exp(d.Shannon),
+		},
+	}
+	if d.Simpson > 0 {
+		out.Hill.Q2 = 1 / d.Simpson
+	}
+
+	variance := d.ShannonVariance()
+	out.Shannon = ShannonEstimate{
+		Value:    d.Shannon,
+		Variance: variance,
+		StdError: math.Sqrt(variance),
+		Lower:    d.Shannon,
+		Upper:    d.Shannon,
+	}
+	if d.Individuals > 1 {
+		margin := studentTTwoTailed(opts.Probability, d.Individuals-1) * out.Shannon.StdError
+		out.Shannon.Lower = math.Max(0, d.Shannon-margin)
+		out.Shannon.Upper = d.Shannon + margin
+	}
+
+	out.Shannon.BootstrapStdError, out.Shannon.BootstrapLower, out.Shannon.BootstrapUpper =
+		bootstrapShannon(counts, opts)
+
+	return out
+}
+
+// bootstrapShannon reamostra os N indivíduos com reposição e retorna o desvio padrão e o
+// intervalo por percentis dos H' obtidos. Cada reamostragem sorteia as contagens das espécies
+// (multinomial por binomiais sucessivas), com custo por espécie e não por indivíduo.
+func bootstrapShannon(counts map[string]int, opts DiversityOptions) (stdErr, lower, upper float64) {
+	// ordem estável das espécies para que a semente reproduza o resultado
+	names := SortedSpeciesNames(counts)
+
+	n := 0
+	for _, name := range names {
+		n += counts[name]
+	}
+	if n == 0 || opts.BootstrapIterations <= 0 {
+		return 0, 0, 0
+	}
+
+	rng := rand.New(rand.NewSource(opts.Seed))
+	values := make([]float64, opts.BootstrapIterations)
+
+	for it := range values {
+		var h float64
+		remaining, mass := n, n
+		for _, name := range names {
+			if remaining == 0 {
+				break
+			}
+			c := counts[name]
+			x := binomialSample(rng, remaining, float64(c)/float64(mass))
+			remaining -= x
+			mass -= c
+			if x > 0 {
+				p := float64(x) / float64(n)
+				h -= p * math.Log(p)
+			}
+		}
+		values[it] = h
+	}
+
+	_, variance := MeanAndVariance(values)
+	sort.Float64s(values)
+	alpha := (1 - opts.Probability) / 2
+
+	return math.Sqrt(variance), percentile(values, alpha), percentile(values, 1-alpha)
+}
+
+// CompareDiversity compara o H' de duas análises pelo teste t de Hutcheson
+//
+//	t = (H'1 - H'2) / √(Var1 + Var2)
+//	gl = (Var1 + Var2)² / (Var1²/N1 + Var2²/N2)
+func CompareDiversity(first, second *types.PhytoAnalysisComplete, probability float64) *DiversityComparison {
+	a := ComputeDiversity(SpeciesAbundances(first.Specimens))
+	b := ComputeDiversity(SpeciesAbundances(second.Specimens))
+
+	out := &DiversityComparison{
+		Probability: probability,
+		First:       toDiversitySample(first, a),
+		Second:      toDiversitySample(second, b),
+		Difference:  a.Shannon - b.Shannon,
+		PValue:      1,
+	}
+
+	varA, varB := out.First.ShannonVariance, out.Second.ShannonVariance
+	sumVar := varA + varB
+	if a.Individuals == 0 || b.Individuals == 0 || sumVar <= 0 {
+		return out
+	}
+
+	out.TStatistic = out.Difference / math.Sqrt(sumVar)
+	out.DegreesOfFreedom = sumVar * sumVar / (varA*varA/float64(a.Individuals) + varB*varB/float64(b.Individuals))
+
+	// t tabelado com gl arredondado para baixo (conservador)
+	df := int(math.Floor(out.DegreesOfFreedom))
+	if df < 1 {
+		df = 1
+	}
+	out.CriticalT = studentTTwoTailed(probability, df)
+	out.PValue = 2 * (1 - studentTCDF(math.Abs(out.TStatistic), out.DegreesOfFreedom))
+	out.Significant = out.PValue < 1-probability
+
+	return out
+}
+
+func toDiversitySample(p *types.PhytoAnalysisComplete, d Diversity) DiversitySample {
+	return DiversitySample{
+		AnalysisID:       p.ID,
+		Title:            p.Title,
+		IndividualsCount: d.Individuals,
+		SpeciesCount:     d.Species,
+		Shannon:          d.Shannon,
+		ShannonVariance:  d.ShannonVariance(),
+	}
+}
@@ -0,0 +1,136 @@
+package phytometrics
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+	"github.com/stretchr/testify/require"
+)
+
+// diversityFixture cria uma análise com a abundância informada por espécie
+func diversityFixture(id string, counts map[string]int) *types.PhytoAnalysisComplete {
+	p := &types.PhytoAnalysisComplete{ID: id, PortionQuantity: 1, PortionArea: 10000}
+	for name, n := range counts {
+		for i := 0; i < n; i++ {
+			p.Specimens = append(p.Specimens, &types.SpecimenWithSpecies{Portion: "1", Cap1: 50, ScientificName: name})
+		}
+	}
+	return p
+}
+
+func TestComputeDiversityStatistics(t *testing.T) {
+	t.Parallel()
+
+	// p = 0.5, 0.25, 0.25 -> H' = 1,5 ln 2; D = 0,375
+	p := diversityFixture("a", map[string]int{"A a": 2, "B b": 1, "C c": 1})
+	opts := DiversityOptions{Probability: 0.95, BootstrapIterations: 200, Seed: 7}
+
+	resp := ComputeDiversityStatistics(p, opts)
+	ln2 := math.Log(2)
+
+	require.Equal(t, 4, resp.IndividualsCount)
+	require.Equal(t, 3, resp.SpeciesCount)
+	require.InDelta(t, 1.5*ln2, resp.Shannon.Value, 1e-9)
+	require.InDelta(t, (ln2*ln2+1)/16, resp.Shannon.Variance, 1e-9)
+	require.InDelta(t, 0.375, resp.Simpson, 1e-9)
+	require.InDelta(t, 1/0.375, *resp.InverseSimpson, 1e-9)
+	require.InDelta(t, 2/math.Log(4), *resp.Margalef, 1e-9)
+	require.InDelta(t, 1.5, *resp.Menhinick, 1e-9)
+	require.InDelta(t, 1.5*ln2/math.Log(3), *resp.Pielou, 1e-9)
+	require.InDelta(t, 3, resp.Hill.Q0, 1e-9)
+	require.InDelta(t, math.Exp(1.5*ln2), resp.Hill.Q1, 1e-9)
+	require.InDelta(t, 1/0.375, resp.Hill.Q2, 1e-9)
+
+	require.LessOrEqual(t, resp.Shannon.Lower, resp.Shannon.Value)
+	require.GreaterOrEqual(t, resp.Shannon.Upper, resp.Shannon.Value)
+	require.LessOrEqual(t, resp.Shannon.BootstrapLower, resp.Shannon.BootstrapUpper)
+	require.Positive(t, resp.Shannon.BootstrapStdError)
+
+	// mesma semente reproduz o bootstrap
+	require.Equal(t, resp, ComputeDiversityStatistics(p, opts))
+}
+
+func TestBinomialSample(t *testing.T) {
+	t.Parallel()
+
+	rng := rand.New(rand.NewSource(3))
+	// inversão exata, simetria (p > 0,5) e aproximação normal
+	for _, c := range []struct {
+		n int
+		p float64
+	}{{40, 0.2}, {50, 0.9}, {10000, 0.3}} {
+		values := make([]float64, 20000)
+		for i := range values {
+			x := binomialSample(rng, c.n, c.p)
+			require.True(t, x >= 0 && x <= c.n)
+			values[i] = float64(x)
+		}
+		mean, variance := MeanAndVariance(values)
+		expectedVar := float64(c.n) * c.p * (1 - c.p)
+		require.InDelta(t, float64(c.n)*c.p, mean, 4*math.Sqrt(expectedVar/float64(len(values))), "n=%d p=%g", c.n, c.p)
+		require.InEpsilon(t, expectedVar, variance, 0.05, "n=%d p=%g", c.n, c.p)
+	}
+	require.Equal(t, 0, binomialSample(rng, 10, 0))
+	require.Equal(t, 10, binomialSample(rng, 10, 1))
+}
+
+func TestBootstrapShannon_LargeSample(t *testing.T) {
+	t.Parallel()
+
+	// ~1,3 milhão de indivíduos: o custo depende das espécies, não dos indivíduos
+	counts := make(map[string]int)
+	n := 0
+	for i := 0; i < 50; i++ {
+		counts[fmt.Sprintf("S%d s", i)] = 1000 * (i + 1)
+		n += 1000 * (i + 1)
+	}
+	var h, sumLn2 float64
+	for _, c := range counts {
+		p := float64(c) / float64(n)
+		h -= p * math.Log(p)
+		sumLn2 += p * math.Log(p) * math.Log(p)
+	}
+	analyticStdErr := math.Sqrt((sumLn2 - h*h) / float64(n))
+
+	stdErr, lower, upper := bootstrapShannon(counts, DiversityOptions{Probability: 0.95, BootstrapIterations: 2000, Seed: 11})
+	require.InEpsilon(t, analyticStdErr, stdErr, 0.1)
+	require.Less(t, lower, h)
+	require.Greater(t, upper, h)
+}
+
+func TestCompareDiversity(t *testing.T) {
+	t.Parallel()
+
+	uniform := make(map[string]int)
+	for i := 0; i < 10; i++ {
+		uniform[fmt.Sprintf("S%d s", i)] = 10
+	}
+	dominated := map[string]int{"S0 s": 91}
+	for i := 1; i < 10; i++ {
+		dominated[fmt.Sprintf("S%d s", i)] = 1
+	}
+
+	a := diversityFixture("a", uniform)
+	b := diversityFixture("b", dominated)
+
+	same := CompareDiversity(a, a, 0.95)
+	require.Zero(t, same.TStatistic)
+	require.InDelta(t, 1, same.PValue, 1e-9)
+	require.False(t, same.Significant)
+
+	diff := CompareDiversity(a, b, 0.95)
+	require.Equal(t, "a", diff.First.AnalysisID)
+	require.Equal(t, "b", diff.Second.AnalysisID)
+	require.Positive(t, diff.Difference)
+	require.Greater(t, diff.TStatistic, diff.CriticalT)
+	require.Less(t, diff.PValue, 0.05)
+	require.True(t, diff.Significant)
+
+	// o teste é simétrico
+	rev := CompareDiversity(b, a, 0.95)
+	require.InDelta(t, -diff.TStatistic, rev.TStatistic, 1e-9)
+	require.InDelta(t, diff.PValue, rev.PValue, 1e-9)
+}
@@ -0,0 +1,362 @@
+package phytometrics
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+)
+
+// daysPerYear converte o intervalo entre campanhas em anos
+const daysPerYear = 365.25
+
+// Dynamics representa a dinâmica da floresta entre duas campanhas das mesmas parcelas permanentes.
+// As árvores são identificadas pela plaqueta; indivíduos sem plaqueta ficam fora da dinâmica.
+type Dynamics struct {
+	PreviousAnalysisID string    `json:"previousAnalysisId"`
+	CurrentAnalysisID  string    `json:"currentAnalysisId"`
+	PreviousDate       time.Time `json:"previousDate"`
+	CurrentDate        time.Time `json:"currentDate"`
+	IntervalYears      float64   `json:"intervalYears"`
+	Plots              []string  `json:"plots"`         // parcelas remedidas (presentes nas duas campanhas)
+	SampledAreaHa      float64   `json:"sampledAreaHa"` // área das parcelas remedidas
+
+	Individuals  DynamicsCounts    `json:"individuals"`
+	BasalArea    DynamicsStock     `json:"basalArea"` // m²/ha
+	Volume       DynamicsStock     `json:"volume"`    // m³/ha
+	DbhIncrement DbhIncrement      `json:"dbhIncrement"`
+	Species      []SpeciesDynamics `json:"species"`
+
+	UntaggedPrevious int      `json:"untaggedPrevious"` // indivíduos sem plaqueta nas parcelas remedidas
+	UntaggedCurrent  int      `json:"untaggedCurrent"`
+	Warnings         []string `json:"warnings"`
+}
+
+// DynamicsCounts representa a contagem de indivíduos e as taxas anuais (Sheil et al., 1995)
+//
+//	M = [1 - ((N0 - m) / N0)^(1/t)] × 100   R = [1 - (1 - r / Nt)^(1/t)] × 100
+//	mudança líquida = [(Nt / N0)^(1/t) - 1] × 100   rotatividade = (M + R) / 2
+type DynamicsCounts struct {
+	Initial         int     `json:"initial"` // N0
+	Final           int     `json:"final"`   // Nt
+	Survivors       int     `json:"survivors"`
+	Dead            int     `json:"dead"`
+	Recruits        int     `json:"recruits"`
+	InitialPerHa    float64 `json:"initialPerHa"`
+	FinalPerHa      float64 `json:"finalPerHa"`
+	MortalityRate   float64 `json:"mortalityRate"`   // %/ano
+	RecruitmentRate float64 `json:"recruitmentRate"` // %/ano
+	NetChangeRate   float64 `json:"netChangeRate"`   // %/ano
+	TurnoverRate    float64 `json:"turnoverRate"`    // %/ano
+}
+
+// DynamicsStock representa a variação de um estoque por hectare (área basal ou volume)
+//
+//	perda = [1 - ((E0 - Em - Ed) / E0)^(1/t)] × 100   ganho = [1 - (1 - (Er + Eg) / Et)^(1/t)] × 100
+type DynamicsStock struct {
+	Initial                 float64 `json:"initial"`
+	Final                   float64 `json:"final"`
+	SurvivorsGrowth         float64 `json:"survivorsGrowth"`         // crescimento líquido dos sobreviventes
+	Mortality               float64 `json:"mortality"`               // estoque dos mortos na campanha anterior
+	Recruitment             float64 `json:"recruitment"`             // estoque dos recrutas
+	GrossIncrement          float64 `json:"grossIncrement"`          // crescimento dos sobreviventes + recrutamento
+	NetChange               float64 `json:"netChange"`               // final - inicial
+	PeriodicAnnualIncrement float64 `json:"periodicAnnualIncrement"` // mudança líquida por ano
+	GrossAnnualIncrement    float64 `json:"grossAnnualIncrement"`    // incremento bruto por ano
+	LossRate                float64 `json:"lossRate"`                // %/ano
+	GainRate                float64 `json:"gainRate"`                // %/ano
+}
+
+// DbhIncrement representa o incremento diamétrico dos sobreviventes
+type DbhIncrement struct {
+	Trees          int     `json:"trees"`
+	MeanPeriodicCm float64 `json:"meanPeriodicCm"` // incremento médio no intervalo
+	MeanAnnualCm   float64 `json:"meanAnnualCm"`   // incremento periódico anual médio (IPA)
+	StdDevAnnualCm float64 `json:"stdDevAnnualCm"`
+	NegativeTrees  int     `json:"negativeTrees"` // sobreviventes com DAP menor (verificar a medição)
+}
+
+// SpeciesDynamics representa a dinâmica de uma espécie (sobreviventes pela identificação atual)
+type SpeciesDynamics struct {
+	ScientificName         string  `json:"scientificName"`
+	Family                 string  `json:"family"`
+	Initial                int     `json:"initial"`
+	Final                  int     `json:"final"`
+	Survivors              int     `json:"survivors"`
+	Dead                   int     `json:"dead"`
+	Recruits               int     `json:"recruits"`
+	BasalAreaInitial       float64 `json:"basalAreaInitial"` // m²/ha
+	BasalAreaFinal         float64 `json:"basalAreaFinal"`   // m²/ha
+	MortalityRate          float64 `json:"mortalityRate"`    // %/ano
+	RecruitmentRate        float64 `json:"recruitmentRate"`  // %/ano
+	MeanAnnualDbhIncrement float64 `json:"meanAnnualDbhIncrement"`
+}
+
+// dynamicsTree guarda as medidas de uma árvore plaqueteada em uma campanha
+type dynamicsTree struct {
+	specimen *types.SpecimenWithSpecies
+	metrics  SpecimenMetrics
+}
+
+// ComputeDynamics calcula a dinâmica entre a campanha anterior e a atual. Entram apenas as parcelas
+// presentes nas duas campanhas; árvores plaqueteadas só na anterior são mortas e só na atual, recrutas.
+// Indivíduos registrados como mortos ficam fora do estoque: mortos na campanha atual contam na mortalidade.
+// As alturas devem ser estimadas antes, com a relação hipsométrica de cada campanha.
+func ComputeDynamics(previous, current *types.PhytoAnalysisComplete) *Dynamics {
+	out := &Dynamics{
+		PreviousAnalysisID: previous.ID,
+		CurrentAnalysisID:  current.ID,
+		PreviousDate:       previous.InitialDate,
+		CurrentDate:        current.InitialDate,
+		Plots:              make([]string, 0),
+		Species:            make([]SpeciesDynamics, 0),
+		Warnings:           make([]string, 0),
+	}
+
+	out.IntervalYears = current.InitialDate.Sub(previous.InitialDate).Hours() / 24 / daysPerYear
+	if out.IntervalYears <= 0 {
+		out.Warnings = append(out.Warnings, "current campaign must be after the previous campaign: annual rates not computed")
+	}
+
+	remeasured := remeasuredPlots(previous, current)
+	for code, area := range remeasured {
+		out.Plots = append(out.Plots, code)
+		out.SampledAreaHa += area / 10000.0
+	}
+	sort.Strings(out.Plots)
+	if len(out.Plots) == 0 {
+		out.Warnings = append(out.Warnings, "no plots measured in both campaigns")
+		return out
+	}
+
+	before, untaggedBefore := tagTrees(previous, remeasured, "previous", &out.Warnings)
+	after, untaggedAfter := tagTrees(current, remeasured, "current", &out.Warnings)
+	out.UntaggedPrevious, out.UntaggedCurrent = untaggedBefore, untaggedAfter
+	if untaggedBefore+untaggedAfter > 0 {
+		out.Warnings = append(out.Warnings, "specimens without tag are not included in the dynamics")
+	}
+
+	type speciesAcc struct {
+		out       SpeciesDynamics
+		increment float64
+	}
+	species := make(map[string]*speciesAcc)
+	getSpecies := func(s *types.SpecimenWithSpecies) *speciesAcc {
+		key := speciesKey(s)
+		a, ok := species[key]
+		if !ok {
+			a = &speciesAcc{out: SpeciesDynamics{ScientificName: s.ScientificName, Family: s.Family}}
+			species[key] = a
+		}
+		return a
+	}
+
+	var (
+		ba, vol          stockAcc
+		increments       []float64
+		periodicIncrease float64
+	)
+	for _, tag := range sortedTags(before) {
+		b := before[tag]
+		sp := getSpecies(b.specimen)
+		sp.out.Initial++
+		sp.out.BasalAreaInitial += b.metrics.BasalAreaM2
+		ba.initial += b.metrics.BasalAreaM2
+		vol.initial += b.metrics.VolumeM3
+
+		a, survived := after[tag]
+		if !survived {
+			out.Individuals.Dead++
+			sp.out.Dead++
+			ba.mortality += b.metrics.BasalAreaM2
+			vol.mortality += b.metrics.VolumeM3
+			continue
+		}
+
+		out.Individuals.Survivors++
+		ba.addGrowth(a.metrics.BasalAreaM2 - b.metrics.BasalAreaM2)
+		vol.addGrowth(a.metrics.VolumeM3 - b.metrics.VolumeM3)
+		if a.specimen.Portion != b.specimen.Portion {
+			out.Warnings = append(out.Warnings, "tag "+tag+" changed plot")
+		}
+
+		increase := a.metrics.DbhCm - b.metrics.DbhCm
+		if increase < 0 {
+			out.DbhIncrement.NegativeTrees++
+		}
+		periodicIncrease += increase
+		if out.IntervalYears > 0 {
+			increments = append(increments, increase/out.IntervalYears)
+			getSpecies(a.specimen).increment += increase / out.IntervalYears
+		}
+	}
+	for _, tag := range sortedTags(after) {
+		a := after[tag]
+		sp := getSpecies(a.specimen)
+		sp.out.Final++
+		sp.out.BasalAreaFinal += a.metrics.BasalAreaM2
+		ba.final += a.metrics.BasalAreaM2
+		vol.final += a.metrics.VolumeM3
+
+		if _, survived := before[tag]; survived {
+			sp.out.Survivors++
+			continue
+		}
+		out.Individuals.Recruits++
+		sp.out.Recruits++
+		ba.recruitment += a.metrics.BasalAreaM2
+		vol.recruitment += a.metrics.VolumeM3
+	}
+
+	out.Individuals.Initial = len(before)
+	out.Individuals.Final = len(after)
+	out.Individuals.InitialPerHa = float64(len(before)) / out.SampledAreaHa
+	out.Individuals.FinalPerHa = float64(len(after)) / out.SampledAreaHa
+	if out.IntervalYears > 0 {
+		c := &out.Individuals
+		c.MortalityRate = mortalityRate(c.Initial, c.Dead, out.IntervalYears)
+		c.RecruitmentRate = recruitmentRate(c.Final, c.Recruits, out.IntervalYears)
+		if c.Initial > 0 && c.Final > 0 {
+			c.NetChangeRate = (math.Pow(float64(c.Final)/float64(c.Initial), 1/out.IntervalYears) - 1) * 100
+		}
+		c.TurnoverRate = (c.MortalityRate + c.RecruitmentRate) / 2
+	}
+
+	out.BasalArea = ba.stock(out.SampledAreaHa, out.IntervalYears)
+	out.Volume = vol.stock(out.SampledAreaHa, out.IntervalYears)
+
+	out.DbhIncrement.Trees = out.Individuals.Survivors
+	if out.Individuals.Survivors > 0 {
+		out.DbhIncrement.MeanPeriodicCm = periodicIncrease / float64(out.Individuals.Survivors)
+	}
+	if len(increments) > 0 {
+		mean, variance := MeanAndVariance(increments)
+		out.DbhIncrement.MeanAnnualCm = mean
+		out.DbhIncrement.StdDevAnnualCm = math.Sqrt(variance)
+	}
+
+	for _, a := range species {
+		sp := a.out
+		sp.BasalAreaInitial /= out.SampledAreaHa
+		sp.BasalAreaFinal /= out.SampledAreaHa
+		if out.IntervalYears > 0 {
+			sp.MortalityRate = mortalityRate(sp.Initial, sp.Dead, out.IntervalYears)
+			sp.RecruitmentRate = recruitmentRate(sp.Final, sp.Recruits, out.IntervalYears)
+		}
+		if sp.Survivors > 0 {
+			sp.MeanAnnualDbhIncrement = a.increment / float64(sp.Survivors)
+		}
+		out.Species = append(out.Species, sp)
+	}
+	sort.Slice(out.Species, func(i, j int) bool { return out.Species[i].ScientificName < out.Species[j].ScientificName })
+
+	return out
+}
+
+// remeasuredPlots retorna as parcelas presentes nas duas campanhas com a área (m²) da campanha atual
+func remeasuredPlots(previous, current *types.PhytoAnalysisComplete) map[string]float64 {
+	before := make(map[string]bool)
+	for _, pl := range ComputePlots(previous) {
+		before[pl.Code] = true
+	}
+
+	out := make(map[string]float64)
+	for _, pl := range ComputePlots(current) {
+		if before[pl.Code] && pl.AreaM2 > 0 {
+			out[pl.Code] = pl.AreaM2
+		}
+	}
+	return out
+}
+
+// tagTrees indexa pela plaqueta as árvores das parcelas remedidas; retorna também quantas não têm plaqueta
+func tagTrees(p *types.PhytoAnalysisComplete, plots map[string]float64, campaign string, warnings *[]string) (map[string]dynamicsTree, int) {
+	metrics := ComputeSpecimens(p)
+	trees := make(map[string]dynamicsTree)
+	untagged := 0
+
+	for i, s := range p.Specimens {
+		if _, ok := plots[s.Portion]; !ok || IsDead(s) {
+			continue
+		}
+		if s.Tag == nil || *s.Tag == "" {
+			untagged++
+			continue
+		}
+		if _, dup := trees[*s.Tag]; dup {
+			*warnings = append(*warnings, "duplicated tag "+*s.Tag+" in the "+campaign+" campaign")
+			continue
+		}
+		trees[*s.Tag] = dynamicsTree{specimen: s, metrics: metrics[i]}
+	}
+	return trees, untagged
+}
+
+// sortedTags retorna as plaquetas em ordem (avisos determinísticos)
+func sortedTags(trees map[string]dynamicsTree) []string {
+	tags := make([]string, 0, len(trees))
+	for tag := range trees {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// stockAcc acumula os componentes da variação de um estoque (totais das parcelas remedidas)
+type stockAcc struct {
+	initial, final, mortality, recruitment float64
+	growth, decrement                      float64 // crescimento e redução dos sobreviventes
+}
+
+func (a *stockAcc) addGrowth(delta float64) {
+	if delta >= 0 {
+		a.growth += delta
+	} else {
+		a.decrement -= delta
+	}
+}
+
+// stock converte os totais para hectare e calcula as taxas anuais de perda e ganho
+func (a *stockAcc) stock(areaHa, years float64) DynamicsStock {
+	st := DynamicsStock{
+		Initial:         a.initial / areaHa,
+		Final:           a.final / areaHa,
+		SurvivorsGrowth: (a.growth - a.decrement) / areaHa,
+		Mortality:       a.mortality / areaHa,
+		Recruitment:     a.recruitment / areaHa,
+	}
+	st.GrossIncrement = st.SurvivorsGrowth + st.Recruitment
+	st.NetChange = st.Final - st.Initial
+	if years <= 0 {
+		return st
+	}
+
+	st.PeriodicAnnualIncrement = st.NetChange / years
+	st.GrossAnnualIncrement = st.GrossIncrement / years
+	if a.initial > 0 {
+		remaining := math.Max(0, (a.initial-a.mortality-a.decrement)/a.initial)
+		st.LossRate = (1 - math.Pow(remaining, 1/years)) * 100
+	}
+	if a.final > 0 {
+		kept := math.Max(0, 1-(a.recruitment+a.growth)/a.final)
+		st.GainRate = (1 - math.Pow(kept, 1/years)) * 100
+	}
+	return st
+}
+
+// mortalityRate retorna a taxa anual de mortalidade (%/ano)
+func mortalityRate(initial, dead int, years float64) float64 {
+	if initial == 0 {
+		return 0
+	}
+	return (1 - math.Pow(float64(initial-dead)/float64(initial), 1/years)) * 100
+}
+
+// recruitmentRate retorna a taxa anual de recrutamento (%/ano)
+func recruitmentRate(final, recruits int, years float64) float64 {
+	if final == 0 {
+		return 0
+	}
+	return (1 - math.Pow(1-float64(recruits)/float64(final), 1/years)) * 100
+}
@@ -0,0 +1,123 @@
+package phytometrics
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+	"github.com/stretchr/testify/require"
+)
+
+func tagged(tag, portion string, dbhCm float64) *types.SpecimenWithSpecies {
+	s := &types.SpecimenWithSpecies{Portion: portion, Cap1: dbhCm * math.Pi, Height: 10, ScientificName: "A a", Family: "Fabaceae"}
+	if tag != "" {
+		s.Tag = &tag
+	}
+	return s
+}
+
+func TestComputeDynamics(t *testing.T) {
+	t.Parallel()
+
+	previous := &types.PhytoAnalysisComplete{
+		ID:          "c1",
+		InitialDate: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		PortionArea: 100,
+		Specimens: []*types.SpecimenWithSpecies{
+			tagged("T1", "P1", 10),
+			tagged("T2", "P1", 10),
+			tagged("T3", "P1", 10), // morta
+			tagged("", "P1", 10),   // sem plaqueta
+			tagged("T9", "P9", 10), // parcela não remedida
+		},
+	}
+	current := &types.PhytoAnalysisComplete{
+		ID:          "c2",
+		InitialDate: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+		PortionArea: 100,
+		Specimens: []*types.SpecimenWithSpecies{
+			tagged("T1", "P1", 12),
+			tagged("T2", "P1", 10),
+			tagged("T4", "P1", 5), // recruta
+		},
+	}
+
+	d := ComputeDynamics(previous, current)
+
+	years := 731.0 / daysPerYear
+	require.InDelta(t, years, d.IntervalYears, 1e-9)
+	require.Equal(t, []string{"P1"}, d.Plots)
+	require.InDelta(t, 0.01, d.SampledAreaHa, 1e-12)
+	require.Equal(t, 1, d.UntaggedPrevious)
+	require.Zero(t, d.UntaggedCurrent)
+
+	c := d.Individuals
+	require.Equal(t, 3, c.Initial)
+	require.Equal(t, 3, c.Final)
+	require.Equal(t, 2, c.Survivors)
+	require.Equal(t, 1, c.Dead)
+	require.Equal(t, 1, c.Recruits)
+	require.InDelta(t, 300, c.InitialPerHa, 1e-9)
+	require.InDelta(t, (1-math.Pow(2.0/3.0, 1/years))*100, c.MortalityRate, 1e-9)
+	require.InDelta(t, (1-math.Pow(2.0/3.0, 1/years))*100, c.RecruitmentRate, 1e-9)
+	require.InDelta(t, 0, c.NetChangeRate, 1e-9)
+
+	g := func(dbh float64) float64 { return math.Pi * dbh * dbh / 40000 }
+	ba := d.BasalArea
+	require.InDelta(t, 3*g(10)/0.01, ba.Initial, 1e-9)
+	require.InDelta(t, (g(12)+g(10)+g(5))/0.01, ba.Final, 1e-9)
+	require.InDelta(t, g(10)/0.01, ba.Mortality, 1e-9)
+	require.InDelta(t, g(5)/0.01, ba.Recruitment, 1e-9)
+	require.InDelta(t, (g(12)-g(10))/0.01, ba.SurvivorsGrowth, 1e-9)
+	require.InDelta(t, ba.Final-ba.Initial, ba.NetChange, 1e-9)
+	require.InDelta(t, ba.NetChange/years, ba.PeriodicAnnualIncrement, 1e-9)
+
+	require.Equal(t, 2, d.DbhIncrement.Trees)
+	require.InDelta(t, 1, d.DbhIncrement.MeanPeriodicCm, 1e-9)
+	require.InDelta(t, 1/years, d.DbhIncrement.MeanAnnualCm, 1e-9)
+
+	require.Len(t, d.Species, 1)
+	require.Equal(t, 1, d.Species[0].Dead)
+	require.Equal(t, 1, d.Species[0].Recruits)
+}
+
+func TestComputeDynamics_NoCommonPlots(t *testing.T) {
+	t.Parallel()
+
+	previous := &types.PhytoAnalysisComplete{PortionArea: 100, Specimens: []*types.SpecimenWithSpecies{tagged("T1", "P1", 10)}}
+	current := &types.PhytoAnalysisComplete{PortionArea: 100, Specimens: []*types.SpecimenWithSpecies{tagged("T1", "P2", 10)}}
+
+	d := ComputeDynamics(previous, current)
+
+	require.Empty(t, d.Plots)
+	require.Zero(t, d.Individuals.Initial)
+	require.Contains(t, d.Warnings, "no plots measured in both campaigns")
+}
+
+func TestComputeDynamics_DeadStatus(t *testing.T) {
+	t.Parallel()
+
+	dead := tagged("T2", "P1", 10)
+	dead.Status = types.SpecimenStatusDeadStanding
+	alreadyDead := tagged("T3", "P1", 10)
+	alreadyDead.Status = types.SpecimenStatusFallen
+
+	previous := &types.PhytoAnalysisComplete{
+		InitialDate: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		PortionArea: 100,
+		Specimens:   []*types.SpecimenWithSpecies{tagged("T1", "P1", 10), tagged("T2", "P1", 10), alreadyDead},
+	}
+	current := &types.PhytoAnalysisComplete{
+		InitialDate: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+		PortionArea: 100,
+		Specimens:   []*types.SpecimenWithSpecies{tagged("T1", "P1", 10), dead},
+	}
+
+	d := ComputeDynamics(previous, current)
+
+	require.Equal(t, 2, d.Individuals.Initial)
+	require.Equal(t, 1, d.Individuals.Final)
+	require.Equal(t, 1, d.Individuals.Dead)
+	require.Zero(t, d.Individuals.Recruits)
+}
@@ -0,0 +1,214 @@
+package phytometrics
+
+import (
+	"sort"
+
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+)
+
+// Grupos sucessionais do perfil ecológico
+const (
+	SuccessionalPioneer        = "pioneer"         // pioneira (P)
+	SuccessionalEarlySecondary = "early_secondary" // secundária inicial (IS)
+	SuccessionalLateSecondary  = "late_secondary"  // secundária tardia (LS)
+	SuccessionalClimax         = "climax"          // clímax (C)
+	SuccessionalOther          = "other"           // demais classificações da legislação (S, MS, AS)
+)
+
+// Origens do perfil ecológico
+const (
+	OriginNative         = "native"          // nativa (N)
+	OriginExotic         = "exotic"          // exótica (EX)
+	OriginInvasiveExotic = "invasive_exotic" // exótica invasora (EXI)
+)
+
+// EcologyUnknown agrupa os indivíduos sem a informação (espécie sem legislação ou sem hábito,
+// morfoespécies)
+const EcologyUnknown = "unknown"
+
+var successionalGroups = map[string]string{
+	"P":  SuccessionalPioneer,
+	"IS": SuccessionalEarlySecondary,
+	"LS": SuccessionalLateSecondary,
+	"C":  SuccessionalClimax,
+	"S":  SuccessionalOther,
+	"MS": SuccessionalOther,
+	"AS": SuccessionalOther,
+}
+
+var successionalOrder = []string{
+	SuccessionalPioneer, SuccessionalEarlySecondary, SuccessionalLateSecondary, SuccessionalClimax,
+	SuccessionalOther, EcologyUnknown,
+}
+
+var origins = map[string]string{"N": OriginNative, "EX": OriginExotic, "EXI": OriginInvasiveExotic}
+
+var originOrder = []string{OriginNative, OriginExotic, OriginInvasiveExotic, EcologyUnknown}
+
+// SuccessionalGroup retorna o grupo sucessional da espécie pela legislação mais específica
+func SuccessionalGroup(s *types.SpecimenWithSpecies) string {
+	for _, l := range s.Legislations {
+		if g, ok := successionalGroups[l.SuccessionalEcology]; ok {
+			return g
+		}
+	}
+	return EcologyUnknown
+}
+
+// SpeciesOrigin retorna a origem da espécie pela legislação mais específica
+func SpeciesOrigin(s *types.SpecimenWithSpecies) string {
+	for _, l := range s.Legislations {
+		if o, ok := origins[l.Origin]; ok {
+			return o
+		}
+	}
+	return EcologyUnknown
+}
+
+// IsThreatenedSpecies indica se a espécie do indivíduo está ameaçada (CR, EN ou VU)
+func IsThreatenedSpecies(s *types.SpecimenWithSpecies) bool {
+	return s.ThreatStatus != nil && threatenedStatuses[*s.ThreatStatus]
+}
+
+// EcologicalGroup representa a participação de um grupo (sucessional, origem ou hábito) na análise
+type EcologicalGroup struct {
+	Group             string  `json:"group"`
+	Individuals       int     `json:"individuals"`
+	Species           int     `json:"species"`
+	BasalAreaM2       float64 `json:"basalAreaM2"`
+	BasalAreaPerHa    float64 `json:"basalAreaPerHa"`    // zero sem área amostrada
+	IndividualsShare  float64 `json:"individualsShare"`  // % dos indivíduos
+	BasalAreaShare    float64 `json:"basalAreaShare"`    // % da área basal
+	ThreatenedSpecies int     `json:"threatenedSpecies"` // espécies ameaçadas (CR, EN ou VU)
+	ProtectedSpecies  int     `json:"protectedSpecies"`  // espécies protegidas (imunes de corte)
+}
+
+// Ecology representa o perfil ecológico da análise: indivíduos, espécies e área basal por grupo
+// sucessional, origem e hábito. A origem e o grupo sucessional vêm da legislação vigente mais
+// específica da espécie.
+type Ecology struct {
+	Successional []EcologicalGroup `json:"successional"` // ordem fixa dos grupos
+	Origin       []EcologicalGroup `json:"origin"`       // ordem fixa das origens
+	Habit        []EcologicalGroup `json:"habit"`        // por indivíduos (desc)
+
+	ThreatenedSpecies     int `json:"threatenedSpecies"`
+	ThreatenedIndividuals int `json:"threatenedIndividuals"`
+	ProtectedSpecies      int `json:"protectedSpecies"`
+	ProtectedIndividuals  int `json:"protectedIndividuals"`
+}
+
+// ecologyAccumulator acumula um grupo e as espécies já contadas nele
+type ecologyAccumulator struct {
+	group   EcologicalGroup
+	species map[string]bool
+}
+
+func (a *ecologyAccumulator) add(s *types.SpecimenWithSpecies, basalAreaM2 float64) {
+	a.group.Individuals++
+	a.group.BasalAreaM2 += basalAreaM2
+	key := speciesKey(s)
+	if a.species[key] {
+		return
+	}
+	a.species[key] = true
+	a.group.Species++
+	if IsThreatenedSpecies(s) {
+		a.group.ThreatenedSpecies++
+	}
+	if s.Protected {
+		a.group.ProtectedSpecies++
+	}
+}
+
+// ecologyBreakdown agrupa os indivíduos pela chave informada
+type ecologyBreakdown map[string]*ecologyAccumulator
+
+func (b ecologyBreakdown) add(group string, s *types.SpecimenWithSpecies, basalAreaM2 float64) {
+	acc, ok := b[group]
+	if !ok {
+		acc = &ecologyAccumulator{group: EcologicalGroup{Group: group}, species: make(map[string]bool)}
+		b[group] = acc
+	}
+	acc.add(s, basalAreaM2)
+}
+
+// groups finaliza as participações; com order, segue a ordem informada, senão ordena por
+// indivíduos (desc). Grupos sem indivíduos são omitidos.
+func (b ecologyBreakdown) groups(order []string, individuals int, basalAreaM2, sampledArea float64) []EcologicalGroup {
+	out := make([]EcologicalGroup, 0, len(b))
+	finish := func(acc *ecologyAccumulator) {
+		g := acc.group
+		if individuals > 0 {
+			g.IndividualsShare = float64(g.Individuals) / float64(individuals) * 100
+		}
+		if basalAreaM2 > 0 {
+			g.BasalAreaShare = g.BasalAreaM2 / basalAreaM2 * 100
+		}
+		if sampledArea > 0 {
+			g.BasalAreaPerHa = g.BasalAreaM2 / sampledArea
+		}
+		out = append(out, g)
+	}
+
+	if order != nil {
+		for _, key := range order {
+			if acc, ok := b[key]; ok {
+				finish(acc)
+			}
+		}
+		return out
+	}
+
+	for _, acc := range b {
+		finish(acc)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Individuals != out[j].Individuals {
+			return out[i].Individuals > out[j].Individuals
+		}
+		return out[i].Group < out[j].Group
+	})
+	return out
+}
+
+// ComputeEcology calcula o perfil ecológico (specimens na mesma ordem de p.Specimens)
+func ComputeEcology(p *types.PhytoAnalysisComplete, specimens []SpecimenMetrics) *Ecology {
+	successional := make(ecologyBreakdown)
+	origin := make(ecologyBreakdown)
+	habit := make(ecologyBreakdown)
+
+	out := &Ecology{}
+	threatened := make(map[string]bool)
+	protected := make(map[string]bool)
+	var basalAreaM2 float64
+	for i, s := range p.Specimens {
+		g := specimens[i].BasalAreaM2
+		basalAreaM2 += g
+
+		successional.add(SuccessionalGroup(s), s, g)
+		origin.add(SpeciesOrigin(s), s, g)
+		h := EcologyUnknown
+		if s.Habit != nil && *s.Habit != "" {
+			h = *s.Habit
+		}
+		habit.add(h, s, g)
+
+		key := speciesKey(s)
+		if IsThreatenedSpecies(s) {
+			out.ThreatenedIndividuals++
+			threatened[key] = true
+		}
+		if s.Protected {
+			out.ProtectedIndividuals++
+			protected[key] = true
+		}
+	}
+	out.ThreatenedSpecies = len(threatened)
+	out.ProtectedSpecies = len(protected)
+
+	n := len(p.Specimens)
+	out.Successional = successional.groups(successionalOrder, n, basalAreaM2, p.SampledArea)
+	out.Origin = origin.groups(originOrder, n, basalAreaM2, p.SampledArea)
+	out.Habit = habit.groups(nil, n, basalAreaM2, p.SampledArea)
+	return out
+}
@@ -0,0 +1,66 @@
+package phytometrics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeEcology(t *testing.T) {
+	t.Parallel()
+
+	arv, pal := "ARV", "PAL"
+	en := "EN"
+	pioneerNative := []types.SpeciesLegislationStatus{
+		{LawScope: "STATE", SuccessionalEcology: "P", Origin: "N", ThreatStatus: "EN"},
+		{LawScope: "FEDERAL", SuccessionalEcology: "C", Origin: "N", ThreatStatus: "LC"},
+	}
+	climaxExotic := []types.SpeciesLegislationStatus{
+		{LawScope: "FEDERAL", SuccessionalEcology: "C", Origin: "EXI", Protected: true},
+	}
+	p := &types.PhytoAnalysisComplete{
+		SampledArea: 0.5,
+		Specimens: []*types.SpecimenWithSpecies{
+			{Portion: "1", Cap1: 20 * math.Pi, Height: 10, ScientificName: "A a", Habit: &arv, ThreatStatus: &en, Legislations: pioneerNative},
+			{Portion: "1", Cap1: 20 * math.Pi, Height: 10, ScientificName: "A a", Habit: &arv, ThreatStatus: &en, Legislations: pioneerNative},
+			{Portion: "1", Cap1: 40 * math.Pi, Height: 10, ScientificName: "B b", Habit: &pal, Protected: true, Legislations: climaxExotic},
+			{Portion: "1", Cap1: 20 * math.Pi, Height: 10, ScientificName: "Indet. 1"},
+		},
+	}
+
+	basal := func(dbhCm float64) float64 { return math.Pi * dbhCm * dbhCm / 40000 }
+	total := 3*basal(20) + basal(40)
+
+	out := ComputeEcology(p, ComputeSpecimens(p))
+
+	// grupo sucessional da legislação mais específica (estadual), na ordem fixa dos grupos
+	require.Len(t, out.Successional, 3)
+	require.Equal(t, SuccessionalPioneer, out.Successional[0].Group)
+	require.Equal(t, 2, out.Successional[0].Individuals)
+	require.Equal(t, 1, out.Successional[0].Species)
+	require.Equal(t, 1, out.Successional[0].ThreatenedSpecies)
+	require.InDelta(t, 50, out.Successional[0].IndividualsShare, 1e-9)
+	require.InDelta(t, 2*basal(20), out.Successional[0].BasalAreaM2, 1e-9)
+	require.InDelta(t, 4*basal(20), out.Successional[0].BasalAreaPerHa, 1e-9)
+	require.InDelta(t, 2*basal(20)/total*100, out.Successional[0].BasalAreaShare, 1e-9)
+	require.Equal(t, SuccessionalClimax, out.Successional[1].Group)
+	require.Equal(t, 1, out.Successional[1].ProtectedSpecies)
+	require.Equal(t, EcologyUnknown, out.Successional[2].Group)
+
+	require.Len(t, out.Origin, 3)
+	require.Equal(t, OriginNative, out.Origin[0].Group)
+	require.Equal(t, OriginInvasiveExotic, out.Origin[1].Group)
+	require.Equal(t, EcologyUnknown, out.Origin[2].Group)
+
+	// hábito por indivíduos (desc)
+	require.Len(t, out.Habit, 3)
+	require.Equal(t, "ARV", out.Habit[0].Group)
+	require.Equal(t, 2, out.Habit[0].Individuals)
+
+	require.Equal(t, 1, out.ThreatenedSpecies)
+	require.Equal(t, 2, out.ThreatenedIndividuals)
+	require.Equal(t, 1, out.ProtectedSpecies)
+	require.Equal(t, 1, out.ProtectedIndividuals)
+}
@@ -0,0 +1,272 @@
+package phytometrics
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+)
+
+// Variáveis disponíveis nas expressões das equações
+const (
+	VarDbh         = "dap" // DAP equivalente (cm)
+	VarCap         = "cap" // CAP equivalente (cm)
+	VarHeight      = "h"   // altura total (m)
+	VarBasalArea   = "g"   // área basal (m²)
+	VarWoodDensity = "rho" // densidade básica da madeira (g/cm³)
+	VarFormFactor  = "ff"  // fator de forma resolvido (legislação > análise > cilíndrico)
+)
+
+// EquationVariables lista as variáveis aceitas nas expressões
+var EquationVariables = []string{VarDbh, VarCap, VarHeight, VarBasalArea, VarWoodDensity, VarFormFactor}
+
+// FormFactorEquationID identifica o cálculo padrão de volume (G × H × ff)
+const FormFactorEquationID = "form-factor"
+
+// coefficientName restringe o nome dos coeficientes (ex.: b0, b1, beta_2)
+var coefficientName = regexp.MustCompile(`^[a-z][a-z0-9_]{0,31}$`)
+
+// EquationInput representa as medidas de um indivíduo usadas nas equações
+type EquationInput struct {
+	DbhCm       float64 // DAP (cm)
+	HeightM     float64 // Altura total (m)
+	BasalAreaM2 float64 // Área basal (m²)
+	WoodDensity float64 // Densidade básica da madeira (g/cm³)
+	FormFactor  float64 // Fator de forma
+}
+
+// CompiledEquation é uma equação validada, pronta para avaliação
+type CompiledEquation struct {
+	Data types.EquationData
+	expr *Expr
+}
+
+// CompileEquation valida a equação (tipo, coeficientes e expressão) e a compila.
+// A expressão só pode usar as variáveis de EquationVariables e os próprios coeficientes.
+func CompileEquation(def types.EquationData) (*CompiledEquation, error) {
+	if strings.TrimSpace(def.Name) == "" {
+		return nil, errors.New("name is required")
+	}
+	if def.Kind != types.EquationKindVolume && def.Kind != types.EquationKindBiomass {
+		return nil, errors.New("invalid kind")
+	}
+
+	allowed := make(map[string]bool, len(EquationVariables)+len(def.Coefficients))
+	for _, v := range EquationVariables {
+		allowed[v] = true
+	}
+	for name, value := range def.Coefficients {
+		if !coefficientName.MatchString(name) || allowed[name] || isReservedName(name) {
+			return nil, fmt.Errorf("invalid coefficient name %q", name)
+		}
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return nil, fmt.Errorf("invalid value for coefficient %q", name)
+		}
+		allowed[name] = true
+	}
+
+	expr, err := ParseExpr(def.Expression, allowed)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CompiledEquation{Data: def, expr: expr}, nil
+}
+
+func isReservedName(name string) bool {
+	_, isFunc := exprFuncs[name]
+	_, isConst := exprConsts[name]
+	return isFunc || isConst
+}
+
+// Uses indica se a expressão usa a variável informada
+func (c *CompiledEquation) Uses(variable string) bool {
+	for _, name := range c.expr.names {
+		if name == variable {
+			return true
+		}
+	}
+	return false
+}
+
+// Evaluate avalia a equação para um indivíduo
+func (c *CompiledEquation) Evaluate(in EquationInput) (float64, error) {
+	vars := make(map[string]float64, len(EquationVariables)+len(c.Data.Coefficients))
+	for name, value := range c.Data.Coefficients {
+		vars[name] = value
+	}
+	vars[VarDbh] = in.DbhCm
+	vars[VarCap] = in.DbhCm * math.Pi
+	vars[VarHeight] = in.HeightM
+	vars[VarBasalArea] = in.BasalAreaM2
+	vars[VarWoodDensity] = in.WoodDensity
+	vars[VarFormFactor] = in.FormFactor
+	return c.expr.Eval(vars)
+}
+
+// AppliesToSpecimen indica se a equação se aplica ao espécime (hábito, família e espécie)
+func (c *CompiledEquation) AppliesToSpecimen(s *types.SpecimenWithSpecies) bool {
+	a := c.Data.Applicability
+	if len(a.SpeciesIDs) > 0 && !containsFold(a.SpeciesIDs, s.SpecieID) {
+		return false
+	}
+	if len(a.Families) > 0 && !containsFold(a.Families, s.Family) {
+		return false
+	}
+	if len(a.Habits) > 0 && (s.Habit == nil || !containsFold(a.Habits, *s.Habit)) {
+		return false
+	}
+	return true
+}
+
+// EquationMatches indica se a aplicabilidade atende ao filtro (campos vazios não filtram)
+func EquationMatches(eq types.EquationData, f types.EquationFilter) bool {
+	a := eq.Applicability
+	switch {
+	case f.Kind != "" && eq.Kind != f.Kind:
+		return false
+	case f.State != "" && len(a.States) > 0 && !containsFold(a.States, f.State):
+		return false
+	case f.VegetationType != "" && len(a.VegetationTypes) > 0 && !containsFold(a.VegetationTypes, f.VegetationType):
+		return false
+	case f.Habit != "" && len(a.Habits) > 0 && !containsFold(a.Habits, f.Habit):
+		return false
+	case f.Family != "" && len(a.Families) > 0 && !containsFold(a.Families, f.Family):
+		return false
+	}
+	return true
+}
+
+// EquationSpecificity pontua o quanto a equação é específica: equações regionais e restritas a
+// grupos taxonômicos aparecem antes das genéricas na sugestão
+func EquationSpecificity(eq types.EquationData) int {
+	a := eq.Applicability
+	score := 0
+	for _, restricted := range []bool{
+		len(a.States) > 0,
+		len(a.VegetationTypes) > 0,
+		len(a.Habits) > 0,
+		len(a.Families) > 0,
+		len(a.SpeciesIDs) > 0,
+	} {
+		if restricted {
+			score++
+		}
+	}
+	return score
+}
+
+func containsFold(list []string, v string) bool {
+	v = strings.TrimSpace(v)
+	for _, item := range list {
+		if strings.EqualFold(strings.TrimSpace(item), v) {
+			return true
+		}
+	}
+	return false
+}
+
+func ptr(s string) *string { return &s }
+
+// builtinEquations são as equações disponíveis sem cadastro
+var builtinEquations = []types.EquationData{
+	{
+		ID:         FormFactorEquationID,
+		Name:       "Fator de forma (G × H × ff)",
+		Kind:       types.EquationKindVolume,
+		Expression: "g * h * ff",
+		Reference:  ptr("Volume cilíndrico corrigido pelo fator de forma da legislação, da análise ou 1,0."),
+	},
+	{
+		ID:           "chave2014",
+		Name:         "Chave et al. (2014) - pantropical",
+		Kind:         types.EquationKindBiomass,
+		Expression:   "b0 * (rho * dap^2 * h)^b1",
+		Coefficients: map[string]float64{"b0": 0.0673, "b1": 0.976},
+		Reference:    ptr("Chave, J. et al. Improved allometric models to estimate the aboveground biomass of tropical trees. Global Change Biology, 20, 3177-3190, 2014."),
+	},
+	{
+		ID:           "chave2005-moist",
+		Name:         "Chave et al. (2005) - floresta úmida, sem altura",
+		Kind:         types.EquationKindBiomass,
+		Expression:   "rho * exp(b0 + b1 * ln(dap) + b2 * ln(dap)^2 + b3 * ln(dap)^3)",
+		Coefficients: map[string]float64{"b0": -1.499, "b1": 2.148, "b2": 0.207, "b3": -0.0281},
+		Reference:    ptr("Chave, J. et al. Tree allometry and improved estimation of carbon stocks and balance in tropical forests. Oecologia, 145, 87-99, 2005."),
+	},
+	{
+		ID:           "brown1997-moist",
+		Name:         "Brown (1997) - floresta tropical úmida",
+		Kind:         types.EquationKindBiomass,
+		Expression:   "exp(b0 + b1 * ln(dap))",
+		Coefficients: map[string]float64{"b0": -2.134, "b1": 2.530},
+		Reference:    ptr("Brown, S. Estimating biomass and biomass change of tropical forests: a primer. FAO Forestry Paper 134, 1997."),
+	},
+}
+
+var compiledBuiltins = func() map[string]*CompiledEquation {
+	out := make(map[string]*CompiledEquation, len(builtinEquations))
+	for i := range builtinEquations {
+		builtinEquations[i].BuiltIn = true
+		c, err := CompileEquation(builtinEquations[i])
+		if err != nil {
+			panic("phytometrics: invalid builtin equation " + builtinEquations[i].ID + ": " + err.Error())
+		}
+		out[builtinEquations[i].ID] = c
+	}
+	return out
+}()
+
+// BuiltinEquations lista as equações embutidas do tipo informado (vazio = todas), em ordem de ID
+func BuiltinEquations(kind string) []types.EquationData {
+	out := make([]types.EquationData, 0, len(builtinEquations))
+	for _, eq := range builtinEquations {
+		if kind == "" || eq.Kind == kind {
+			out = append(out, eq)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+// BuiltinEquation retorna a equação embutida com o ID informado
+func BuiltinEquation(id string) (*CompiledEquation, bool) {
+	c, ok := compiledBuiltins[id]
+	return c, ok
+}
+
+// EquationTemplate representa um modelo de equação a ser ajustado (coeficientes regionais)
+type EquationTemplate struct {
+	ID           string   `json:"id"`
+	Name         string   `json:"name"`
+	Kind         string   `json:"kind"`
+	Expression   string   `json:"expression"`
+	Coefficients []string `json:"coefficients"`
+}
+
+// EquationTemplates lista os modelos volumétricos usuais para cadastro com coeficientes próprios
+func EquationTemplates() []EquationTemplate {
+	return []EquationTemplate{
+		{ID: "schumacher-hall", Name: "Schumacher-Hall", Kind: types.EquationKindVolume, Expression: "b0 * dap^b1 * h^b2", Coefficients: []string{"b0", "b1", "b2"}},
+		{ID: "schumacher-hall-log", Name: "Schumacher-Hall (logarítmica)", Kind: types.EquationKindVolume, Expression: "exp(b0 + b1 * ln(dap) + b2 * ln(h))", Coefficients: []string{"b0", "b1", "b2"}},
+		{ID: "spurr", Name: "Spurr", Kind: types.EquationKindVolume, Expression: "b0 + b1 * dap^2 * h", Coefficients: []string{"b0", "b1"}},
+		{ID: "spurr-log", Name: "Spurr (logarítmica)", Kind: types.EquationKindVolume, Expression: "exp(b0 + b1 * ln(dap^2 * h))", Coefficients: []string{"b0", "b1"}},
+		{ID: "husch", Name: "Husch", Kind: types.EquationKindVolume, Expression: "exp(b0 + b1 * ln(dap))", Coefficients: []string{"b0", "b1"}},
+	}
+}
+
+// compileOrNil compila a equação selecionada; equações inválidas (não deveriam ser gravadas)
+// são ignoradas e o cálculo usa o padrão
+func compileOrNil(def *types.EquationData) *CompiledEquation {
+	if def == nil {
+		return nil
+	}
+	c, err := CompileEquation(*def)
+	if err != nil {
+		return nil
+	}
+	return c
+}
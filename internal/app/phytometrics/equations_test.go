@@ -0,0 +1,105 @@
+package phytometrics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompileEquation_Validation(t *testing.T) {
+	t.Parallel()
+
+	valid := types.EquationData{
+		Name:         "Schumacher-Hall",
+		Kind:         types.EquationKindVolume,
+		Expression:   "b0 * dap^b1 * h^b2",
+		Coefficients: map[string]float64{"b0": 0.00005, "b1": 2, "b2": 1},
+	}
+	eq, err := CompileEquation(valid)
+	require.NoError(t, err)
+	require.True(t, eq.Uses(VarDbh))
+	require.False(t, eq.Uses(VarWoodDensity))
+
+	cases := map[string]func(d *types.EquationData){
+		"sem nome":               func(d *types.EquationData) { d.Name = " " },
+		"tipo inválido":          func(d *types.EquationData) { d.Kind = "height" },
+		"coeficiente ausente":    func(d *types.EquationData) { d.Coefficients = map[string]float64{"b0": 1, "b1": 2} },
+		"coeficiente = variável": func(d *types.EquationData) { d.Coefficients = map[string]float64{"h": 1} },
+		"coeficiente = função":   func(d *types.EquationData) { d.Coefficients = map[string]float64{"exp": 1} },
+		"coeficiente inválido":   func(d *types.EquationData) { d.Coefficients = map[string]float64{"B-0": 1} },
+		"coeficiente infinito":   func(d *types.EquationData) { d.Coefficients = map[string]float64{"b0": math.Inf(1)} },
+		"expressão inválida":     func(d *types.EquationData) { d.Expression = "b0 * dap^" },
+	}
+	for name, mutate := range cases {
+		def := valid
+		def.Coefficients = map[string]float64{"b0": 0.00005, "b1": 2, "b2": 1}
+		mutate(&def)
+		_, err := CompileEquation(def)
+		require.Error(t, err, name)
+	}
+}
+
+func TestVolumeModel(t *testing.T) {
+	t.Parallel()
+
+	ff := 0.7
+	habit := "ARVORE"
+	p := &types.PhytoAnalysisComplete{
+		DefaultFormFactor: &ff,
+		Specimens: []*types.SpecimenWithSpecies{
+			{Portion: "1", Cap1: 20 * math.Pi, Height: 10, ScientificName: "A a", Habit: &habit},
+			{Portion: "1", Cap1: 20 * math.Pi, Height: 10, ScientificName: "B b"},
+		},
+	}
+
+	// Sem seleção: G × H × ff
+	metrics := ComputeSpecimens(p)
+	require.Equal(t, FormFactorEquationID, metrics[0].VolumeEquationID)
+	g := math.Pi * 0.2 * 0.2 / 4
+	require.InDelta(t, g*10*ff, metrics[0].VolumeM3, 1e-9)
+
+	// A equação embutida do fator de forma reproduz o cálculo padrão
+	builtin, _ := BuiltinEquation(FormFactorEquationID)
+	v, err := builtin.Evaluate(EquationInput{DbhCm: 20, HeightM: 10, BasalAreaM2: g, FormFactor: ff})
+	require.NoError(t, err)
+	require.InDelta(t, metrics[0].VolumeM3, v, 1e-9)
+
+	// Equação restrita a árvores: o espécime sem hábito usa o fator de forma
+	p.VolumeEquation = &types.EquationData{
+		ID:            "spurr-mg",
+		Name:          "Spurr MG",
+		Kind:          types.EquationKindVolume,
+		Expression:    "b0 + b1 * dap^2 * h",
+		Coefficients:  map[string]float64{"b0": 0.01, "b1": 0.00004},
+		Applicability: types.EquationApplicability{Habits: []string{"arvore"}},
+	}
+	metrics = ComputeSpecimens(p)
+	require.Equal(t, "spurr-mg", metrics[0].VolumeEquationID)
+	require.InDelta(t, 0.01+0.00004*400*10, metrics[0].VolumeM3, 1e-9)
+	require.Equal(t, FormFactorEquationID, metrics[1].VolumeEquationID)
+	require.InDelta(t, g*10*ff, metrics[1].VolumeM3, 1e-9)
+
+	require.Equal(t, "spurr-mg", UsedEquations(p).Volume.ID)
+	require.Equal(t, DefaultBiomassEquationID, UsedEquations(p).Biomass.ID)
+}
+
+func TestEquationMatches(t *testing.T) {
+	t.Parallel()
+
+	eq := types.EquationData{
+		Kind:          types.EquationKindVolume,
+		Applicability: types.EquationApplicability{States: []string{"MG", "SP"}, Habits: []string{"ARVORE"}},
+	}
+
+	require.True(t, EquationMatches(eq, types.EquationFilter{}))
+	require.True(t, EquationMatches(eq, types.EquationFilter{Kind: types.EquationKindVolume, State: "mg"}))
+	require.True(t, EquationMatches(eq, types.EquationFilter{Family: "Fabaceae"})) // sem restrição de família
+	require.False(t, EquationMatches(eq, types.EquationFilter{Kind: types.EquationKindBiomass}))
+	require.False(t, EquationMatches(eq, types.EquationFilter{State: "BA"}))
+	require.False(t, EquationMatches(eq, types.EquationFilter{Habit: "PALMEIRA"}))
+
+	require.Equal(t, 2, EquationSpecificity(eq))
+	require.Zero(t, EquationSpecificity(types.EquationData{}))
+}
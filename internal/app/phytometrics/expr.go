@@ -0,0 +1,417 @@
+package phytometrics
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Limites do avaliador de expressões (as expressões vêm do cadastro de equações)
+const (
+	maxExprLength = 1000
+	maxExprDepth  = 64
+)
+
+// Erros do avaliador de expressões
+var (
+	ErrExprEmpty     = errors.New("empty expression")
+	ErrExprTooLong   = errors.New("expression too long")
+	ErrExprTooDeep   = errors.New("expression too deeply nested")
+	ErrExprNotFinite = errors.New("expression result is not a finite number")
+)
+
+// exprFuncs são as únicas funções aceitas nas expressões, com a respectiva aridade
+var exprFuncs = map[string]struct {
+	arity int
+	fn    func(args []float64) float64
+}{
+	"ln":    {1, func(a []float64) float64 { return math.Log(a[0]) }},
+	"log10": {1, func(a []float64) float64 { return math.Log10(a[0]) }},
+	"exp":   {1, func(a []float64) float64 { return math.Exp(a[0]) }},
+	"sqrt":  {1, func(a []float64) float64 { return math.Sqrt(a[0]) }},
+	"abs":   {1, func(a []float64) float64 { return math.Abs(a[0]) }},
+	"pow":   {2, func(a []float64) float64 { return math.Pow(a[0], a[1]) }},
+	"min":   {2, func(a []float64) float64 { return math.Min(a[0], a[1]) }},
+	"max":   {2, func(a []float64) float64 { return math.Max(a[0], a[1]) }},
+}
+
+// exprConsts são as constantes nomeadas aceitas nas expressões
+var exprConsts = map[string]float64{
+	"pi": math.Pi,
+	"e":  math.E,
+}
+
+// Expr é uma expressão aritmética compilada. Aceita números, identificadores (variáveis e
+// coeficientes), + - * / ^, parênteses e as funções de exprFuncs; não há chamadas arbitrárias
+// nem acesso a nada além das variáveis informadas na avaliação.
+type Expr struct {
+	src   string
+	root  exprNode
+	names []string // identificadores usados (variáveis e coeficientes), sem repetição
+}
+
+// String retorna a expressão original
+func (e *Expr) String() string { return e.src }
+
+// Names retorna os identificadores usados pela expressão
+func (e *Expr) Names() []string { return append([]string(nil), e.names...) }
+
+// Eval avalia a expressão; identificadores ausentes em vars são erro
+func (e *Expr) Eval(vars map[string]float64) (float64, error) {
+	v, err := e.root.eval(vars)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, ErrExprNotFinite
+	}
+	return v, nil
+}
+
+// ParseExpr compila a expressão. allowed, quando não nulo, restringe os identificadores aceitos.
+func ParseExpr(src string, allowed map[string]bool) (*Expr, error) {
+	src = strings.TrimSpace(src)
+	if src == "" {
+		return nil, ErrExprEmpty
+	}
+	if len(src) > maxExprLength {
+		return nil, ErrExprTooLong
+	}
+
+	tokens, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &exprParser{tokens: tokens, allowed: allowed, seen: make(map[string]bool)}
+	root, err := p.parseSum(0)
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %q at position %d", t.text, t.pos+1)
+	}
+
+	return &Expr{src: src, root: root, names: p.names}, nil
+}
+
+// --- tokenizer ---
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokIdent
+	tokOp
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	num  float64
+	pos  int
+}
+
+func tokenize(src string) ([]token, error) {
+	tokens := make([]token, 0)
+	runes := []rune(src)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsDigit(r) || r == '.':
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			// notação científica (1e-3, 2.5E+2)
+			if i < len(runes) && (runes[i] == 'e' || runes[i] == 'E') {
+				j := i + 1
+				if j < len(runes) && (runes[j] == '+' || runes[j] == '-') {
+					j++
+				}
+				if j < len(runes) && unicode.IsDigit(runes[j]) {
+					for j < len(runes) && unicode.IsDigit(runes[j]) {
+						j++
+					}
+					i = j
+				}
+			}
+			text := string(runes[start:i])
+			v, err := strconv.ParseFloat(text, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %q at position %d", text, start+1)
+			}
+			tokens = append(tokens, token{kind: tokNumber, text: text, num: v, pos: start})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokIdent, text: strings.ToLower(string(runes[start:i])), pos: start})
+		case strings.ContainsRune("+-*/^", r):
+			tokens = append(tokens, token{kind: tokOp, text: string(r), pos: i})
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokLParen, text: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokRParen, text: ")", pos: i})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{kind: tokComma, text: ",", pos: i})
+			i++
+		default:
+			return nil, fmt.Errorf("unexpected character %q at position %d", r, i+1)
+		}
+	}
+	return append(tokens, token{kind: tokEOF, text: "end of expression", pos: len(runes)}), nil
+}
+
+// --- parser (descida recursiva) ---
+//
+//	sum     = product { ("+" | "-") product }
+//	product = unary { ("*" | "/") unary }
+//	unary   = ("+" | "-") unary | power
+//	power   = primary [ "^" unary ]
+//	primary = number | ident | ident "(" sum { "," sum } ")" | "(" sum ")"
+
+type exprParser struct {
+	tokens  []token
+	pos     int
+	allowed map[string]bool
+	seen    map[string]bool
+	names   []string
+}
+
+func (p *exprParser) peek() token { return p.tokens[p.pos] }
+
+func (p *exprParser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *exprParser) parseSum(depth int) (exprNode, error) {
+	if depth > maxExprDepth {
+		return nil, ErrExprTooDeep
+	}
+	left, err := p.parseProduct(depth)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		if t.kind != tokOp || (t.text != "+" && t.text != "-") {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseProduct(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: t.text[0], left: left, right: right}
+	}
+}
+
+func (p *exprParser) parseProduct(depth int) (exprNode, error) {
+	left, err := p.parseUnary(depth)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		if t.kind != tokOp || (t.text != "*" && t.text != "/") {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseUnary(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: t.text[0], left: left, right: right}
+	}
+}
+
+func (p *exprParser) parseUnary(depth int) (exprNode, error) {
+	if depth > maxExprDepth {
+		return nil, ErrExprTooDeep
+	}
+	t := p.peek()
+	if t.kind == tokOp && (t.text == "-" || t.text == "+") {
+		p.next()
+		operand, err := p.parseUnary(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if t.text == "-" {
+			return negNode{operand: operand}, nil
+		}
+		return operand, nil
+	}
+	return p.parsePower(depth)
+}
+
+func (p *exprParser) parsePower(depth int) (exprNode, error) {
+	base, err := p.parsePrimary(depth)
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind == tokOp && t.text == "^" {
+		p.next()
+		// associativo à direita: a^b^c = a^(b^c); -x^2 = -(x^2)
+		exponent, err := p.parseUnary(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return binaryNode{op: '^', left: base, right: exponent}, nil
+	}
+	return base, nil
+}
+
+func (p *exprParser) parsePrimary(depth int) (exprNode, error) {
+	t := p.next()
+	switch t.kind {
+	case tokNumber:
+		return numNode(t.num), nil
+	case tokLParen:
+		inner, err := p.parseSum(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if c := p.next(); c.kind != tokRParen {
+			return nil, fmt.Errorf("expected \")\" at position %d", c.pos+1)
+		}
+		return inner, nil
+	case tokIdent:
+		if p.peek().kind == tokLParen {
+			return p.parseCall(t, depth)
+		}
+		if v, ok := exprConsts[t.text]; ok {
+			return numNode(v), nil
+		}
+		if p.allowed != nil && !p.allowed[t.text] {
+			return nil, fmt.Errorf("unknown identifier %q at position %d", t.text, t.pos+1)
+		}
+		if !p.seen[t.text] {
+			p.seen[t.text] = true
+			p.names = append(p.names, t.text)
+		}
+		return varNode(t.text), nil
+	default:
+		return nil, fmt.Errorf("unexpected %q at position %d", t.text, t.pos+1)
+	}
+}
+
+func (p *exprParser) parseCall(name token, depth int) (exprNode, error) {
+	fn, ok := exprFuncs[name.text]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %q at position %d", name.text, name.pos+1)
+	}
+	p.next() // "("
+
+	args := make([]exprNode, 0, fn.arity)
+	for {
+		arg, err := p.parseSum(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+
+		t := p.next()
+		if t.kind == tokRParen {
+			break
+		}
+		if t.kind != tokComma {
+			return nil, fmt.Errorf("expected \",\" or \")\" at position %d", t.pos+1)
+		}
+	}
+	if len(args) != fn.arity {
+		return nil, fmt.Errorf("function %q expects %d argument(s), got %d", name.text, fn.arity, len(args))
+	}
+
+	return callNode{name: name.text, fn: fn.fn, args: args}, nil
+}
+
+// --- árvore de avaliação ---
+
+type exprNode interface {
+	eval(vars map[string]float64) (float64, error)
+}
+
+type numNode float64
+
+func (n numNode) eval(map[string]float64) (float64, error) { return float64(n), nil }
+
+type varNode string
+
+func (n varNode) eval(vars map[string]float64) (float64, error) {
+	v, ok := vars[string(n)]
+	if !ok {
+		return 0, fmt.Errorf("missing value for %q", string(n))
+	}
+	return v, nil
+}
+
+type negNode struct{ operand exprNode }
+
+func (n negNode) eval(vars map[string]float64) (float64, error) {
+	v, err := n.operand.eval(vars)
+	return -v, err
+}
+
+type binaryNode struct {
+	op          byte
+	left, right exprNode
+}
+
+func (n binaryNode) eval(vars map[string]float64) (float64, error) {
+	a, err := n.left.eval(vars)
+	if err != nil {
+		return 0, err
+	}
+	b, err := n.right.eval(vars)
+	if err != nil {
+		return 0, err
+	}
+	switch n.op {
+	case '+':
+		return a + b, nil
+	case '-':
+		return a - b, nil
+	case '*':
+		return a * b, nil
+	case '/':
+		return a / b, nil
+	default:
+		return math.Pow(a, b), nil
+	}
+}
+
+type callNode struct {
+	name string
+	fn   func(args []float64) float64
+	args []exprNode
+}
+
+func (n callNode) eval(vars map[string]float64) (float64, error) {
+	values := make([]float64, len(n.args))
+	for i, arg := range n.args {
+		v, err := arg.eval(vars)
+		if err != nil {
+			return 0, err
+		}
+		values[i] = v
+	}
+	return n.fn(values), nil
+}
@@ -0,0 +1,84 @@
+package phytometrics
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseExpr_Eval(t *testing.T) {
+	t.Parallel()
+
+	vars := map[string]float64{"x": 2, "y": 3}
+	cases := []struct {
+		src  string
+		want float64
+	}{
+		{"1 + 2 * 3", 7},
+		{"(1 + 2) * 3", 9},
+		{"2 ^ 3 ^ 2", 512}, // associativo à direita
+		{"-x ^ 2", -4},
+		{"x * -y", -6},
+		{"10 / 4 - 1", 1.5},
+		{"1.5e2 + 2E-1", 150.2},
+		{"ln(e) + log10(100) + exp(0)", 4},
+		{"sqrt(16) + abs(-2) + pow(x, y)", 14},
+		{"min(x, y) + max(x, y)", 5},
+		{"2 * PI", 2 * math.Pi},
+		{"X * Y", 6},
+	}
+	for _, c := range cases {
+		expr, err := ParseExpr(c.src, nil)
+		require.NoError(t, err, c.src)
+		v, err := expr.Eval(vars)
+		require.NoError(t, err, c.src)
+		require.InDelta(t, c.want, v, 1e-12, c.src)
+	}
+}
+
+func TestParseExpr_Errors(t *testing.T) {
+	t.Parallel()
+
+	allowed := map[string]bool{"x": true}
+	for _, src := range []string{
+		"",
+		"1 +",
+		"(1 + 2",
+		"1 2",
+		"x $ 2",
+		"y + 1",     // identificador não permitido
+		"system(1)", // função desconhecida
+		"pow(x)",    // aridade
+		"ln(x, 2)",  // aridade
+		"x..2",      // número inválido
+		"max(x 1)",  // separador ausente
+	} {
+		_, err := ParseExpr(src, allowed)
+		require.Error(t, err, src)
+	}
+
+	_, err := ParseExpr(strings.Repeat("1+", maxExprLength), nil)
+	require.ErrorIs(t, err, ErrExprTooLong)
+
+	_, err = ParseExpr(strings.Repeat("(", 100)+"1"+strings.Repeat(")", 100), nil)
+	require.ErrorIs(t, err, ErrExprTooDeep)
+}
+
+func TestExpr_EvalErrors(t *testing.T) {
+	t.Parallel()
+
+	expr, err := ParseExpr("ln(x) / y", nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"x", "y"}, expr.Names())
+
+	_, err = expr.Eval(map[string]float64{"x": 1})
+	require.Error(t, err)
+
+	_, err = expr.Eval(map[string]float64{"x": 0, "y": 1})
+	require.ErrorIs(t, err, ErrExprNotFinite)
+
+	_, err = expr.Eval(map[string]float64{"x": 1, "y": 0})
+	require.ErrorIs(t, err, ErrExprNotFinite)
+}
@@ -0,0 +1,206 @@
+// Package phytometrics concentra o cálculo dos indicadores fitossociológicos de uma análise
+// (motor de métricas). O resultado é persistido em snapshots versionados pelo serviço de
+// análises e servido na leitura, sem recalcular a cada requisição.
+package phytometrics
+
+import (
+	"math"
+
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+)
+
+const (
+	// StackingFactor converte m³ em metro estéreo (mst) quando a análise não define as regras
+	// de classificação da madeira (ver ResolveAssortment)
+	StackingFactor = 0.7
+	// CylindricalFormFactor é usado quando não há legislação nem padrão da análise
+	CylindricalFormFactor = 1.0
+	// UnknownFamily agrupa espécimes sem família cadastrada na tabela por família
+	UnknownFamily = "Indeterminada"
+)
+
+// ABI calcula a Área Basal Individual (cm²) a partir dos CAPs do espécime
+// ABI = (CAP1²)/(4π) + (CAP2²)/(4π) + ... + (CAP6²)/(4π)
+func ABI(s *types.SpecimenWithSpecies) float64 {
+	abi := (s.Cap1 * s.Cap1) / (4 * math.Pi)
+	for _, c := range []*float64{s.Cap2, s.Cap3, s.Cap4, s.Cap5, s.Cap6} {
+		if c != nil {
+			abi += (*c * *c) / (4 * math.Pi)
+		}
+	}
+	return abi
+}
+
+// DbhAndBasalFromABI retorna o DAP (cm) e a área basal (m²) a partir da ABI em cm²
+func DbhAndBasalFromABI(abiCm2 float64) (dbhCm, basalM2 float64) {
+	if abiCm2 <= 0 {
+		return 0, 0
+	}
+	// CR11.2 – CAP_mean = √( ABI × 4π )
+	capMean := math.Sqrt(abiCm2 * 4 * math.Pi) // cm
+	// CR11.3 – DBH(cm) = CAP_mean / π
+	dbhCm = capMean / math.Pi // cm
+	// CR11.4 – G(m²) = ABI / 10.000
+	basalM2 = abiCm2 / 10000.0 // m²
+	return dbhCm, basalM2
+}
+
+// VolumeFromABI calcula o volume em m³ a partir de ABI (cm²), altura (m) e fator de forma
+func VolumeFromABI(abiCm2, heightM, formFactor float64) float64 {
+	if abiCm2 <= 0 || heightM <= 0 {
+		return 0
+	}
+	// CR11.4: G(m²) = ABI / 10.000
+	g := abiCm2 / 10000.0
+	// CR11.5: Volume = G(m²) × Height(m) × ff
+	return g * heightM * formFactor
+}
+
+// ResolveFormFactor retorna o fator de forma do espécime: legislação aplicável à espécie,
+// senão o padrão da análise, senão cilíndrico (1.0)
+func ResolveFormFactor(s *types.SpecimenWithSpecies, defaultFormFactor *float64) float64 {
+	if s.FormFactor != nil && *s.FormFactor > 0 {
+		return *s.FormFactor
+	}
+	if defaultFormFactor != nil && *defaultFormFactor > 0 {
+		return *defaultFormFactor
+	}
+	return CylindricalFormFactor
+}
+
+// SpecimenMetrics representa as medidas individuais de um espécime
+type SpecimenMetrics struct {
+	ABICm2              float64
+	DbhCm               float64
+	BasalAreaM2         float64
+	VolumeM3            float64 // pela equação volumétrica (padrão: com fator de forma)
+	CylindricalVolumeM3 float64
+	FormFactor          float64
+	VolumeEquationID    string  // equação usada no volume do espécime
+	StdDevDbhCm         float64 // desvio padrão do DAP da espécie
+}
+
+// VolumeModel calcula o volume individual pela equação volumétrica selecionada na análise.
+// Sem seleção, ou para espécimes fora da aplicabilidade da equação, usa G × H × ff.
+type VolumeModel struct {
+	DefaultFormFactor *float64
+	Equation          *CompiledEquation // nil = fator de forma
+}
+
+// NewVolumeModel monta o modelo de volume da análise
+func NewVolumeModel(p *types.PhytoAnalysisComplete) *VolumeModel {
+	return &VolumeModel{
+		DefaultFormFactor: p.DefaultFormFactor,
+		Equation:          compileOrNil(p.VolumeEquation),
+	}
+}
+
+// EquationData retorna a equação volumétrica da análise (a do fator de forma quando não há seleção)
+func (m *VolumeModel) EquationData() types.EquationData {
+	if m.Equation != nil {
+		return m.Equation.Data
+	}
+	eq, _ := BuiltinEquation(FormFactorEquationID)
+	return eq.Data
+}
+
+// Volume calcula o volume (m³) do espécime a partir da ABI (cm²) e retorna a equação usada
+func (m *VolumeModel) Volume(s *types.SpecimenWithSpecies, abiCm2, formFactor float64) (float64, string) {
+	if abiCm2 <= 0 || s.Height <= 0 {
+		return 0, FormFactorEquationID
+	}
+	if m.Equation != nil && m.Equation.AppliesToSpecimen(s) {
+		dbh, g := DbhAndBasalFromABI(abiCm2)
+		density := DefaultWoodDensity
+		if s.WoodDensity != nil && *s.WoodDensity > 0 {
+			density = *s.WoodDensity
+		}
+		v, err := m.Equation.Evaluate(EquationInput{
+			DbhCm:       dbh,
+			HeightM:     s.Height,
+			BasalAreaM2: g,
+			WoodDensity: density,
+			FormFactor:  formFactor,
+		})
+		if err == nil && v >= 0 {
+			return v, m.Equation.Data.ID
+		}
+	}
+	return VolumeFromABI(abiCm2, s.Height, formFactor), FormFactorEquationID
+}
+
+// Specimen calcula as medidas de um espécime isolado (sem o desvio padrão da espécie)
+func (m *VolumeModel) Specimen(s *types.SpecimenWithSpecies) SpecimenMetrics {
+	out := SpecimenMetrics{
+		ABICm2:           ABI(s),
+		FormFactor:       ResolveFormFactor(s, m.DefaultFormFactor),
+		VolumeEquationID: FormFactorEquationID,
+	}
+	if out.ABICm2 > 0 {
+		out.DbhCm, out.BasalAreaM2 = DbhAndBasalFromABI(out.ABICm2)
+		out.VolumeM3, out.VolumeEquationID = m.Volume(s, out.ABICm2, out.FormFactor)
+		out.CylindricalVolumeM3 = VolumeFromABI(out.ABICm2, s.Height, CylindricalFormFactor)
+	}
+	return out
+}
+
+// ComputeSpecimens calcula as medidas individuais, na mesma ordem de p.Specimens
+func ComputeSpecimens(p *types.PhytoAnalysisComplete) []SpecimenMetrics {
+	out := make([]SpecimenMetrics, len(p.Specimens))
+	dapBySpecies := make(map[string][]float64)
+	model := NewVolumeModel(p)
+
+	for i, s := range p.Specimens {
+		m := model.Specimen(s)
+		out[i] = m
+
+		if key := speciesKey(s); key != "" && m.DbhCm > 0 {
+			dapBySpecies[key] = append(dapBySpecies[key], m.DbhCm)
+		}
+	}
+
+	// desvio padrão amostral (n-1) do DAP por espécie
+	stdDevBySpecies := make(map[string]float64, len(dapBySpecies))
+	for key, daps := range dapBySpecies {
+		_, variance := MeanAndVariance(daps)
+		stdDevBySpecies[key] = math.Sqrt(variance)
+	}
+	for i, s := range p.Specimens {
+		out[i].StdDevDbhCm = stdDevBySpecies[speciesKey(s)]
+	}
+
+	return out
+}
+
+// speciesKey agrupa por espécie (preferência por nome científico)
+func speciesKey(s *types.SpecimenWithSpecies) string {
+	if s.ScientificName != "" {
+		return s.ScientificName
+	}
+	return s.SpecieID
+}
+
+// MeanAndVariance retorna a média e a variância amostral (n-1)
+func MeanAndVariance(values []float64) (mean, variance float64) {
+	n := len(values)
+	if n == 0 {
+		return 0, 0
+	}
+
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean = sum / float64(n)
+
+	if n < 2 {
+		return mean, 0
+	}
+
+	var sq float64
+	for _, v := range values {
+		diff := v - mean
+		sq += diff * diff
+	}
+	return mean, sq / float64(n-1)
+}
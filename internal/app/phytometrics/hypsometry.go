@@ -0,0 +1,346 @@
+package phytometrics
+
+import (
+	"math"
+	"sort"
+
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+)
+
+// MinHypsometricSample é o número mínimo de árvores com altura medida para ajustar os modelos
+const MinHypsometricSample = 10
+
+// breastHeightM é a altura do DAP (1,30 m), assíntota inferior do modelo de Prodan
+const breastHeightM = 1.3
+
+// Modelos hipsométricos ajustados (h em m, dap em cm)
+const (
+	HypsometricCurtis     = "curtis"
+	HypsometricHenricksen = "henricksen"
+	HypsometricProdan     = "prodan"
+	HypsometricStoffels   = "stoffels"
+)
+
+// hypsometricModel descreve um modelo linearizado: y = Σ b_i·x_i, ajustado por mínimos quadrados
+type hypsometricModel struct {
+	id         string
+	name       string
+	expression string
+	logScale   bool                                 // y = ln(h): estimativas com o fator de correção de Meyer
+	terms      func(dbh float64) []float64          // variáveis independentes (inclui o intercepto)
+	response   func(dbh, h float64) (float64, bool) // variável dependente transformada
+	height     func(dbh, y float64) float64         // altura a partir do valor ajustado
+}
+
+var hypsometricModels = []hypsometricModel{
+	{
+		id:         HypsometricCurtis,
+		name:       "Curtis",
+		expression: "ln(h) = b0 + b1 / dap",
+		logScale:   true,
+		terms:      func(d float64) []float64 { return []float64{1, 1 / d} },
+		response:   func(_, h float64) (float64, bool) { return math.Log(h), true },
+		height:     func(_, y float64) float64 { return math.Exp(y) },
+	},
+	{
+		id:         HypsometricHenricksen,
+		name:       "Henricksen",
+		expression: "h = b0 + b1 * ln(dap)",
+		terms:      func(d float64) []float64 { return []float64{1, math.Log(d)} },
+		response:   func(_, h float64) (float64, bool) { return h, true },
+		height:     func(_, y float64) float64 { return y },
+	},
+	{
+		id:         HypsometricProdan,
+		name:       "Prodan",
+		expression: "h = 1.3 + dap^2 / (b0 + b1 * dap + b2 * dap^2)",
+		terms:      func(d float64) []float64 { return []float64{1, d, d * d} },
+		response: func(d, h float64) (float64, bool) {
+			if h <= breastHeightM {
+				return 0, false
+			}
+			return d * d / (h - breastHeightM), true
+		},
+		height: func(d, y float64) float64 {
+			if y <= 0 {
+				return math.NaN()
+			}
+			return breastHeightM + d*d/y
+		},
+	},
+	{
+		id:         HypsometricStoffels,
+		name:       "Stoffels",
+		expression: "ln(h) = b0 + b1 * ln(dap)",
+		logScale:   true,
+		terms:      func(d float64) []float64 { return []float64{1, math.Log(d)} },
+		response:   func(_, h float64) (float64, bool) { return math.Log(h), true },
+		height:     func(_, y float64) float64 { return math.Exp(y) },
+	},
+}
+
+// HypsometricFit representa um modelo hipsométrico ajustado e suas estatísticas (na escala da altura)
+type HypsometricFit struct {
+	Model            string    `json:"model"`
+	Name             string    `json:"name"`
+	Expression       string    `json:"expression"`
+	Coefficients     []float64 `json:"coefficients"`     // b0, b1, ...
+	CorrectionFactor float64   `json:"correctionFactor"` // Meyer, para modelos logarítmicos (1 nos demais)
+	SampleSize       int       `json:"sampleSize"`
+	R2               float64   `json:"r2"`              // coeficiente de determinação
+	AdjustedR2       float64   `json:"adjustedR2"`      // R² ajustado
+	StdErrorM        float64   `json:"stdErrorM"`       // erro padrão da estimativa (m)
+	StdErrorPercent  float64   `json:"stdErrorPercent"` // erro padrão da estimativa (%)
+}
+
+// Hypsometry representa a relação hipsométrica da análise e o preenchimento das alturas
+type Hypsometry struct {
+	Selected *HypsometricFit  `json:"selected,omitempty"` // modelo usado nas estimativas (menor erro padrão)
+	Fits     []HypsometricFit `json:"fits"`               // modelos ajustados, do melhor para o pior
+
+	MeasuredIndividuals  int `json:"measuredIndividuals"`  // indivíduos com altura medida
+	EstimatedIndividuals int `json:"estimatedIndividuals"` // indivíduos com altura estimada pelo modelo
+	MissingIndividuals   int `json:"missingIndividuals"`   // indivíduos sem altura (amostra insuficiente)
+}
+
+// Predict estima a altura (m) para o DAP informado; retorna 0 quando não há estimativa válida
+func (f *HypsometricFit) Predict(dbhCm float64) float64 {
+	if f == nil || dbhCm <= 0 {
+		return 0
+	}
+	for _, m := range hypsometricModels {
+		if m.id != f.Model {
+			continue
+		}
+		terms := m.terms(dbhCm)
+		if len(terms) != len(f.Coefficients) {
+			return 0
+		}
+		y := 0.0
+		for i, x := range terms {
+			y += f.Coefficients[i] * x
+		}
+		h := m.height(dbhCm, y)
+		if m.logScale {
+			h *= f.CorrectionFactor
+		}
+		if math.IsNaN(h) || math.IsInf(h, 0) || h <= 0 {
+			return 0
+		}
+		return h
+	}
+	return 0
+}
+
+// heightMeasured indica se o espécime tem altura medida em campo
+func heightMeasured(s *types.SpecimenWithSpecies) bool {
+	return s.Height > 0 && !s.HeightEstimated
+}
+
+// FitHypsometry ajusta os modelos hipsométricos com as árvores de altura medida.
+// Sem amostra suficiente, retorna apenas as contagens (sem modelo selecionado).
+func FitHypsometry(p *types.PhytoAnalysisComplete) *Hypsometry {
+	out := &Hypsometry{Fits: make([]HypsometricFit, 0, len(hypsometricModels))}
+
+	var dbhs, heights []float64
+	for _, s := range p.Specimens {
+		// mortos excluídos pelo protocolo não entram no ajuste
+		if !heightMeasured(s) || (p.Measurement.ExcludeDead && IsDead(s)) {
+			continue
+		}
+		out.MeasuredIndividuals++
+		if dbh, _ := DbhAndBasalFromABI(ABI(s)); dbh > 0 {
+			dbhs = append(dbhs, dbh)
+			heights = append(heights, s.Height)
+		}
+	}
+
+	if len(dbhs) >= MinHypsometricSample {
+		for _, m := range hypsometricModels {
+			if fit, ok := fitHypsometricModel(m, dbhs, heights); ok {
+				out.Fits = append(out.Fits, fit)
+			}
+		}
+		sort.SliceStable(out.Fits, func(i, j int) bool {
+			if out.Fits[i].StdErrorM != out.Fits[j].StdErrorM {
+				return out.Fits[i].StdErrorM < out.Fits[j].StdErrorM
+			}
+			return out.Fits[i].R2 > out.Fits[j].R2
+		})
+		if len(out.Fits) > 0 {
+			selected := out.Fits[0]
+			out.Selected = &selected
+		}
+	}
+
+	return out
+}
+
+// EstimateHeights preenche a altura dos espécimes sem medição com o modelo de menor erro padrão,
+// marcando-as como estimadas. Pode ser chamada novamente: as alturas estimadas são recalculadas.
+func EstimateHeights(p *types.PhytoAnalysisComplete) *Hypsometry {
+	h := FitHypsometry(p)
+	ApplyHypsometricFit(p.Specimens, h)
+	return h
+}
+
+// ApplyHypsometricFit preenche as alturas não medidas com o modelo selecionado e atualiza as contagens
+func ApplyHypsometricFit(specimens []*types.SpecimenWithSpecies, h *Hypsometry) {
+	h.EstimatedIndividuals, h.MissingIndividuals = 0, 0
+	for _, s := range specimens {
+		if heightMeasured(s) {
+			continue
+		}
+		if FillHeight(s, h.Selected) {
+			h.EstimatedIndividuals++
+		} else {
+			h.MissingIndividuals++
+		}
+	}
+}
+
+// FillHeight estima a altura do espécime sem medição com o modelo informado (nil = sem modelo).
+// Retorna true quando o espécime tem altura medida ou estimada.
+func FillHeight(s *types.SpecimenWithSpecies, fit *HypsometricFit) bool {
+	if heightMeasured(s) {
+		return true
+	}
+	s.Height, s.HeightEstimated = 0, false
+
+	dbh, _ := DbhAndBasalFromABI(ABI(s))
+	if v := fit.Predict(dbh); v > 0 {
+		s.Height, s.HeightEstimated = v, true
+		return true
+	}
+	return false
+}
+
+// fitHypsometricModel ajusta o modelo por mínimos quadrados e calcula as estatísticas na escala da altura
+func fitHypsometricModel(m hypsometricModel, dbhs, heights []float64) (HypsometricFit, bool) {
+	xs := make([][]float64, 0, len(dbhs))
+	ys := make([]float64, 0, len(dbhs))
+	usedDbh := make([]float64, 0, len(dbhs))
+	usedHeight := make([]float64, 0, len(dbhs))
+	for i, d := range dbhs {
+		y, ok := m.response(d, heights[i])
+		if !ok {
+			continue
+		}
+		xs = append(xs, m.terms(d))
+		ys = append(ys, y)
+		usedDbh = append(usedDbh, d)
+		usedHeight = append(usedHeight, heights[i])
+	}
+
+	params := len(m.terms(1))
+	n := len(ys)
+	if n < MinHypsometricSample || n <= params {
+		return HypsometricFit{}, false
+	}
+
+	coef, ok := leastSquares(xs, ys)
+	if !ok {
+		return HypsometricFit{}, false
+	}
+
+	fit := HypsometricFit{
+		Model:            m.id,
+		Name:             m.name,
+		Expression:       m.expression,
+		Coefficients:     coef,
+		CorrectionFactor: 1,
+		SampleSize:       n,
+	}
+
+	// Fator de correção de Meyer: exp(QMres/2) na escala logarítmica
+	if m.logScale {
+		var ssLog float64
+		for i, x := range xs {
+			r := ys[i] - dot(coef, x)
+			ssLog += r * r
+		}
+		fit.CorrectionFactor = math.Exp(ssLog / float64(n-params) / 2)
+	}
+
+	// Estatísticas com as alturas observadas das árvores usadas no ajuste
+	var meanH float64
+	for _, h := range usedHeight {
+		meanH += h
+	}
+	meanH /= float64(n)
+
+	var ssRes, ssTot float64
+	for i, d := range usedDbh {
+		r := usedHeight[i] - fit.Predict(d)
+		ssRes += r * r
+		t := usedHeight[i] - meanH
+		ssTot += t * t
+	}
+	if ssTot <= 0 {
+		return HypsometricFit{}, false
+	}
+
+	fit.R2 = 1 - ssRes/ssTot
+	fit.AdjustedR2 = 1 - (1-fit.R2)*float64(n-1)/float64(n-params)
+	fit.StdErrorM = math.Sqrt(ssRes / float64(n-params))
+	fit.StdErrorPercent = fit.StdErrorM / meanH * 100
+
+	return fit, true
+}
+
+// leastSquares resolve as equações normais (X'X)b = X'y por eliminação de Gauss com pivoteamento
+func leastSquares(xs [][]float64, ys []float64) ([]float64, bool) {
+	k := len(xs[0])
+	a := make([][]float64, k)
+	for i := range a {
+		a[i] = make([]float64, k+1)
+	}
+	for r, x := range xs {
+		for i := 0; i < k; i++ {
+			for j := 0; j < k; j++ {
+				a[i][j] += x[i] * x[j]
+			}
+			a[i][k] += x[i] * ys[r]
+		}
+	}
+
+	for col := 0; col < k; col++ {
+		pivot := col
+		for r := col + 1; r < k; r++ {
+			if math.Abs(a[r][col]) > math.Abs(a[pivot][col]) {
+				pivot = r
+			}
+		}
+		if math.Abs(a[pivot][col]) < 1e-12 {
+			return nil, false
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+		for r := col + 1; r < k; r++ {
+			f := a[r][col] / a[col][col]
+			for c := col; c <= k; c++ {
+				a[r][c] -= f * a[col][c]
+			}
+		}
+	}
+
+	b := make([]float64, k)
+	for i := k - 1; i >= 0; i-- {
+		v := a[i][k]
+		for j := i + 1; j < k; j++ {
+			v -= a[i][j] * b[j]
+		}
+		b[i] = v / a[i][i]
+		if math.IsNaN(b[i]) || math.IsInf(b[i], 0) {
+			return nil, false
+		}
+	}
+	return b, true
+}
+
+func dot(a, b []float64) float64 {
+	var s float64
+	for i := range a {
+		s += a[i] * b[i]
+	}
+	return s
+}
@@ -0,0 +1,116 @@
+package phytometrics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+	"github.com/stretchr/testify/require"
+)
+
+// hypsometricSample gera árvores com h = 2 + 5·ln(dap) e um desvio alternado de ±0,3 m
+func hypsometricSample(n int) []*types.SpecimenWithSpecies {
+	out := make([]*types.SpecimenWithSpecies, 0, n)
+	for i := 0; i < n; i++ {
+		dbh := 8 + float64(i)*2
+		noise := 0.3
+		if i%2 == 1 {
+			noise = -0.3
+		}
+		out = append(out, &types.SpecimenWithSpecies{
+			Portion:        "1",
+			Cap1:           dbh * math.Pi,
+			Height:         2 + 5*math.Log(dbh) + noise,
+			ScientificName: "A a",
+		})
+	}
+	return out
+}
+
+func TestFitHypsometry(t *testing.T) {
+	t.Parallel()
+
+	p := &types.PhytoAnalysisComplete{Specimens: hypsometricSample(20)}
+
+	h := FitHypsometry(p)
+
+	require.Equal(t, 20, h.MeasuredIndividuals)
+	require.Len(t, h.Fits, 4)
+	require.NotNil(t, h.Selected)
+	require.Equal(t, h.Fits[0], *h.Selected)
+	for i := 1; i < len(h.Fits); i++ {
+		require.LessOrEqual(t, h.Fits[i-1].StdErrorM, h.Fits[i].StdErrorM)
+	}
+
+	// O modelo de Henricksen reproduz a relação usada na geração dos dados
+	var henricksen *HypsometricFit
+	for i := range h.Fits {
+		if h.Fits[i].Model == HypsometricHenricksen {
+			henricksen = &h.Fits[i]
+		}
+	}
+	require.NotNil(t, henricksen)
+	require.InDelta(t, 2, henricksen.Coefficients[0], 0.5)
+	require.InDelta(t, 5, henricksen.Coefficients[1], 0.2)
+	require.Equal(t, 1.0, henricksen.CorrectionFactor)
+	require.Greater(t, henricksen.R2, 0.95)
+	require.Less(t, henricksen.AdjustedR2, henricksen.R2)
+	require.InDelta(t, 0.3, henricksen.StdErrorM, 0.05)
+	require.InDelta(t, 2+5*math.Log(30), henricksen.Predict(30), 0.2)
+
+	for _, fit := range h.Fits {
+		require.Equal(t, 20, fit.SampleSize)
+		require.Greater(t, fit.StdErrorPercent, 0.0)
+		if fit.Model == HypsometricCurtis || fit.Model == HypsometricStoffels {
+			require.Greater(t, fit.CorrectionFactor, 1.0) // correção de Meyer
+		}
+	}
+}
+
+func TestEstimateHeights(t *testing.T) {
+	t.Parallel()
+
+	p := &types.PhytoAnalysisComplete{SampledArea: 1, Specimens: hypsometricSample(12)}
+	p.Specimens = append(p.Specimens,
+		&types.SpecimenWithSpecies{Portion: "2", Cap1: 20 * math.Pi, ScientificName: "B b"},
+		&types.SpecimenWithSpecies{Portion: "2", Cap1: 0, ScientificName: "C c"}, // sem DAP
+	)
+
+	h := EstimateHeights(p)
+
+	require.Equal(t, 12, h.MeasuredIndividuals)
+	require.Equal(t, 1, h.EstimatedIndividuals)
+	require.Equal(t, 1, h.MissingIndividuals)
+
+	estimated := p.Specimens[12]
+	require.True(t, estimated.HeightEstimated)
+	require.InDelta(t, h.Selected.Predict(20), estimated.Height, 1e-12)
+	require.False(t, p.Specimens[0].HeightEstimated)
+	require.False(t, p.Specimens[13].HeightEstimated)
+
+	// Nova chamada não usa as alturas estimadas no ajuste e mantém o resultado
+	again := EstimateHeights(p)
+	require.Equal(t, h.Selected.Coefficients, again.Selected.Coefficients)
+	require.InDelta(t, h.Selected.Predict(20), estimated.Height, 1e-12)
+
+	// O resultado do motor registra a relação hipsométrica e o volume usa a altura estimada
+	result := Compute(p)
+	require.NotNil(t, result.Hypsometry)
+	require.Equal(t, 1, result.Hypsometry.EstimatedIndividuals)
+	require.Greater(t, ComputeSpecimens(p)[12].VolumeM3, 0.0)
+}
+
+func TestEstimateHeights_InsufficientSample(t *testing.T) {
+	t.Parallel()
+
+	p := &types.PhytoAnalysisComplete{Specimens: hypsometricSample(MinHypsometricSample - 1)}
+	p.Specimens = append(p.Specimens, &types.SpecimenWithSpecies{Portion: "2", Cap1: 20 * math.Pi})
+
+	h := EstimateHeights(p)
+
+	require.Nil(t, h.Selected)
+	require.Empty(t, h.Fits)
+	require.Equal(t, 0, h.EstimatedIndividuals)
+	require.Equal(t, 1, h.MissingIndividuals)
+	require.Zero(t, p.Specimens[len(p.Specimens)-1].Height)
+}
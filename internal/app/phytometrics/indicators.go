@@ -0,0 +1,534 @@
+package phytometrics
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+)
+
+// Summary representa as métricas agregadas da análise (do SUASSU-186)
+type Summary struct {
+	IndividualsCount int     `json:"individualsCount"` // Número de indivíduos (total de specimens)
+	SpeciesCount     int     `json:"speciesCount"`     // Número de espécies (scientific names únicos)
+	MeanDBHCm        float64 `json:"meanDbhCm"`        // DAP médio (cm)
+	MeanHeightM      float64 `json:"meanHeightM"`      // Altura média (m)
+	DensityIndHa     float64 `json:"densityIndHa"`     // Densidade (ind/ha)
+	VolumeTotalM3    float64 `json:"volumeTotalM3"`    // Volume total (m³)
+	VolumeTotalMst   float64 `json:"volumeTotalMst"`   // Volume total (mst)
+	VolumePerHa      float64 `json:"volumePerHa"`      // Volume (m³/ha)
+	BasalAreaPerHa   float64 `json:"basalAreaPerHa"`   // Área basal (m²/ha)
+
+	// Volumes cilíndricos (G × H, sem fator de forma); os volumes acima já aplicam o fator de forma
+	CylindricalVolumeTotalM3 float64 `json:"cylindricalVolumeTotalM3"` // Volume cilíndrico total (m³)
+	CylindricalVolumePerHa   float64 `json:"cylindricalVolumePerHa"`   // Volume cilíndrico (m³/ha)
+}
+
+// SpeciesStructure representa a linha da estrutura horizontal de uma espécie
+type SpeciesStructure struct {
+	ScientificName string  `json:"scientificName"`
+	Family         string  `json:"family"`
+	Individuals    int     `json:"individuals"` // Número de indivíduos
+	BasalArea      float64 `json:"basalArea"`   // Área basal total da espécie (m²)
+	DA             float64 `json:"da"`          // Densidade Absoluta (ind/ha)
+	DR             float64 `json:"dr"`          // Densidade Relativa (%)
+	FA             float64 `json:"fa"`          // Frequência Absoluta (%)
+	FR             float64 `json:"fr"`          // Frequência Relativa (%)
+	DoA            float64 `json:"doa"`         // Dominância Absoluta (m²/ha)
+	DoR            float64 `json:"dor"`         // Dominância Relativa (%)
+	IVI            float64 `json:"ivi"`         // Índice de Valor de Importância (DR + FR + DoR)
+	IVC            float64 `json:"ivc"`         // Índice de Valor de Cobertura (DR + DoR)
+}
+
+// FamilyStructure representa a linha da estrutura horizontal agregada por família
+type FamilyStructure struct {
+	Family       string  `json:"family"`
+	SpeciesCount int     `json:"speciesCount"` // Número de espécies da família
+	Individuals  int     `json:"individuals"`  // Número de indivíduos
+	BasalArea    float64 `json:"basalArea"`    // Área basal total da família (m²)
+	DA           float64 `json:"da"`           // Densidade Absoluta (ind/ha)
+	DR           float64 `json:"dr"`           // Densidade Relativa (%)
+	FA           float64 `json:"fa"`           // Frequência Absoluta (%)
+	FR           float64 `json:"fr"`           // Frequência Relativa (%)
+	DoA          float64 `json:"doa"`          // Dominância Absoluta (m²/ha)
+	DoR          float64 `json:"dor"`          // Dominância Relativa (%)
+	IVI          float64 `json:"ivi"`          // Índice de Valor de Importância (DR + FR + DoR)
+	IVC          float64 `json:"ivc"`          // Índice de Valor de Cobertura (DR + DoR)
+}
+
+// CollectorCurvePoint representa um ponto da curva coletor
+type CollectorCurvePoint struct {
+	CumulativeArea  float64 `json:"cumulativeArea"`  // Área acumulada (m²)
+	ObservedSpecies int     `json:"observedSpecies"` // Número acumulado de espécies observadas
+}
+
+// CollectorCurve representa os dados da curva coletor, na ordem de levantamento das parcelas.
+// A curva média com IC, por aleatorizações da ordem, é a de ComputeSpeciesAccumulation.
+type CollectorCurve struct {
+	Points []CollectorCurvePoint `json:"points"` // Pontos da curva
+}
+
+// Indicators representa os indicadores fitossociológicos da análise (do SUASSU-284)
+type Indicators struct {
+	// Campos básicos
+	IndividualsCount int     `json:"individualsCount"` // Número de indivíduos
+	SpeciesCount     int     `json:"speciesCount"`     // Número de espécies
+	PlotsCount       int     `json:"plotsCount"`       // Número de parcelas
+	PlotsArea        float64 `json:"plotsArea"`        // Área de parcelas (m²)
+
+	// Campos calculados
+	Density              *float64 `json:"density,omitempty"`              // Densidade Geral (ind/ha)
+	BasalArea            *float64 `json:"basalArea,omitempty"`            // Área basal (m²/ha)
+	Volume               *float64 `json:"volume,omitempty"`               // Volume (m³/ha)
+	ReplacementVolume    *float64 `json:"replacementVolume,omitempty"`    // Volume de reposição (m³)
+	ReplacementVolumeMst *float64 `json:"replacementVolumeMst,omitempty"` // Volume de reposição (mst)
+
+	CylindricalVolume            *float64 `json:"cylindricalVolume,omitempty"`            // Volume cilíndrico (m³/ha)
+	CylindricalReplacementVolume *float64 `json:"cylindricalReplacementVolume,omitempty"` // Volume de reposição cilíndrico (m³)
+	SampledAreaHa                *float64 `json:"sampledAreaHa,omitempty"`                // Área amostrada (ha)
+	ShannonIndex                 *float64 `json:"shannonIndex,omitempty"`                 // Índice de Shannon (H')
+	SimpsonIndex                 *float64 `json:"simpsonIndex,omitempty"`                 // Índice de Simpson (D)
+	PielouEvennessIndex          *float64 `json:"pielouEvennessIndex,omitempty"`          // Índice de Equabilidade de Pielou (J')
+	InverseSimpsonIndex          *float64 `json:"inverseSimpsonIndex,omitempty"`          // Inverso de Simpson (1/D)
+	MargalefIndex                *float64 `json:"margalefIndex,omitempty"`                // Índice de Margalef ((S-1)/ln N)
+	MenhinickIndex               *float64 `json:"menhinickIndex,omitempty"`               // Índice de Menhinick (S/√N)
+
+	// Dados para gráficos
+	SpeciesData    []SpeciesStructure `json:"speciesData,omitempty"`    // Estrutura horizontal por espécie (ordenada por IVI)
+	FamilyData     []FamilyStructure  `json:"familyData,omitempty"`     // Estrutura horizontal por família (ordenada por IVI)
+	CollectorCurve *CollectorCurve    `json:"collectorCurve,omitempty"` // Dados da curva coletor
+}
+
+// Result é o resultado completo do motor para uma análise (conteúdo do snapshot)
+type Result struct {
+	Summary    Summary     `json:"summary"`
+	Indicators *Indicators `json:"indicators"`
+	Biomass    *Biomass    `json:"biomass,omitempty"`    // ausente em snapshots anteriores à versão 2 do motor
+	Equations  *Equations  `json:"equations,omitempty"`  // ausente em snapshots anteriores à versão 3 do motor
+	Hypsometry *Hypsometry `json:"hypsometry,omitempty"` // ausente em snapshots anteriores à versão 4 do motor
+	Ecology    *Ecology    `json:"ecology,omitempty"`    // ausente em snapshots anteriores à versão 5 do motor
+}
+
+// Equations registra as equações usadas no resultado (cópia da definição no momento do cálculo)
+type Equations struct {
+	Volume  types.EquationData `json:"volume"`
+	Biomass types.EquationData `json:"biomass"`
+}
+
+// IsDead indica se o indivíduo foi registrado como morto (em pé ou caído)
+func IsDead(s *types.SpecimenWithSpecies) bool {
+	return s.Status == types.SpecimenStatusDeadStanding || s.Status == types.SpecimenStatusFallen
+}
+
+// ExcludeDead remove os indivíduos mortos quando o protocolo da análise os exclui dos indicadores
+func ExcludeDead(p *types.PhytoAnalysisComplete) {
+	p.Specimens = WithoutDead(p).Specimens
+}
+
+// WithoutDead retorna a análise com os espécimes usados nos indicadores (sem os mortos quando o
+// protocolo os exclui), sem alterar p; os espécimes são compartilhados com a original
+func WithoutDead(p *types.PhytoAnalysisComplete) *types.PhytoAnalysisComplete {
+	if !p.Measurement.ExcludeDead {
+		return p
+	}
+	out := *p
+	out.Specimens = make([]*types.SpecimenWithSpecies, 0, len(p.Specimens))
+	for _, s := range p.Specimens {
+		if !IsDead(s) {
+			out.Specimens = append(out.Specimens, s)
+		}
+	}
+	return &out
+}
+
+// Compute calcula as métricas agregadas e os indicadores da análise.
+// Os mortos saem do cálculo conforme o protocolo (ver ExcludeDead) e as alturas não medidas
+// são preenchidas pela relação hipsométrica (ver EstimateHeights).
+func Compute(p *types.PhytoAnalysisComplete) *Result {
+	ExcludeDead(p)
+	hypsometry := EstimateHeights(p)
+	specimens := ComputeSpecimens(p)
+	return &Result{
+		Summary:    ComputeSummary(p, specimens),
+		Indicators: ComputeIndicators(p),
+		Biomass:    ComputeDefaultBiomass(p),
+		Equations:  UsedEquations(p),
+		Hypsometry: hypsometry,
+		Ecology:    ComputeEcology(p, specimens),
+	}
+}
+
+// UsedEquations retorna as equações de volume e biomassa usadas no cálculo da análise
+func UsedEquations(p *types.PhytoAnalysisComplete) *Equations {
+	return &Equations{
+		Volume:  NewVolumeModel(p).EquationData(),
+		Biomass: BiomassEquation(p).Data,
+	}
+}
+
+// ComputeSummary agrega as medidas individuais (specimens na mesma ordem de p.Specimens)
+func ComputeSummary(p *types.PhytoAnalysisComplete, specimens []SpecimenMetrics) Summary {
+	var out Summary
+	var sumDbhCm, sumHeight, sumBasal float64
+
+	uniqueSpecies := make(map[string]bool)
+	for i, s := range p.Specimens {
+		m := specimens[i]
+		sumDbhCm += m.DbhCm
+		sumBasal += m.BasalAreaM2
+		out.VolumeTotalM3 += m.VolumeM3
+		out.CylindricalVolumeTotalM3 += m.CylindricalVolumeM3
+
+		if s.Height > 0 {
+			sumHeight += s.Height
+		}
+		if s.ScientificName != "" {
+			uniqueSpecies[s.ScientificName] = true
+		}
+	}
+
+	n := len(p.Specimens)
+	out.IndividualsCount = n
+	out.SpeciesCount = len(uniqueSpecies)
+
+	if n > 0 {
+		out.MeanDBHCm = sumDbhCm / float64(n)
+		out.MeanHeightM = sumHeight / float64(n)
+	}
+
+	if p.SampledArea > 0 {
+		out.DensityIndHa = float64(n) / p.SampledArea
+		out.VolumePerHa = out.VolumeTotalM3 / p.SampledArea
+		out.CylindricalVolumePerHa = out.CylindricalVolumeTotalM3 / p.SampledArea
+		out.BasalAreaPerHa = sumBasal / p.SampledArea
+	}
+
+	if stacking := StackingFactorOf(p); stacking > 0 {
+		out.VolumeTotalMst = out.VolumeTotalM3 / stacking
+	}
+
+	return out
+}
+
+// ComputeIndicators calcula todos os indicadores fitossociológicos
+func ComputeIndicators(p *types.PhytoAnalysisComplete) *Indicators {
+	if len(p.Specimens) == 0 {
+		return &Indicators{
+			IndividualsCount: 0,
+			SpeciesCount:     0,
+			PlotsCount:       p.PortionQuantity,
+			PlotsArea:        p.PortionArea * float64(p.PortionQuantity),
+		}
+	}
+
+	N := len(p.Specimens) // Número total de indivíduos
+
+	// n_i: número de indivíduos por espécie
+	speciesCount := SpeciesAbundances(p.Specimens)
+
+	S := len(speciesCount) // Número de espécies
+	P := p.PortionQuantity // Número de parcelas
+
+	// Área de parcelas (m²) e área amostrada (ha)
+	plotsArea := p.PortionArea * float64(P)
+	sampledAreaHa := plotsArea / 10000.0
+
+	// Densidade (ind/ha)
+	var density *float64
+	if sampledAreaHa > 0 {
+		d := float64(N) / sampledAreaHa
+		density = &d
+	}
+
+	// Área basal total e volume total
+	var totalBasalArea float64 // em m²
+	var totalVolume float64    // em m³ (com fator de forma)
+	var totalCylVolume float64 // em m³ (cilíndrico)
+
+	volumeModel := NewVolumeModel(p)
+	for _, s := range p.Specimens {
+		abi := ABI(s)
+		_, g := DbhAndBasalFromABI(abi)
+
+		totalBasalArea += g
+		v, _ := volumeModel.Volume(s, abi, ResolveFormFactor(s, p.DefaultFormFactor))
+		totalVolume += v
+		totalCylVolume += VolumeFromABI(abi, s.Height, CylindricalFormFactor)
+	}
+
+	// Área basal (m²/ha) e volume (m³/ha)
+	var basalArea, volume, cylindricalVolume *float64
+	if sampledAreaHa > 0 {
+		ba := totalBasalArea / sampledAreaHa
+		basalArea = &ba
+		v := totalVolume / sampledAreaHa
+		volume = &v
+		cv := totalCylVolume / sampledAreaHa
+		cylindricalVolume = &cv
+	}
+
+	// Volume de reposição (m³) - valor agregado, não dividido por ha
+	replacementVolume := totalVolume
+	cylindricalReplacementVolume := totalCylVolume
+
+	var replacementVolumeMst *float64
+	if stacking := StackingFactorOf(p); stacking > 0 {
+		mst := replacementVolume / stacking
+		replacementVolumeMst = &mst
+	}
+
+	// Índices de diversidade
+	var shannonIndex, simpsonIndex *float64
+	diversity := ComputeDiversity(speciesCount)
+	if S > 0 && N > 0 {
+		// Índice de Shannon: H' = -Σ(p_i × ln(p_i))
+		shannonIndex = &diversity.Shannon
+		// Índice de Simpson: D = Σ(p_i²)
+		simpsonIndex = &diversity.Simpson
+	}
+
+	// Estrutura horizontal por espécie e por família (DA, DR, FA, FR, DoA, DoR, IVI, IVC)
+	speciesData, familyData := ComputeHorizontalStructure(p.Specimens, P, sampledAreaHa)
+
+	return &Indicators{
+		IndividualsCount:     N,
+		SpeciesCount:         S,
+		PlotsCount:           P,
+		PlotsArea:            plotsArea,
+		Density:              density,
+		BasalArea:            basalArea,
+		Volume:               volume,
+		ReplacementVolume:    &replacementVolume,
+		ReplacementVolumeMst: replacementVolumeMst,
+		SampledAreaHa:        &sampledAreaHa,
+		ShannonIndex:         shannonIndex,
+		SimpsonIndex:         simpsonIndex,
+		PielouEvennessIndex:  diversity.Pielou(),
+		InverseSimpsonIndex:  diversity.InverseSimpson(),
+		MargalefIndex:        diversity.Margalef(),
+		MenhinickIndex:       diversity.Menhinick(),
+		SpeciesData:          speciesData,
+		FamilyData:           familyData,
+		CollectorCurve:       ComputeCollectorCurve(p),
+
+		CylindricalVolume:            cylindricalVolume,
+		CylindricalReplacementVolume: &cylindricalReplacementVolume,
+	}
+}
+
+// ComputeCollectorCurve calcula os dados da curva coletor
+// Retorna pontos com área acumulada e número acumulado de espécies
+// Os specimens já vêm ordenados por parcela e data do banco de dados; a área acumulada soma a
+// área de cada parcela (ComputePlots)
+func ComputeCollectorCurve(p *types.PhytoAnalysisComplete) *CollectorCurve {
+	specimens := p.Specimens
+	if len(specimens) == 0 {
+		return nil
+	}
+
+	// Agrupar por parcela mantendo a ordem
+	type plotData struct {
+		plot    string
+		species map[string]bool
+	}
+
+	plotList := make([]plotData, 0)
+	plotMap := make(map[string]int) // parcela -> índice na lista
+
+	for _, s := range specimens {
+		if s.ScientificName == "" {
+			continue
+		}
+
+		idx, exists := plotMap[s.Portion]
+		if !exists {
+			idx = len(plotList)
+			plotList = append(plotList, plotData{
+				plot:    s.Portion,
+				species: make(map[string]bool),
+			})
+			plotMap[s.Portion] = idx
+		}
+
+		plotList[idx].species[s.ScientificName] = true
+	}
+
+	// Pontos da curva, a partir de (0, 0)
+	points := make([]CollectorCurvePoint, 0, len(plotList)+1)
+	points = append(points, CollectorCurvePoint{})
+
+	areas := plotAreaByCode(p)
+	cumulativeArea := 0.0
+	observedSpeciesSet := make(map[string]bool)
+	for _, plotInfo := range plotList {
+		cumulativeArea += areas[plotInfo.plot]
+		for species := range plotInfo.species {
+			observedSpeciesSet[species] = true
+		}
+
+		points = append(points, CollectorCurvePoint{
+			CumulativeArea:  cumulativeArea,
+			ObservedSpecies: len(observedSpeciesSet),
+		})
+	}
+
+	return &CollectorCurve{Points: points}
+}
+
+// structureGroup acumula os dados brutos de um grupo (espécie ou família) da estrutura horizontal
+type structureGroup struct {
+	family      string
+	individuals int
+	basalArea   float64 // m²
+	plots       map[string]bool
+	species     map[string]bool
+}
+
+// structureValues são os parâmetros fitossociológicos calculados para um grupo
+type structureValues struct {
+	DA, DR, FA, FR, DoA, DoR, IVI, IVC float64
+}
+
+// ComputeHorizontalStructure monta a tabela de estrutura horizontal por espécie e por família,
+// ambas ordenadas por IVI (decrescente)
+//
+//	DA = n_i / área(ha)          DR = n_i / N × 100
+//	FA = P_i / P × 100           FR = FA_i / ΣFA × 100
+//	DoA = G_i / área(ha)         DoR = G_i / ΣG × 100
+//	IVI = DR + FR + DoR          IVC = DR + DoR
+func ComputeHorizontalStructure(specimens []*types.SpecimenWithSpecies, plotsCount int, sampledAreaHa float64) ([]SpeciesStructure, []FamilyStructure) {
+	bySpecies := make(map[string]*structureGroup)
+	byFamily := make(map[string]*structureGroup)
+
+	addTo := func(groups map[string]*structureGroup, key string, s *types.SpecimenWithSpecies, g float64) {
+		grp, ok := groups[key]
+		if !ok {
+			grp = &structureGroup{plots: make(map[string]bool), species: make(map[string]bool)}
+			groups[key] = grp
+		}
+		grp.individuals++
+		grp.basalArea += g
+		grp.plots[s.Portion] = true
+		grp.species[s.ScientificName] = true
+		if grp.family == "" {
+			grp.family = s.Family
+		}
+	}
+
+	for _, s := range specimens {
+		if s.ScientificName == "" {
+			continue
+		}
+		_, g := DbhAndBasalFromABI(ABI(s))
+
+		family := strings.TrimSpace(s.Family)
+		if family == "" {
+			family = UnknownFamily
+		}
+
+		addTo(bySpecies, s.ScientificName, s, g)
+		addTo(byFamily, family, s, g)
+	}
+
+	speciesValues := computeStructureValues(bySpecies, plotsCount, sampledAreaHa)
+	speciesData := make([]SpeciesStructure, 0, len(bySpecies))
+	for name, grp := range bySpecies {
+		v := speciesValues[name]
+		speciesData = append(speciesData, SpeciesStructure{
+			ScientificName: name,
+			Family:         grp.family,
+			Individuals:    grp.individuals,
+			BasalArea:      grp.basalArea,
+			DA:             v.DA,
+			DR:             v.DR,
+			FA:             v.FA,
+			FR:             v.FR,
+			DoA:            v.DoA,
+			DoR:            v.DoR,
+			IVI:            v.IVI,
+			IVC:            v.IVC,
+		})
+	}
+	sort.Slice(speciesData, func(i, j int) bool {
+		if speciesData[i].IVI != speciesData[j].IVI {
+			return speciesData[i].IVI > speciesData[j].IVI
+		}
+		return speciesData[i].ScientificName < speciesData[j].ScientificName
+	})
+
+	familyValues := computeStructureValues(byFamily, plotsCount, sampledAreaHa)
+	familyData := make([]FamilyStructure, 0, len(byFamily))
+	for name, grp := range byFamily {
+		v := familyValues[name]
+		familyData = append(familyData, FamilyStructure{
+			Family:       name,
+			SpeciesCount: len(grp.species),
+			Individuals:  grp.individuals,
+			BasalArea:    grp.basalArea,
+			DA:           v.DA,
+			DR:           v.DR,
+			FA:           v.FA,
+			FR:           v.FR,
+			DoA:          v.DoA,
+			DoR:          v.DoR,
+			IVI:          v.IVI,
+			IVC:          v.IVC,
+		})
+	}
+	sort.Slice(familyData, func(i, j int) bool {
+		if familyData[i].IVI != familyData[j].IVI {
+			return familyData[i].IVI > familyData[j].IVI
+		}
+		return familyData[i].Family < familyData[j].Family
+	})
+
+	return speciesData, familyData
+}
+
+// computeStructureValues calcula os parâmetros absolutos e relativos de cada grupo.
+// Os totais são somados em ordem estável para que o snapshot seja reprodutível.
+func computeStructureValues(groups map[string]*structureGroup, plotsCount int, sampledAreaHa float64) map[string]structureValues {
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var totalIndividuals int
+	var totalBasalArea, totalFA float64
+
+	fa := make(map[string]float64, len(groups))
+	for _, key := range keys {
+		grp := groups[key]
+		totalIndividuals += grp.individuals
+		totalBasalArea += grp.basalArea
+		if plotsCount > 0 {
+			fa[key] = float64(len(grp.plots)) / float64(plotsCount) * 100.0
+		}
+		totalFA += fa[key]
+	}
+
+	out := make(map[string]structureValues, len(groups))
+	for key, grp := range groups {
+		var v structureValues
+		v.FA = fa[key]
+		if sampledAreaHa > 0 {
+			v.DA = float64(grp.individuals) / sampledAreaHa
+			v.DoA = grp.basalArea / sampledAreaHa
+		}
+		if totalIndividuals > 0 {
+			v.DR = float64(grp.individuals) / float64(totalIndividuals) * 100.0
+		}
+		if totalFA > 0 {
+			v.FR = v.FA / totalFA * 100.0
+		}
+		if totalBasalArea > 0 {
+			v.DoR = grp.basalArea / totalBasalArea * 100.0
+		}
+		v.IVC = v.DR + v.DoR
+		v.IVI = v.IVC + v.FR
+		out[key] = v
+	}
+
+	return out
+}
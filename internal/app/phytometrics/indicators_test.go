@@ -0,0 +1,76 @@
+package phytometrics
+
+import (
+	"testing"
+
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeHorizontalStructure(t *testing.T) {
+	t.Parallel()
+
+	// 2 parcelas em 1 ha: A ocorre nas duas (2 ind.), B e C em uma (1 ind. cada)
+	specimens := []*types.SpecimenWithSpecies{
+		{Portion: "1", Cap1: 100, ScientificName: "A a", Family: "Fabaceae"},
+		{Portion: "2", Cap1: 100, ScientificName: "A a", Family: "Fabaceae"},
+		{Portion: "1", Cap1: 200, ScientificName: "B b", Family: "Fabaceae"},
+		{Portion: "2", Cap1: 100, ScientificName: "C c"},
+	}
+
+	speciesData, familyData := ComputeHorizontalStructure(specimens, 2, 1)
+	require.Len(t, speciesData, 3)
+
+	var sumDR, sumFR, sumDoR, sumIVI float64
+	for _, sp := range speciesData {
+		sumDR += sp.DR
+		sumFR += sp.FR
+		sumDoR += sp.DoR
+		sumIVI += sp.IVI
+		require.InDelta(t, sp.DR+sp.DoR, sp.IVC, 1e-9)
+	}
+	require.InDelta(t, 100, sumDR, 1e-9)
+	require.InDelta(t, 100, sumFR, 1e-9)
+	require.InDelta(t, 100, sumDoR, 1e-9)
+	require.InDelta(t, 300, sumIVI, 1e-9)
+
+	// G(CAP 200) = 4 × G(CAP 100): ΣG = 7 unidades, A = 2, B = 4, C = 1
+	a := speciesData[0]
+	require.Equal(t, "A a", a.ScientificName)
+	require.Equal(t, 2, a.Individuals)
+	require.InDelta(t, 2.0, a.DA, 1e-9)
+	require.InDelta(t, 50, a.DR, 1e-9)
+	require.InDelta(t, 100, a.FA, 1e-9)
+	require.InDelta(t, 50, a.FR, 1e-9)
+	require.InDelta(t, 200.0/7.0, a.DoR, 1e-9)
+	require.InDelta(t, 100+200.0/7.0, a.IVI, 1e-9)
+	require.Equal(t, "B b", speciesData[1].ScientificName)
+	require.InDelta(t, 400.0/7.0, speciesData[1].DoR, 1e-9)
+	require.Equal(t, "C c", speciesData[2].ScientificName)
+
+	require.Len(t, familyData, 2)
+	require.Equal(t, "Fabaceae", familyData[0].Family)
+	require.Equal(t, 2, familyData[0].SpeciesCount)
+	require.Equal(t, 3, familyData[0].Individuals)
+	require.Equal(t, UnknownFamily, familyData[1].Family)
+}
+
+func TestExcludeDead(t *testing.T) {
+	t.Parallel()
+
+	specimens := []*types.SpecimenWithSpecies{
+		{Portion: "1", Cap1: 100, Status: types.SpecimenStatusAlive},
+		{Portion: "1", Cap1: 100, Status: types.SpecimenStatusDeadStanding},
+		{Portion: "1", Cap1: 100, Status: types.SpecimenStatusFallen},
+		{Portion: "1", Cap1: 100, Status: types.SpecimenStatusResprouting},
+	}
+
+	kept := &types.PhytoAnalysisComplete{Specimens: specimens}
+	ExcludeDead(kept)
+	require.Len(t, kept.Specimens, 4)
+
+	p := &types.PhytoAnalysisComplete{Measurement: types.MeasurementProtocol{ExcludeDead: true}, Specimens: specimens}
+	ExcludeDead(p)
+	require.Len(t, p.Specimens, 2)
+	require.Equal(t, types.SpecimenStatusResprouting, p.Specimens[1].Status)
+}
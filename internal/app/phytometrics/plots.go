@@ -0,0 +1,118 @@
+package phytometrics
+
+import (
+	"sort"
+
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+)
+
+// PlotMetrics representa os totais de uma parcela e os valores extrapolados por hectare
+type PlotMetrics struct {
+	Code         string  `json:"code"`
+	AreaM2       float64 `json:"areaM2"`
+	Registered   bool    `json:"registered"` // parcela cadastrada (false = apenas o código dos espécimes)
+	Individuals  int     `json:"individuals"`
+	SpeciesCount int     `json:"speciesCount"`
+	MeanDBHCm    float64 `json:"meanDbhCm"`
+	MeanHeightM  float64 `json:"meanHeightM"` // inclui alturas estimadas pela relação hipsométrica
+	BasalAreaM2  float64 `json:"basalAreaM2"`
+	VolumeM3     float64 `json:"volumeM3"` // com fator de forma ou equação selecionada
+
+	DensityIndHa   float64 `json:"densityIndHa"`
+	BasalAreaPerHa float64 `json:"basalAreaPerHa"`
+	VolumePerHa    float64 `json:"volumePerHa"`
+}
+
+// PlotSampling retorna o número de parcelas, a área média (m²) e a área amostrada (ha)
+// derivados das parcelas cadastradas
+func PlotSampling(plots []*types.PlotData) (quantity int, meanAreaM2, sampledAreaHa float64) {
+	var total float64
+	for _, pl := range plots {
+		total += pl.AreaM2
+	}
+	if len(plots) == 0 {
+		return 0, 0, 0
+	}
+	return len(plots), total / float64(len(plots)), total / 10000.0
+}
+
+// plotAreaByCode retorna a área (m²) de cada parcela pelo código, como em ComputePlots
+func plotAreaByCode(p *types.PhytoAnalysisComplete) map[string]float64 {
+	plots := ComputePlots(p)
+	out := make(map[string]float64, len(plots))
+	for _, pl := range plots {
+		out[pl.Code] = pl.AreaM2
+	}
+	return out
+}
+
+// ComputePlots agrega os espécimes por parcela, ordenadas pelo código. Parcelas cadastradas
+// sem indivíduos entram com zero; sem cadastro, a área de cada parcela é PortionArea.
+func ComputePlots(p *types.PhytoAnalysisComplete) []PlotMetrics {
+	type acc struct {
+		out     PlotMetrics
+		species map[string]bool
+		dbh     float64
+		height  float64
+		heights int
+	}
+
+	byCode := make(map[string]*acc)
+	get := func(code string) *acc {
+		a, ok := byCode[code]
+		if !ok {
+			a = &acc{out: PlotMetrics{Code: code, AreaM2: p.PortionArea}, species: make(map[string]bool)}
+			byCode[code] = a
+		}
+		return a
+	}
+
+	for _, pl := range p.Plots {
+		a := get(pl.Code)
+		a.out.AreaM2 = pl.AreaM2
+		a.out.Registered = true
+	}
+
+	metrics := ComputeSpecimens(p)
+	for i, s := range p.Specimens {
+		a := get(s.Portion)
+		m := metrics[i]
+
+		a.out.Individuals++
+		a.species[s.ScientificName] = true
+		a.dbh += m.DbhCm
+		a.out.BasalAreaM2 += m.BasalAreaM2
+		a.out.VolumeM3 += m.VolumeM3
+		if s.Height > 0 {
+			a.height += s.Height
+			a.heights++
+		}
+	}
+
+	codes := make([]string, 0, len(byCode))
+	for code := range byCode {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	out := make([]PlotMetrics, 0, len(codes))
+	for _, code := range codes {
+		a := byCode[code]
+		pm := a.out
+		pm.SpeciesCount = len(a.species)
+		if pm.Individuals > 0 {
+			pm.MeanDBHCm = a.dbh / float64(pm.Individuals)
+		}
+		if a.heights > 0 {
+			pm.MeanHeightM = a.height / float64(a.heights)
+		}
+		if pm.AreaM2 > 0 {
+			expansion := 10000.0 / pm.AreaM2
+			pm.DensityIndHa = float64(pm.Individuals) * expansion
+			pm.BasalAreaPerHa = pm.BasalAreaM2 * expansion
+			pm.VolumePerHa = pm.VolumeM3 * expansion
+		}
+		out = append(out, pm)
+	}
+	return out
+}
@@ -0,0 +1,68 @@
+package phytometrics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlotSampling(t *testing.T) {
+	t.Parallel()
+
+	quantity, meanArea, sampledArea := PlotSampling([]*types.PlotData{{AreaM2: 100}, {AreaM2: 400}})
+	require.Equal(t, 2, quantity)
+	require.Equal(t, 250.0, meanArea)
+	require.Equal(t, 0.05, sampledArea)
+
+	quantity, meanArea, sampledArea = PlotSampling(nil)
+	require.Zero(t, quantity)
+	require.Zero(t, meanArea)
+	require.Zero(t, sampledArea)
+}
+
+func TestComputePlots(t *testing.T) {
+	t.Parallel()
+
+	p := &types.PhytoAnalysisComplete{
+		PortionArea: 100,
+		Plots: []*types.PlotData{
+			{Code: "P2", AreaM2: 400},
+			{Code: "P1", AreaM2: 200},
+			{Code: "P3", AreaM2: 200}, // sem indivíduos
+		},
+		Specimens: []*types.SpecimenWithSpecies{
+			{Portion: "P1", Cap1: 10 * math.Pi, Height: 8, ScientificName: "A a"},
+			{Portion: "P1", Cap1: 30 * math.Pi, Height: 12, ScientificName: "B b"},
+			{Portion: "P2", Cap1: 20 * math.Pi, Height: 10, ScientificName: "A a"},
+			{Portion: "X", Cap1: 20 * math.Pi, Height: 10, ScientificName: "A a"}, // sem cadastro
+		},
+	}
+
+	out := ComputePlots(p)
+
+	require.Len(t, out, 4)
+	require.Equal(t, []string{"P1", "P2", "P3", "X"}, []string{out[0].Code, out[1].Code, out[2].Code, out[3].Code})
+
+	p1 := out[0]
+	require.True(t, p1.Registered)
+	require.Equal(t, 2, p1.Individuals)
+	require.Equal(t, 2, p1.SpeciesCount)
+	require.InDelta(t, 20, p1.MeanDBHCm, 1e-9)
+	require.InDelta(t, 10, p1.MeanHeightM, 1e-9)
+	require.InDelta(t, 100, p1.DensityIndHa, 1e-9) // 2 ind em 200 m²
+	require.InDelta(t, p1.BasalAreaM2*50, p1.BasalAreaPerHa, 1e-9)
+
+	// a área de cada parcela define a expansão por hectare
+	require.InDelta(t, 25, out[1].DensityIndHa, 1e-9)
+
+	require.True(t, out[2].Registered)
+	require.Zero(t, out[2].Individuals)
+	require.Zero(t, out[2].DensityIndHa)
+
+	// parcela sem cadastro usa a área por parcela da análise
+	require.False(t, out[3].Registered)
+	require.Equal(t, 100.0, out[3].AreaM2)
+	require.InDelta(t, 100, out[3].DensityIndHa, 1e-9)
+}
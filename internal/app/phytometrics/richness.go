@@ -0,0 +1,244 @@
+package phytometrics
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+)
+
+// Padrões da curva de acumulação aleatorizada
+const (
+	DefaultAccumulationPermutations = 100
+	MaxAccumulationPermutations     = 1000
+)
+
+// AccumulationOptions define o número de permutações e a semente do gerador aleatório
+type AccumulationOptions struct {
+	Permutations int
+	Seed         int64 // mesma semente e mesmos dados geram a mesma curva
+}
+
+// AccumulationPoint representa a riqueza esperada para um número de parcelas
+type AccumulationPoint struct {
+	Plots          int     `json:"plots"`          // Número de parcelas acumuladas
+	CumulativeArea float64 `json:"cumulativeArea"` // Área acumulada (m²)
+	MeanSpecies    float64 `json:"meanSpecies"`    // Média de espécies entre as permutações
+	StdDev         float64 `json:"stdDev"`         // Desvio padrão entre as permutações
+	Lower          float64 `json:"lower"`          // Percentil 2,5%
+	Upper          float64 `json:"upper"`          // Percentil 97,5%
+}
+
+// RichnessEstimators representa os estimadores não paramétricos de riqueza
+type RichnessEstimators struct {
+	ObservedSpecies int     `json:"observedSpecies"` // S_obs
+	Singletons      int     `json:"singletons"`      // F1: espécies com 1 indivíduo
+	Doubletons      int     `json:"doubletons"`      // F2: espécies com 2 indivíduos
+	Uniques         int     `json:"uniques"`         // Q1: espécies em 1 parcela
+	Duplicates      int     `json:"duplicates"`      // Q2: espécies em 2 parcelas
+	Chao1           float64 `json:"chao1"`           // Chao 1 (abundância, com correção de viés)
+	Chao2           float64 `json:"chao2"`           // Chao 2 (incidência, com correção de viés)
+	Jackknife1      float64 `json:"jackknife1"`      // Jackknife de 1ª ordem
+	Jackknife2      float64 `json:"jackknife2"`      // Jackknife de 2ª ordem
+	Bootstrap       float64 `json:"bootstrap"`       // Bootstrap
+}
+
+// SpeciesAccumulation representa a curva de acumulação aleatorizada e os estimadores
+type SpeciesAccumulation struct {
+	Permutations int                 `json:"permutations"`
+	Seed         int64               `json:"seed"`
+	PlotsCount   int                 `json:"plotsCount"` // inclui parcelas sem indivíduos
+	Curve        []AccumulationPoint `json:"curve"`
+	Estimators   RichnessEstimators  `json:"estimators"`
+}
+
+// plotIncidence representa as espécies presentes em cada parcela, a área de cada parcela
+// e a abundância por espécie
+type plotIncidence struct {
+	plots     []map[string]bool
+	areas     []float64 // área (m²) de cada parcela, na ordem de plots
+	abundance map[string]int
+}
+
+// buildPlotIncidence monta a matriz de presença por parcela, com a área de cada uma (ComputePlots).
+// Parcelas cadastradas sem indivíduos e as demais previstas em PortionQuantity entram vazias.
+func buildPlotIncidence(p *types.PhytoAnalysisComplete) plotIncidence {
+	index := make(map[string]int)
+	areas := plotAreaByCode(p)
+	inc := plotIncidence{abundance: make(map[string]int)}
+	add := func(code string) int {
+		idx, ok := index[code]
+		if !ok {
+			idx = len(inc.plots)
+			index[code] = idx
+			inc.plots = append(inc.plots, make(map[string]bool))
+			inc.areas = append(inc.areas, areas[code])
+		}
+		return idx
+	}
+
+	for _, s := range p.Specimens {
+		if s.ScientificName == "" {
+			continue
+		}
+		idx := add(s.Portion)
+		inc.plots[idx][s.ScientificName] = true
+		inc.abundance[s.ScientificName]++
+	}
+	for _, pl := range p.Plots {
+		add(pl.Code)
+	}
+
+	for len(inc.plots) < p.PortionQuantity {
+		inc.plots = append(inc.plots, map[string]bool{})
+		inc.areas = append(inc.areas, p.PortionArea)
+	}
+
+	return inc
+}
+
+// ComputeSpeciesAccumulation calcula a curva de acumulação por permutações das parcelas
+// e os estimadores de riqueza a partir da presença por parcela
+func ComputeSpeciesAccumulation(p *types.PhytoAnalysisComplete, opts AccumulationOptions) *SpeciesAccumulation {
+	inc := buildPlotIncidence(p)
+
+	return &SpeciesAccumulation{
+		Permutations: opts.Permutations,
+		Seed:         opts.Seed,
+		PlotsCount:   len(inc.plots),
+		Curve:        randomizedAccumulationCurve(inc, opts),
+		Estimators:   calculateRichnessEstimators(inc),
+	}
+}
+
+// randomizedAccumulationCurve acumula as espécies em ordens aleatórias de parcelas e
+// resume, para cada número de parcelas, a média, o desvio e o intervalo de 95% das permutações.
+// A área acumulada é a média, entre as permutações, da soma das áreas das parcelas.
+func randomizedAccumulationCurve(inc plotIncidence, opts AccumulationOptions) []AccumulationPoint {
+	m := len(inc.plots)
+	if m == 0 || opts.Permutations <= 0 {
+		return []AccumulationPoint{}
+	}
+
+	rng := rand.New(rand.NewSource(opts.Seed))
+	order := make([]int, m)
+	for i := range order {
+		order[i] = i
+	}
+
+	// richness[k][perm] = espécies acumuladas após k+1 parcelas na permutação perm
+	richness := make([][]float64, m)
+	for k := range richness {
+		richness[k] = make([]float64, opts.Permutations)
+	}
+	cumulativeArea := make([]float64, m)
+
+	for perm := 0; perm < opts.Permutations; perm++ {
+		rng.Shuffle(m, func(i, j int) { order[i], order[j] = order[j], order[i] })
+
+		seen := make(map[string]bool)
+		area := 0.0
+		for k, idx := range order {
+			for sp := range inc.plots[idx] {
+				seen[sp] = true
+			}
+			richness[k][perm] = float64(len(seen))
+			area += inc.areas[idx]
+			cumulativeArea[k] += area
+		}
+	}
+
+	points := make([]AccumulationPoint, 0, m+1)
+	points = append(points, AccumulationPoint{})
+	for k, values := range richness {
+		mean, variance := MeanAndVariance(values)
+
+		sorted := append([]float64(nil), values...)
+		sort.Float64s(sorted)
+
+		points = append(points, AccumulationPoint{
+			Plots:          k + 1,
+			CumulativeArea: cumulativeArea[k] / float64(opts.Permutations),
+			MeanSpecies:    mean,
+			StdDev:         math.Sqrt(variance),
+			Lower:          percentile(sorted, 0.025),
+			Upper:          percentile(sorted, 0.975),
+		})
+	}
+
+	return points
+}
+
+// calculateRichnessEstimators calcula os estimadores de riqueza (m = número de parcelas)
+//
+//	Chao1 = S + F1(F1-1) / 2(F2+1)
+//	Chao2 = S + ((m-1)/m) Q1(Q1-1) / 2(Q2+1)
+//	Jack1 = S + Q1 (m-1)/m
+//	Jack2 = S + Q1(2m-3)/m - Q2(m-2)² / m(m-1)
+//	Boot  = S + Σ (1 - p_k)^m, p_k = proporção de parcelas com a espécie k
+func calculateRichnessEstimators(inc plotIncidence) RichnessEstimators {
+	m := float64(len(inc.plots))
+
+	incidence := make(map[string]int)
+	for _, plot := range inc.plots {
+		for sp := range plot {
+			incidence[sp]++
+		}
+	}
+
+	est := RichnessEstimators{ObservedSpecies: len(inc.abundance)}
+	for _, n := range inc.abundance {
+		switch n {
+		case 1:
+			est.Singletons++
+		case 2:
+			est.Doubletons++
+		}
+	}
+	for _, q := range incidence {
+		switch q {
+		case 1:
+			est.Uniques++
+		case 2:
+			est.Duplicates++
+		}
+	}
+
+	s := float64(est.ObservedSpecies)
+	f1, f2 := float64(est.Singletons), float64(est.Doubletons)
+	q1, q2 := float64(est.Uniques), float64(est.Duplicates)
+
+	est.Chao1 = s + f1*(f1-1)/(2*(f2+1))
+	est.Chao2 = s
+	est.Jackknife1 = s
+	est.Jackknife2 = s
+	est.Bootstrap = s
+
+	if m > 0 {
+		est.Chao2 = s + ((m-1)/m)*q1*(q1-1)/(2*(q2+1))
+		est.Jackknife1 = s + q1*(m-1)/m
+		for _, q := range incidence {
+			est.Bootstrap += math.Pow(1-float64(q)/m, m)
+		}
+	}
+	if m > 1 {
+		est.Jackknife2 = s + q1*(2*m-3)/m - q2*(m-2)*(m-2)/(m*(m-1))
+	}
+
+	return est
+}
+
+// percentile retorna o percentil q (0-1) de valores já ordenados, com interpolação linear
+func percentile(sorted []float64, q float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	pos := q * float64(len(sorted)-1)
+	lo := int(math.Floor(pos))
+	hi := int(math.Ceil(pos))
+	if lo == hi {
+		return sorted[lo]
+	}
+	return sorted[lo] + (sorted[hi]-sorted[lo])*(pos-float64(lo))
+}
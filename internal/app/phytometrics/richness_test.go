@@ -0,0 +1,102 @@
+package phytometrics
+
+import (
+	"testing"
+
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+	"github.com/stretchr/testify/require"
+)
+
+func richnessFixture() *types.PhytoAnalysisComplete {
+	// 4 parcelas (a 4ª sem indivíduos); abundâncias A=4, B=1, C=1, D=2; incidências A=3, B=C=D=1
+	return &types.PhytoAnalysisComplete{
+		PortionQuantity: 4,
+		PortionArea:     100,
+		Specimens: []*types.SpecimenWithSpecies{
+			{Portion: "1", Cap1: 50, ScientificName: "A a"},
+			{Portion: "1", Cap1: 50, ScientificName: "A a"},
+			{Portion: "1", Cap1: 50, ScientificName: "B b"},
+			{Portion: "2", Cap1: 50, ScientificName: "A a"},
+			{Portion: "2", Cap1: 50, ScientificName: "C c"},
+			{Portion: "3", Cap1: 50, ScientificName: "A a"},
+			{Portion: "3", Cap1: 50, ScientificName: "D d"},
+			{Portion: "3", Cap1: 50, ScientificName: "D d"},
+		},
+	}
+}
+
+func TestCalculateRichnessEstimators(t *testing.T) {
+	t.Parallel()
+
+	est := calculateRichnessEstimators(buildPlotIncidence(richnessFixture()))
+
+	require.Equal(t, 4, est.ObservedSpecies)
+	require.Equal(t, 2, est.Singletons)
+	require.Equal(t, 1, est.Doubletons)
+	require.Equal(t, 3, est.Uniques)
+	require.Equal(t, 0, est.Duplicates)
+
+	require.InDelta(t, 4.5, est.Chao1, 1e-9)       // 4 + 2·1/(2·2)
+	require.InDelta(t, 6.25, est.Chao2, 1e-9)      // 4 + (3/4)·3·2/(2·1)
+	require.InDelta(t, 6.25, est.Jackknife1, 1e-9) // 4 + 3·3/4
+	require.InDelta(t, 7.75, est.Jackknife2, 1e-9) // 4 + 3·5/4
+	require.InDelta(t, 4.953125, est.Bootstrap, 1e-9)
+}
+
+func TestComputeSpeciesAccumulation_Reproducible(t *testing.T) {
+	t.Parallel()
+
+	p := richnessFixture()
+	opts := AccumulationOptions{Permutations: 50, Seed: 42}
+
+	first := ComputeSpeciesAccumulation(p, opts)
+	second := ComputeSpeciesAccumulation(p, opts)
+	require.Equal(t, first, second)
+
+	require.Equal(t, 4, first.PlotsCount)
+	require.Len(t, first.Curve, 5)
+	require.Zero(t, first.Curve[0].MeanSpecies)
+
+	last := first.Curve[4]
+	require.Equal(t, 4, last.Plots)
+	require.InDelta(t, 400, last.CumulativeArea, 1e-9)
+	require.InDelta(t, 4, last.MeanSpecies, 1e-9)
+	require.Zero(t, last.StdDev)
+
+	for i := 1; i < len(first.Curve); i++ {
+		pt := first.Curve[i]
+		require.GreaterOrEqual(t, pt.MeanSpecies, first.Curve[i-1].MeanSpecies)
+		require.LessOrEqual(t, pt.Lower, pt.MeanSpecies)
+		require.GreaterOrEqual(t, pt.Upper, pt.MeanSpecies)
+	}
+}
+
+func TestAccumulationCurves_UsePlotAreas(t *testing.T) {
+	t.Parallel()
+
+	// parcelas cadastradas de 100 e 300 m²; a C (50 m²) não tem indivíduos
+	p := &types.PhytoAnalysisComplete{
+		PortionArea: 1000,
+		Plots: []*types.PlotData{
+			{Code: "A", AreaM2: 100},
+			{Code: "B", AreaM2: 300},
+			{Code: "C", AreaM2: 50},
+		},
+		Specimens: []*types.SpecimenWithSpecies{
+			{Portion: "B", Cap1: 50, ScientificName: "A a"},
+			{Portion: "A", Cap1: 50, ScientificName: "B b"},
+		},
+	}
+
+	collector := ComputeCollectorCurve(p)
+	require.Len(t, collector.Points, 3)
+	require.InDelta(t, 300, collector.Points[1].CumulativeArea, 1e-9)
+	require.InDelta(t, 400, collector.Points[2].CumulativeArea, 1e-9)
+
+	acc := ComputeSpeciesAccumulation(p, AccumulationOptions{Permutations: 200, Seed: 1})
+	require.Equal(t, 3, acc.PlotsCount)
+	require.InDelta(t, 450, acc.Curve[3].CumulativeArea, 1e-9)
+	// média entre as ordens: perto de 450/3 por parcela
+	require.Greater(t, acc.Curve[1].CumulativeArea, 100.0)
+	require.Less(t, acc.Curve[1].CumulativeArea, 300.0)
+}
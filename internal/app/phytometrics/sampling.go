@@ -0,0 +1,234 @@
+package phytometrics
+
+import (
+	"math"
+
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+)
+
+// Padrões da checagem de suficiência amostral
+const (
+	DefaultSamplingProbability = 0.90 // nível de confiança
+	DefaultSamplingTargetError = 10.0 // erro de amostragem admissível (%)
+
+	// fração amostral a partir da qual a população é tratada como finita (1 - f < 0,98)
+	finitePopulationFraction = 0.02
+	// limite de iterações no cálculo do número de parcelas necessárias (t depende de n)
+	requiredPlotsMaxIter = 50
+)
+
+// SamplingOptions define o nível de confiança e o erro admissível
+type SamplingOptions struct {
+	Probability        float64 // ex.: 0.90
+	TargetErrorPercent float64 // ex.: 10
+}
+
+// DefaultSamplingOptions retorna as opções padrão (10% de erro a 90% de probabilidade)
+func DefaultSamplingOptions() SamplingOptions {
+	return SamplingOptions{
+		Probability:        DefaultSamplingProbability,
+		TargetErrorPercent: DefaultSamplingTargetError,
+	}
+}
+
+// PlotSummary representa os totais de uma parcela extrapolados por hectare
+type PlotSummary struct {
+	Portion        string  `json:"portion"`
+	Individuals    int     `json:"individuals"`
+	DensityIndHa   float64 `json:"densityIndHa"`   // ind/ha
+	BasalAreaPerHa float64 `json:"basalAreaPerHa"` // m²/ha
+	VolumePerHa    float64 `json:"volumePerHa"`    // m³/ha (com fator de forma)
+}
+
+// SamplingVariableStats representa a estatística da amostragem casual simples de uma variável
+type SamplingVariableStats struct {
+	Mean                    float64 `json:"mean"`                    // Média por hectare
+	Variance                float64 `json:"variance"`                // Variância
+	StdDev                  float64 `json:"stdDev"`                  // Desvio padrão
+	CoefficientOfVariation  float64 `json:"coefficientOfVariation"`  // CV (%)
+	VarianceOfMean          float64 `json:"varianceOfMean"`          // Variância da média (com correção de população finita, se aplicável)
+	StandardError           float64 `json:"standardError"`           // Erro padrão da média
+	AbsoluteError           float64 `json:"absoluteError"`           // Erro de amostragem absoluto (t × Sx)
+	RelativeErrorPercent    float64 `json:"relativeErrorPercent"`    // Erro de amostragem relativo (%)
+	ConfidenceIntervalLower float64 `json:"confidenceIntervalLower"` // IC por hectare (limite inferior)
+	ConfidenceIntervalUpper float64 `json:"confidenceIntervalUpper"` // IC por hectare (limite superior)
+	TotalEstimate           float64 `json:"totalEstimate"`           // Estimativa para a área total
+	TotalConfidenceLower    float64 `json:"totalConfidenceLower"`    // IC para a área total (limite inferior)
+	TotalConfidenceUpper    float64 `json:"totalConfidenceUpper"`    // IC para a área total (limite superior)
+	RequiredPlots           int     `json:"requiredPlots"`           // Parcelas necessárias para o erro admissível
+	Sufficient              bool    `json:"sufficient"`              // Erro relativo dentro do admissível
+}
+
+// SamplingVariables agrupa as variáveis avaliadas na suficiência amostral
+type SamplingVariables struct {
+	Volume    SamplingVariableStats `json:"volume"`    // m³/ha
+	BasalArea SamplingVariableStats `json:"basalArea"` // m²/ha
+	Density   SamplingVariableStats `json:"density"`   // ind/ha
+}
+
+// SamplingStatistics representa a estatística do inventário e a checagem de suficiência
+type SamplingStatistics struct {
+	Probability        float64 `json:"probability"`
+	TargetErrorPercent float64 `json:"targetErrorPercent"`
+	StudentT           float64 `json:"studentT"`        // t bicaudal com n-1 graus de liberdade
+	PlotsCount         int     `json:"plotsCount"`      // n: parcelas amostradas (inclui parcelas vazias)
+	EmptyPlots         int     `json:"emptyPlots"`      // parcelas sem indivíduos
+	PopulationPlots    float64 `json:"populationPlots"` // N: parcelas possíveis na área total
+	SamplingFraction   float64 `json:"samplingFraction"`
+	FinitePopulation   bool    `json:"finitePopulation"` // correção de população finita aplicada
+
+	Variables SamplingVariables `json:"variables"`
+	Plots     []PlotSummary     `json:"plots"`
+}
+
+// ComputeSamplingStatistics calcula a estatística de amostragem casual simples da análise.
+// Cada parcela é extrapolada para hectare; TotalArea é considerada em hectares.
+//
+//	s²ȳ = s²/n × (1 - n/N)   (população finita)   Ea = t × sȳ   Er = Ea / ȳ × 100
+//	n = t²CV² / (E² + t²CV²/N) (população finita)  n = t²CV² / E² (infinita)
+func ComputeSamplingStatistics(p *types.PhytoAnalysisComplete, opts SamplingOptions) *SamplingStatistics {
+	plots := summarizePlots(p)
+
+	n := len(plots)
+	emptyPlots := 0
+	for _, pl := range plots {
+		if pl.Individuals == 0 {
+			emptyPlots++ // parcelas cadastradas sem indivíduos
+		}
+	}
+	if p.PortionQuantity > n {
+		emptyPlots += p.PortionQuantity - n
+		n = p.PortionQuantity
+	}
+
+	out := &SamplingStatistics{
+		Probability:        opts.Probability,
+		TargetErrorPercent: opts.TargetErrorPercent,
+		PlotsCount:         n,
+		EmptyPlots:         emptyPlots,
+		Plots:              plots,
+	}
+
+	if p.PortionArea > 0 && p.TotalArea > 0 {
+		out.PopulationPlots = p.TotalArea * 10000.0 / p.PortionArea
+	}
+	if out.PopulationPlots > 0 {
+		out.SamplingFraction = float64(n) / out.PopulationPlots
+		out.FinitePopulation = out.SamplingFraction > finitePopulationFraction
+	}
+
+	if n < 2 {
+		return out
+	}
+	out.StudentT = studentTTwoTailed(opts.Probability, n-1)
+
+	volumes := make([]float64, n)
+	basalAreas := make([]float64, n)
+	densities := make([]float64, n)
+	for i, pl := range plots {
+		volumes[i] = pl.VolumePerHa
+		basalAreas[i] = pl.BasalAreaPerHa
+		densities[i] = pl.DensityIndHa
+	}
+
+	out.Variables = SamplingVariables{
+		Volume:    calculateSamplingStats(volumes, out, p.TotalArea),
+		BasalArea: calculateSamplingStats(basalAreas, out, p.TotalArea),
+		Density:   calculateSamplingStats(densities, out, p.TotalArea),
+	}
+
+	return out
+}
+
+// summarizePlots retorna os valores por hectare de cada parcela (com a área de cada parcela cadastrada)
+func summarizePlots(p *types.PhytoAnalysisComplete) []PlotSummary {
+	plots := ComputePlots(p)
+
+	out := make([]PlotSummary, 0, len(plots))
+	for _, pl := range plots {
+		out = append(out, PlotSummary{
+			Portion:        pl.Code,
+			Individuals:    pl.Individuals,
+			DensityIndHa:   pl.DensityIndHa,
+			BasalAreaPerHa: pl.BasalAreaPerHa,
+			VolumePerHa:    pl.VolumePerHa,
+		})
+	}
+	return out
+}
+
+// calculateSamplingStats calcula a estatística de uma variável; values deve ter uma posição por parcela
+// (parcelas vazias com zero)
+func calculateSamplingStats(values []float64, s *SamplingStatistics, totalAreaHa float64) SamplingVariableStats {
+	n := len(values)
+	mean, variance := MeanAndVariance(values)
+
+	st := SamplingVariableStats{
+		Mean:     mean,
+		Variance: variance,
+		StdDev:   math.Sqrt(variance),
+	}
+	if mean > 0 {
+		st.CoefficientOfVariation = st.StdDev / mean * 100.0
+	}
+
+	st.VarianceOfMean = variance / float64(n)
+	if s.FinitePopulation {
+		// censo (n >= N): a variância da média é nula
+		st.VarianceOfMean *= math.Max(0, 1-s.SamplingFraction)
+	}
+	st.StandardError = math.Sqrt(st.VarianceOfMean)
+	st.AbsoluteError = s.StudentT * st.StandardError
+	if mean > 0 {
+		st.RelativeErrorPercent = st.AbsoluteError / mean * 100.0
+	}
+
+	st.ConfidenceIntervalLower = mean - st.AbsoluteError
+	st.ConfidenceIntervalUpper = mean + st.AbsoluteError
+	if totalAreaHa > 0 {
+		st.TotalEstimate = mean * totalAreaHa
+		st.TotalConfidenceLower = st.ConfidenceIntervalLower * totalAreaHa
+		st.TotalConfidenceUpper = st.ConfidenceIntervalUpper * totalAreaHa
+	}
+
+	st.RequiredPlots = requiredPlots(st.CoefficientOfVariation, s.TargetErrorPercent, s.Probability, n, s.PopulationPlots, s.FinitePopulation)
+	st.Sufficient = mean > 0 && st.RelativeErrorPercent <= s.TargetErrorPercent
+
+	return st
+}
+
+// requiredPlots calcula o número de parcelas para atingir o erro admissível, recalculando t
+// com n-1 graus de liberdade até convergir
+func requiredPlots(cv, targetError, probability float64, sampled int, populationPlots float64, finite bool) int {
+	if targetError <= 0 || cv <= 0 {
+		return sampled
+	}
+
+	current := sampled
+	for i := 0; i < requiredPlotsMaxIter; i++ {
+		df := current - 1
+		if df < 1 {
+			df = 1
+		}
+		t := studentTTwoTailed(probability, df)
+		t2cv2 := t * t * cv * cv
+
+		var next float64
+		if finite && populationPlots > 0 {
+			next = t2cv2 / (targetError*targetError + t2cv2/populationPlots)
+		} else {
+			next = t2cv2 / (targetError * targetError)
+		}
+
+		nextPlots := int(math.Ceil(next))
+		if nextPlots < 2 {
+			nextPlots = 2
+		}
+		if nextPlots == current {
+			break
+		}
+		current = nextPlots
+	}
+
+	return current
+}
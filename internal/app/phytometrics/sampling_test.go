@@ -0,0 +1,60 @@
+package phytometrics
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStudentTQuantile(t *testing.T) {
+	t.Parallel()
+
+	// valores de tabela da distribuição t
+	require.InDelta(t, 1.812, studentTTwoTailed(0.90, 10), 1e-3)
+	require.InDelta(t, 2.571, studentTTwoTailed(0.95, 5), 1e-3)
+	require.InDelta(t, 6.314, studentTTwoTailed(0.90, 1), 1e-3)
+	require.InDelta(t, 1.645, studentTTwoTailed(0.90, 100000), 1e-3)
+}
+
+func TestComputeSamplingStatistics(t *testing.T) {
+	t.Parallel()
+
+	// 4 parcelas de 100 m² com 1, 2, 3 e 0 indivíduos -> densidades 100, 200, 300, 0 ind/ha
+	specimens := make([]*types.SpecimenWithSpecies, 0)
+	for plot, count := range []int{1, 2, 3} {
+		for i := 0; i < count; i++ {
+			specimens = append(specimens, &types.SpecimenWithSpecies{
+				Portion:        fmt.Sprintf("P%d", plot+1),
+				Cap1:           30,
+				Height:         5,
+				ScientificName: "A a",
+			})
+		}
+	}
+	p := &types.PhytoAnalysisComplete{
+		PortionQuantity: 4,
+		PortionArea:     100,
+		TotalArea:       100, // ha -> N = 10000 parcelas (população infinita)
+		Specimens:       specimens,
+	}
+
+	out := ComputeSamplingStatistics(p, DefaultSamplingOptions())
+
+	require.Equal(t, 4, out.PlotsCount)
+	require.Equal(t, 1, out.EmptyPlots)
+	require.False(t, out.FinitePopulation)
+	require.InDelta(t, 2.353, out.StudentT, 1e-3)
+
+	d := out.Variables.Density
+	require.InDelta(t, 150, d.Mean, 1e-9)
+	require.InDelta(t, 50000.0/3.0, d.Variance, 1e-6)
+	sx := math.Sqrt(50000.0 / 3.0 / 4.0)
+	require.InDelta(t, sx, d.StandardError, 1e-9)
+	require.InDelta(t, out.StudentT*sx/150*100, d.RelativeErrorPercent, 1e-9)
+	require.InDelta(t, 150*100, d.TotalEstimate, 1e-6)
+	require.False(t, d.Sufficient)
+	require.Greater(t, d.RequiredPlots, 4)
+}
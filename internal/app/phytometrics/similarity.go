@@ -0,0 +1,193 @@
+package phytometrics
+
+import (
+	"sort"
+
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+)
+
+// Índices aceitos para o agrupamento
+const (
+	SimilarityIndexJaccard  = "jaccard"
+	SimilarityIndexSorensen = "sorensen"
+)
+
+// SimilarityAnalysis representa uma análise incluída na comparação florística
+type SimilarityAnalysis struct {
+	AnalysisID   string `json:"analysisId"`
+	Title        string `json:"title"`
+	ProjectID    string `json:"projectId"`
+	ProjectTitle string `json:"projectTitle"`
+	SpeciesCount int    `json:"speciesCount"`
+}
+
+// SimilarityPair representa a similaridade florística entre duas análises
+type SimilarityPair struct {
+	FirstID         string   `json:"firstId"`
+	SecondID        string   `json:"secondId"`
+	Jaccard         float64  `json:"jaccard"`         // c / (a + b - c)
+	Sorensen        float64  `json:"sorensen"`        // 2c / (a + b)
+	SharedSpecies   []string `json:"sharedSpecies"`   // Espécies comuns às duas
+	ExclusiveFirst  []string `json:"exclusiveFirst"`  // Espécies apenas na primeira
+	ExclusiveSecond []string `json:"exclusiveSecond"` // Espécies apenas na segunda
+}
+
+// DendrogramNode representa um nó do dendrograma; folhas são análises
+type DendrogramNode struct {
+	AnalysisID string            `json:"analysisId,omitempty"` // Apenas nas folhas
+	Label      string            `json:"label,omitempty"`      // Título da análise (folhas)
+	Height     float64           `json:"height"`               // Dissimilaridade da fusão (0 nas folhas)
+	Similarity float64           `json:"similarity"`           // 1 - height
+	Size       int               `json:"size"`                 // Número de análises no grupo
+	Children   []*DendrogramNode `json:"children,omitempty"`
+}
+
+// FloristicSimilarity representa a comparação florística entre análises
+type FloristicSimilarity struct {
+	Index      string               `json:"index"` // Índice usado no agrupamento
+	Analyses   []SimilarityAnalysis `json:"analyses"`
+	Pairs      []SimilarityPair     `json:"pairs"`
+	Dendrogram *DendrogramNode      `json:"dendrogram"` // Agrupamento UPGMA
+}
+
+// ComputeFloristicSimilarity calcula a similaridade par a par (Jaccard e Sørensen) e o
+// dendrograma UPGMA com a dissimilaridade do índice informado (1 - similaridade)
+func ComputeFloristicSimilarity(analyses []*types.PhytoAnalysisComplete, index string) *FloristicSimilarity {
+	out := &FloristicSimilarity{
+		Index:    index,
+		Analyses: make([]SimilarityAnalysis, 0, len(analyses)),
+		Pairs:    make([]SimilarityPair, 0),
+	}
+
+	speciesSets := make([]map[string]bool, len(analyses))
+	for i, p := range analyses {
+		set := make(map[string]bool)
+		for _, s := range p.Specimens {
+			if s.ScientificName != "" {
+				set[s.ScientificName] = true
+			}
+		}
+		speciesSets[i] = set
+
+		out.Analyses = append(out.Analyses, SimilarityAnalysis{
+			AnalysisID:   p.ID,
+			Title:        p.Title,
+			ProjectID:    p.ProjectID,
+			ProjectTitle: p.ProjectTitle,
+			SpeciesCount: len(set),
+		})
+	}
+
+	n := len(analyses)
+	distances := make([][]float64, n)
+	for i := range distances {
+		distances[i] = make([]float64, n)
+	}
+
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			pair := compareSpeciesSets(speciesSets[i], speciesSets[j])
+			pair.FirstID = analyses[i].ID
+			pair.SecondID = analyses[j].ID
+			out.Pairs = append(out.Pairs, pair)
+
+			similarity := pair.Jaccard
+			if index == SimilarityIndexSorensen {
+				similarity = pair.Sorensen
+			}
+			distances[i][j] = 1 - similarity
+			distances[j][i] = 1 - similarity
+		}
+	}
+
+	leaves := make([]*DendrogramNode, n)
+	for i, p := range analyses {
+		leaves[i] = &DendrogramNode{AnalysisID: p.ID, Label: p.Title, Similarity: 1, Size: 1}
+	}
+	out.Dendrogram = upgma(leaves, distances)
+
+	return out
+}
+
+// compareSpeciesSets calcula os índices e as listas de espécies comuns e exclusivas
+func compareSpeciesSets(first, second map[string]bool) SimilarityPair {
+	pair := SimilarityPair{
+		SharedSpecies:   make([]string, 0),
+		ExclusiveFirst:  make([]string, 0),
+		ExclusiveSecond: make([]string, 0),
+	}
+
+	for name := range first {
+		if second[name] {
+			pair.SharedSpecies = append(pair.SharedSpecies, name)
+		} else {
+			pair.ExclusiveFirst = append(pair.ExclusiveFirst, name)
+		}
+	}
+	for name := range second {
+		if !first[name] {
+			pair.ExclusiveSecond = append(pair.ExclusiveSecond, name)
+		}
+	}
+	sort.Strings(pair.SharedSpecies)
+	sort.Strings(pair.ExclusiveFirst)
+	sort.Strings(pair.ExclusiveSecond)
+
+	a, b, c := float64(len(first)), float64(len(second)), float64(len(pair.SharedSpecies))
+	if a+b-c > 0 {
+		pair.Jaccard = c / (a + b - c)
+	}
+	if a+b > 0 {
+		pair.Sorensen = 2 * c / (a + b)
+	}
+
+	return pair
+}
+
+// upgma agrupa os nós pela média não ponderada das distâncias; a cada passo funde o par mais
+// próximo (empates pelo menor índice) e recalcula a distância pela média ponderada pelo tamanho
+func upgma(nodes []*DendrogramNode, distances [][]float64) *DendrogramNode {
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	active := make([]int, len(nodes))
+	for i := range active {
+		active[i] = i
+	}
+
+	for len(active) > 1 {
+		bestA, bestB := 0, 1
+		best := distances[active[0]][active[1]]
+		for x := 0; x < len(active); x++ {
+			for y := x + 1; y < len(active); y++ {
+				if d := distances[active[x]][active[y]]; d < best {
+					best, bestA, bestB = d, x, y
+				}
+			}
+		}
+
+		i, j := active[bestA], active[bestB]
+		sizeI, sizeJ := float64(nodes[i].Size), float64(nodes[j].Size)
+
+		// o grupo fundido ocupa a posição de i
+		for _, k := range active {
+			if k == i || k == j {
+				continue
+			}
+			d := (sizeI*distances[i][k] + sizeJ*distances[j][k]) / (sizeI + sizeJ)
+			distances[i][k] = d
+			distances[k][i] = d
+		}
+		nodes[i] = &DendrogramNode{
+			Height:     best,
+			Similarity: 1 - best,
+			Size:       nodes[i].Size + nodes[j].Size,
+			Children:   []*DendrogramNode{nodes[i], nodes[j]},
+		}
+
+		active = append(active[:bestB], active[bestB+1:]...)
+	}
+
+	return nodes[active[0]]
+}
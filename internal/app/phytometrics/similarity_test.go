@@ -0,0 +1,70 @@
+package phytometrics
+
+import (
+	"testing"
+
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+	"github.com/stretchr/testify/require"
+)
+
+func similarityFixture(id string, species ...string) *types.PhytoAnalysisComplete {
+	p := &types.PhytoAnalysisComplete{ID: id, Title: "Fragmento " + id}
+	for _, name := range species {
+		p.Specimens = append(p.Specimens, &types.SpecimenWithSpecies{ScientificName: name})
+	}
+	return p
+}
+
+func TestComputeFloristicSimilarity(t *testing.T) {
+	t.Parallel()
+
+	analyses := []*types.PhytoAnalysisComplete{
+		similarityFixture("a", "A a", "B b", "C c", "C c"),
+		similarityFixture("b", "A a", "B b", "D d"),
+		similarityFixture("c", "E e", "F f"),
+	}
+
+	resp := ComputeFloristicSimilarity(analyses, SimilarityIndexJaccard)
+
+	require.Len(t, resp.Analyses, 3)
+	require.Equal(t, 3, resp.Analyses[0].SpeciesCount)
+	require.Len(t, resp.Pairs, 3)
+
+	ab := resp.Pairs[0]
+	require.Equal(t, "a", ab.FirstID)
+	require.Equal(t, "b", ab.SecondID)
+	require.InDelta(t, 2.0/4.0, ab.Jaccard, 1e-9)  // c=2, a=3, b=3
+	require.InDelta(t, 4.0/6.0, ab.Sorensen, 1e-9) // 2c/(a+b)
+	require.Equal(t, []string{"A a", "B b"}, ab.SharedSpecies)
+	require.Equal(t, []string{"C c"}, ab.ExclusiveFirst)
+	require.Equal(t, []string{"D d"}, ab.ExclusiveSecond)
+
+	require.Zero(t, resp.Pairs[1].Jaccard)
+
+	// UPGMA: a e b se unem a 0,5; c entra na raiz com dissimilaridade 1
+	root := resp.Dendrogram
+	require.Equal(t, 3, root.Size)
+	require.InDelta(t, 1.0, root.Height, 1e-9)
+	require.Len(t, root.Children, 2)
+
+	ab2 := root.Children[0]
+	require.Equal(t, 2, ab2.Size)
+	require.InDelta(t, 0.5, ab2.Height, 1e-9)
+	require.InDelta(t, 0.5, ab2.Similarity, 1e-9)
+	require.Equal(t, "a", ab2.Children[0].AnalysisID)
+	require.Equal(t, "b", ab2.Children[1].AnalysisID)
+	require.Equal(t, "c", root.Children[1].AnalysisID)
+}
+
+func TestComputeFloristicSimilarity_SorensenDendrogram(t *testing.T) {
+	t.Parallel()
+
+	analyses := []*types.PhytoAnalysisComplete{
+		similarityFixture("a", "A a", "B b", "C c"),
+		similarityFixture("b", "A a", "B b", "D d"),
+	}
+
+	resp := ComputeFloristicSimilarity(analyses, SimilarityIndexSorensen)
+	require.Equal(t, SimilarityIndexSorensen, resp.Index)
+	require.InDelta(t, 1-4.0/6.0, resp.Dendrogram.Height, 1e-9)
+}
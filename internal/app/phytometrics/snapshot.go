@@ -0,0 +1,244 @@
+package phytometrics
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+)
+
+// EngineVersion identifica a versão das fórmulas do motor; deve ser incrementada sempre que
+// uma alteração de cálculo mudar o resultado, para que snapshots antigos sejam identificados
+const EngineVersion = 8
+
+// Eventos que originam um snapshot
+const (
+	ReasonInitial              = "initial"               // leitura de uma análise sem snapshot
+	ReasonCreated              = "analysis_created"      // criação da análise
+	ReasonUpdated              = "analysis_updated"      // alteração dos dados da análise (áreas, fator de forma)
+	ReasonSpecimensImported    = "specimens_imported"    // importação de espécimes
+	ReasonSpecimensChanged     = "specimens_changed"     // inclusão, edição ou exclusão avulsa de espécime
+	ReasonRecomputed           = "recomputed"            // recálculo explícito
+	ReasonEngineUpgraded       = "engine_upgraded"       // leitura de um snapshot gerado por versão anterior do motor
+	ReasonEquationChanged      = "equation_changed"      // seleção de equação volumétrica ou alométrica
+	ReasonPlotsChanged         = "plots_changed"         // inclusão, edição ou exclusão de parcela
+	ReasonMorphospeciesChanged = "morphospecies_changed" // renomeação ou resolução de morfoespécie
+	ReasonAssortmentChanged    = "assortment_changed"    // alteração das regras de tora e lenha (fator de empilhamento)
+)
+
+// Snapshot representa uma versão persistida do resultado do motor para uma análise
+type Snapshot struct {
+	ID              string
+	PhytoAnalysisID string
+	Version         int    // sequencial por análise (0 = calculado na leitura, ainda não gravado)
+	EngineVersion   int    // versão do motor que gerou o resultado
+	Reason          string // evento que originou o snapshot
+	SpecimensCount  int
+	CreatedAt       time.Time
+	Result          *Result
+}
+
+// Outdated indica que o snapshot foi gerado por uma versão anterior do motor
+func (s *Snapshot) Outdated() bool {
+	return s.EngineVersion < EngineVersion
+}
+
+// Change representa a diferença de um valor entre dois resultados.
+// Previous/Current nulos indicam valor ausente (ex.: espécie incluída ou removida).
+type Change struct {
+	Path     string   `json:"path"`
+	Previous *float64 `json:"previous"`
+	Current  *float64 `json:"current"`
+	Delta    *float64 `json:"delta,omitempty"`
+}
+
+// Recomputation representa o recálculo explícito dos indicadores
+type Recomputation struct {
+	Previous *Snapshot // nil quando não havia snapshot
+	Current  *Snapshot
+	Changes  []Change
+	Created  bool // false quando o resultado não mudou e nenhuma versão nova foi gravada
+}
+
+// diffTolerance é a tolerância relativa abaixo da qual dois valores são considerados iguais
+const diffTolerance = 1e-9
+
+// Diff compara dois resultados e retorna os valores alterados: métricas agregadas,
+// indicadores, biomassa/carbono e, por espécie, indivíduos, área basal e IVI
+func Diff(previous, current *Result) []Change {
+	changes := make([]Change, 0)
+
+	prev := flatten(previous)
+	curr := flatten(current)
+
+	paths := make([]string, 0, len(curr))
+	seen := make(map[string]bool, len(curr))
+	for _, f := range curr {
+		paths = append(paths, f.path)
+		seen[f.path] = true
+	}
+	for _, f := range prev {
+		if !seen[f.path] {
+			paths = append(paths, f.path)
+		}
+	}
+
+	prevByPath := indexFields(prev)
+	currByPath := indexFields(curr)
+	for _, path := range paths {
+		p, c := prevByPath[path], currByPath[path]
+		if sameValue(p, c) {
+			continue
+		}
+		ch := Change{Path: path, Previous: p, Current: c}
+		if p != nil && c != nil {
+			d := *c - *p
+			ch.Delta = &d
+		}
+		changes = append(changes, ch)
+	}
+
+	return changes
+}
+
+// EquationsChanged indica se as equações usadas (ID, expressão ou coeficientes) diferem
+func EquationsChanged(previous, current *Result) bool {
+	var prev, curr *Equations
+	if previous != nil {
+		prev = previous.Equations
+	}
+	if current != nil {
+		curr = current.Equations
+	}
+	if prev == nil || curr == nil {
+		return prev != curr
+	}
+	return !sameEquation(prev.Volume, curr.Volume) || !sameEquation(prev.Biomass, curr.Biomass)
+}
+
+func sameEquation(a, b types.EquationData) bool {
+	if a.ID != b.ID || a.Expression != b.Expression || len(a.Coefficients) != len(b.Coefficients) {
+		return false
+	}
+	for name, v := range a.Coefficients {
+		if w, ok := b.Coefficients[name]; !ok || v != w {
+			return false
+		}
+	}
+	return true
+}
+
+type field struct {
+	path  string
+	value *float64
+}
+
+func indexFields(fields []field) map[string]*float64 {
+	out := make(map[string]*float64, len(fields))
+	for _, f := range fields {
+		out[f.path] = f.value
+	}
+	return out
+}
+
+func sameValue(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	scale := math.Max(1, math.Max(math.Abs(*a), math.Abs(*b)))
+	return math.Abs(*a-*b) <= diffTolerance*scale
+}
+
+func num(v float64) *float64 { return &v }
+
+// flatten lista os valores comparáveis de um resultado em ordem estável
+func flatten(r *Result) []field {
+	if r == nil {
+		return nil
+	}
+
+	s := r.Summary
+	fields := []field{
+		{"summary.individualsCount", num(float64(s.IndividualsCount))},
+		{"summary.speciesCount", num(float64(s.SpeciesCount))},
+		{"summary.meanDbhCm", num(s.MeanDBHCm)},
+		{"summary.meanHeightM", num(s.MeanHeightM)},
+		{"summary.densityIndHa", num(s.DensityIndHa)},
+		{"summary.volumeTotalM3", num(s.VolumeTotalM3)},
+		{"summary.volumeTotalMst", num(s.VolumeTotalMst)},
+		{"summary.volumePerHa", num(s.VolumePerHa)},
+		{"summary.basalAreaPerHa", num(s.BasalAreaPerHa)},
+		{"summary.cylindricalVolumeTotalM3", num(s.CylindricalVolumeTotalM3)},
+		{"summary.cylindricalVolumePerHa", num(s.CylindricalVolumePerHa)},
+	}
+
+	if b := r.Biomass; b != nil {
+		fields = append(fields,
+			field{"biomass.abovegroundBiomassT", num(b.AbovegroundBiomassT)},
+			field{"biomass.totalBiomassT", num(b.TotalBiomassT)},
+			field{"biomass.carbonT", num(b.CarbonT)},
+			field{"biomass.co2eT", num(b.CO2eT)},
+			field{"biomass.carbonPerHa", num(b.CarbonPerHa)},
+		)
+	}
+
+	if h := r.Hypsometry; h != nil {
+		fields = append(fields,
+			field{"hypsometry.measuredIndividuals", num(float64(h.MeasuredIndividuals))},
+			field{"hypsometry.estimatedIndividuals", num(float64(h.EstimatedIndividuals))},
+			field{"hypsometry.missingIndividuals", num(float64(h.MissingIndividuals))},
+		)
+		if h.Selected != nil {
+			fields = append(fields,
+				field{"hypsometry.r2", num(h.Selected.R2)},
+				field{"hypsometry.stdErrorM", num(h.Selected.StdErrorM)},
+			)
+		}
+	}
+
+	if e := r.Ecology; e != nil {
+		fields = append(fields,
+			field{"ecology.threatenedSpecies", num(float64(e.ThreatenedSpecies))},
+			field{"ecology.threatenedIndividuals", num(float64(e.ThreatenedIndividuals))},
+			field{"ecology.protectedSpecies", num(float64(e.ProtectedSpecies))},
+			field{"ecology.protectedIndividuals", num(float64(e.ProtectedIndividuals))},
+		)
+	}
+
+	ind := r.Indicators
+	if ind == nil {
+		return fields
+	}
+
+	fields = append(fields,
+		field{"indicators.plotsCount", num(float64(ind.PlotsCount))},
+		field{"indicators.plotsArea", num(ind.PlotsArea)},
+		field{"indicators.density", ind.Density},
+		field{"indicators.basalArea", ind.BasalArea},
+		field{"indicators.volume", ind.Volume},
+		field{"indicators.replacementVolume", ind.ReplacementVolume},
+		field{"indicators.replacementVolumeMst", ind.ReplacementVolumeMst},
+		field{"indicators.cylindricalVolume", ind.CylindricalVolume},
+		field{"indicators.cylindricalReplacementVolume", ind.CylindricalReplacementVolume},
+		field{"indicators.shannonIndex", ind.ShannonIndex},
+		field{"indicators.simpsonIndex", ind.SimpsonIndex},
+		field{"indicators.pielouEvennessIndex", ind.PielouEvennessIndex},
+		field{"indicators.inverseSimpsonIndex", ind.InverseSimpsonIndex},
+		field{"indicators.margalefIndex", ind.MargalefIndex},
+		field{"indicators.menhinickIndex", ind.MenhinickIndex},
+	)
+
+	species := append([]SpeciesStructure(nil), ind.SpeciesData...)
+	sort.Slice(species, func(i, j int) bool { return species[i].ScientificName < species[j].ScientificName })
+	for _, sp := range species {
+		prefix := "species[" + sp.ScientificName + "]."
+		fields = append(fields,
+			field{prefix + "individuals", num(float64(sp.Individuals))},
+			field{prefix + "basalArea", num(sp.BasalArea)},
+			field{prefix + "ivi", num(sp.IVI)},
+		)
+	}
+
+	return fields
+}
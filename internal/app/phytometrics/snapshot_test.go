@@ -0,0 +1,62 @@
+package phytometrics
+
+import (
+	"testing"
+
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	p := &types.PhytoAnalysisComplete{
+		PortionQuantity: 2,
+		PortionArea:     5000,
+		SampledArea:     1,
+		Specimens: []*types.SpecimenWithSpecies{
+			{ID: "1", Portion: "1", Height: 10, Cap1: 100, ScientificName: "A a", Family: "Fa"},
+			{ID: "2", Portion: "2", Height: 8, Cap1: 60, ScientificName: "B b", Family: "Fb"},
+		},
+	}
+	previous := Compute(p)
+
+	// sem mudanças nos dados, nenhum valor muda
+	require.Empty(t, Diff(previous, Compute(p)))
+
+	// inclui uma espécie nova
+	p.Specimens = append(p.Specimens, &types.SpecimenWithSpecies{ID: "3", Portion: "2", Height: 5, Cap1: 40, ScientificName: "C c", Family: "Fc"})
+	changes := Diff(previous, Compute(p))
+
+	byPath := make(map[string]Change, len(changes))
+	for _, ch := range changes {
+		byPath[ch.Path] = ch
+	}
+
+	count := byPath["summary.individualsCount"]
+	require.InDelta(t, 2, *count.Previous, 1e-12)
+	require.InDelta(t, 3, *count.Current, 1e-12)
+	require.InDelta(t, 1, *count.Delta, 1e-12)
+
+	added := byPath["species[C c].individuals"]
+	require.Nil(t, added.Previous)
+	require.InDelta(t, 1, *added.Current, 1e-12)
+	require.Nil(t, added.Delta)
+
+	// a espécie A não mudou de indivíduos, mas o IVI sim (a densidade relativa caiu)
+	_, ok := byPath["species[A a].individuals"]
+	require.False(t, ok)
+	require.Contains(t, byPath, "species[A a].ivi")
+
+	// sem resultado anterior, todos os valores aparecem como novos
+	for _, ch := range Diff(nil, previous) {
+		require.Nil(t, ch.Previous)
+	}
+}
+
+func TestSnapshot_Outdated(t *testing.T) {
+	t.Parallel()
+
+	require.False(t, (&Snapshot{EngineVersion: EngineVersion}).Outdated())
+	require.True(t, (&Snapshot{EngineVersion: EngineVersion - 1}).Outdated())
+}
@@ -0,0 +1,168 @@
+package phytometrics
+
+import (
+	"math"
+	"math/rand"
+)
+
+// regularizedIncompleteBeta calcula I_x(a, b) pela fração contínua de Lentz
+func regularizedIncompleteBeta(a, b, x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+
+	lga, _ := math.Lgamma(a)
+	lgb, _ := math.Lgamma(b)
+	lgab, _ := math.Lgamma(a + b)
+	front := math.Exp(lgab - lga - lgb + a*math.Log(x) + b*math.Log(1-x))
+
+	// A fração converge rápido para x < (a+1)/(a+b+2); caso contrário usa a simetria
+	if x > (a+1)/(a+b+2) {
+		return 1 - regularizedIncompleteBeta(b, a, 1-x)
+	}
+	return front * betaContinuedFraction(a, b, x) / a
+}
+
+func betaContinuedFraction(a, b, x float64) float64 {
+	const (
+		maxIter = 300
+		eps     = 1e-14
+		tiny    = 1e-300
+	)
+
+	c := 1.0
+	d := 1 - (a+b)*x/(a+1)
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	h := d
+
+	for m := 1; m <= maxIter; m++ {
+		fm := float64(m)
+
+		// termo par
+		num := fm * (b - fm) * x / ((a + 2*fm - 1) * (a + 2*fm))
+		d = 1 + num*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + num/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		h *= d * c
+
+		// termo ímpar
+		num = -(a + fm) * (a + b + fm) * x / ((a + 2*fm) * (a + 2*fm + 1))
+		d = 1 + num*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + num/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+
+		if math.Abs(delta-1) < eps {
+			break
+		}
+	}
+
+	return h
+}
+
+// studentTCDF retorna P(T <= t) para a distribuição t de Student com df graus de liberdade
+// (df fracionário é aceito, como no teste de Hutcheson)
+func studentTCDF(t float64, df float64) float64 {
+	v := df
+	x := v / (v + t*t)
+	tail := 0.5 * regularizedIncompleteBeta(v/2, 0.5, x)
+	if t >= 0 {
+		return 1 - tail
+	}
+	return tail
+}
+
+// studentTQuantile retorna o valor t tal que P(T <= t) = p, por bisseção sobre a CDF
+func studentTQuantile(p float64, df int) float64 {
+	if df < 1 || p <= 0 || p >= 1 {
+		return math.NaN()
+	}
+	if p == 0.5 {
+		return 0
+	}
+	if p < 0.5 {
+		return -studentTQuantile(1-p, df)
+	}
+
+	lo, hi := 0.0, 1.0
+	for studentTCDF(hi, float64(df)) < p {
+		hi *= 2
+		if hi > 1e8 {
+			break
+		}
+	}
+
+	for i := 0; i < 200; i++ {
+		mid := (lo + hi) / 2
+		if studentTCDF(mid, float64(df)) < p {
+			lo = mid
+		} else {
+			hi = mid
+		}
+		if hi-lo < 1e-10 {
+			break
+		}
+	}
+
+	return (lo + hi) / 2
+}
+
+// studentTTwoTailed retorna o t bicaudal para a probabilidade de confiança informada (ex.: 0.90)
+func studentTTwoTailed(probability float64, df int) float64 {
+	return studentTQuantile(1-(1-probability)/2, df)
+}
+
+// binomialExactMean é a média (com p <= 0,5) até a qual a binomial é sorteada pela inversão exata
+const binomialExactMean = 30
+
+// binomialSample sorteia Binomial(n, p) com custo limitado: inversão exata para médias pequenas e
+// aproximação normal (com correção de continuidade) acima de binomialExactMean
+func binomialSample(rng *rand.Rand, n int, p float64) int {
+	if n <= 0 || p <= 0 {
+		return 0
+	}
+	if p >= 1 {
+		return n
+	}
+	if p > 0.5 {
+		return n - binomialSample(rng, n, 1-p)
+	}
+
+	mean := float64(n) * p
+	if mean >= binomialExactMean {
+		x := math.Floor(mean + math.Sqrt(mean*(1-p))*rng.NormFloat64() + 0.5)
+		return int(math.Max(0, math.Min(float64(n), x)))
+	}
+
+	// inversão sequencial: P(X = x+1) = P(X = x) · (n-x)/(x+1) · p/q
+	q := 1 - p
+	ratio := p / q
+	prob := math.Pow(q, float64(n))
+	u := rng.Float64()
+	x := 0
+	for u > prob && x < n {
+		u -= prob
+		prob *= float64(n-x) / float64(x+1) * ratio
+		x++
+	}
+	return x
+}
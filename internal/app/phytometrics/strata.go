@@ -0,0 +1,323 @@
+package phytometrics
+
+import (
+	"math"
+	"sort"
+
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+)
+
+// StratumAnalysis retorna a análise restrita ao estrato: as parcelas do estrato, os espécimes dessas
+// parcelas, o número e a área das parcelas derivados delas e a área do estrato como área total.
+// As alturas devem ser estimadas antes, com a relação hipsométrica de toda a análise.
+func StratumAnalysis(p *types.PhytoAnalysisComplete, stratum *types.StratumData) *types.PhytoAnalysisComplete {
+	sub := *p
+	sub.TotalArea = stratum.AreaHa
+	sub.Strata = []*types.StratumData{stratum}
+	sub.Plots = make([]*types.PlotData, 0)
+	sub.Specimens = make([]*types.SpecimenWithSpecies, 0)
+
+	codes := make(map[string]bool)
+	for _, pl := range p.Plots {
+		if pl.StratumID != nil && *pl.StratumID == stratum.ID {
+			sub.Plots = append(sub.Plots, pl)
+			codes[pl.Code] = true
+		}
+	}
+	for _, s := range p.Specimens {
+		if codes[s.Portion] {
+			sub.Specimens = append(sub.Specimens, s)
+		}
+	}
+
+	sub.PortionQuantity, sub.PortionArea, sub.SampledArea = PlotSampling(sub.Plots)
+	return &sub
+}
+
+// UnstratifiedPlots retorna, ordenados, os códigos das parcelas fora dos estratos: cadastradas
+// sem estrato ou apenas referenciadas pelos espécimes
+func UnstratifiedPlots(p *types.PhytoAnalysisComplete) []string {
+	stratified := make(map[string]bool, len(p.Plots))
+	for _, pl := range p.Plots {
+		if pl.StratumID != nil {
+			stratified[pl.Code] = true
+		}
+	}
+
+	seen := make(map[string]bool)
+	out := make([]string, 0)
+	add := func(code string) {
+		if !stratified[code] && !seen[code] {
+			seen[code] = true
+			out = append(out, code)
+		}
+	}
+	for _, pl := range p.Plots {
+		add(pl.Code)
+	}
+	for _, s := range p.Specimens {
+		add(s.Portion)
+	}
+
+	sort.Strings(out)
+	return out
+}
+
+// StratifiedVariableStats representa o estimador da amostragem estratificada de uma variável
+type StratifiedVariableStats struct {
+	Mean                    float64        `json:"mean"`                    // ȳst = Σ Wh ȳh (por hectare)
+	VarianceOfMean          float64        `json:"varianceOfMean"`          // s²ȳst = Σ Wh² s²h / nh × (1 - fh)
+	StandardError           float64        `json:"standardError"`           // sȳst
+	DegreesOfFreedom        int            `json:"degreesOfFreedom"`        // graus de liberdade efetivos (Satterthwaite)
+	StudentT                float64        `json:"studentT"`                // t bicaudal com os graus de liberdade efetivos
+	AbsoluteError           float64        `json:"absoluteError"`           // t × sȳst
+	RelativeErrorPercent    float64        `json:"relativeErrorPercent"`    // Erro de amostragem relativo (%)
+	ConfidenceIntervalLower float64        `json:"confidenceIntervalLower"` // IC por hectare (limite inferior)
+	ConfidenceIntervalUpper float64        `json:"confidenceIntervalUpper"` // IC por hectare (limite superior)
+	TotalEstimate           float64        `json:"totalEstimate"`           // ȳst × área dos estratos amostrados
+	TotalConfidenceLower    float64        `json:"totalConfidenceLower"`
+	TotalConfidenceUpper    float64        `json:"totalConfidenceUpper"`
+	RequiredPlots           int            `json:"requiredPlots"` // Parcelas necessárias (alocação proporcional)
+	Allocation              map[string]int `json:"allocation"`    // Parcelas necessárias por estrato (código)
+	Sufficient              bool           `json:"sufficient"`
+}
+
+// StratifiedVariables agrupa as variáveis avaliadas na amostragem estratificada
+type StratifiedVariables struct {
+	Volume    StratifiedVariableStats `json:"volume"`    // m³/ha
+	BasalArea StratifiedVariableStats `json:"basalArea"` // m²/ha
+	Density   StratifiedVariableStats `json:"density"`   // ind/ha
+}
+
+// StratumSampling representa a estatística e os indicadores de um estrato
+type StratumSampling struct {
+	ID         string              `json:"id"`
+	Code       string              `json:"code"`
+	Name       string              `json:"name"`
+	AreaHa     float64             `json:"areaHa"`
+	Weight     float64             `json:"weight"` // Wh = Ah / A (estratos amostrados)
+	Sampling   *SamplingStatistics `json:"sampling"`
+	Indicators *Indicators         `json:"indicators"`
+}
+
+// StratifiedSampling representa a amostragem estratificada da análise
+type StratifiedSampling struct {
+	Probability        float64  `json:"probability"`
+	TargetErrorPercent float64  `json:"targetErrorPercent"`
+	PlotsCount         int      `json:"plotsCount"`      // parcelas nos estratos
+	StrataCount        int      `json:"strataCount"`     // estratos com parcelas
+	TotalAreaHa        float64  `json:"totalAreaHa"`     // soma das áreas dos estratos amostrados
+	UnassignedPlots    []string `json:"unassignedPlots"` // parcelas fora dos estratos (não entram nos estimadores)
+	Warnings           []string `json:"warnings"`
+
+	Variables StratifiedVariables `json:"variables"`
+	Strata    []StratumSampling   `json:"strata"`
+}
+
+// stratumSample guarda os valores por hectare das parcelas de um estrato amostrado
+type stratumSample struct {
+	code      string
+	weight    float64
+	n         int
+	fpc       float64 // 1 - fh quando a população é finita, senão 1
+	popPlots  float64 // Nh
+	volumes   []float64
+	basal     []float64
+	densities []float64
+}
+
+// ComputeStratifiedSampling calcula os estimadores da amostragem estratificada e, por estrato,
+// a estatística da amostragem casual simples e a tabela fitossociológica.
+//
+//	Wh = Ah / A   ȳst = Σ Wh ȳh   s²ȳst = Σ Wh² s²h / nh × (1 - fh)
+//	n = t² Σ Wh s²h / (E² + t² Σ Wh s²h / N)   nh = n × Wh (alocação proporcional)
+func ComputeStratifiedSampling(p *types.PhytoAnalysisComplete, opts SamplingOptions) *StratifiedSampling {
+	out := &StratifiedSampling{
+		Probability:        opts.Probability,
+		TargetErrorPercent: opts.TargetErrorPercent,
+		UnassignedPlots:    UnstratifiedPlots(p),
+		Warnings:           make([]string, 0),
+		Strata:             make([]StratumSampling, 0, len(p.Strata)),
+	}
+	if len(out.UnassignedPlots) > 0 {
+		out.Warnings = append(out.Warnings, "plots without stratum are not included in the stratified estimators")
+	}
+
+	strata := make([]*types.StratumData, len(p.Strata))
+	copy(strata, p.Strata)
+	sort.SliceStable(strata, func(i, j int) bool { return strata[i].Code < strata[j].Code })
+
+	samples := make([]*stratumSample, 0, len(strata))
+	for _, st := range strata {
+		sub := StratumAnalysis(p, st)
+		sampling := ComputeSamplingStatistics(sub, opts)
+
+		out.Strata = append(out.Strata, StratumSampling{
+			ID:         st.ID,
+			Code:       st.Code,
+			Name:       st.Name,
+			AreaHa:     st.AreaHa,
+			Sampling:   sampling,
+			Indicators: ComputeIndicators(sub),
+		})
+
+		switch {
+		case sampling.PlotsCount == 0:
+			out.Warnings = append(out.Warnings, "stratum "+st.Code+" has no plots and is not included in the estimators")
+			continue
+		case sampling.PlotsCount == 1:
+			out.Warnings = append(out.Warnings, "stratum "+st.Code+" has a single plot: its variance cannot be estimated")
+		}
+
+		sample := &stratumSample{code: st.Code, n: sampling.PlotsCount, fpc: 1, popPlots: sampling.PopulationPlots}
+		if sampling.FinitePopulation {
+			sample.fpc = math.Max(0, 1-sampling.SamplingFraction)
+		}
+		sample.volumes = make([]float64, sample.n) // parcelas vazias com zero
+		sample.basal = make([]float64, sample.n)
+		sample.densities = make([]float64, sample.n)
+		for i, pl := range sampling.Plots {
+			sample.volumes[i] = pl.VolumePerHa
+			sample.basal[i] = pl.BasalAreaPerHa
+			sample.densities[i] = pl.DensityIndHa
+		}
+
+		samples = append(samples, sample)
+		out.PlotsCount += sample.n
+		out.TotalAreaHa += st.AreaHa
+	}
+	out.StrataCount = len(samples)
+
+	if out.TotalAreaHa <= 0 {
+		return out
+	}
+	for i, st := range out.Strata {
+		for _, sample := range samples {
+			if sample.code == st.Code {
+				sample.weight = st.AreaHa / out.TotalAreaHa
+				out.Strata[i].Weight = sample.weight
+			}
+		}
+	}
+
+	out.Variables = StratifiedVariables{
+		Volume:    calculateStratifiedStats(samples, func(s *stratumSample) []float64 { return s.volumes }, out, opts),
+		BasalArea: calculateStratifiedStats(samples, func(s *stratumSample) []float64 { return s.basal }, out, opts),
+		Density:   calculateStratifiedStats(samples, func(s *stratumSample) []float64 { return s.densities }, out, opts),
+	}
+
+	return out
+}
+
+// calculateStratifiedStats combina as médias e variâncias dos estratos de uma variável
+func calculateStratifiedStats(samples []*stratumSample, values func(*stratumSample) []float64, s *StratifiedSampling, opts SamplingOptions) StratifiedVariableStats {
+	st := StratifiedVariableStats{Allocation: make(map[string]int, len(samples))}
+
+	var weightedVariance float64 // Σ Wh s²h
+	var numerator, denominator float64
+	var populationPlots float64
+	finite := true
+	for _, sample := range samples {
+		mean, variance := MeanAndVariance(values(sample))
+		st.Mean += sample.weight * mean
+
+		if sample.popPlots > 0 {
+			populationPlots += sample.popPlots
+		} else {
+			finite = false
+		}
+		if sample.n < 2 {
+			continue
+		}
+
+		weightedVariance += sample.weight * variance
+		term := sample.weight * sample.weight * variance / float64(sample.n) * sample.fpc
+		st.VarianceOfMean += term
+		numerator += term
+		denominator += term * term / float64(sample.n-1)
+	}
+
+	st.StandardError = math.Sqrt(st.VarianceOfMean)
+	st.DegreesOfFreedom = stratifiedDegreesOfFreedom(samples, numerator, denominator)
+	if st.DegreesOfFreedom < 1 {
+		return st
+	}
+	st.StudentT = studentTTwoTailed(opts.Probability, st.DegreesOfFreedom)
+	st.AbsoluteError = st.StudentT * st.StandardError
+	if st.Mean > 0 {
+		st.RelativeErrorPercent = st.AbsoluteError / st.Mean * 100.0
+	}
+
+	st.ConfidenceIntervalLower = st.Mean - st.AbsoluteError
+	st.ConfidenceIntervalUpper = st.Mean + st.AbsoluteError
+	st.TotalEstimate = st.Mean * s.TotalAreaHa
+	st.TotalConfidenceLower = st.ConfidenceIntervalLower * s.TotalAreaHa
+	st.TotalConfidenceUpper = st.ConfidenceIntervalUpper * s.TotalAreaHa
+
+	if !finite {
+		populationPlots = 0
+	}
+	st.RequiredPlots = stratifiedRequiredPlots(weightedVariance, st.Mean, opts, len(samples), s.PlotsCount, populationPlots)
+	for _, sample := range samples {
+		nh := int(math.Ceil(float64(st.RequiredPlots) * sample.weight))
+		if nh < 2 {
+			nh = 2
+		}
+		st.Allocation[sample.code] = nh
+	}
+	st.Sufficient = st.Mean > 0 && st.RelativeErrorPercent <= opts.TargetErrorPercent
+
+	return st
+}
+
+// stratifiedDegreesOfFreedom retorna os graus de liberdade efetivos de Satterthwaite
+// (Σ nh - 1 quando as variâncias são nulas)
+func stratifiedDegreesOfFreedom(samples []*stratumSample, numerator, denominator float64) int {
+	if denominator > 0 {
+		return int(math.Floor(numerator * numerator / denominator))
+	}
+	df := 0
+	for _, sample := range samples {
+		if sample.n > 1 {
+			df += sample.n - 1
+		}
+	}
+	return df
+}
+
+// stratifiedRequiredPlots calcula o número de parcelas para o erro admissível com alocação proporcional,
+// recalculando t com n - H graus de liberdade até convergir
+func stratifiedRequiredPlots(weightedVariance, mean float64, opts SamplingOptions, strata, sampled int, populationPlots float64) int {
+	if opts.TargetErrorPercent <= 0 || weightedVariance <= 0 || mean <= 0 {
+		return sampled
+	}
+	e := opts.TargetErrorPercent / 100.0 * mean
+
+	current := sampled
+	for i := 0; i < requiredPlotsMaxIter; i++ {
+		df := current - strata
+		if df < 1 {
+			df = 1
+		}
+		t := studentTTwoTailed(opts.Probability, df)
+		t2s2 := t * t * weightedVariance
+
+		var next float64
+		if populationPlots > 0 {
+			next = t2s2 / (e*e + t2s2/populationPlots)
+		} else {
+			next = t2s2 / (e * e)
+		}
+
+		nextPlots := int(math.Ceil(next))
+		if nextPlots < 2*strata {
+			nextPlots = 2 * strata
+		}
+		if nextPlots == current {
+			break
+		}
+		current = nextPlots
+	}
+
+	return current
+}
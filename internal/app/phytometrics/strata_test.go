@@ -0,0 +1,80 @@
+package phytometrics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeStratifiedSampling(t *testing.T) {
+	t.Parallel()
+
+	strata := []*types.StratumData{
+		{ID: "sa", Code: "A", Name: "Fragmento", AreaHa: 60},
+		{ID: "sb", Code: "B", Name: "Regeneração", AreaHa: 40},
+		{ID: "sc", Code: "C", Name: "Não amostrado", AreaHa: 10},
+	}
+	sa, sb := "sa", "sb"
+	plots := []*types.PlotData{
+		{Code: "A1", AreaM2: 100, StratumID: &sa},
+		{Code: "A2", AreaM2: 100, StratumID: &sa},
+		{Code: "B1", AreaM2: 100, StratumID: &sb},
+		{Code: "B2", AreaM2: 100, StratumID: &sb},
+		{Code: "X1", AreaM2: 100},
+	}
+
+	// densidades: A = 200 e 400 ind/ha, B = 100 e 100 ind/ha
+	specimens := make([]*types.SpecimenWithSpecies, 0)
+	for portion, count := range map[string]int{"A1": 2, "A2": 4, "B1": 1, "B2": 1, "X1": 3} {
+		for i := 0; i < count; i++ {
+			specimens = append(specimens, &types.SpecimenWithSpecies{
+				Portion:        portion,
+				Cap1:           30,
+				Height:         5,
+				ScientificName: "A a",
+				Family:         "Fabaceae",
+			})
+		}
+	}
+
+	p := &types.PhytoAnalysisComplete{
+		PortionQuantity: 5,
+		PortionArea:     100,
+		TotalArea:       110,
+		Plots:           plots,
+		Strata:          strata,
+		Specimens:       specimens,
+	}
+
+	out := ComputeStratifiedSampling(p, DefaultSamplingOptions())
+
+	require.Equal(t, 4, out.PlotsCount)
+	require.Equal(t, 2, out.StrataCount)
+	require.InDelta(t, 100, out.TotalAreaHa, 1e-9)
+	require.Equal(t, []string{"X1"}, out.UnassignedPlots)
+	require.Len(t, out.Warnings, 2) // parcela sem estrato e estrato C sem parcelas
+
+	require.Len(t, out.Strata, 3)
+	require.InDelta(t, 0.6, out.Strata[0].Weight, 1e-9)
+	require.InDelta(t, 0.4, out.Strata[1].Weight, 1e-9)
+	require.Zero(t, out.Strata[2].Weight)
+	require.Equal(t, 2, out.Strata[0].Sampling.PlotsCount)
+	require.InDelta(t, 300, out.Strata[0].Sampling.Variables.Density.Mean, 1e-9)
+	require.NotNil(t, out.Strata[0].Indicators)
+
+	// ȳst = 0,6 × 300 + 0,4 × 100; s²ȳst = 0,6² × 20000 / 2 (variância nula em B)
+	d := out.Variables.Density
+	require.InDelta(t, 220, d.Mean, 1e-9)
+	require.InDelta(t, 3600, d.VarianceOfMean, 1e-6)
+	require.InDelta(t, 60, d.StandardError, 1e-9)
+	require.Equal(t, 1, d.DegreesOfFreedom)
+	require.InDelta(t, 6.314, d.StudentT, 1e-3)
+	require.InDelta(t, d.StudentT*60/220*100, d.RelativeErrorPercent, 1e-9)
+	require.InDelta(t, 220*100, d.TotalEstimate, 1e-6)
+	require.False(t, d.Sufficient)
+	require.Greater(t, d.RequiredPlots, 4)
+	require.Equal(t, int(math.Ceil(float64(d.RequiredPlots)*0.6)), d.Allocation["A"])
+	require.NotContains(t, d.Allocation, "C")
+}
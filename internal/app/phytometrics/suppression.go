@@ -0,0 +1,215 @@
+package phytometrics
+
+import (
+	"sort"
+
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+)
+
+// DefaultLogMinDbhCm é o DAP mínimo (cm) para tora quando a análise não define as regras
+const DefaultLogMinDbhCm = 20.0
+
+// threatenedStatuses são os graus de ameaça (IUCN) que exigem listagem à parte na supressão
+var threatenedStatuses = map[string]bool{"CR": true, "EN": true, "VU": true}
+
+// ResolveAssortment retorna as regras de classificação da análise, com os padrões do motor
+// nos valores não definidos
+func ResolveAssortment(p *types.PhytoAnalysisComplete) types.AssortmentRules {
+	rules := types.AssortmentRules{LogMinDbhCm: DefaultLogMinDbhCm, StackingFactor: StackingFactor}
+	if p.Assortment == nil {
+		return rules
+	}
+	if p.Assortment.LogMinDbhCm > 0 {
+		rules.LogMinDbhCm = p.Assortment.LogMinDbhCm
+	}
+	if p.Assortment.StackingFactor > 0 {
+		rules.StackingFactor = p.Assortment.StackingFactor
+	}
+	rules.DeadAsFirewood = p.Assortment.DeadAsFirewood
+	return rules
+}
+
+// StackingFactorOf retorna o fator de empilhamento (m³ -> mst) da análise
+func StackingFactorOf(p *types.PhytoAnalysisComplete) float64 {
+	return ResolveAssortment(p).StackingFactor
+}
+
+// TimberProduct classifica o indivíduo em tora ou lenha pelas regras da análise
+func TimberProduct(rules types.AssortmentRules, s *types.SpecimenWithSpecies, dbhCm float64) string {
+	if rules.DeadAsFirewood && IsDead(s) {
+		return types.TimberProductFirewood
+	}
+	if dbhCm >= rules.LogMinDbhCm {
+		return types.TimberProductLog
+	}
+	return types.TimberProductFirewood
+}
+
+// IsProtectedSpecies indica se a espécie do indivíduo é protegida ou ameaçada (CR, EN ou VU)
+func IsProtectedSpecies(s *types.SpecimenWithSpecies) bool {
+	return s.Protected || IsThreatenedSpecies(s)
+}
+
+// ProductVolume representa o volume de um produto da supressão
+type ProductVolume struct {
+	Individuals float64 `json:"individuals"` // na estimativa, extrapolado para a área total
+	VolumeM3    float64 `json:"volumeM3"`
+	VolumeMst   float64 `json:"volumeMst"` // metros estéreos (m³ / fator de empilhamento)
+}
+
+// SuppressionVolumes representa o volume suprimido por produto
+type SuppressionVolumes struct {
+	Log      ProductVolume `json:"log"`      // tora
+	Firewood ProductVolume `json:"firewood"` // lenha
+	Total    ProductVolume `json:"total"`
+}
+
+// SpeciesSuppression representa o volume suprimido de uma espécie
+type SpeciesSuppression struct {
+	SpeciesID      string              `json:"speciesId,omitempty"` // vazio para morfoespécies
+	ScientificName string              `json:"scientificName"`
+	Family         string              `json:"family"`
+	PopularName    *string             `json:"popularName,omitempty"`
+	Protected      bool                `json:"protected"`
+	ThreatStatus   *string             `json:"threatStatus,omitempty"`
+	Sampled        SuppressionVolumes  `json:"sampled"`
+	Estimated      *SuppressionVolumes `json:"estimated,omitempty"`
+}
+
+// SuppressionReport representa o volume da supressão de vegetação por produto e por espécie.
+// Os valores amostrados são extrapolados para a área total da análise (área de supressão).
+type SuppressionReport struct {
+	Rules         types.AssortmentRules `json:"rules"`
+	SampledAreaHa float64               `json:"sampledAreaHa"`
+	TotalAreaHa   float64               `json:"totalAreaHa"`
+
+	Sampled   SuppressionVolumes  `json:"sampled"`
+	PerHa     *SuppressionVolumes `json:"perHa,omitempty"`
+	Estimated *SuppressionVolumes `json:"estimated,omitempty"` // omitido sem área amostrada ou total
+
+	// Espécies sem restrição de corte, por volume (desc)
+	Species []SpeciesSuppression `json:"species"`
+	// Espécies protegidas ou ameaçadas, listadas à parte, por volume (desc)
+	ProtectedSpecies []SpeciesSuppression `json:"protectedSpecies"`
+	ProtectedSampled SuppressionVolumes   `json:"protectedSampled"`
+}
+
+// add soma um indivíduo ao produto
+func (v *SuppressionVolumes) add(product string, volumeM3 float64) {
+	target := &v.Firewood
+	if product == types.TimberProductLog {
+		target = &v.Log
+	}
+	target.Individuals++
+	target.VolumeM3 += volumeM3
+	v.Total.Individuals++
+	v.Total.VolumeM3 += volumeM3
+}
+
+// withStacking preenche os metros estéreos pelo fator de empilhamento
+func (v SuppressionVolumes) withStacking(stackingFactor float64) SuppressionVolumes {
+	for _, pv := range []*ProductVolume{&v.Log, &v.Firewood, &v.Total} {
+		pv.VolumeMst = 0
+		if stackingFactor > 0 {
+			pv.VolumeMst = pv.VolumeM3 / stackingFactor
+		}
+	}
+	return v
+}
+
+// scaled multiplica os volumes e indivíduos (extrapolação por área)
+func (v SuppressionVolumes) scaled(factor float64) SuppressionVolumes {
+	for _, pv := range []*ProductVolume{&v.Log, &v.Firewood, &v.Total} {
+		pv.Individuals *= factor
+		pv.VolumeM3 *= factor
+		pv.VolumeMst *= factor
+	}
+	return v
+}
+
+// ComputeSuppression calcula o volume da supressão por produto (tora e lenha) e por espécie,
+// com o volume individual da equação volumétrica da análise
+func ComputeSuppression(p *types.PhytoAnalysisComplete) *SuppressionReport {
+	rules := ResolveAssortment(p)
+	out := &SuppressionReport{
+		Rules:            rules,
+		SampledAreaHa:    p.SampledArea,
+		TotalAreaHa:      p.TotalArea,
+		Species:          make([]SpeciesSuppression, 0),
+		ProtectedSpecies: make([]SpeciesSuppression, 0),
+	}
+
+	metrics := ComputeSpecimens(p)
+	bySpecies := make(map[string]*SpeciesSuppression)
+	order := make([]string, 0)
+	for i, s := range p.Specimens {
+		m := metrics[i]
+		product := TimberProduct(rules, s, m.DbhCm)
+		out.Sampled.add(product, m.VolumeM3)
+
+		key := speciesKey(s)
+		sp, ok := bySpecies[key]
+		if !ok {
+			family := s.Family
+			if family == "" {
+				family = UnknownFamily
+			}
+			sp = &SpeciesSuppression{
+				SpeciesID:      s.SpecieID,
+				ScientificName: s.ScientificName,
+				Family:         family,
+				PopularName:    s.PopularName,
+				Protected:      IsProtectedSpecies(s),
+				ThreatStatus:   s.ThreatStatus,
+			}
+			bySpecies[key] = sp
+			order = append(order, key)
+		}
+		sp.Sampled.add(product, m.VolumeM3)
+		if sp.Protected {
+			out.ProtectedSampled.add(product, m.VolumeM3)
+		}
+	}
+
+	// fator de extrapolação da amostra para a área total
+	var expansion float64
+	if p.SampledArea > 0 && p.TotalArea > 0 {
+		expansion = p.TotalArea / p.SampledArea
+	}
+
+	out.Sampled = out.Sampled.withStacking(rules.StackingFactor)
+	out.ProtectedSampled = out.ProtectedSampled.withStacking(rules.StackingFactor)
+	if p.SampledArea > 0 {
+		perHa := out.Sampled.scaled(1 / p.SampledArea)
+		out.PerHa = &perHa
+	}
+	if expansion > 0 {
+		estimated := out.Sampled.scaled(expansion)
+		out.Estimated = &estimated
+	}
+
+	for _, key := range order {
+		sp := bySpecies[key]
+		sp.Sampled = sp.Sampled.withStacking(rules.StackingFactor)
+		if expansion > 0 {
+			estimated := sp.Sampled.scaled(expansion)
+			sp.Estimated = &estimated
+		}
+		if sp.Protected {
+			out.ProtectedSpecies = append(out.ProtectedSpecies, *sp)
+		} else {
+			out.Species = append(out.Species, *sp)
+		}
+	}
+
+	for _, list := range [][]SpeciesSuppression{out.Species, out.ProtectedSpecies} {
+		sort.SliceStable(list, func(i, j int) bool {
+			if list[i].Sampled.Total.VolumeM3 != list[j].Sampled.Total.VolumeM3 {
+				return list[i].Sampled.Total.VolumeM3 > list[j].Sampled.Total.VolumeM3
+			}
+			return list[i].ScientificName < list[j].ScientificName
+		})
+	}
+
+	return out
+}
@@ -0,0 +1,59 @@
+package phytometrics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeSuppression(t *testing.T) {
+	t.Parallel()
+
+	vu := "VU"
+	p := &types.PhytoAnalysisComplete{
+		SampledArea: 0.1,
+		TotalArea:   2,
+		Assortment:  &types.AssortmentRules{LogMinDbhCm: 25, StackingFactor: 0.5, DeadAsFirewood: true},
+		Specimens: []*types.SpecimenWithSpecies{
+			{Portion: "1", Cap1: 30 * math.Pi, Height: 10, ScientificName: "A a", Family: "Fabaceae", Status: "alive"},
+			{Portion: "1", Cap1: 10 * math.Pi, Height: 10, ScientificName: "A a", Family: "Fabaceae", Status: "alive"},
+			{Portion: "1", Cap1: 40 * math.Pi, Height: 10, ScientificName: "A a", Family: "Fabaceae", Status: "dead_standing"},
+			{Portion: "1", Cap1: 30 * math.Pi, Height: 10, ScientificName: "B b", Family: "Lauraceae", Status: "alive", ThreatStatus: &vu},
+		},
+	}
+
+	volume := func(dbhCm float64) float64 { return math.Pi * dbhCm * dbhCm / 40000 * 10 }
+
+	out := ComputeSuppression(p)
+
+	require.Equal(t, 25.0, out.Rules.LogMinDbhCm)
+	require.Equal(t, 2.0, out.Sampled.Log.Individuals)
+	require.Equal(t, 2.0, out.Sampled.Firewood.Individuals) // DAP abaixo do mínimo e morta
+	require.InDelta(t, 2*volume(30), out.Sampled.Log.VolumeM3, 1e-9)
+	require.InDelta(t, volume(10)+volume(40), out.Sampled.Firewood.VolumeM3, 1e-9)
+	require.InDelta(t, out.Sampled.Total.VolumeM3/0.5, out.Sampled.Total.VolumeMst, 1e-9)
+
+	require.NotNil(t, out.Estimated)
+	require.InDelta(t, out.Sampled.Total.VolumeM3*20, out.Estimated.Total.VolumeM3, 1e-9)
+	require.InDelta(t, 80, out.Estimated.Total.Individuals, 1e-9)
+	require.InDelta(t, out.Sampled.Log.VolumeM3*10, out.PerHa.Log.VolumeM3, 1e-9)
+
+	// espécie ameaçada listada à parte
+	require.Len(t, out.Species, 1)
+	require.Equal(t, "A a", out.Species[0].ScientificName)
+	require.Len(t, out.ProtectedSpecies, 1)
+	require.Equal(t, "B b", out.ProtectedSpecies[0].ScientificName)
+	require.True(t, out.ProtectedSpecies[0].Protected)
+	require.InDelta(t, volume(30), out.ProtectedSampled.Log.VolumeM3, 1e-9)
+}
+
+func TestResolveAssortment_Defaults(t *testing.T) {
+	t.Parallel()
+
+	rules := ResolveAssortment(&types.PhytoAnalysisComplete{})
+	require.Equal(t, DefaultLogMinDbhCm, rules.LogMinDbhCm)
+	require.Equal(t, StackingFactor, rules.StackingFactor)
+	require.False(t, rules.DeadAsFirewood)
+}
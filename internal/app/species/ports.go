@@ -14,6 +14,8 @@ type Repo interface {
 	GetByID(ctx context.Context, id string) (*types.SpeciesWithLegislation, error)
 	GetByScientificName(ctx context.Context, scientificName string) (*types.SpeciesWithLegislation, error)
 	GetMapByScientificNames(ctx context.Context, names []string) (map[string]string, error)
+	GetNamesByIDs(ctx context.Context, ids []string) (map[string]string, error)
+	MatchScientificNames(ctx context.Context, names []string, limit int) (map[string][]types.SpeciesMatch, error)
 	List(ctx context.Context, limit, offset int32) ([]*types.SpeciesWithLegislation, error)
 	UpdateSpecies(ctx context.Context, s *domainspecies.Species) error
 	UpdateLegislation(ctx context.Context, sl *domainspecies.SpeciesLegislation) error
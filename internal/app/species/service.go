@@ -15,6 +15,7 @@ type ServiceInterface interface {
 	GetByScientificName(ctx context.Context, scientificName string) (*types.SpeciesWithLegislation, error)
 	GetOrCreate(ctx context.Context, in CreateInput) (*types.SpeciesWithLegislation, error)
 	List(ctx context.Context, limit, offset int32) ([]*types.SpeciesWithLegislation, error)
+	UpdateWoodDensity(ctx context.Context, id string, woodDensity *float64) (*types.SpeciesWithLegislation, error)
 }
 
 type Service struct {
@@ -30,6 +31,7 @@ type CreateInput struct {
 	Family              string
 	PopularName         *string
 	Habit               *string
+	WoodDensity         *float64
 	LawScope            string
 	LawID               *string
 	IsLawActive         bool
@@ -61,6 +63,10 @@ func (s *Service) Create(ctx context.Context, in CreateInput) (string, error) {
 		species.SetHabit(in.Habit)
 	}
 
+	if in.WoodDensity != nil {
+		species.SetWoodDensity(in.WoodDensity)
+	}
+
 	if err := species.Validate(); err != nil {
 		return "", apperr.Wrap(err, apperr.CodeInvalid, "invalid species data")
 	}
@@ -142,3 +148,33 @@ func (s *Service) List(ctx context.Context, limit, offset int32) ([]*types.Speci
 
 	return s.repo.List(ctx, limit, offset)
 }
+
+// UpdateWoodDensity define (ou remove, com nil) a densidade básica da madeira da espécie
+func (s *Service) UpdateWoodDensity(ctx context.Context, id string, woodDensity *float64) (*types.SpeciesWithLegislation, error) {
+	current, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	species := &domainspecies.Species{
+		ID:             current.ID,
+		ScientificName: current.ScientificName,
+		Family:         current.Family,
+		PopularName:    current.PopularName,
+		Habit:          current.Habit,
+		WoodDensity:    current.WoodDensity,
+		CreatedAt:      current.CreatedAt,
+		UpdatedAt:      current.UpdatedAt,
+	}
+	species.SetWoodDensity(woodDensity)
+
+	if err := species.Validate(); err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeInvalid, "invalid species data")
+	}
+
+	if err := s.repo.UpdateSpecies(ctx, species); err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeInternal, "failed to update species")
+	}
+
+	return s.repo.GetByID(ctx, id)
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/ESG-Project/suassu-api/internal/app/types"
 	domainspecimen "github.com/ESG-Project/suassu-api/internal/domain/specimen"
+	postgres "github.com/ESG-Project/suassu-api/internal/infra/db/postgres"
 )
 
 // Repo define a interface do repositório de Specimen
@@ -18,3 +19,9 @@ type Repo interface {
 	CountByPhytoAnalysis(ctx context.Context, phytoAnalysisID string) (int64, error)
 }
 
+
+// IndicatorsRefresher atualiza o snapshot de indicadores da análise após alterações nos espécimes,
+// dentro da transação da alteração
+type IndicatorsRefresher interface {
+	RefreshIndicatorsInTx(ctx context.Context, repos postgres.Repos, phytoAnalysisID string, reason string) error
+}
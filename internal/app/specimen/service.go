@@ -2,11 +2,14 @@ package specimen
 
 import (
 	"context"
+	"strings"
 	"time"
 
+	"github.com/ESG-Project/suassu-api/internal/app/phytometrics"
 	"github.com/ESG-Project/suassu-api/internal/app/types"
 	"github.com/ESG-Project/suassu-api/internal/apperr"
 	domainspecimen "github.com/ESG-Project/suassu-api/internal/domain/specimen"
+	postgres "github.com/ESG-Project/suassu-api/internal/infra/db/postgres"
 	"github.com/google/uuid"
 )
 
@@ -19,42 +22,127 @@ type ServiceInterface interface {
 }
 
 type Service struct {
-	repo Repo
+	repo       Repo
+	txm        postgres.TxManagerInterface
+	indicators IndicatorsRefresher // opcional
 }
 
-func NewService(r Repo) *Service {
-	return &Service{repo: r}
+func NewService(r Repo, txm postgres.TxManagerInterface) *Service {
+	return &Service{repo: r, txm: txm}
+}
+
+// NewServiceWithIndicators cria o serviço atualizando os indicadores da análise a cada alteração,
+// na mesma transação da escrita do espécime
+func NewServiceWithIndicators(r Repo, txm postgres.TxManagerInterface, indicators IndicatorsRefresher) *Service {
+	return &Service{repo: r, txm: txm, indicators: indicators}
+}
+
+// write aplica a alteração nos espécimes em uma transação (com as validações que dependem da análise)
+// e, com indicadores, grava o snapshot da análise na mesma transação: se o snapshot falhar, a
+// alteração é desfeita
+func (s *Service) write(ctx context.Context, phytoAnalysisID string, change func(repos postgres.Repos) error) error {
+	if s.txm == nil {
+		return apperr.New(apperr.CodeInvalid, "transaction manager required")
+	}
+
+	return s.txm.RunInTx(ctx, func(repos postgres.Repos) error {
+		if err := change(repos); err != nil {
+			return err
+		}
+		if s.indicators == nil {
+			return nil
+		}
+		return s.indicators.RefreshIndicatorsInTx(ctx, repos, phytoAnalysisID, phytometrics.ReasonSpecimensChanged)
+	})
+}
+
+// validateInAnalysis confere as referências do espécime à sua análise, na transação da escrita.
+// Com parcelas cadastradas, a parcela do espécime deve existir (como na importação), e a plaqueta
+// não pode estar em outro espécime da análise.
+func validateInAnalysis(ctx context.Context, repos postgres.Repos, sp *domainspecimen.Specimen) error {
+	plots, err := repos.Plots().ListByPhytoAnalysis(ctx, sp.PhytoAnalysisID)
+	if err != nil {
+		return err
+	}
+	if len(plots) > 0 && !hasPlot(plots, sp.Portion) {
+		return apperr.New(apperr.CodeInvalid, "plot not found")
+	}
+
+	if sp.Tag != nil {
+		used, err := repos.Specimens().TagInUse(ctx, sp.PhytoAnalysisID, *sp.Tag, sp.ID)
+		if err != nil {
+			return err
+		}
+		if used {
+			return apperr.New(apperr.CodeConflict, "tag already used in the analysis")
+		}
+	}
+	if sp.MorphospeciesID != nil {
+		if _, err := repos.Morphospecies().GetByID(ctx, sp.PhytoAnalysisID, *sp.MorphospeciesID); err != nil {
+			if apperr.CodeOf(err) == apperr.CodeNotFound {
+				return apperr.New(apperr.CodeInvalid, "morphospecies not found in the analysis")
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func hasPlot(plots []*types.PlotData, code string) bool {
+	for _, p := range plots {
+		if p.Code == code {
+			return true
+		}
+	}
+	return false
 }
 
 type CreateInput struct {
-	Portion         string
-	Height          float64
-	Cap1            float64
-	Cap2            *float64
-	Cap3            *float64
-	Cap4            *float64
-	Cap5            *float64
-	Cap6            *float64
-	RegisterDate    time.Time
-	PhytoAnalysisID string
-	SpecieID        string
+	Portion          string
+	Height           *float64 // opcional: sem medição, é estimada pela relação hipsométrica da análise
+	Cap1             float64
+	Cap2             *float64
+	Cap3             *float64
+	Cap4             *float64
+	Cap5             *float64
+	Cap6             *float64
+	RegisterDate     time.Time
+	PhytoAnalysisID  string
+	SpecieID         string
+	MorphospeciesID  *string  // alternativa à espécie: morfoespécie da análise
+	Latitude         *float64 // opcional: localização do indivíduo (WGS84)
+	Longitude        *float64
+	Tag              *string // opcional: plaqueta, identidade da árvore entre campanhas
+	Status           string  // opcional: situação do indivíduo (vazia = viva)
+	Phytosanitary    *string // opcional: condição fitossanitária
+	BifurcationNotes *string // opcional: observações sobre a bifurcação
+	Observations     *string
 }
 
 type UpdateInput struct {
-	Portion      string
-	Height       float64
-	Cap1         float64
-	Cap2         *float64
-	Cap3         *float64
-	Cap4         *float64
-	Cap5         *float64
-	Cap6         *float64
-	RegisterDate time.Time
-	SpecieID     string
+	Portion          string
+	Height           *float64
+	Cap1             float64
+	Cap2             *float64
+	Cap3             *float64
+	Cap4             *float64
+	Cap5             *float64
+	Cap6             *float64
+	RegisterDate     time.Time
+	SpecieID         string
+	MorphospeciesID  *string
+	Latitude         *float64
+	Longitude        *float64
+	Tag              *string
+	Status           string
+	Phytosanitary    *string
+	BifurcationNotes *string
+	Observations     *string
 }
 
 func (s *Service) Create(ctx context.Context, in CreateInput) (string, error) {
-	if in.Portion == "" || in.PhytoAnalysisID == "" || in.SpecieID == "" {
+	in.Portion = strings.TrimSpace(in.Portion)
+	if in.Portion == "" || in.PhytoAnalysisID == "" || (in.SpecieID == "" && in.MorphospeciesID == nil) {
 		return "", apperr.New(apperr.CodeInvalid, "missing required fields")
 	}
 
@@ -70,12 +158,22 @@ func (s *Service) Create(ctx context.Context, in CreateInput) (string, error) {
 	)
 
 	specimen.SetOptionalCaps(in.Cap2, in.Cap3, in.Cap4, in.Cap5, in.Cap6)
+	specimen.SetLocation(in.Latitude, in.Longitude)
+	specimen.SetTag(in.Tag)
+	specimen.SetCondition(in.Status, in.Phytosanitary, in.BifurcationNotes, in.Observations)
+	specimen.SetMorphospecies(in.MorphospeciesID)
 
 	if err := specimen.Validate(); err != nil {
 		return "", apperr.Wrap(err, apperr.CodeInvalid, "invalid specimen data")
 	}
 
-	if err := s.repo.Create(ctx, specimen); err != nil {
+	err := s.write(ctx, in.PhytoAnalysisID, func(repos postgres.Repos) error {
+		if err := validateInAnalysis(ctx, repos, specimen); err != nil {
+			return err
+		}
+		return repos.Specimens().Create(ctx, specimen)
+	})
+	if err != nil {
 		return "", err
 	}
 
@@ -95,7 +193,8 @@ func (s *Service) ListByPhytoAnalysis(ctx context.Context, phytoAnalysisID strin
 }
 
 func (s *Service) Update(ctx context.Context, id string, in UpdateInput) error {
-	if in.Portion == "" || in.SpecieID == "" {
+	in.Portion = strings.TrimSpace(in.Portion)
+	if in.Portion == "" || (in.SpecieID == "" && in.MorphospeciesID == nil) {
 		return apperr.New(apperr.CodeInvalid, "missing required fields")
 	}
 
@@ -120,14 +219,34 @@ func (s *Service) Update(ctx context.Context, id string, in UpdateInput) error {
 	specimen.UpdatedAt = time.Now()
 
 	specimen.SetOptionalCaps(in.Cap2, in.Cap3, in.Cap4, in.Cap5, in.Cap6)
+	specimen.SetLocation(in.Latitude, in.Longitude)
+	specimen.SetTag(in.Tag)
+	specimen.SetCondition(in.Status, in.Phytosanitary, in.BifurcationNotes, in.Observations)
+	specimen.SetMorphospecies(in.MorphospeciesID)
 
 	if err := specimen.Validate(); err != nil {
 		return apperr.Wrap(err, apperr.CodeInvalid, "invalid specimen data")
 	}
 
-	return s.repo.Update(ctx, specimen)
+	return s.write(ctx, existing.PhytoAnalysisID, func(repos postgres.Repos) error {
+		if err := validateInAnalysis(ctx, repos, specimen); err != nil {
+			return err
+		}
+		return repos.Specimens().Update(ctx, specimen)
+	})
 }
 
 func (s *Service) Delete(ctx context.Context, id string) error {
-	return s.repo.Delete(ctx, id)
+	if s.indicators == nil {
+		return s.repo.Delete(ctx, id)
+	}
+
+	existing, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return apperr.Wrap(err, apperr.CodeNotFound, "specimen not found")
+	}
+
+	return s.write(ctx, existing.PhytoAnalysisID, func(repos postgres.Repos) error {
+		return repos.Specimens().Delete(ctx, id)
+	})
 }
@@ -0,0 +1,43 @@
+package types
+
+import "time"
+
+// Tipos de equação do cadastro
+const (
+	EquationKindVolume  = "volume"  // volume individual (m³)
+	EquationKindBiomass = "biomass" // biomassa seca acima do solo (kg)
+)
+
+// EquationData representa uma equação volumétrica ou alométrica com seus coeficientes.
+// É gravada como JSON junto da seleção da análise e do snapshot dos indicadores.
+type EquationData struct {
+	ID            string                `json:"id"`
+	Name          string                `json:"name"`
+	Kind          string                `json:"kind"`
+	Expression    string                `json:"expression"`
+	Coefficients  map[string]float64    `json:"coefficients,omitempty"`
+	Applicability EquationApplicability `json:"applicability"`
+	Reference     *string               `json:"reference,omitempty"`
+	BuiltIn       bool                  `json:"builtIn"`
+	CreatedAt     time.Time             `json:"createdAt"`
+	UpdatedAt     time.Time             `json:"updatedAt"`
+}
+
+// EquationApplicability restringe onde a equação se aplica; listas vazias não restringem.
+// Estado e tipo de vegetação descrevem a análise; hábito, família e espécie, cada espécime.
+type EquationApplicability struct {
+	States          []string `json:"states,omitempty"` // UFs (ex.: MG, SP)
+	VegetationTypes []string `json:"vegetationTypes,omitempty"`
+	Habits          []string `json:"habits,omitempty"`
+	Families        []string `json:"families,omitempty"`
+	SpeciesIDs      []string `json:"speciesIds,omitempty"`
+}
+
+// EquationFilter representa os filtros da listagem do cadastro de equações
+type EquationFilter struct {
+	Kind           string
+	State          string
+	VegetationType string
+	Habit          string
+	Family         string
+}
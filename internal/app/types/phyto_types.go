@@ -50,6 +50,21 @@ type PhytoAnalysisComplete struct {
 	ProjectLatitude     *string
 	ProjectLongitude    *string
 	ProjectAddInfo      *string
+	// Fator de forma padrão para espécies sem legislação aplicável (nil = cilíndrico)
+	DefaultFormFactor *float64
+	// Protocolo de medição e critério de inclusão usados na importação dos espécimes
+	Measurement MeasurementProtocol
+	// Equações selecionadas para a análise (nil = padrão do motor)
+	VolumeEquation  *EquationData
+	BiomassEquation *EquationData
+	// Parcelas cadastradas (vazio = parcelas apenas pelo código informado nos espécimes)
+	Plots []*PlotData
+	// Estratos cadastrados (vazio = população homogênea, área total em TotalArea)
+	Strata []*StratumData
+	// Campanha anterior remedida por esta análise (nil = primeira campanha ou análise avulsa)
+	PreviousAnalysisID *string
+	// Regras de classificação da madeira suprimida em produtos (nil = regras padrão do motor)
+	Assortment *AssortmentRules
 	// Lista de espécimes
 	Specimens []*SpecimenWithSpecies
 }
@@ -58,7 +73,8 @@ type PhytoAnalysisComplete struct {
 type SpecimenWithSpecies struct {
 	ID              string
 	Portion         string
-	Height          float64
+	Height          float64 // altura medida (0 quando não medida) ou estimada pela relação hipsométrica
+	HeightEstimated bool    // true quando a altura foi estimada pela relação hipsométrica
 	Cap1            float64
 	Cap2            *float64
 	Cap3            *float64
@@ -67,13 +83,110 @@ type SpecimenWithSpecies struct {
 	Cap6            *float64
 	RegisterDate    time.Time
 	PhytoAnalysisID string
-	SpecieID        string
+	SpecieID        string // vazio quando o indivíduo é de uma morfoespécie
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
+	// Morfoespécie da análise (nil = espécie do cadastro); ScientificName traz o nome da morfoespécie
+	MorphospeciesID *string
+	// Localização do indivíduo em WGS84 (nil quando não georreferenciado)
+	Latitude  *float64
+	Longitude *float64
+	// Plaqueta do indivíduo: identidade da árvore entre campanhas (nil = não plaqueteado)
+	Tag *string
+	// Situação do indivíduo (SpecimenStatus*) e observações de campo
+	Status           string
+	Phytosanitary    *string // PhytosanitaryGood, PhytosanitaryFair ou PhytosanitaryPoor (nil = não avaliado)
+	BifurcationNotes *string
+	Observations     *string
 	// Dados da espécie
 	ScientificName string
 	Family         string
 	PopularName    *string
+	// Fator de forma da legislação aplicável à espécie (nil quando não há)
+	FormFactor *float64
+	// Densidade básica da madeira da espécie em g/cm³ (nil quando não cadastrada)
+	WoodDensity *float64
+	// Hábito da espécie (nil quando não cadastrado)
+	Habit *string
+	// Proteção da espécie nas legislações vigentes: protegida (corte restrito) e o grau de
+	// ameaça mais restritivo (LC, NT, VU, EN ou CR; nil quando não há legislação)
+	Protected    bool
+	ThreatStatus *string
+	// Legislações vigentes da espécie, da municipal para a federal (vazio quando não há)
+	Legislations []SpeciesLegislationStatus
+}
+
+// SpeciesLegislationStatus representa os atributos de uma legislação vigente da espécie usados
+// nos cálculos da análise (ex.: regras de compensação, perfil ecológico)
+type SpeciesLegislationStatus struct {
+	LawScope            string  `json:"lawScope"` // FEDERAL, STATE ou MUNICIPAL
+	LawID               *string `json:"lawId,omitempty"`
+	Protected           bool    `json:"protected"`
+	ThreatStatus        string  `json:"threatStatus"`        // LC, NT, VU, EN ou CR
+	Origin              string  `json:"origin"`              // N, EX ou EXI
+	SuccessionalEcology string  `json:"successionalEcology"` // P, IS, S, C, LS, MS ou AS
+}
+
+// Situação do indivíduo no momento da medição
+const (
+	SpecimenStatusAlive        = "alive"
+	SpecimenStatusDeadStanding = "dead_standing" // morta em pé
+	SpecimenStatusFallen       = "fallen"        // morta caída
+	SpecimenStatusResprouting  = "resprouting"   // rebrotando
+)
+
+// Condição fitossanitária do indivíduo
+const (
+	PhytosanitaryGood = "good"
+	PhytosanitaryFair = "fair"
+	PhytosanitaryPoor = "poor"
+)
+
+// Campos aceitos para ordenar a listagem de espécimes
+const (
+	SpecimenSortPortion        = "portion"
+	SpecimenSortScientificName = "scientificName"
+	SpecimenSortFamily         = "family"
+	SpecimenSortDbh            = "dbh"
+	SpecimenSortMeasuredHeight = "measuredHeight" // altura medida em campo; sem medição = 0
+	SpecimenSortRegisterDate   = "registerDate"
+)
+
+// SpecimenFilter representa os filtros e a ordenação da listagem de espécimes de uma análise
+type SpecimenFilter struct {
+	Portion           string
+	SpecieID          string
+	MorphospeciesID   string
+	Tag               string
+	Status            string // SpecimenStatus*
+	Phytosanitary     string // Phytosanitary*
+	ScientificName    string // sem diferenciar maiúsculas
+	Family            string // sem diferenciar maiúsculas
+	MinDbhCm          *float64
+	MaxDbhCm          *float64
+	MinMeasuredHeight *float64 // altura medida em campo; exclui os sem medição (altura estimada)
+	MaxMeasuredHeight *float64
+	SortBy            string // SpecimenSort*; vazio = parcela
+	Desc              bool
+}
+
+// SpecimenCursorKey representa a chave de cursor da listagem de espécimes.
+// A ordenação vai no cursor para que ele só seja aceito na mesma consulta que o gerou.
+type SpecimenCursorKey struct {
+	SortBy string `json:"sort"`
+	Desc   bool   `json:"desc,omitempty"`
+	Value  string `json:"value"` // valor do campo de ordenação no último item (texto do banco)
+	ID     string `json:"id"`
+
+	// Value convertido para o tipo do campo de ordenação (preenchido na leitura do cursor)
+	Number *float64   `json:"-"` // dbh, measuredHeight
+	Time   *time.Time `json:"-"` // registerDate
+}
+
+// SpecimenPageInfo representa informações de paginação da listagem de espécimes
+type SpecimenPageInfo struct {
+	Next    *SpecimenCursorKey
+	HasMore bool
 }
 
 // SpeciesWithLegislation representa uma espécie com dados das legislações
@@ -83,6 +196,7 @@ type SpeciesWithLegislation struct {
 	Family         string
 	PopularName    *string
 	Habit          *string
+	WoodDensity    *float64
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
 	// Lista de legislações associadas
@@ -104,3 +218,103 @@ type LegislationData struct {
 	CreatedAt           time.Time
 	UpdatedAt           time.Time
 }
+
+// Modos de medição dos fustes em campo
+const (
+	MeasurementModeCAP = "cap" // circunferência à altura do peito
+	MeasurementModeDAP = "dap" // diâmetro à altura do peito
+)
+
+// Unidades de medição dos fustes
+const (
+	MeasurementUnitCm = "cm"
+	MeasurementUnitMm = "mm"
+)
+
+// Tratamento dos fustes abaixo do critério de inclusão
+const (
+	InclusionReject = "reject" // a linha é inválida
+	InclusionFlag   = "flag"   // a linha é aceita e sinalizada no relatório
+)
+
+// MeasurementProtocol descreve como os fustes são medidos na análise e o critério de inclusão.
+// Os espécimes são sempre gravados como CAP em cm: o protocolo é aplicado na importação.
+type MeasurementProtocol struct {
+	Mode            string   `json:"mode"`                   // MeasurementModeCAP (padrão) ou MeasurementModeDAP
+	Unit            string   `json:"unit"`                   // MeasurementUnitCm (padrão) ou MeasurementUnitMm
+	InclusionMin    *float64 `json:"inclusionMin,omitempty"` // valor mínimo por fuste, no modo e unidade do protocolo (nil = sem critério)
+	InclusionPolicy string   `json:"inclusionPolicy"`        // InclusionReject (padrão) ou InclusionFlag
+	ExcludeDead     bool     `json:"excludeDead"`            // indivíduos mortos ficam fora dos indicadores
+}
+
+// Produtos da madeira suprimida
+const (
+	TimberProductLog      = "log"      // tora
+	TimberProductFirewood = "firewood" // lenha
+)
+
+// AssortmentRules define como o volume suprimido é classificado em produtos (tora e lenha)
+type AssortmentRules struct {
+	LogMinDbhCm    float64 `json:"logMinDbhCm"`    // DAP mínimo (cm) para tora; abaixo dele o indivíduo vira lenha
+	StackingFactor float64 `json:"stackingFactor"` // fator de empilhamento: mst = m³ / fator
+	DeadAsFirewood bool    `json:"deadAsFirewood"` // indivíduos mortos vão para lenha independentemente do DAP
+}
+
+// CompensationRule associa atributos de uma legislação vigente da espécie à razão de plantio
+// compensatório (mudas por indivíduo suprimido). Critérios vazios aceitam qualquer valor.
+type CompensationRule struct {
+	ID             string   `json:"id"`
+	Description    string   `json:"description"`              // citada no relatório
+	LawScope       string   `json:"lawScope,omitempty"`       // FEDERAL, STATE ou MUNICIPAL
+	Protected      *bool    `json:"protected,omitempty"`      // espécie protegida (imune de corte) na legislação
+	ThreatStatuses []string `json:"threatStatuses,omitempty"` // graus de ameaça aceitos (ex.: CR, EN)
+	Ratio          float64  `json:"ratio"`                    // mudas por indivíduo suprimido
+}
+
+// InvalidSpecimenRow representa uma linha de importação com os erros encontrados
+type InvalidSpecimenRow struct {
+	RowNumber int      `json:"rowNumber"`
+	Errors    []string `json:"errors"`
+	// Espécies do catálogo sugeridas quando o nome científico não foi encontrado
+	Suggestions []SpeciesMatch `json:"suggestions,omitempty"`
+}
+
+// FlaggedSpecimenRow representa uma linha aceita com avisos (ex.: fuste abaixo do critério de inclusão)
+type FlaggedSpecimenRow struct {
+	RowNumber int      `json:"rowNumber"`
+	Warnings  []string `json:"warnings"`
+}
+
+// SpeciesMatch representa uma espécie do catálogo candidata para um nome científico informado
+type SpeciesMatch struct {
+	SpeciesID      string  `json:"speciesId"`
+	ScientificName string  `json:"scientificName"`
+	Family         string  `json:"family"`
+	Score          float64 `json:"score"` // similaridade entre os nomes normalizados (0..1)
+	Exact          bool    `json:"exact"` // nomes iguais após a normalização
+}
+
+// SpecimenImportReport representa o resultado da validação (dry-run) de uma importação de espécimes
+type SpecimenImportReport struct {
+	TotalRows      int      // Linhas preenchidas (linhas em branco são ignoradas)
+	ValidRows      int      // Linhas aprovadas em todas as etapas
+	AnalysisErrors []string // Erros nos dados da análise (apenas na criação)
+	InvalidRows    []InvalidSpecimenRow
+	FlaggedRows    []FlaggedSpecimenRow // Linhas aceitas com fustes abaixo do critério de inclusão
+	// Morfoespécies que a importação cadastra (nomes sem determinação no catálogo)
+	NewMorphospecies []string
+	// Prévia da análise com os espécimes válidos, usada para calcular os indicadores
+	Preview *PhytoAnalysisComplete
+}
+
+// MorphospeciesData representa uma morfoespécie da análise (indivíduos ainda sem determinação botânica)
+type MorphospeciesData struct {
+	ID              string    `json:"id"`
+	PhytoAnalysisID string    `json:"phytoAnalysisId"`
+	Name            string    `json:"name"`
+	Family          *string   `json:"family,omitempty"`
+	Genus           *string   `json:"genus,omitempty"`
+	SpecimenCount   int64     `json:"specimenCount"`
+	CreatedAt       time.Time `json:"createdAt"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+}
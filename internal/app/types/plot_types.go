@@ -0,0 +1,39 @@
+package types
+
+import "time"
+
+// Coordinate representa um ponto geográfico (graus decimais, WGS84)
+type Coordinate struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// PlotData representa uma parcela da análise; os espécimes a referenciam pelo código (Portion)
+type PlotData struct {
+	ID              string       `json:"id"`
+	PhytoAnalysisID string       `json:"phytoAnalysisId"`
+	Code            string       `json:"code"`
+	AreaM2          float64      `json:"areaM2"`
+	Shape           string       `json:"shape"`
+	WidthM          *float64     `json:"widthM,omitempty"`
+	LengthM         *float64     `json:"lengthM,omitempty"`
+	RadiusM         *float64     `json:"radiusM,omitempty"`
+	Center          *Coordinate  `json:"center,omitempty"`
+	Vertices        []Coordinate `json:"vertices,omitempty"`
+	VegetationNotes *string      `json:"vegetationNotes,omitempty"`
+	StratumID       *string      `json:"stratumId,omitempty"`
+	CreatedAt       time.Time    `json:"createdAt"`
+	UpdatedAt       time.Time    `json:"updatedAt"`
+}
+
+// StratumData representa um estrato da análise (amostragem estratificada)
+type StratumData struct {
+	ID              string    `json:"id"`
+	PhytoAnalysisID string    `json:"phytoAnalysisId"`
+	Code            string    `json:"code"`
+	Name            string    `json:"name"`
+	AreaHa          float64   `json:"areaHa"`
+	Description     *string   `json:"description,omitempty"`
+	CreatedAt       time.Time `json:"createdAt"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+}
@@ -0,0 +1,66 @@
+package morphospecies
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// Morphospecies representa um táxon provisório da análise, usado para indivíduos ainda sem
+// determinação botânica (ex.: "Indet. 1", "Myrcia sp. 2"). Pode ser resolvida depois para uma
+// espécie do cadastro, quando os espécimes passam a apontar para a espécie.
+type Morphospecies struct {
+	ID              string
+	PhytoAnalysisID string
+	Name            string
+	Family          *string // nil quando não determinada
+	Genus           *string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// NewMorphospecies cria uma nova instância de Morphospecies
+func NewMorphospecies(id, phytoAnalysisID, name string) *Morphospecies {
+	now := time.Now()
+	return &Morphospecies{
+		ID:              id,
+		PhytoAnalysisID: phytoAnalysisID,
+		Name:            strings.Join(strings.Fields(name), " "),
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+}
+
+// SetTaxonomy define a família e o gênero já determinados (vazios = não determinados)
+func (m *Morphospecies) SetTaxonomy(family, genus *string) {
+	m.Family = trimmedOrNil(family)
+	m.Genus = trimmedOrNil(genus)
+}
+
+// Validate valida se a morfoespécie está em um estado válido
+func (m *Morphospecies) Validate() error {
+	if m.Name == "" {
+		return errors.New("name is required")
+	}
+	if len(m.Name) > 255 {
+		return errors.New("name must have at most 255 characters")
+	}
+	if m.Family != nil && len(*m.Family) > 255 {
+		return errors.New("family must have at most 255 characters")
+	}
+	if m.Genus != nil && len(*m.Genus) > 255 {
+		return errors.New("genus must have at most 255 characters")
+	}
+	return nil
+}
+
+func trimmedOrNil(v *string) *string {
+	if v == nil {
+		return nil
+	}
+	t := strings.TrimSpace(*v)
+	if t == "" {
+		return nil
+	}
+	return &t
+}
@@ -17,8 +17,19 @@ type PhytoAnalysis struct {
 	SampledArea     float64
 	Description     *string
 	ProjectID       string
-	CreatedAt       time.Time
-	UpdatedAt       time.Time
+	// Fator de forma usado quando a espécie não tem legislação aplicável (nil = cilíndrico)
+	DefaultFormFactor *float64
+	// Protocolo de medição dos fustes: "cap" ou "dap", em "cm" ou "mm"
+	MeasurementMode string
+	MeasurementUnit string
+	// Critério de inclusão por fuste, no modo e unidade do protocolo (nil = sem critério)
+	InclusionMin *float64
+	// Fustes abaixo do critério: "reject" ou "flag"
+	InclusionPolicy string
+	// Indivíduos mortos (em pé ou caídos) fora dos indicadores
+	ExcludeDead bool
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
 }
 
 // NewPhytoAnalysis cria uma nova instância de PhytoAnalysis
@@ -40,6 +51,9 @@ func NewPhytoAnalysis(
 		TotalArea:       totalArea,
 		SampledArea:     sampledArea,
 		ProjectID:       projectID,
+		MeasurementMode: "cap",
+		MeasurementUnit: "cm",
+		InclusionPolicy: "reject",
 		CreatedAt:       now,
 		UpdatedAt:       now,
 	}
@@ -68,6 +82,21 @@ func (p *PhytoAnalysis) Validate() error {
 	if p.InitialDate.IsZero() {
 		return errors.New("initial date is required")
 	}
+	if p.DefaultFormFactor != nil && *p.DefaultFormFactor <= 0 {
+		return errors.New("default form factor must be positive")
+	}
+	if p.MeasurementMode != "cap" && p.MeasurementMode != "dap" {
+		return errors.New("measurement mode must be cap or dap")
+	}
+	if p.MeasurementUnit != "cm" && p.MeasurementUnit != "mm" {
+		return errors.New("measurement unit must be cm or mm")
+	}
+	if p.InclusionMin != nil && *p.InclusionMin <= 0 {
+		return errors.New("inclusion minimum must be positive")
+	}
+	if p.InclusionPolicy != "reject" && p.InclusionPolicy != "flag" {
+		return errors.New("inclusion policy must be reject or flag")
+	}
 	return nil
 }
 
@@ -76,6 +105,20 @@ func (p *PhytoAnalysis) SetDescription(description *string) {
 	p.Description = description
 }
 
+// SetDefaultFormFactor define o fator de forma padrão da análise
+func (p *PhytoAnalysis) SetDefaultFormFactor(formFactor *float64) {
+	p.DefaultFormFactor = formFactor
+}
+
+// SetMeasurementProtocol define o protocolo de medição e os critérios de inclusão da análise
+func (p *PhytoAnalysis) SetMeasurementProtocol(mode, unit string, inclusionMin *float64, inclusionPolicy string, excludeDead bool) {
+	p.MeasurementMode = mode
+	p.MeasurementUnit = unit
+	p.InclusionMin = inclusionMin
+	p.InclusionPolicy = inclusionPolicy
+	p.ExcludeDead = excludeDead
+}
+
 // Update atualiza os dados da análise
 func (p *PhytoAnalysis) Update(
 	title string,
@@ -0,0 +1,143 @@
+package plot
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"time"
+)
+
+// Formatos de parcela
+const (
+	ShapeRectangle = "rectangle" // largura × comprimento
+	ShapeCircle    = "circle"    // raio
+	ShapeIrregular = "irregular" // área informada (ex.: a partir dos vértices)
+)
+
+// Coordinate representa um ponto geográfico (graus decimais, WGS84)
+type Coordinate struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// Plot representa uma parcela de uma análise fitossociológica.
+// Os espécimes referenciam a parcela pelo código (Specimen.Portion).
+type Plot struct {
+	ID              string
+	PhytoAnalysisID string
+	Code            string
+	AreaM2          float64
+	Shape           string
+	// Dimensões (m), conforme o formato
+	WidthM  *float64
+	LengthM *float64
+	RadiusM *float64
+	// Localização: centro e/ou vértices do polígono
+	Center          *Coordinate
+	Vertices        []Coordinate
+	VegetationNotes *string
+	StratumID       *string // estrato da parcela (amostragem estratificada)
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// NewPlot cria uma nova instância de Plot
+func NewPlot(id, phytoAnalysisID, code, shape string, areaM2 float64) *Plot {
+	now := time.Now()
+	return &Plot{
+		ID:              id,
+		PhytoAnalysisID: phytoAnalysisID,
+		Code:            code,
+		Shape:           shape,
+		AreaM2:          areaM2,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+}
+
+// SetDimensions define as dimensões da parcela
+func (p *Plot) SetDimensions(widthM, lengthM, radiusM *float64) {
+	p.WidthM = widthM
+	p.LengthM = lengthM
+	p.RadiusM = radiusM
+}
+
+// SetLocation define o centro e os vértices da parcela
+func (p *Plot) SetLocation(center *Coordinate, vertices []Coordinate) {
+	p.Center = center
+	p.Vertices = vertices
+}
+
+// SetVegetationNotes define as observações da vegetação na parcela
+func (p *Plot) SetVegetationNotes(notes *string) {
+	p.VegetationNotes = notes
+}
+
+// SetStratum define o estrato da parcela (nil = sem estrato)
+func (p *Plot) SetStratum(stratumID *string) {
+	p.StratumID = stratumID
+}
+
+// DeriveArea calcula a área pelas dimensões quando não foi informada
+func (p *Plot) DeriveArea() {
+	if p.AreaM2 > 0 {
+		return
+	}
+	switch p.Shape {
+	case ShapeRectangle:
+		if p.WidthM != nil && p.LengthM != nil {
+			p.AreaM2 = *p.WidthM * *p.LengthM
+		}
+	case ShapeCircle:
+		if p.RadiusM != nil {
+			p.AreaM2 = math.Pi * *p.RadiusM * *p.RadiusM
+		}
+	}
+}
+
+// Validate valida se a parcela está em um estado válido
+func (p *Plot) Validate() error {
+	if strings.TrimSpace(p.PhytoAnalysisID) == "" {
+		return errors.New("phyto analysis ID is required")
+	}
+	if strings.TrimSpace(p.Code) == "" {
+		return errors.New("code is required")
+	}
+	switch p.Shape {
+	case ShapeRectangle:
+		if p.WidthM == nil || p.LengthM == nil {
+			return errors.New("rectangle plot requires width and length")
+		}
+	case ShapeCircle:
+		if p.RadiusM == nil {
+			return errors.New("circle plot requires radius")
+		}
+	case ShapeIrregular:
+	default:
+		return errors.New("shape must be rectangle, circle or irregular")
+	}
+	for _, d := range []*float64{p.WidthM, p.LengthM, p.RadiusM} {
+		if d != nil && *d <= 0 {
+			return errors.New("plot dimensions must be positive")
+		}
+	}
+	if p.AreaM2 <= 0 {
+		return errors.New("area must be positive")
+	}
+	if p.Center != nil && !validCoordinate(*p.Center) {
+		return errors.New("invalid center coordinate")
+	}
+	if len(p.Vertices) > 0 && len(p.Vertices) < 3 {
+		return errors.New("plot polygon requires at least 3 vertices")
+	}
+	for _, v := range p.Vertices {
+		if !validCoordinate(v) {
+			return errors.New("invalid vertex coordinate")
+		}
+	}
+	return nil
+}
+
+func validCoordinate(c Coordinate) bool {
+	return c.Latitude >= -90 && c.Latitude <= 90 && c.Longitude >= -180 && c.Longitude <= 180
+}
@@ -6,13 +6,17 @@ import (
 	"time"
 )
 
+// MaxWoodDensity é a maior densidade básica aceita (g/cm³); madeiras nativas não passam de ~1,3
+const MaxWoodDensity = 1.5
+
 // Species representa a entidade de espécie no domínio
 type Species struct {
 	ID             string
 	ScientificName string
 	Family         string
 	PopularName    *string
-	Habit          *string // ARB, ANF, ARV, EME FIX, FLU FIX, FLU LIV, HERB, PAL, TREP
+	Habit          *string  // ARB, ANF, ARV, EME FIX, FLU FIX, FLU LIV, HERB, PAL, TREP
+	WoodDensity    *float64 // Densidade básica da madeira (g/cm³)
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
 	Legislations   []*SpeciesLegislation
@@ -95,6 +99,10 @@ func (s *Species) Validate() error {
 		}
 	}
 
+	if s.WoodDensity != nil && (*s.WoodDensity <= 0 || *s.WoodDensity > MaxWoodDensity) {
+		return errors.New("invalid wood density")
+	}
+
 	return nil
 }
 
@@ -137,6 +145,12 @@ func (s *Species) SetHabit(habit *string) {
 	s.Habit = habit
 }
 
+// SetWoodDensity define a densidade básica da madeira da espécie
+func (s *Species) SetWoodDensity(woodDensity *float64) {
+	s.WoodDensity = woodDensity
+	s.UpdatedAt = time.Now()
+}
+
 // AddLegislation adiciona uma legislação à espécie
 func (s *Species) AddLegislation(legislation *SpeciesLegislation) {
 	s.Legislations = append(s.Legislations, legislation)
@@ -0,0 +1,113 @@
+package species
+
+import (
+	"strings"
+	"unicode"
+)
+
+// qualificadores de identificação ignorados na comparação (ex.: "Ocotea cf. puberula")
+var nameQualifiers = map[string]bool{
+	"cf": true, "cf.": true, "aff": true, "aff.": true,
+}
+
+// categorias infraespecíficas preservadas na comparação, já na forma canônica
+var infraspecificRanks = map[string]string{
+	"subsp.": "subsp.", "subsp": "subsp.", "ssp.": "subsp.", "ssp": "subsp.",
+	"var.": "var.", "var": "var.",
+	"f.": "f.", "fo.": "f.", "forma": "f.",
+}
+
+var diacriticsReplacer = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n", "×", "x",
+)
+
+// NormalizeScientificName reduz o nome científico à forma usada na comparação:
+// minúsculas, sem acentos, sem citação de autor e sem qualificadores (cf., aff.).
+// Ex.: "Cedrela  fissilis Vell." -> "cedrela fissilis".
+func NormalizeScientificName(name string) string {
+	tokens := strings.Fields(diacriticsReplacer.Replace(strings.ToLower(name)))
+	if len(tokens) == 0 {
+		return ""
+	}
+
+	parts := []string{cleanNameToken(tokens[0])}
+	epithetFound := false
+	for i := 1; i < len(tokens); i++ {
+		tok := tokens[i]
+		if nameQualifiers[tok] {
+			continue
+		}
+
+		if !epithetFound {
+			if strings.HasPrefix(tok, "(") {
+				// autor do basiônimo logo após o gênero: não há epíteto
+				break
+			}
+			parts = append(parts, cleanNameToken(tok))
+			epithetFound = true
+			continue
+		}
+
+		// Após o epíteto, apenas categorias infraespecíficas são mantidas; o restante é autoria
+		rank, ok := infraspecificRanks[tok]
+		if !ok || i+1 >= len(tokens) {
+			continue
+		}
+		parts = append(parts, rank, cleanNameToken(tokens[i+1]))
+		i++
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// cleanNameToken remove pontuação das bordas, preservando letras, dígitos e hífen
+func cleanNameToken(tok string) string {
+	return strings.TrimFunc(tok, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-'
+	})
+}
+
+// NameDistance calcula a distância de edição (Levenshtein) entre dois nomes já normalizados
+func NameDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 {
+		return len(rb)
+	}
+	if len(rb) == 0 {
+		return len(ra)
+	}
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+// NameSimilarity retorna a similaridade (0..1) entre dois nomes já normalizados
+func NameSimilarity(a, b string) float64 {
+	maxLen := max(len([]rune(a)), len([]rune(b)))
+	if maxLen == 0 {
+		return 1
+	}
+	return 1 - float64(NameDistance(a, b))/float64(maxLen)
+}
@@ -0,0 +1,39 @@
+package species_test
+
+import (
+	"testing"
+
+	domainspecies "github.com/ESG-Project/suassu-api/internal/domain/species"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeScientificName(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"Cedrela fissilis":                                 "cedrela fissilis",
+		"  Cedrela   fissilis Vell. ":                      "cedrela fissilis",
+		"CEDRELA FISSILIS":                                 "cedrela fissilis",
+		"Handroanthus impetiginosus (Mart. ex DC.) Mattos": "handroanthus impetiginosus",
+		"Ocotea cf. puberula":                              "ocotea puberula",
+		"Inga vera subsp. affinis (DC.) T.D.Penn.":         "inga vera subsp. affinis",
+		"Erythrina crista-galli L.":                        "erythrina crista-galli",
+		"Açoita-cavalo":                                    "acoita-cavalo",
+		"":                                                 "",
+	}
+
+	for in, want := range cases {
+		require.Equal(t, want, domainspecies.NormalizeScientificName(in), in)
+	}
+}
+
+func TestNameDistance(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, 0, domainspecies.NameDistance("cedrela fissilis", "cedrela fissilis"))
+	require.Equal(t, 1, domainspecies.NameDistance("cedrela fisilis", "cedrela fissilis"))
+	require.Equal(t, 2, domainspecies.NameDistance("cedrella fisilis", "cedrela fissilis"))
+	require.Equal(t, 3, domainspecies.NameDistance("", "abc"))
+	require.InDelta(t, 1.0, domainspecies.NameSimilarity("", ""), 0.0001)
+	require.Greater(t, domainspecies.NameSimilarity("cedrela fisilis", "cedrela fissilis"), 0.9)
+}
@@ -10,7 +10,7 @@ import (
 type Specimen struct {
 	ID              string
 	Portion         string
-	Height          float64
+	Height          *float64 // nil quando não medida (estimada pela relação hipsométrica)
 	Cap1            float64
 	Cap2            *float64
 	Cap3            *float64
@@ -19,16 +19,27 @@ type Specimen struct {
 	Cap6            *float64
 	RegisterDate    time.Time
 	PhytoAnalysisID string
-	SpecieID        string
+	SpecieID        string  // vazio quando o indivíduo é de uma morfoespécie
+	MorphospeciesID *string // morfoespécie da análise (nil = espécie do cadastro)
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
+	Latitude        *float64 // WGS84; nil quando não georreferenciado
+	Longitude       *float64
+	Tag             *string // plaqueta: identidade da árvore entre campanhas de remedição
+	// Situação do indivíduo: "alive", "dead_standing", "fallen" ou "resprouting"
+	Status string
+	// Condição fitossanitária: "good", "fair" ou "poor" (nil = não avaliada)
+	Phytosanitary    *string
+	BifurcationNotes *string
+	Observations     *string
 }
 
 // NewSpecimen cria uma nova instância de Specimen
 func NewSpecimen(
 	id string,
 	portion string,
-	height, cap1 float64,
+	height *float64,
+	cap1 float64,
 	registerDate time.Time,
 	phytoAnalysisID, specieID string,
 ) *Specimen {
@@ -41,6 +52,7 @@ func NewSpecimen(
 		RegisterDate:    registerDate,
 		PhytoAnalysisID: phytoAnalysisID,
 		SpecieID:        specieID,
+		Status:          "alive",
 		CreatedAt:       now,
 		UpdatedAt:       now,
 	}
@@ -51,7 +63,7 @@ func (s *Specimen) Validate() error {
 	if strings.TrimSpace(s.Portion) == "" {
 		return errors.New("portion is required")
 	}
-	if s.Height <= 0 {
+	if s.Height != nil && *s.Height <= 0 {
 		return errors.New("height must be positive")
 	}
 	if s.Cap1 <= 0 {
@@ -60,12 +72,32 @@ func (s *Specimen) Validate() error {
 	if strings.TrimSpace(s.PhytoAnalysisID) == "" {
 		return errors.New("phyto analysis ID is required")
 	}
-	if strings.TrimSpace(s.SpecieID) == "" {
+	if strings.TrimSpace(s.SpecieID) == "" && s.MorphospeciesID == nil {
 		return errors.New("specie ID is required")
 	}
+	if strings.TrimSpace(s.SpecieID) != "" && s.MorphospeciesID != nil {
+		return errors.New("specimen must reference either a species or a morphospecies")
+	}
 	if s.RegisterDate.IsZero() {
 		return errors.New("register date is required")
 	}
+	if (s.Latitude == nil) != (s.Longitude == nil) {
+		return errors.New("latitude and longitude must be informed together")
+	}
+	if s.Latitude != nil && (*s.Latitude < -90 || *s.Latitude > 90 || *s.Longitude < -180 || *s.Longitude > 180) {
+		return errors.New("invalid coordinate")
+	}
+	if s.Tag != nil && len(*s.Tag) > 50 {
+		return errors.New("tag must have at most 50 characters")
+	}
+	switch s.Status {
+	case "alive", "dead_standing", "fallen", "resprouting":
+	default:
+		return errors.New("status must be alive, dead_standing, fallen or resprouting")
+	}
+	if s.Phytosanitary != nil && *s.Phytosanitary != "good" && *s.Phytosanitary != "fair" && *s.Phytosanitary != "poor" {
+		return errors.New("phytosanitary condition must be good, fair or poor")
+	}
 	return nil
 }
 
@@ -78,3 +110,46 @@ func (s *Specimen) SetOptionalCaps(cap2, cap3, cap4, cap5, cap6 *float64) {
 	s.Cap6 = cap6
 }
 
+// SetLocation define a localização do indivíduo (latitude e longitude em WGS84)
+func (s *Specimen) SetLocation(latitude, longitude *float64) {
+	s.Latitude = latitude
+	s.Longitude = longitude
+}
+
+// SetMorphospecies associa o indivíduo a uma morfoespécie da análise; nesse caso não
+// há espécie do cadastro (SpecieID vazio)
+func (s *Specimen) SetMorphospecies(morphospeciesID *string) {
+	s.MorphospeciesID = trimmedOrNil(morphospeciesID)
+}
+
+// SetTag define a plaqueta do indivíduo (vazia = não plaqueteado)
+func (s *Specimen) SetTag(tag *string) {
+	s.Tag = trimmedOrNil(tag)
+}
+
+// SetCondition define a situação, a condição fitossanitária e as observações de campo
+// (situação vazia = viva; textos vazios = não informados)
+func (s *Specimen) SetCondition(status string, phytosanitary, bifurcationNotes, observations *string) {
+	s.Status = strings.ToLower(strings.TrimSpace(status))
+	if s.Status == "" {
+		s.Status = "alive"
+	}
+	s.Phytosanitary = trimmedOrNil(phytosanitary)
+	if s.Phytosanitary != nil {
+		v := strings.ToLower(*s.Phytosanitary)
+		s.Phytosanitary = &v
+	}
+	s.BifurcationNotes = trimmedOrNil(bifurcationNotes)
+	s.Observations = trimmedOrNil(observations)
+}
+
+func trimmedOrNil(v *string) *string {
+	if v == nil {
+		return nil
+	}
+	t := strings.TrimSpace(*v)
+	if t == "" {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,53 @@
+package stratum
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// Stratum representa um estrato da área inventariada (ex.: fragmento florestal, área em regeneração).
+// As parcelas indicam o estrato a que pertencem.
+type Stratum struct {
+	ID              string
+	PhytoAnalysisID string
+	Code            string
+	Name            string
+	AreaHa          float64
+	Description     *string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// NewStratum cria uma nova instância de Stratum
+func NewStratum(id, phytoAnalysisID, code, name string, areaHa float64) *Stratum {
+	now := time.Now()
+	return &Stratum{
+		ID:              id,
+		PhytoAnalysisID: phytoAnalysisID,
+		Code:            code,
+		Name:            name,
+		AreaHa:          areaHa,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+}
+
+// SetDescription define a descrição do estrato
+func (s *Stratum) SetDescription(description *string) {
+	s.Description = description
+}
+
+// Validate valida se o estrato está em um estado válido
+func (s *Stratum) Validate() error {
+	if strings.TrimSpace(s.Code) == "" {
+		return errors.New("code is required")
+	}
+	if strings.TrimSpace(s.Name) == "" {
+		return errors.New("name is required")
+	}
+	if s.AreaHa <= 0 {
+		return errors.New("area must be positive")
+	}
+	return nil
+}
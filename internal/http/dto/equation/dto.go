@@ -0,0 +1,32 @@
+package equationdto
+
+import (
+	appequation "github.com/ESG-Project/suassu-api/internal/app/equation"
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+)
+
+// EquationResponse representa uma equação do cadastro (embutida ou cadastrada)
+type EquationResponse = types.EquationData
+
+// EquationRequest representa a criação ou alteração de uma equação.
+// Na alteração o tipo é ignorado: não é possível trocar uma equação de volume por uma de biomassa.
+type EquationRequest struct {
+	Name          string                      `json:"name"`
+	Kind          string                      `json:"kind"` // volume | biomass
+	Expression    string                      `json:"expression"`
+	Coefficients  map[string]float64          `json:"coefficients"`
+	Applicability types.EquationApplicability `json:"applicability"`
+	Reference     *string                     `json:"reference"`
+}
+
+// ToInput converte a requisição para a entrada do serviço
+func (r EquationRequest) ToInput() appequation.Input {
+	return appequation.Input{
+		Name:          r.Name,
+		Kind:          r.Kind,
+		Expression:    r.Expression,
+		Coefficients:  r.Coefficients,
+		Applicability: r.Applicability,
+		Reference:     r.Reference,
+	}
+}
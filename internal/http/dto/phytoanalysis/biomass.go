@@ -0,0 +1,32 @@
+package phytoanalysisdto
+
+import (
+	"github.com/ESG-Project/suassu-api/internal/app/phytometrics"
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+)
+
+// Respostas da biomassa e do estoque de carbono
+type (
+	BiomassResponse        = phytometrics.Biomass
+	SpeciesBiomassResponse = phytometrics.SpeciesBiomass
+)
+
+// BiomassOptions define a equação alométrica e os fatores da estimativa
+type BiomassOptions struct {
+	Equation *phytometrics.CompiledEquation // nil = equação selecionada na análise
+	phytometrics.BiomassOptions
+}
+
+// DefaultBiomassOptions retorna as opções padrão (equação da análise, fatores do IPCC)
+func DefaultBiomassOptions() BiomassOptions {
+	return BiomassOptions{BiomassOptions: phytometrics.DefaultBiomassOptions()}
+}
+
+// ToBiomassResponse estima biomassa e carbono da análise com as opções informadas
+func ToBiomassResponse(p *types.PhytoAnalysisComplete, opts BiomassOptions) *BiomassResponse {
+	eq := opts.Equation
+	if eq == nil {
+		eq = phytometrics.BiomassEquation(p)
+	}
+	return phytometrics.ComputeBiomass(p, opts.BiomassOptions, eq)
+}
@@ -0,0 +1,10 @@
+package phytoanalysisdto
+
+import "time"
+
+// CreateCampaignRequest representa a requisição para criar a remedição das parcelas permanentes
+type CreateCampaignRequest struct {
+	Title       string    `json:"title"`
+	InitialDate time.Time `json:"initialDate"`
+	Description *string   `json:"description,omitempty"`
+}
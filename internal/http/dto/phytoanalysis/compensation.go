@@ -0,0 +1,26 @@
+package phytoanalysisdto
+
+import (
+	"github.com/ESG-Project/suassu-api/internal/app/phytometrics"
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+)
+
+// Regras e resposta do plantio compensatório
+type (
+	CompensationRuleDTO      = types.CompensationRule
+	CompensationResponse     = phytometrics.Compensation
+	CompensationLineResponse = phytometrics.CompensationLine
+)
+
+// CompensationRequest representa a simulação do plantio compensatório com regras próprias
+type CompensationRequest struct {
+	Rules []CompensationRuleDTO `json:"rules"` // avaliadas em ordem; omitido = regras padrão
+}
+
+// ToCompensationResponse calcula as mudas exigidas pela supressão da análise
+func ToCompensationResponse(p *types.PhytoAnalysisComplete, rules []CompensationRuleDTO) *CompensationResponse {
+	if len(rules) == 0 {
+		rules = phytometrics.DefaultCompensationRules()
+	}
+	return phytometrics.ComputeCompensation(p, rules)
+}
@@ -0,0 +1,22 @@
+package phytoanalysisdto
+
+import (
+	"github.com/ESG-Project/suassu-api/internal/app/phytometrics"
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+)
+
+// Opções e resposta das distribuições diamétrica e hipsométrica
+type (
+	ClassDistributionOptions  = phytometrics.ClassDistributionOptions
+	ClassDistributionResponse = phytometrics.ClassDistributions
+)
+
+// DefaultClassDistributionOptions retorna as opções padrão das distribuições
+func DefaultClassDistributionOptions() ClassDistributionOptions {
+	return phytometrics.DefaultClassDistributionOptions()
+}
+
+// ToClassDistributionResponse calcula as distribuições de DAP e altura da análise
+func ToClassDistributionResponse(p *types.PhytoAnalysisComplete, opts ClassDistributionOptions) (*ClassDistributionResponse, error) {
+	return phytometrics.ComputeClassDistributions(p, opts)
+}
@@ -0,0 +1,30 @@
+package phytoanalysisdto
+
+import (
+	"github.com/ESG-Project/suassu-api/internal/app/phytometrics"
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+)
+
+// Padrões das estatísticas de diversidade
+const (
+	DefaultDiversityProbability = phytometrics.DefaultDiversityProbability
+	DefaultBootstrapIterations  = phytometrics.DefaultBootstrapIterations
+	MaxBootstrapIterations      = phytometrics.MaxBootstrapIterations
+)
+
+// Opções e respostas das estatísticas de diversidade
+type (
+	DiversityOptions            = phytometrics.DiversityOptions
+	DiversityResponse           = phytometrics.DiversityStatistics
+	DiversityComparisonResponse = phytometrics.DiversityComparison
+)
+
+// ToDiversityResponse calcula os índices de diversidade com o IC analítico e por bootstrap do H'
+func ToDiversityResponse(p *types.PhytoAnalysisComplete, opts DiversityOptions) *DiversityResponse {
+	return phytometrics.ComputeDiversityStatistics(p, opts)
+}
+
+// ToDiversityComparisonResponse compara o H' de duas análises pelo teste t de Hutcheson
+func ToDiversityComparisonResponse(first, second *types.PhytoAnalysisComplete, probability float64) *DiversityComparisonResponse {
+	return phytometrics.CompareDiversity(first, second, probability)
+}
@@ -0,0 +1,12 @@
+// Package phytoanalysisdto define os formatos HTTP da análise fitossociológica.
+//
+// As respostas analíticas (indicadores, suficiência amostral, diversidade, riqueza, distribuições,
+// biomassa, hipsometria, supressão, compensação, perfil ecológico, similaridade e estratificação)
+// são os próprios tipos do motor de métricas (app/phytometrics), expostos aqui por aliases: as tags
+// JSON do motor são o contrato da API, e alterar um campo no motor altera a resposta.
+//
+// Só os indicadores são servidos do snapshot gravado. As demais análises (/sampling, /diversity,
+// /species-accumulation, /distributions, /biomass e os relatórios) dependem de parâmetros da
+// consulta e são recalculadas a cada requisição a partir dos espécimes; servi-las do snapshot está
+// fora do escopo atual.
+package phytoanalysisdto
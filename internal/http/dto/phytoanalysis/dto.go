@@ -1,27 +1,37 @@
 package phytoanalysisdto
 
 import (
-	"math"
 	"time"
 
+	"github.com/ESG-Project/suassu-api/internal/app/phytometrics"
 	"github.com/ESG-Project/suassu-api/internal/app/types"
 )
 
 // CreatePhytoAnalysisRequest representa a requisição para criar uma análise fitossociológica
 type CreatePhytoAnalysisRequest struct {
-	Title           string          `json:"title"`
-	InitialDate     time.Time       `json:"initialDate"`
-	PortionQuantity int             `json:"portionQuantity"`
-	PortionArea     float64         `json:"portionArea"`
-	TotalArea       float64         `json:"totalArea"`
-	Description     *string         `json:"description,omitempty"`
-	ProjectID       string          `json:"projectId"`
-	Specimens       []SpecimenInput `json:"specimens,omitempty"`
+	Title           string    `json:"title"`
+	InitialDate     time.Time `json:"initialDate"`
+	PortionQuantity int       `json:"portionQuantity"`
+	PortionArea     float64   `json:"portionArea"`
+	TotalArea       float64   `json:"totalArea"`
+	Description     *string   `json:"description,omitempty"`
+	ProjectID       string    `json:"projectId"`
+	// Fator de forma para espécies sem legislação aplicável (omitido = cilíndrico, 1.0)
+	DefaultFormFactor *float64 `json:"defaultFormFactor,omitempty"`
+	// Protocolo de medição: as medidas dos fustes (cap1..cap6) seguem o modo e a unidade informados
+	Measurement MeasurementProtocolDTO `json:"measurement"`
+	// Parcelas (opcional): quando informadas, portionQuantity e portionArea são calculados a partir delas
+	Plots     []PlotRequest   `json:"plots,omitempty"`
+	Specimens []SpecimenInput `json:"specimens,omitempty"`
+	// Nome científico (como informado) -> speciesID, para resolver nomes não encontrados
+	SpeciesMapping map[string]string `json:"speciesMapping,omitempty"`
+	// Morfoespécies declaradas para nomes sem determinação (nomes não declarados são detectados pelo padrão "Gênero sp. 1", "Indet. 2")
+	Morphospecies []MorphospeciesRequest `json:"morphospecies,omitempty"`
 }
 
 type SpecimenInput struct {
 	Portion      string    `json:"portion"`
-	Height       float64   `json:"height"`
+	Height       *float64  `json:"height,omitempty"` // omitida = não medida (estimada pela relação hipsométrica)
 	Cap1         float64   `json:"cap1"`
 	Cap2         *float64  `json:"cap2,omitempty"`
 	Cap3         *float64  `json:"cap3,omitempty"`
@@ -29,6 +39,14 @@ type SpecimenInput struct {
 	Cap5         *float64  `json:"cap5,omitempty"`
 	Cap6         *float64  `json:"cap6,omitempty"`
 	RegisterDate time.Time `json:"registerDate"`
+	Latitude     *float64  `json:"latitude,omitempty"` // localização do indivíduo (WGS84, graus decimais)
+	Longitude    *float64  `json:"longitude,omitempty"`
+	Tag          *string   `json:"tag,omitempty"` // plaqueta da árvore, identidade entre campanhas
+	// Situação do indivíduo: alive (padrão), dead_standing, fallen ou resprouting
+	Status           string  `json:"status,omitempty"`
+	Phytosanitary    *string `json:"phytosanitary,omitempty"` // good, fair ou poor
+	BifurcationNotes *string `json:"bifurcationNotes,omitempty"`
+	Observations     *string `json:"observations,omitempty"`
 	// Nome científico da espécie (obrigatório)
 	ScientificName string `json:"scientificName"`
 }
@@ -41,6 +59,10 @@ type UpdatePhytoAnalysisRequest struct {
 	PortionArea     float64   `json:"portionArea"`
 	TotalArea       float64   `json:"totalArea"`
 	Description     *string   `json:"description,omitempty"`
+	// Fator de forma para espécies sem legislação aplicável (omitido = cilíndrico, 1.0)
+	DefaultFormFactor *float64 `json:"defaultFormFactor,omitempty"`
+	// Protocolo de medição das próximas importações (omitido = CAP em cm, sem critério)
+	Measurement MeasurementProtocolDTO `json:"measurement"`
 }
 
 // PhytoAnalysisResponse representa a resposta de uma análise fitossociológica
@@ -53,6 +75,12 @@ type PhytoAnalysisResponse struct {
 	TotalArea       float64   `json:"totalArea"`
 	SampledArea     float64   `json:"sampledArea"`
 
+	DefaultFormFactor *float64 `json:"defaultFormFactor,omitempty"` // Fator de forma padrão da análise
+
+	Measurement *MeasurementProtocolDTO `json:"measurement,omitempty"` // Protocolo de medição e critério de inclusão
+
+	PreviousAnalysisID *string `json:"previousAnalysisId,omitempty"` // Campanha anterior remedida (parcelas permanentes)
+
 	Description *string   `json:"description,omitempty"`
 	ProjectID   string    `json:"projectId"`
 	CreatedAt   time.Time `json:"createdAt"`
@@ -73,8 +101,25 @@ type PhytoAnalysisResponse struct {
 	VolumePerHa    float64 `json:"volumePerHa"`    // Volume (m³/ha)
 	BasalAreaPerHa float64 `json:"basalAreaPerHa"` // Área basal (m²/ha)
 
+	// Volumes cilíndricos (G × H, sem fator de forma); os volumes acima já aplicam o fator de forma
+	CylindricalVolumeTotalM3 float64 `json:"cylindricalVolumeTotalM3"` // Volume cilíndrico total (m³)
+	CylindricalVolumePerHa   float64 `json:"cylindricalVolumePerHa"`   // Volume cilíndrico (m³/ha)
+
 	// Indicadores fitossociológicos (do SUASSU-284)
-	Indicators *PhytosociologicalIndicators `json:"indicators,omitempty"`
+	Indicators         *PhytosociologicalIndicators `json:"indicators,omitempty"`
+	IndicatorsSnapshot *IndicatorSnapshotInfo       `json:"indicatorsSnapshot,omitempty"` // Versão dos indicadores servidos
+
+	// Biomassa e estoque de carbono (equação selecionada e fatores padrão do motor)
+	Biomass *BiomassResponse `json:"biomass,omitempty"`
+
+	// Equações de volume e biomassa usadas nos resultados
+	Equations *EquationsResponse `json:"equations,omitempty"`
+
+	// Relação hipsométrica usada para estimar as alturas não medidas
+	Hypsometry *HypsometryResponse `json:"hypsometry,omitempty"`
+
+	// Perfil ecológico: grupo sucessional, origem e hábito, com espécies ameaçadas e protegidas
+	Ecology *EcologyResponse `json:"ecology,omitempty"`
 }
 
 type ProjectInfo struct {
@@ -99,388 +144,61 @@ type ProjectAddress struct {
 }
 
 type SpecimenResponse struct {
-	ID             string    `json:"id"`
-	Portion        string    `json:"portion"`
-	Height         float64   `json:"height"`
-	Cap1           float64   `json:"cap1"`
-	Cap2           *float64  `json:"cap2,omitempty"`
-	Cap3           *float64  `json:"cap3,omitempty"`
-	Cap4           *float64  `json:"cap4,omitempty"`
-	Cap5           *float64  `json:"cap5,omitempty"`
-	Cap6           *float64  `json:"cap6,omitempty"`
-	RegisterDate   time.Time `json:"registerDate"`
-	SpecieID       string    `json:"specieId"`
-	ScientificName string    `json:"scientificName"`
-	Family         string    `json:"family"`
-	PopularName    *string   `json:"popularName,omitempty"`
-	VolumeM3       float64   `json:"volumeM3"`    // volume individual (m³)
-	DbhCm          float64   `json:"dbhCm"`       // DAP individual (cm)
-	BasalAreaM2    float64   `json:"basalAreaM2"` // área basal individual (m²)
-	StdDevDbhCm    float64   `json:"stdDevDbhCm"` // desvio padrão do DAP da espécie (cm)
-}
-
-// SpeciesPhytosociologicalData representa dados fitossociológicos por espécie
-type SpeciesPhytosociologicalData struct {
-	ScientificName string  `json:"scientificName"`
-	DA             float64 `json:"da"` // Densidade Absoluta (ind/ha)
-	DR             float64 `json:"dr"` // Densidade Relativa (%)
-	FA             float64 `json:"fa"` // Frequência Absoluta (%)
-}
-
-// CollectorCurvePoint representa um ponto da curva coletor
-type CollectorCurvePoint struct {
-	CumulativeArea  float64 `json:"cumulativeArea"`  // Área acumulada (m²)
-	ObservedSpecies int     `json:"observedSpecies"` // Número acumulado de espécies observadas
-	TrendSpecies    float64 `json:"trendSpecies"`    // Número acumulado de espécies (tendência)
-}
-
-// CollectorCurveData representa os dados da curva coletor
-type CollectorCurveData struct {
-	Points []CollectorCurvePoint `json:"points"` // Pontos da curva
+	ID               string    `json:"id"`
+	Portion          string    `json:"portion"`
+	Height           float64   `json:"height"`          // altura medida ou estimada (0 = sem altura)
+	HeightEstimated  bool      `json:"heightEstimated"` // altura estimada pela relação hipsométrica
+	Cap1             float64   `json:"cap1"`
+	Cap2             *float64  `json:"cap2,omitempty"`
+	Cap3             *float64  `json:"cap3,omitempty"`
+	Cap4             *float64  `json:"cap4,omitempty"`
+	Cap5             *float64  `json:"cap5,omitempty"`
+	Cap6             *float64  `json:"cap6,omitempty"`
+	RegisterDate     time.Time `json:"registerDate"`
+	SpecieID         string    `json:"specieId"`
+	MorphospeciesID  *string   `json:"morphospeciesId,omitempty"`
+	ScientificName   string    `json:"scientificName"`
+	Family           string    `json:"family"`
+	PopularName      *string   `json:"popularName,omitempty"`
+	Latitude         *float64  `json:"latitude,omitempty"`
+	Longitude        *float64  `json:"longitude,omitempty"`
+	Tag              *string   `json:"tag,omitempty"`
+	Status           string    `json:"status"`
+	Phytosanitary    *string   `json:"phytosanitary,omitempty"`
+	BifurcationNotes *string   `json:"bifurcationNotes,omitempty"`
+	Observations     *string   `json:"observations,omitempty"`
+	VolumeM3         float64   `json:"volumeM3"`              // volume individual pela equação da análise (m³)
+	CylVolumeM3      float64   `json:"cylindricalVolumeM3"`   // volume individual cilíndrico (m³)
+	FormFactor       float64   `json:"formFactor"`            // fator de forma aplicado
+	DbhCm            float64   `json:"dbhCm"`                 // DAP individual (cm)
+	BasalAreaM2      float64   `json:"basalAreaM2"`           // área basal individual (m²)
+	StdDevDbhCm      float64   `json:"stdDevDbhCm,omitempty"` // desvio padrão do DAP da espécie (cm), apenas no detalhe da análise
+
+	VolumeEquationID string `json:"volumeEquationId"` // equação usada no volume individual
 }
 
-// PhytosociologicalIndicators representa os indicadores calculados da análise
-type PhytosociologicalIndicators struct {
-	// Campos básicos (já existentes)
-	IndividualsCount int     `json:"individualsCount"` // Número de indivíduos
-	SpeciesCount     int     `json:"speciesCount"`     // Número de espécies
-	PlotsCount       int     `json:"plotsCount"`       // Número de parcelas
-	PlotsArea        float64 `json:"plotsArea"`        // Área de parcelas (m²)
-
-	// Campos calculados
-	Density              *float64 `json:"density,omitempty"`              // Densidade Geral (ind/ha)
-	BasalArea            *float64 `json:"basalArea,omitempty"`            // Área basal (m²/ha)
-	Volume               *float64 `json:"volume,omitempty"`               // Volume (m³/ha)
-	ReplacementVolume    *float64 `json:"replacementVolume,omitempty"`    // Volume de reposição (m³)
-	ReplacementVolumeMst *float64 `json:"replacementVolumeMst,omitempty"` // Volume de reposição (mst)
-	SampledAreaHa        *float64 `json:"sampledAreaHa,omitempty"`        // Área amostrada (ha)
-	ShannonIndex         *float64 `json:"shannonIndex,omitempty"`         // Índice de Shannon (H')
-	SimpsonIndex         *float64 `json:"simpsonIndex,omitempty"`         // Índice de Simpson (D)
-	PielouEvennessIndex  *float64 `json:"pielouEvennessIndex,omitempty"`  // Índice de Equabilidade de Pielou (J')
-
-	// Dados para gráficos
-	SpeciesData    []SpeciesPhytosociologicalData `json:"speciesData,omitempty"`    // DA, DR, FA por espécie
-	CollectorCurve *CollectorCurveData            `json:"collectorCurve,omitempty"` // Dados da curva coletor
-}
-
-const (
-	pi             = 3.14159265358979323846
-	stackingFactor = 0.7
+// Respostas dos indicadores fitossociológicos
+type (
+	PhytosociologicalIndicators  = phytometrics.Indicators
+	SpeciesPhytosociologicalData = phytometrics.SpeciesStructure
+	FamilyPhytosociologicalData  = phytometrics.FamilyStructure
+	CollectorCurveData           = phytometrics.CollectorCurve
+	CollectorCurvePoint          = phytometrics.CollectorCurvePoint
 )
 
-// calculateABI calcula a Área Basal Individual (cm²)
-// ABI = (CAP1²)/(4π) + (CAP2²)/(4π) + ... + (CAP6²)/(4π)
-func calculateABI(cap1 float64, cap2, cap3, cap4, cap5, cap6 *float64) float64 {
-	abi := (cap1 * cap1) / (4 * pi)
-
-	if cap2 != nil {
-		abi += (*cap2 * *cap2) / (4 * pi)
-	}
-	if cap3 != nil {
-		abi += (*cap3 * *cap3) / (4 * pi)
-	}
-	if cap4 != nil {
-		abi += (*cap4 * *cap4) / (4 * pi)
-	}
-	if cap5 != nil {
-		abi += (*cap5 * *cap5) / (4 * pi)
-	}
-	if cap6 != nil {
-		abi += (*cap6 * *cap6) / (4 * pi)
-	}
-
-	return abi
-}
-
-// calculateBasalArea calcula a Área Basal (G) em m²
-// G(m²) = ABI / 10,000
-func calculateBasalArea(abi float64) float64 {
-	return abi / 10000.0
-}
-
-// calculateVolume calcula o Volume em m³
-// Vol = G(m²) × Height
-func calculateVolume(basalArea, height float64) float64 {
-	return basalArea * height
-}
-
-// calculateCollectorCurve calcula os dados da curva coletor
-// Retorna pontos com área acumulada e número acumulado de espécies
-// Os specimens já vêm ordenados por parcela e data do banco de dados
-func calculateCollectorCurve(specimens []*types.SpecimenWithSpecies, portionArea float64) *CollectorCurveData {
-	if len(specimens) == 0 {
-		return nil
-	}
-
-	// Processar specimens na ordem que vêm (já ordenados por parcela e data)
-	// Agrupar por parcela mantendo a ordem
-	type plotData struct {
-		plot    string
-		species map[string]bool
-	}
-
-	plotList := make([]plotData, 0)
-	plotMap := make(map[string]int) // parcela -> índice na lista
-
-	for _, s := range specimens {
-		if s.ScientificName == "" {
-			continue
-		}
-
-		idx, exists := plotMap[s.Portion]
-		if !exists {
-			// Nova parcela
-			idx = len(plotList)
-			plotList = append(plotList, plotData{
-				plot:    s.Portion,
-				species: make(map[string]bool),
-			})
-			plotMap[s.Portion] = idx
-		}
-
-		// Adicionar espécie à parcela
-		plotList[idx].species[s.ScientificName] = true
-	}
-
-	// Calcular pontos da curva
-	points := make([]CollectorCurvePoint, 0, len(plotList)+1)
-	cumulativeArea := 0.0
-	observedSpeciesSet := make(map[string]bool)
-
-	// Adicionar ponto inicial (0, 0)
-	points = append(points, CollectorCurvePoint{
-		CumulativeArea:  0,
-		ObservedSpecies: 0,
-		TrendSpecies:    0,
-	})
-
-	// Para cada parcela na ordem
-	for _, plotInfo := range plotList {
-		// Adicionar área da parcela
-		cumulativeArea += portionArea
-
-		// Adicionar espécies encontradas nesta parcela ao conjunto total
-		for species := range plotInfo.species {
-			observedSpeciesSet[species] = true
-		}
-
-		// Adicionar ponto após processar esta parcela
-		points = append(points, CollectorCurvePoint{
-			CumulativeArea:  cumulativeArea,
-			ObservedSpecies: len(observedSpeciesSet),
-			TrendSpecies:    0, // Será calculado depois
-		})
-	}
-
-	// Calcular curva de tendência usando regressão linear simples
-	// y = a + b*x, onde y é número de espécies e x é área acumulada
-	if len(points) > 1 {
-		// Calcular coeficientes da regressão linear
-		n := float64(len(points))
-		var sumX, sumY, sumXY, sumX2 float64
-
-		for _, p := range points {
-			x := p.CumulativeArea
-			y := float64(p.ObservedSpecies)
-			sumX += x
-			sumY += y
-			sumXY += x * y
-			sumX2 += x * x
-		}
-
-		// Calcular coeficientes: b = (n*ΣXY - ΣX*ΣY) / (n*ΣX² - (ΣX)²)
-		denominator := n*sumX2 - sumX*sumX
-		var b, a float64
-
-		if denominator != 0 {
-			b = (n*sumXY - sumX*sumY) / denominator
-			a = (sumY - b*sumX) / n
-		}
-
-		// Aplicar tendência aos pontos
-		for i := range points {
-			points[i].TrendSpecies = a + b*points[i].CumulativeArea
-			// Garantir que a tendência não seja negativa
-			if points[i].TrendSpecies < 0 {
-				points[i].TrendSpecies = 0
-			}
-		}
-	}
-
-	return &CollectorCurveData{
-		Points: points,
-	}
-}
-
-// calculatePhytosociologicalIndicators calcula todos os indicadores fitossociológicos
-func calculatePhytosociologicalIndicators(p *types.PhytoAnalysisComplete) *PhytosociologicalIndicators {
-	if len(p.Specimens) == 0 {
-		return &PhytosociologicalIndicators{
-			IndividualsCount: 0,
-			SpeciesCount:     0,
-			PlotsCount:       p.PortionQuantity,
-			PlotsArea:        p.PortionArea * float64(p.PortionQuantity),
-		}
-	}
-
-	// Constantes
-	N := len(p.Specimens) // Número total de indivíduos
-
-	// Contar espécies únicas e indivíduos por espécie
-	uniqueSpecies := make(map[string]bool)
-	speciesCount := make(map[string]int)             // n_i: número de indivíduos por espécie
-	speciesPlots := make(map[string]map[string]bool) // parcelas onde cada espécie ocorre
-
-	for _, s := range p.Specimens {
-		if s.ScientificName != "" {
-			uniqueSpecies[s.ScientificName] = true
-			speciesCount[s.ScientificName]++
-
-			// Registrar parcela onde a espécie ocorre
-			if speciesPlots[s.ScientificName] == nil {
-				speciesPlots[s.ScientificName] = make(map[string]bool)
-			}
-			speciesPlots[s.ScientificName][s.Portion] = true
-		}
-	}
-
-	S := len(uniqueSpecies) // Número de espécies
-	P := p.PortionQuantity  // Número de parcelas
-
-	// Calcular área de parcelas (m²)
-	plotsArea := p.PortionArea * float64(P)
-
-	// Calcular área amostrada em hectares
-	sampledAreaHa := plotsArea / 10000.0
-
-	// Calcular densidade (ind/ha)
-	var density *float64
-	if sampledAreaHa > 0 {
-		d := float64(N) / sampledAreaHa
-		density = &d
-	}
-
-	// Calcular área basal total e volume total
-	var totalBasalArea float64 // em m²
-	var totalVolume float64    // em m³
-
-	for _, s := range p.Specimens {
-		abi := calculateABI(s.Cap1, s.Cap2, s.Cap3, s.Cap4, s.Cap5, s.Cap6)
-		g := calculateBasalArea(abi)
-		vol := calculateVolume(g, s.Height)
-
-		totalBasalArea += g
-		totalVolume += vol
-	}
-
-	// Calcular área basal por hectare (m²/ha)
-	var basalArea *float64
-	if sampledAreaHa > 0 {
-		ba := totalBasalArea / sampledAreaHa
-		basalArea = &ba
-	}
-
-	// Calcular volume por hectare (m³/ha)
-	var volume *float64
-	if sampledAreaHa > 0 {
-		v := totalVolume / sampledAreaHa
-		volume = &v
-	}
-
-	// Volume de reposição (m³) - valor agregado, não dividido por ha
-	replacementVolume := totalVolume
-
-	var replacementVolumeMst *float64
-	if stackingFactor > 0 {
-		mst := replacementVolume / stackingFactor
-		replacementVolumeMst = &mst
-	}
-
-	// Calcular índices de diversidade
-	var shannonIndex *float64
-	var simpsonIndex *float64
-	var pielouIndex *float64
-
-	if S > 0 && N > 0 {
-		// Índice de Shannon: H' = -Σ(p_i × ln(p_i))
-		var shannon float64
-		// Índice de Simpson: D = Σ(p_i²)
-		var simpson float64
-
-		for _, n_i := range speciesCount {
-			p_i := float64(n_i) / float64(N)
-			if p_i > 0 {
-				shannon -= p_i * math.Log(p_i)
-				simpson += p_i * p_i
-			}
-		}
-
-		shannonIndex = &shannon
-		simpsonIndex = &simpson
-
-		// Índice de Pielou: J' = H' / ln(S)
-		if S > 1 {
-			lnS := math.Log(float64(S))
-			if lnS > 0 {
-				pielou := shannon / lnS
-				pielouIndex = &pielou
-			}
-		}
-	}
-
-	// Calcular dados para gráficos (DA, DR, FA por espécie)
-	speciesData := make([]SpeciesPhytosociologicalData, 0, S)
-
-	for speciesName := range uniqueSpecies {
-		n_i := speciesCount[speciesName]
-		p_i := len(speciesPlots[speciesName]) // número de parcelas onde a espécie ocorre
-
-		// DA_i = n_i / Área_amostrada_ha
-		var da float64
-		if sampledAreaHa > 0 {
-			da = float64(n_i) / sampledAreaHa
-		}
-
-		// DR_i = (n_i / N) × 100
-		var dr float64
-		if N > 0 {
-			dr = (float64(n_i) / float64(N)) * 100.0
-		}
-
-		// FA_i = (p_i / P) × 100
-		var fa float64
-		if P > 0 {
-			fa = (float64(p_i) / float64(P)) * 100.0
-		}
-
-		speciesData = append(speciesData, SpeciesPhytosociologicalData{
-			ScientificName: speciesName,
-			DA:             da,
-			DR:             dr,
-			FA:             fa,
-		})
-	}
-
-	// Calcular curva coletor
-	collectorCurve := calculateCollectorCurve(p.Specimens, p.PortionArea)
-
-	return &PhytosociologicalIndicators{
-		IndividualsCount:     N,
-		SpeciesCount:         S,
-		PlotsCount:           P,
-		PlotsArea:            plotsArea,
-		Density:              density,
-		BasalArea:            basalArea,
-		Volume:               volume,
-		ReplacementVolume:    &replacementVolume,
-		ReplacementVolumeMst: replacementVolumeMst,
-		SampledAreaHa:        &sampledAreaHa,
-		ShannonIndex:         shannonIndex,
-		SimpsonIndex:         simpsonIndex,
-		PielouEvennessIndex:  pielouIndex,
-		SpeciesData:          speciesData,
-		CollectorCurve:       collectorCurve,
-	}
+// SpecimenImportReportResponse representa o resultado da validação (dry-run) de uma importação
+type SpecimenImportReportResponse struct {
+	Valid          bool                       `json:"valid"`                    // true quando a importação seria aceita
+	TotalRows      int                        `json:"totalRows"`                // Linhas preenchidas
+	ValidRows      int                        `json:"validRows"`                // Linhas aprovadas em todas as etapas
+	AnalysisErrors []string                   `json:"analysisErrors,omitempty"` // Erros nos dados da análise
+	InvalidRows    []types.InvalidSpecimenRow `json:"invalidRows"`              // Erros por linha (número da linha de origem)
+	FlaggedRows    []types.FlaggedSpecimenRow `json:"flaggedRows"`              // Linhas aceitas com fustes abaixo do critério de inclusão
+	// Morfoespécies que a importação cadastra
+	NewMorphospecies []string `json:"newMorphospecies,omitempty"`
+
+	// Prévia dos indicadores considerando apenas as linhas válidas
+	Indicators *PhytosociologicalIndicators `json:"indicators,omitempty"`
 }
 
 // ToPhytoAnalysisResponse converte tipos internos para resposta HTTP
@@ -507,212 +225,76 @@ func ToPhytoAnalysisResponse(p *types.PhytoAnalysisWithProject) *PhytoAnalysisRe
 	}
 }
 
-// calcula ABI em cm² a partir dos CAPs do SpecimenWithSpecies
-func calcABIFromSpecimen(s *types.SpecimenWithSpecies) float64 {
-	caps := []float64{s.Cap1}
-
-	if s.Cap2 != nil {
-		caps = append(caps, *s.Cap2)
-	}
-	if s.Cap3 != nil {
-		caps = append(caps, *s.Cap3)
-	}
-	if s.Cap4 != nil {
-		caps = append(caps, *s.Cap4)
-	}
-	if s.Cap5 != nil {
-		caps = append(caps, *s.Cap5)
-	}
-	if s.Cap6 != nil {
-		caps = append(caps, *s.Cap6)
-	}
-
-	abi := 0.0
-	for _, cap := range caps {
-		// CR11.1: ABI = Σ (CAPi² / 4π)
-		abi += (cap * cap) / (4 * math.Pi)
-	}
-	return abi
+// ToSpecimenResponse converte um espécime da listagem, com as medidas pelo modelo de volume da análise
+func ToSpecimenResponse(s *types.SpecimenWithSpecies, model *phytometrics.VolumeModel) SpecimenResponse {
+	return toSpecimenResponse(s, model.Specimen(s))
 }
 
-// volume em m³ a partir de ABI (cm²) e altura (m)
-func calcVolumeFromABI(abiCm2, heightM float64) float64 {
-	if abiCm2 <= 0 || heightM <= 0 {
-		return 0
+func toSpecimenResponse(s *types.SpecimenWithSpecies, m phytometrics.SpecimenMetrics) SpecimenResponse {
+	return SpecimenResponse{
+		ID:               s.ID,
+		Portion:          s.Portion,
+		Height:           s.Height,
+		HeightEstimated:  s.HeightEstimated,
+		Cap1:             s.Cap1,
+		Cap2:             s.Cap2,
+		Cap3:             s.Cap3,
+		Cap4:             s.Cap4,
+		Cap5:             s.Cap5,
+		Cap6:             s.Cap6,
+		RegisterDate:     s.RegisterDate,
+		SpecieID:         s.SpecieID,
+		MorphospeciesID:  s.MorphospeciesID,
+		ScientificName:   s.ScientificName,
+		Family:           s.Family,
+		PopularName:      s.PopularName,
+		Latitude:         s.Latitude,
+		Longitude:        s.Longitude,
+		Tag:              s.Tag,
+		Status:           s.Status,
+		Phytosanitary:    s.Phytosanitary,
+		BifurcationNotes: s.BifurcationNotes,
+		Observations:     s.Observations,
+		VolumeM3:         m.VolumeM3,
+		CylVolumeM3:      m.CylindricalVolumeM3,
+		FormFactor:       m.FormFactor,
+		DbhCm:            m.DbhCm,
+		BasalAreaM2:      m.BasalAreaM2,
+		StdDevDbhCm:      m.StdDevDbhCm,
+
+		VolumeEquationID: m.VolumeEquationID,
 	}
-	// CR11.4: G(m²) = ABI / 10.000
-	g := abiCm2 / 10000.0
-	// CR11.5: Volume = G(m²) × Height(m)
-	return g * heightM
 }
 
-// DAP (cm) e área basal (m²) a partir da ABI em cm²
-func calcDbhAndBasalFromABI(abiCm2 float64) (dbhCm, basalM2 float64) {
-	if abiCm2 <= 0 {
-		return 0, 0
-	}
-	// CR11.2 – CAP_mean = √( ABI × 4π )
-	capMean := math.Sqrt(abiCm2 * 4 * math.Pi) // cm
-	// CR11.3 – DBH(cm) = CAP_mean / π
-	dbhCm = capMean / math.Pi // cm
-	// CR11.4 – G(m²) = ABI / 10.000
-	basalM2 = abiCm2 / 10000.0 // m²
-	return dbhCm, basalM2
-}
+// ToPhytoAnalysisCompleteResponse converte análise completa para resposta HTTP.
+// As métricas agregadas e os indicadores vêm do snapshot; sem snapshot, são calculados na hora.
+func ToPhytoAnalysisCompleteResponse(p *types.PhytoAnalysisComplete, snapshot *phytometrics.Snapshot) *PhytoAnalysisResponse {
+	metrics := phytometrics.ComputeSpecimens(p)
 
-// ToPhytoAnalysisCompleteResponse converte análise completa para resposta HTTP
-func ToPhytoAnalysisCompleteResponse(p *types.PhytoAnalysisComplete) *PhytoAnalysisResponse {
 	specimens := make([]SpecimenResponse, 0, len(p.Specimens))
-	uniqueSpecies := make(map[string]bool) // Para contar espécies únicas
-
-	var (
-		sumDbhCm  float64 // soma dos DAPs (cm)
-		sumHeight float64 // soma das alturas (m)
-		sumBasal  float64 // soma das áreas basais (m²)
-		sumVolume float64 // soma dos volumes individuais (m³)
-	)
-
-	// Estrutura auxiliar para guardar métricas por espécime
-	type specMetrics struct {
-		s        *types.SpecimenWithSpecies
-		abiCm2   float64
-		dbhCm    float64
-		basalM2  float64
-		volumeM3 float64
-	}
-
-	metrics := make([]specMetrics, 0, len(p.Specimens))
-	dapBySpecies := make(map[string][]float64)
-
-	// Primeira passada: calcula métricas individuais e acumula para agregados
-	for _, s := range p.Specimens {
-		abi := calcABIFromSpecimen(s) // cm²
-
-		var (
-			dbhCm    float64
-			basalM2  float64
-			volumeM3 float64
-		)
-
-		if abi > 0 {
-			dbhCm, basalM2 = calcDbhAndBasalFromABI(abi)
-			volumeM3 = calcVolumeFromABI(abi, s.Height)
-
-			sumDbhCm += dbhCm
-			sumBasal += basalM2
-			sumVolume += volumeM3
-		}
-
-		metrics = append(metrics, specMetrics{
-			s:        s,
-			abiCm2:   abi,
-			dbhCm:    dbhCm,
-			basalM2:  basalM2,
-			volumeM3: volumeM3,
-		})
-
-		// chave para agrupar por espécie (preferência por nome científico)
-		key := s.ScientificName
-		if key == "" {
-			key = s.SpecieID
-		}
-		if key != "" && dbhCm > 0 {
-			dapBySpecies[key] = append(dapBySpecies[key], dbhCm)
-		}
-
-		// mapa de espécies únicas
-		if s.ScientificName != "" {
-			uniqueSpecies[s.ScientificName] = true
-		}
-
-		// alturas para média
-		if s.Height > 0 {
-			sumHeight += s.Height
+	for i, s := range p.Specimens {
+		specimens = append(specimens, toSpecimenResponse(s, metrics[i]))
+	}
+
+	var result *phytometrics.Result
+	var snapshotInfo *IndicatorSnapshotInfo
+	if snapshot != nil && snapshot.Result != nil {
+		result = snapshot.Result
+		snapshotInfo = ToIndicatorSnapshotInfo(snapshot)
+	} else {
+		// os espécimes listados incluem os mortos; os agregados seguem o protocolo da análise
+		live := phytometrics.WithoutDead(p)
+		liveMetrics := phytometrics.ComputeSpecimens(live)
+		result = &phytometrics.Result{
+			Summary:    phytometrics.ComputeSummary(live, liveMetrics),
+			Indicators: phytometrics.ComputeIndicators(live),
+			Biomass:    phytometrics.ComputeDefaultBiomass(live),
+			Equations:  phytometrics.UsedEquations(live),
+			Hypsometry: phytometrics.EstimateHeights(live),
+			Ecology:    phytometrics.ComputeEcology(live, liveMetrics),
 		}
 	}
-
-	// Segunda passada: desvio padrão do DAP por espécie
-	stdDevBySpecies := make(map[string]float64)
-	for key, daps := range dapBySpecies {
-		if len(daps) <= 1 {
-			stdDevBySpecies[key] = 0
-			continue
-		}
-
-		var sum float64
-		for _, v := range daps {
-			sum += v
-		}
-		mean := sum / float64(len(daps))
-
-		var sq float64
-		for _, v := range daps {
-			diff := v - mean
-			sq += diff * diff
-		}
-
-		// desvio padrão amostral: / (n-1)
-		stdDevBySpecies[key] = math.Sqrt(sq / float64(len(daps)-1))
-	}
-
-	// Monta SpecimenResponse com DAP, área basal, volume e desvio padrão
-	for _, m := range metrics {
-		s := m.s
-
-		key := s.ScientificName
-		if key == "" {
-			key = s.SpecieID
-		}
-		stdDev := stdDevBySpecies[key]
-
-		specimens = append(specimens, SpecimenResponse{
-			ID:             s.ID,
-			Portion:        s.Portion,
-			Height:         s.Height,
-			Cap1:           s.Cap1,
-			Cap2:           s.Cap2,
-			Cap3:           s.Cap3,
-			Cap4:           s.Cap4,
-			Cap5:           s.Cap5,
-			Cap6:           s.Cap6,
-			RegisterDate:   s.RegisterDate,
-			SpecieID:       s.SpecieID,
-			ScientificName: s.ScientificName,
-			Family:         s.Family,
-			PopularName:    s.PopularName,
-			VolumeM3:       m.volumeM3,
-			DbhCm:          m.dbhCm,
-			BasalAreaM2:    m.basalM2,
-			StdDevDbhCm:    stdDev,
-		})
-	}
-
-	n := len(p.Specimens)
-	sampledAreaHa := p.SampledArea
-
-	var (
-		meanDbhCm    float64
-		meanHeightM  float64
-		densityIndHa float64
-		volumePerHa  float64
-		basalPerHa   float64
-	)
-
-	if n > 0 {
-		meanDbhCm = sumDbhCm / float64(n)
-		meanHeightM = sumHeight / float64(n)
-	}
-
-	if sampledAreaHa > 0 {
-		densityIndHa = float64(n) / sampledAreaHa
-		volumePerHa = sumVolume / sampledAreaHa
-		basalPerHa = sumBasal / sampledAreaHa
-	}
-
-	var volumeTotalMst float64
-	if stackingFactor > 0 {
-		volumeTotalMst = sumVolume / stackingFactor
-	}
+	summary := result.Summary
 
 	// Montar endereço do projeto se houver dados
 	var projectAddress *ProjectAddress
@@ -730,9 +312,6 @@ func ToPhytoAnalysisCompleteResponse(p *types.PhytoAnalysisComplete) *PhytoAnaly
 		}
 	}
 
-	// Calcular indicadores fitossociológicos
-	indicators := calculatePhytosociologicalIndicators(p)
-
 	return &PhytoAnalysisResponse{
 		ID:              p.ID,
 		Title:           p.Title,
@@ -745,6 +324,12 @@ func ToPhytoAnalysisCompleteResponse(p *types.PhytoAnalysisComplete) *PhytoAnaly
 		ProjectID:       p.ProjectID,
 		CreatedAt:       p.CreatedAt,
 		UpdatedAt:       p.UpdatedAt,
+
+		DefaultFormFactor: p.DefaultFormFactor,
+		Measurement:       &p.Measurement,
+
+		PreviousAnalysisID: p.PreviousAnalysisID,
+
 		Project: &ProjectInfo{
 			ID:       p.ProjectID,
 			Title:    p.ProjectTitle,
@@ -755,19 +340,58 @@ func ToPhytoAnalysisCompleteResponse(p *types.PhytoAnalysisComplete) *PhytoAnaly
 		},
 		Specimens: specimens,
 
-		IndividualsCount: len(p.Specimens),   // Total de specimens
-		SpeciesCount:     len(uniqueSpecies), // Total de espécies únicas
+		IndividualsCount: summary.IndividualsCount,
+		SpeciesCount:     summary.SpeciesCount,
 
 		// Métricas agregadas (SUASSU-186)
-		MeanDBHCm:      meanDbhCm,
-		MeanHeightM:    meanHeightM,
-		DensityIndHa:   densityIndHa,
-		VolumeTotalM3:  sumVolume,
-		VolumeTotalMst: volumeTotalMst,
-		VolumePerHa:    volumePerHa,
-		BasalAreaPerHa: basalPerHa,
+		MeanDBHCm:      summary.MeanDBHCm,
+		MeanHeightM:    summary.MeanHeightM,
+		DensityIndHa:   summary.DensityIndHa,
+		VolumeTotalM3:  summary.VolumeTotalM3,
+		VolumeTotalMst: summary.VolumeTotalMst,
+		VolumePerHa:    summary.VolumePerHa,
+		BasalAreaPerHa: summary.BasalAreaPerHa,
+
+		CylindricalVolumeTotalM3: summary.CylindricalVolumeTotalM3,
+		CylindricalVolumePerHa:   summary.CylindricalVolumePerHa,
 
 		// Indicadores fitossociológicos (SUASSU-284)
-		Indicators: indicators,
+		Indicators:         result.Indicators,
+		IndicatorsSnapshot: snapshotInfo,
+
+		Biomass:    result.Biomass,
+		Equations:  result.Equations,
+		Hypsometry: result.Hypsometry,
+		Ecology:    result.Ecology,
+	}
+}
+
+// ToSpecimenImportReportResponse converte o relatório de validação para resposta HTTP
+func ToSpecimenImportReportResponse(r *types.SpecimenImportReport) *SpecimenImportReportResponse {
+	invalidRows := r.InvalidRows
+	if invalidRows == nil {
+		invalidRows = []types.InvalidSpecimenRow{}
+	}
+
+	flaggedRows := r.FlaggedRows
+	if flaggedRows == nil {
+		flaggedRows = []types.FlaggedSpecimenRow{}
+	}
+
+	out := &SpecimenImportReportResponse{
+		Valid:          len(r.AnalysisErrors) == 0 && len(invalidRows) == 0,
+		TotalRows:      r.TotalRows,
+		ValidRows:      r.ValidRows,
+		AnalysisErrors: r.AnalysisErrors,
+		InvalidRows:    invalidRows,
+		FlaggedRows:    flaggedRows,
+
+		NewMorphospecies: r.NewMorphospecies,
 	}
+
+	if r.Preview != nil {
+		out.Indicators = phytometrics.ComputeIndicators(r.Preview)
+	}
+
+	return out
 }
@@ -0,0 +1,98 @@
+package phytoanalysisdto
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ESG-Project/suassu-api/internal/app/phytometrics"
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+	"github.com/stretchr/testify/require"
+)
+
+func floatPtr(v float64) *float64 { return &v }
+
+func TestToPhytoAnalysisCompleteResponse_AppliesFormFactor(t *testing.T) {
+	t.Parallel()
+
+	// CAP = 100 cm -> G = 100²/(4π)/10000 m²
+	g := 100.0 * 100.0 / (4 * math.Pi) / 10000.0
+	p := &types.PhytoAnalysisComplete{
+		PortionQuantity:   1,
+		PortionArea:       10000,
+		SampledArea:       1,
+		DefaultFormFactor: floatPtr(0.5),
+		Specimens: []*types.SpecimenWithSpecies{
+			// legislação da espécie tem prioridade sobre o padrão da análise
+			{ID: "a", Portion: "1", Height: 10, Cap1: 100, SpecieID: "sp-1", ScientificName: "A a", FormFactor: floatPtr(0.7)},
+			// sem legislação: usa o padrão da análise
+			{ID: "b", Portion: "1", Height: 10, Cap1: 100, SpecieID: "sp-2", ScientificName: "B b"},
+		},
+	}
+
+	resp := ToPhytoAnalysisCompleteResponse(p, nil)
+
+	require.InDelta(t, 0.7, resp.Specimens[0].FormFactor, 1e-9)
+	require.InDelta(t, g*10*0.7, resp.Specimens[0].VolumeM3, 1e-9)
+	require.InDelta(t, g*10, resp.Specimens[0].CylVolumeM3, 1e-9)
+	require.InDelta(t, 0.5, resp.Specimens[1].FormFactor, 1e-9)
+	require.InDelta(t, g*10*0.5, resp.Specimens[1].VolumeM3, 1e-9)
+
+	require.InDelta(t, g*10*1.2, resp.VolumeTotalM3, 1e-9)
+	require.InDelta(t, g*10*2, resp.CylindricalVolumeTotalM3, 1e-9)
+	require.InDelta(t, g*10*1.2, *resp.Indicators.Volume, 1e-9)
+	require.InDelta(t, g*10*1.2, *resp.Indicators.ReplacementVolume, 1e-9)
+	require.InDelta(t, g*10*2, *resp.Indicators.CylindricalReplacementVolume, 1e-9)
+}
+
+func TestToPhytoAnalysisCompleteResponse_CylindricalWithoutFormFactor(t *testing.T) {
+	t.Parallel()
+
+	p := &types.PhytoAnalysisComplete{
+		PortionQuantity: 1,
+		PortionArea:     10000,
+		SampledArea:     1,
+		Specimens: []*types.SpecimenWithSpecies{
+			{ID: "a", Portion: "1", Height: 10, Cap1: 100, SpecieID: "sp-1", ScientificName: "A a"},
+		},
+	}
+
+	resp := ToPhytoAnalysisCompleteResponse(p, nil)
+
+	require.InDelta(t, 1.0, resp.Specimens[0].FormFactor, 1e-9)
+	require.InDelta(t, resp.CylindricalVolumeTotalM3, resp.VolumeTotalM3, 1e-12)
+}
+
+func TestToPhytoAnalysisCompleteResponse_ServesSnapshot(t *testing.T) {
+	t.Parallel()
+
+	p := &types.PhytoAnalysisComplete{
+		PortionQuantity: 1,
+		PortionArea:     10000,
+		SampledArea:     1,
+		Specimens: []*types.SpecimenWithSpecies{
+			{ID: "a", Portion: "1", Height: 10, Cap1: 100, SpecieID: "sp-1", ScientificName: "A a"},
+		},
+	}
+
+	// o snapshot guarda o resultado do momento do cálculo, mesmo que os dados mudem depois
+	snapshot := &phytometrics.Snapshot{
+		Version:       3,
+		EngineVersion: phytometrics.EngineVersion,
+		Reason:        "specimens_imported",
+		Result: &phytometrics.Result{
+			Summary:    phytometrics.Summary{IndividualsCount: 7, VolumeTotalM3: 42},
+			Indicators: &phytometrics.Indicators{IndividualsCount: 7},
+		},
+	}
+
+	resp := ToPhytoAnalysisCompleteResponse(p, snapshot)
+
+	require.Equal(t, 7, resp.IndividualsCount)
+	require.InDelta(t, 42, resp.VolumeTotalM3, 1e-12)
+	require.Equal(t, 7, resp.Indicators.IndividualsCount)
+	require.Equal(t, 3, resp.IndicatorsSnapshot.Version)
+	require.False(t, resp.IndicatorsSnapshot.Outdated)
+	// os valores individuais continuam calculados a partir dos espécimes
+	require.Len(t, resp.Specimens, 1)
+	require.Positive(t, resp.Specimens[0].VolumeM3)
+}
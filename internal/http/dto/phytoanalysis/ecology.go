@@ -0,0 +1,9 @@
+package phytoanalysisdto
+
+import "github.com/ESG-Project/suassu-api/internal/app/phytometrics"
+
+// Respostas do perfil ecológico
+type (
+	EcologyResponse         = phytometrics.Ecology
+	EcologicalGroupResponse = phytometrics.EcologicalGroup
+)
@@ -0,0 +1,42 @@
+package phytoanalysisdto
+
+import (
+	"github.com/ESG-Project/suassu-api/internal/app/phytometrics"
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+)
+
+// EquationsResponse representa as equações usadas nos resultados
+type EquationsResponse = phytometrics.Equations
+
+// SelectEquationRequest representa a seleção de uma equação do cadastro para a análise
+type SelectEquationRequest struct {
+	EquationID string `json:"equationId"`
+}
+
+// AnalysisEquationsResponse representa as equações da análise e as sugestões do cadastro
+type AnalysisEquationsResponse struct {
+	Volume      types.EquationData    `json:"volume"`   // equação de volume em uso
+	Biomass     types.EquationData    `json:"biomass"`  // equação de biomassa em uso
+	Selected    []types.EquationData  `json:"selected"` // equações selecionadas (vazio = padrões do motor)
+	Suggestions []*types.EquationData `json:"suggestions"`
+}
+
+// ToAnalysisEquationsResponse converte as equações em uso e as sugestões
+func ToAnalysisEquationsResponse(p *types.PhytoAnalysisComplete, suggestions []*types.EquationData) *AnalysisEquationsResponse {
+	used := phytometrics.UsedEquations(p)
+	out := &AnalysisEquationsResponse{
+		Volume:      used.Volume,
+		Biomass:     used.Biomass,
+		Selected:    make([]types.EquationData, 0, 2),
+		Suggestions: suggestions,
+	}
+	for _, eq := range []*types.EquationData{p.VolumeEquation, p.BiomassEquation} {
+		if eq != nil {
+			out.Selected = append(out.Selected, *eq)
+		}
+	}
+	if out.Suggestions == nil {
+		out.Suggestions = []*types.EquationData{}
+	}
+	return out
+}
@@ -0,0 +1,243 @@
+package phytoanalysisdto
+
+import (
+	"encoding/xml"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ESG-Project/suassu-api/internal/app/phytometrics"
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+)
+
+// Tipos das feições na exportação espacial (propriedade "featureType")
+const (
+	GeoFeaturePlot     = "plot"
+	GeoFeatureSpecimen = "specimen"
+)
+
+// GeoJSONFeatureCollection representa a exportação GeoJSON (RFC 7946, WGS84, [longitude, latitude])
+type GeoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Name     string           `json:"name,omitempty"`
+	Features []GeoJSONFeature `json:"features"`
+}
+
+// GeoJSONFeature representa uma parcela ou um indivíduo georreferenciado
+type GeoJSONFeature struct {
+	Type       string          `json:"type"`
+	ID         string          `json:"id,omitempty"`
+	Geometry   GeoJSONGeometry `json:"geometry"`
+	Properties map[string]any  `json:"properties"`
+}
+
+// GeoJSONGeometry representa um ponto ([lon, lat]) ou um polígono ([[[lon, lat], ...]])
+type GeoJSONGeometry struct {
+	Type        string `json:"type"`
+	Coordinates any    `json:"coordinates"`
+}
+
+// ToGeoJSON monta as feições da análise: parcelas como polígono (vértices) ou ponto (centro)
+// e indivíduos como pontos com espécie e atributos dendrométricos. Itens sem localização ficam de fora.
+func ToGeoJSON(p *types.PhytoAnalysisComplete) *GeoJSONFeatureCollection {
+	fc := &GeoJSONFeatureCollection{
+		Type:     "FeatureCollection",
+		Name:     p.Title,
+		Features: make([]GeoJSONFeature, 0),
+	}
+
+	plotMetrics := make(map[string]phytometrics.PlotMetrics, len(p.Plots))
+	// métricas das parcelas pelo protocolo; os indivíduos mortos seguem listados como pontos
+	for _, m := range phytometrics.ComputePlots(phytometrics.WithoutDead(p)) {
+		plotMetrics[m.Code] = m
+	}
+
+	for _, pl := range p.Plots {
+		geometry, ok := plotGeometry(pl)
+		if !ok {
+			continue
+		}
+		m := plotMetrics[pl.Code]
+		props := map[string]any{
+			"featureType":    GeoFeaturePlot,
+			"code":           pl.Code,
+			"shape":          pl.Shape,
+			"areaM2":         pl.AreaM2,
+			"individuals":    m.Individuals,
+			"speciesCount":   m.SpeciesCount,
+			"densityIndHa":   m.DensityIndHa,
+			"basalAreaPerHa": m.BasalAreaPerHa,
+			"volumePerHa":    m.VolumePerHa,
+		}
+		if pl.VegetationNotes != nil {
+			props["vegetationNotes"] = *pl.VegetationNotes
+		}
+		fc.Features = append(fc.Features, GeoJSONFeature{Type: "Feature", ID: pl.ID, Geometry: geometry, Properties: props})
+	}
+
+	metrics := phytometrics.ComputeSpecimens(p)
+	for i, s := range p.Specimens {
+		if s.Latitude == nil || s.Longitude == nil {
+			continue
+		}
+		m := metrics[i]
+		props := map[string]any{
+			"featureType":     GeoFeatureSpecimen,
+			"portion":         s.Portion,
+			"scientificName":  s.ScientificName,
+			"family":          s.Family,
+			"registerDate":    s.RegisterDate.Format("2006-01-02"),
+			"status":          s.Status,
+			"dbhCm":           m.DbhCm,
+			"height":          s.Height,
+			"heightEstimated": s.HeightEstimated,
+			"basalAreaM2":     m.BasalAreaM2,
+			"volumeM3":        m.VolumeM3,
+		}
+		if s.PopularName != nil {
+			props["popularName"] = *s.PopularName
+		}
+		fc.Features = append(fc.Features, GeoJSONFeature{
+			Type:       "Feature",
+			ID:         s.ID,
+			Geometry:   GeoJSONGeometry{Type: "Point", Coordinates: []float64{*s.Longitude, *s.Latitude}},
+			Properties: props,
+		})
+	}
+
+	return fc
+}
+
+// plotGeometry retorna o polígono da parcela (anel fechado) ou, sem vértices, o ponto central
+func plotGeometry(pl *types.PlotData) (GeoJSONGeometry, bool) {
+	if len(pl.Vertices) >= 3 {
+		ring := make([][]float64, 0, len(pl.Vertices)+1)
+		for _, v := range pl.Vertices {
+			ring = append(ring, []float64{v.Longitude, v.Latitude})
+		}
+		first, last := pl.Vertices[0], pl.Vertices[len(pl.Vertices)-1]
+		if first != last {
+			ring = append(ring, []float64{first.Longitude, first.Latitude})
+		}
+		return GeoJSONGeometry{Type: "Polygon", Coordinates: [][][]float64{ring}}, true
+	}
+	if pl.Center != nil {
+		return GeoJSONGeometry{Type: "Point", Coordinates: []float64{pl.Center.Longitude, pl.Center.Latitude}}, true
+	}
+	return GeoJSONGeometry{}, false
+}
+
+// Estrutura mínima do KML 2.2 (pastas de parcelas e indivíduos, atributos em ExtendedData)
+type kmlRoot struct {
+	XMLName  xml.Name    `xml:"kml"`
+	Xmlns    string      `xml:"xmlns,attr"`
+	Document kmlDocument `xml:"Document"`
+}
+
+type kmlDocument struct {
+	Name    string      `xml:"name"`
+	Folders []kmlFolder `xml:"Folder"`
+}
+
+type kmlFolder struct {
+	Name       string         `xml:"name"`
+	Placemarks []kmlPlacemark `xml:"Placemark"`
+}
+
+type kmlPlacemark struct {
+	ID           string      `xml:"id,attr,omitempty"`
+	Name         string      `xml:"name"`
+	ExtendedData []kmlData   `xml:"ExtendedData>Data"`
+	Point        *kmlCoords  `xml:"Point,omitempty"`
+	Polygon      *kmlPolygon `xml:"Polygon,omitempty"`
+}
+
+type kmlData struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value"`
+}
+
+type kmlCoords struct {
+	Coordinates string `xml:"coordinates"`
+}
+
+type kmlPolygon struct {
+	OuterBoundary kmlCoords `xml:"outerBoundaryIs>LinearRing"`
+}
+
+// WriteKML escreve as feições da exportação GeoJSON como KML (parcelas e indivíduos em pastas separadas)
+func WriteKML(w io.Writer, fc *GeoJSONFeatureCollection) error {
+	plots := kmlFolder{Name: "plots", Placemarks: make([]kmlPlacemark, 0)}
+	specimens := kmlFolder{Name: "specimens", Placemarks: make([]kmlPlacemark, 0)}
+
+	for _, f := range fc.Features {
+		pm := kmlPlacemark{ID: f.ID, ExtendedData: kmlExtendedData(f.Properties)}
+		switch c := f.Geometry.Coordinates.(type) {
+		case []float64:
+			pm.Point = &kmlCoords{Coordinates: kmlCoordinates([][]float64{c})}
+		case [][][]float64:
+			pm.Polygon = &kmlPolygon{OuterBoundary: kmlCoords{Coordinates: kmlCoordinates(c[0])}}
+		}
+
+		if f.Properties["featureType"] == GeoFeaturePlot {
+			pm.Name, _ = f.Properties["code"].(string)
+			plots.Placemarks = append(plots.Placemarks, pm)
+		} else {
+			pm.Name, _ = f.Properties["scientificName"].(string)
+			specimens.Placemarks = append(specimens.Placemarks, pm)
+		}
+	}
+
+	root := kmlRoot{
+		Xmlns:    "http://www.opengis.net/kml/2.2",
+		Document: kmlDocument{Name: fc.Name, Folders: []kmlFolder{plots, specimens}},
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(root); err != nil {
+		return err
+	}
+	return enc.Flush()
+}
+
+// kmlExtendedData converte as propriedades (exceto o tipo da feição) em pares nome/valor ordenados
+func kmlExtendedData(props map[string]any) []kmlData {
+	names := make([]string, 0, len(props))
+	for name := range props {
+		if name != "featureType" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	out := make([]kmlData, 0, len(names))
+	for _, name := range names {
+		var value string
+		switch v := props[name].(type) {
+		case float64:
+			value = strconv.FormatFloat(v, 'f', -1, 64)
+		case int:
+			value = strconv.Itoa(v)
+		case bool:
+			value = strconv.FormatBool(v)
+		case string:
+			value = v
+		}
+		out = append(out, kmlData{Name: name, Value: value})
+	}
+	return out
+}
+
+// kmlCoordinates formata os pontos como "lon,lat" separados por espaço
+func kmlCoordinates(points [][]float64) string {
+	parts := make([]string, 0, len(points))
+	for _, pt := range points {
+		parts = append(parts, strconv.FormatFloat(pt[0], 'f', -1, 64)+","+strconv.FormatFloat(pt[1], 'f', -1, 64))
+	}
+	return strings.Join(parts, " ")
+}
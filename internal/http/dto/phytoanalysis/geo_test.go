@@ -0,0 +1,99 @@
+package phytoanalysisdto
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+	"github.com/stretchr/testify/require"
+)
+
+func geoSample() *types.PhytoAnalysisComplete {
+	lat, lng := -15.7801, -47.9292
+	popular := "Copaíba"
+	return &types.PhytoAnalysisComplete{
+		Title:       "Inventário & Cia",
+		PortionArea: 100,
+		Plots: []*types.PlotData{
+			{
+				ID: "p1", Code: "P1", Shape: "irregular", AreaM2: 100,
+				Vertices: []types.Coordinate{
+					{Latitude: -15.78, Longitude: -47.93},
+					{Latitude: -15.78, Longitude: -47.92},
+					{Latitude: -15.77, Longitude: -47.92},
+				},
+			},
+			{ID: "p2", Code: "P2", Shape: "circle", AreaM2: 100, Center: &types.Coordinate{Latitude: -15.7, Longitude: -47.9}},
+			{ID: "p3", Code: "P3", Shape: "rectangle", AreaM2: 100}, // sem localização
+		},
+		Specimens: []*types.SpecimenWithSpecies{
+			{
+				ID: "s1", Portion: "P1", Cap1: 20 * math.Pi, Height: 10, ScientificName: "Copaifera langsdorffii",
+				Family: "Fabaceae", PopularName: &popular, RegisterDate: time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC),
+				Latitude: &lat, Longitude: &lng,
+			},
+			{ID: "s2", Portion: "P1", Cap1: 30, Height: 8, ScientificName: "A a"}, // sem coordenadas
+		},
+	}
+}
+
+func TestToGeoJSON(t *testing.T) {
+	t.Parallel()
+
+	fc := ToGeoJSON(geoSample())
+
+	require.Equal(t, "FeatureCollection", fc.Type)
+	require.Len(t, fc.Features, 3)
+
+	polygon := fc.Features[0]
+	require.Equal(t, "Polygon", polygon.Geometry.Type)
+	ring := polygon.Geometry.Coordinates.([][][]float64)[0]
+	require.Len(t, ring, 4) // anel fechado
+	require.Equal(t, []float64{-47.93, -15.78}, ring[0])
+	require.Equal(t, ring[0], ring[3])
+	require.Equal(t, GeoFeaturePlot, polygon.Properties["featureType"])
+	require.Equal(t, 2, polygon.Properties["individuals"]) // inclui indivíduos sem coordenadas
+
+	require.Equal(t, "Point", fc.Features[1].Geometry.Type)
+	require.Equal(t, "P2", fc.Features[1].Properties["code"])
+
+	specimen := fc.Features[2]
+	require.Equal(t, "s1", specimen.ID)
+	require.Equal(t, []float64{-47.9292, -15.7801}, specimen.Geometry.Coordinates)
+	require.Equal(t, "Copaifera langsdorffii", specimen.Properties["scientificName"])
+	require.InDelta(t, 20, specimen.Properties["dbhCm"].(float64), 1e-9)
+	require.Equal(t, "2026-01-10", specimen.Properties["registerDate"])
+
+	raw, err := json.Marshal(fc)
+	require.NoError(t, err)
+	require.Contains(t, string(raw), `"coordinates":[-47.9292,-15.7801]`)
+}
+
+func TestWriteKML(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteKML(&buf, ToGeoJSON(geoSample())))
+
+	var doc kmlRoot
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+	require.Equal(t, "Inventário & Cia", doc.Document.Name)
+	require.Len(t, doc.Document.Folders, 2)
+
+	plots := doc.Document.Folders[0].Placemarks
+	require.Len(t, plots, 2)
+	require.Equal(t, "P1", plots[0].Name)
+	require.NotNil(t, plots[0].Polygon)
+	require.Equal(t, "-47.93,-15.78 -47.92,-15.78 -47.92,-15.77 -47.93,-15.78", plots[0].Polygon.OuterBoundary.Coordinates)
+	require.Equal(t, "-47.9,-15.7", plots[1].Point.Coordinates)
+
+	specimens := doc.Document.Folders[1].Placemarks
+	require.Len(t, specimens, 1)
+	require.Equal(t, "Copaifera langsdorffii", specimens[0].Name)
+	require.Contains(t, specimens[0].ExtendedData, kmlData{Name: "popularName", Value: "Copaíba"})
+	require.Contains(t, specimens[0].ExtendedData, kmlData{Name: "heightEstimated", Value: "false"})
+}
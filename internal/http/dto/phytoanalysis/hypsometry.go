@@ -0,0 +1,9 @@
+package phytoanalysisdto
+
+import "github.com/ESG-Project/suassu-api/internal/app/phytometrics"
+
+// Respostas da relação hipsométrica
+type (
+	HypsometryResponse     = phytometrics.Hypsometry
+	HypsometricFitResponse = phytometrics.HypsometricFit
+)
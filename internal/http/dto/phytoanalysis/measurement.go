@@ -0,0 +1,7 @@
+package phytoanalysisdto
+
+import "github.com/ESG-Project/suassu-api/internal/app/types"
+
+// MeasurementProtocolDTO descreve como os fustes são medidos (CAP ou DAP, cm ou mm) e os critérios de
+// inclusão (diâmetro mínimo e exclusão dos mortos). Omitido na requisição = CAP em cm, sem critério.
+type MeasurementProtocolDTO = types.MeasurementProtocol
@@ -0,0 +1,13 @@
+package phytoanalysisdto
+
+// MorphospeciesRequest representa o cadastro ou a edição de uma morfoespécie
+type MorphospeciesRequest struct {
+	Name   string  `json:"name"` // ex.: "Myrcia sp. 1", "Indet. 3"
+	Family *string `json:"family,omitempty"`
+	Genus  *string `json:"genus,omitempty"`
+}
+
+// ResolveMorphospeciesRequest representa a determinação de uma morfoespécie como espécie do catálogo
+type ResolveMorphospeciesRequest struct {
+	SpeciesID string `json:"speciesId"`
+}
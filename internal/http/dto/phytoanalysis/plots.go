@@ -0,0 +1,41 @@
+package phytoanalysisdto
+
+import (
+	"github.com/ESG-Project/suassu-api/internal/app/phytometrics"
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+)
+
+// PlotRequest representa o cadastro ou a edição de uma parcela
+type PlotRequest struct {
+	Code            string             `json:"code"`             // código usado na coluna "portion" dos espécimes
+	AreaM2          *float64           `json:"areaM2,omitempty"` // omitida = calculada pelas dimensões
+	Shape           string             `json:"shape"`            // rectangle, circle ou irregular
+	WidthM          *float64           `json:"widthM,omitempty"`
+	LengthM         *float64           `json:"lengthM,omitempty"`
+	RadiusM         *float64           `json:"radiusM,omitempty"`
+	Center          *types.Coordinate  `json:"center,omitempty"`
+	Vertices        []types.Coordinate `json:"vertices,omitempty"` // polígono (WGS84)
+	VegetationNotes *string            `json:"vegetationNotes,omitempty"`
+	StratumID       *string            `json:"stratumId,omitempty"` // estrato da amostragem estratificada
+}
+
+// PlotResponse representa uma parcela (cadastro, quando houver) e suas métricas
+type PlotResponse struct {
+	Plot    *types.PlotData          `json:"plot"` // null = parcela apenas referenciada pelos espécimes
+	Metrics phytometrics.PlotMetrics `json:"metrics"`
+}
+
+// ToPlotsResponse converte as parcelas da análise com as métricas por parcela
+func ToPlotsResponse(p *types.PhytoAnalysisComplete) []PlotResponse {
+	byCode := make(map[string]*types.PlotData, len(p.Plots))
+	for _, pl := range p.Plots {
+		byCode[pl.Code] = pl
+	}
+
+	metrics := phytometrics.ComputePlots(p)
+	out := make([]PlotResponse, 0, len(metrics))
+	for _, m := range metrics {
+		out = append(out, PlotResponse{Plot: byCode[m.Code], Metrics: m})
+	}
+	return out
+}
@@ -0,0 +1,23 @@
+package phytoanalysisdto
+
+import (
+	"github.com/ESG-Project/suassu-api/internal/app/phytometrics"
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+)
+
+// Padrões da curva de acumulação aleatorizada
+const (
+	DefaultAccumulationPermutations = phytometrics.DefaultAccumulationPermutations
+	MaxAccumulationPermutations     = phytometrics.MaxAccumulationPermutations
+)
+
+// Opções e resposta da curva de acumulação e dos estimadores de riqueza
+type (
+	AccumulationOptions         = phytometrics.AccumulationOptions
+	SpeciesAccumulationResponse = phytometrics.SpeciesAccumulation
+)
+
+// ToSpeciesAccumulationResponse calcula a curva de acumulação e os estimadores de riqueza
+func ToSpeciesAccumulationResponse(p *types.PhytoAnalysisComplete, opts AccumulationOptions) *SpeciesAccumulationResponse {
+	return phytometrics.ComputeSpeciesAccumulation(p, opts)
+}
@@ -0,0 +1,22 @@
+package phytoanalysisdto
+
+import (
+	"github.com/ESG-Project/suassu-api/internal/app/phytometrics"
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+)
+
+// Opções e resposta da suficiência amostral
+type (
+	SamplingOptions            = phytometrics.SamplingOptions
+	SamplingStatisticsResponse = phytometrics.SamplingStatistics
+)
+
+// DefaultSamplingOptions retorna as opções padrão (10% de erro a 90% de probabilidade)
+func DefaultSamplingOptions() SamplingOptions {
+	return phytometrics.DefaultSamplingOptions()
+}
+
+// ToSamplingStatisticsResponse calcula a estatística de amostragem casual simples da análise
+func ToSamplingStatisticsResponse(p *types.PhytoAnalysisComplete, opts SamplingOptions) *SamplingStatisticsResponse {
+	return phytometrics.ComputeSamplingStatistics(p, opts)
+}
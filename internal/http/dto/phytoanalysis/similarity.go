@@ -0,0 +1,20 @@
+package phytoanalysisdto
+
+import (
+	"github.com/ESG-Project/suassu-api/internal/app/phytometrics"
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+)
+
+// Índices aceitos para o agrupamento
+const (
+	SimilarityIndexJaccard  = phytometrics.SimilarityIndexJaccard
+	SimilarityIndexSorensen = phytometrics.SimilarityIndexSorensen
+)
+
+// FloristicSimilarityResponse representa a comparação florística
+type FloristicSimilarityResponse = phytometrics.FloristicSimilarity
+
+// ToFloristicSimilarityResponse calcula a similaridade par a par e o dendrograma UPGMA
+func ToFloristicSimilarityResponse(analyses []*types.PhytoAnalysisComplete, index string) *FloristicSimilarityResponse {
+	return phytometrics.ComputeFloristicSimilarity(analyses, index)
+}
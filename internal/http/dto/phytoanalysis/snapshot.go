@@ -0,0 +1,87 @@
+package phytoanalysisdto
+
+import (
+	"time"
+
+	"github.com/ESG-Project/suassu-api/internal/app/phytometrics"
+)
+
+// IndicatorSnapshotInfo identifica a versão persistida dos indicadores
+type IndicatorSnapshotInfo struct {
+	Version        int       `json:"version"` // 0 quando calculado na leitura (ainda não gravado)
+	EngineVersion  int       `json:"engineVersion"`
+	Outdated       bool      `json:"outdated"` // gerado por versão anterior do motor (recalcular para atualizar)
+	Reason         string    `json:"reason"`   // evento que originou o snapshot
+	SpecimensCount int       `json:"specimensCount"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+// IndicatorSnapshotResponse representa um snapshot com as métricas, os indicadores e a biomassa
+type IndicatorSnapshotResponse struct {
+	IndicatorSnapshotInfo
+	Summary    phytometrics.Summary         `json:"summary"`
+	Indicators *PhytosociologicalIndicators `json:"indicators"`
+	Biomass    *BiomassResponse             `json:"biomass,omitempty"`
+	Equations  *EquationsResponse           `json:"equations,omitempty"`
+	Hypsometry *HypsometryResponse          `json:"hypsometry,omitempty"`
+	Ecology    *EcologyResponse             `json:"ecology,omitempty"`
+}
+
+// RecomputeIndicatorsResponse representa o recálculo explícito com a diferença para o snapshot anterior
+type RecomputeIndicatorsResponse struct {
+	Created  bool                      `json:"created"` // false quando nada mudou (nenhuma versão nova)
+	Previous *IndicatorSnapshotInfo    `json:"previous,omitempty"`
+	Current  IndicatorSnapshotResponse `json:"current"`
+	Changes  []phytometrics.Change     `json:"changes"`
+}
+
+// ToIndicatorSnapshotInfo converte os metadados do snapshot
+func ToIndicatorSnapshotInfo(s *phytometrics.Snapshot) *IndicatorSnapshotInfo {
+	return &IndicatorSnapshotInfo{
+		Version:        s.Version,
+		EngineVersion:  s.EngineVersion,
+		Outdated:       s.Outdated(),
+		Reason:         s.Reason,
+		SpecimensCount: s.SpecimensCount,
+		CreatedAt:      s.CreatedAt,
+	}
+}
+
+// ToIndicatorSnapshotResponse converte um snapshot completo
+func ToIndicatorSnapshotResponse(s *phytometrics.Snapshot) IndicatorSnapshotResponse {
+	out := IndicatorSnapshotResponse{IndicatorSnapshotInfo: *ToIndicatorSnapshotInfo(s)}
+	if s.Result != nil {
+		out.Summary = s.Result.Summary
+		out.Indicators = s.Result.Indicators
+		out.Biomass = s.Result.Biomass
+		out.Equations = s.Result.Equations
+		out.Hypsometry = s.Result.Hypsometry
+		out.Ecology = s.Result.Ecology
+	}
+	return out
+}
+
+// ToIndicatorSnapshotInfoList converte a lista de versões (sem o resultado)
+func ToIndicatorSnapshotInfoList(snapshots []*phytometrics.Snapshot) []IndicatorSnapshotInfo {
+	out := make([]IndicatorSnapshotInfo, 0, len(snapshots))
+	for _, s := range snapshots {
+		out = append(out, *ToIndicatorSnapshotInfo(s))
+	}
+	return out
+}
+
+// ToRecomputeIndicatorsResponse converte o resultado do recálculo
+func ToRecomputeIndicatorsResponse(r *phytometrics.Recomputation) *RecomputeIndicatorsResponse {
+	out := &RecomputeIndicatorsResponse{
+		Created: r.Created,
+		Current: ToIndicatorSnapshotResponse(r.Current),
+		Changes: r.Changes,
+	}
+	if out.Changes == nil {
+		out.Changes = []phytometrics.Change{}
+	}
+	if r.Previous != nil {
+		out.Previous = ToIndicatorSnapshotInfo(r.Previous)
+	}
+	return out
+}
@@ -0,0 +1,52 @@
+package phytoanalysisdto
+
+import (
+	"strconv"
+)
+
+// SpecimenCSVHeader é o cabeçalho da exportação CSV de espécimes
+var SpecimenCSVHeader = []string{
+	"id", "portion", "scientificName", "family", "popularName", "specieId", "registerDate",
+	"height", "heightEstimated", "cap1", "cap2", "cap3", "cap4", "cap5", "cap6",
+	"dbhCm", "basalAreaM2", "formFactor", "volumeM3", "cylindricalVolumeM3",
+	"latitude", "longitude", "tag",
+	"status", "phytosanitary", "bifurcationNotes", "observations", "morphospeciesId",
+}
+
+// ToSpecimenCSVRecord converte um espécime em uma linha da exportação CSV (mesma ordem de SpecimenCSVHeader)
+func ToSpecimenCSVRecord(s SpecimenResponse) []string {
+	popularName := ""
+	if s.PopularName != nil {
+		popularName = *s.PopularName
+	}
+
+	return []string{
+		s.ID, s.Portion, s.ScientificName, s.Family, popularName, s.SpecieID, s.RegisterDate.Format("2006-01-02"),
+		formatCSVFloat(s.Height), strconv.FormatBool(s.HeightEstimated), formatCSVFloat(s.Cap1),
+		formatCSVFloatPtr(s.Cap2), formatCSVFloatPtr(s.Cap3), formatCSVFloatPtr(s.Cap4),
+		formatCSVFloatPtr(s.Cap5), formatCSVFloatPtr(s.Cap6),
+		formatCSVFloat(s.DbhCm), formatCSVFloat(s.BasalAreaM2), formatCSVFloat(s.FormFactor),
+		formatCSVFloat(s.VolumeM3), formatCSVFloat(s.CylVolumeM3),
+		formatCSVFloatPtr(s.Latitude), formatCSVFloatPtr(s.Longitude), formatCSVStringPtr(s.Tag),
+		s.Status, formatCSVStringPtr(s.Phytosanitary), formatCSVStringPtr(s.BifurcationNotes),
+		formatCSVStringPtr(s.Observations), formatCSVStringPtr(s.MorphospeciesID),
+	}
+}
+
+func formatCSVFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func formatCSVFloatPtr(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return formatCSVFloat(*v)
+}
+
+func formatCSVStringPtr(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
@@ -0,0 +1,23 @@
+package phytoanalysisdto
+
+import (
+	"github.com/ESG-Project/suassu-api/internal/app/phytometrics"
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+)
+
+// StratumRequest representa o cadastro ou a edição de um estrato
+type StratumRequest struct {
+	Code        string  `json:"code"`
+	Name        string  `json:"name"`   // omitido = código
+	AreaHa      float64 `json:"areaHa"` // área do estrato (ha)
+	Description *string `json:"description,omitempty"`
+}
+
+// StratifiedSamplingResponse representa a amostragem estratificada
+type StratifiedSamplingResponse = phytometrics.StratifiedSampling
+
+// ToStratifiedSamplingResponse calcula os estimadores da amostragem estratificada e, por estrato,
+// a estatística da amostragem casual simples e a tabela fitossociológica
+func ToStratifiedSamplingResponse(p *types.PhytoAnalysisComplete, opts SamplingOptions) *StratifiedSamplingResponse {
+	return phytometrics.ComputeStratifiedSampling(p, opts)
+}
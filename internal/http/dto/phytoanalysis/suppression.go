@@ -0,0 +1,21 @@
+package phytoanalysisdto
+
+import (
+	"github.com/ESG-Project/suassu-api/internal/app/phytometrics"
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+)
+
+// AssortmentRulesDTO define a classificação da madeira suprimida em tora e lenha.
+// Valores omitidos na requisição = padrões do motor (DAP de 20 cm, fator de empilhamento 0,7).
+type AssortmentRulesDTO = types.AssortmentRules
+
+// Respostas do relatório de supressão
+type (
+	SuppressionReportResponse  = phytometrics.SuppressionReport
+	SpeciesSuppressionResponse = phytometrics.SpeciesSuppression
+)
+
+// ToSuppressionReportResponse calcula o volume da supressão por produto e por espécie
+func ToSuppressionReportResponse(p *types.PhytoAnalysisComplete) *SuppressionReportResponse {
+	return phytometrics.ComputeSuppression(p)
+}
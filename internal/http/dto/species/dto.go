@@ -13,11 +13,18 @@ type SpeciesResponse struct {
 	Family         string                `json:"family"`
 	PopularName    *string               `json:"popularName,omitempty"`
 	Habit          *string               `json:"habit,omitempty"`
+	WoodDensity    *float64              `json:"woodDensity,omitempty"` // g/cm³
 	CreatedAt      time.Time             `json:"createdAt"`
 	UpdatedAt      time.Time             `json:"updatedAt"`
 	Legislations   []LegislationResponse `json:"legislations,omitempty"`
 }
 
+// UpdateWoodDensityRequest representa a requisição de alteração da densidade da madeira.
+// woodDensity nulo remove o valor cadastrado (a estimativa de biomassa passa a usar o padrão).
+type UpdateWoodDensityRequest struct {
+	WoodDensity *float64 `json:"woodDensity"`
+}
+
 // LegislationResponse representa a resposta de uma legislação de espécie
 type LegislationResponse struct {
 	ID                  string    `json:"id"`
@@ -58,6 +65,7 @@ func ToSpeciesResponse(s *types.SpeciesWithLegislation) *SpeciesResponse {
 		Family:         s.Family,
 		PopularName:    s.PopularName,
 		Habit:          s.Habit,
+		WoodDensity:    s.WoodDensity,
 		CreatedAt:      s.CreatedAt,
 		UpdatedAt:      s.UpdatedAt,
 		Legislations:   legislations,
@@ -7,57 +7,83 @@ import (
 	"github.com/ESG-Project/suassu-api/internal/app/types"
 )
 
-// CreateSpecimenRequest representa a requisição para criar um specimen
+// CreateSpecimenRequest representa a requisição para criar um specimen.
+// Os fustes vão como CAP em cm, o formato gravado e devolvido pelo GET; o protocolo de medição
+// da análise (DAP, mm e critério de inclusão) vale só para a importação da planilha.
 type CreateSpecimenRequest struct {
-	Portion         string    `json:"portion"`
-	Height          float64   `json:"height"`
-	Cap1            float64   `json:"cap1"`
-	Cap2            *float64  `json:"cap2,omitempty"`
-	Cap3            *float64  `json:"cap3,omitempty"`
-	Cap4            *float64  `json:"cap4,omitempty"`
-	Cap5            *float64  `json:"cap5,omitempty"`
-	Cap6            *float64  `json:"cap6,omitempty"`
-	RegisterDate    time.Time `json:"registerDate"`
-	PhytoAnalysisID string    `json:"phytoAnalysisId"`
-	SpecieID        string    `json:"specieId"`
+	Portion          string    `json:"portion"`
+	Height           *float64  `json:"height,omitempty"` // omitida = não medida (estimada pela relação hipsométrica)
+	Cap1             float64   `json:"cap1"`
+	Cap2             *float64  `json:"cap2,omitempty"`
+	Cap3             *float64  `json:"cap3,omitempty"`
+	Cap4             *float64  `json:"cap4,omitempty"`
+	Cap5             *float64  `json:"cap5,omitempty"`
+	Cap6             *float64  `json:"cap6,omitempty"`
+	RegisterDate     time.Time `json:"registerDate"`
+	PhytoAnalysisID  string    `json:"phytoAnalysisId"`
+	SpecieID         string    `json:"specieId"`
+	MorphospeciesID  *string   `json:"morphospeciesId,omitempty"` // alternativa a specieId: morfoespécie da análise
+	Latitude         *float64  `json:"latitude,omitempty"`        // WGS84, graus decimais
+	Longitude        *float64  `json:"longitude,omitempty"`
+	Tag              *string   `json:"tag,omitempty"`           // plaqueta da árvore (parcelas permanentes)
+	Status           string    `json:"status,omitempty"`        // alive (padrão), dead_standing, fallen ou resprouting
+	Phytosanitary    *string   `json:"phytosanitary,omitempty"` // good, fair ou poor
+	BifurcationNotes *string   `json:"bifurcationNotes,omitempty"`
+	Observations     *string   `json:"observations,omitempty"`
 }
 
-// UpdateSpecimenRequest representa a requisição para atualizar um specimen
+// UpdateSpecimenRequest representa a requisição para atualizar um specimen (CAP em cm, como na criação)
 type UpdateSpecimenRequest struct {
-	Portion      string    `json:"portion"`
-	Height       float64   `json:"height"`
-	Cap1         float64   `json:"cap1"`
-	Cap2         *float64  `json:"cap2,omitempty"`
-	Cap3         *float64  `json:"cap3,omitempty"`
-	Cap4         *float64  `json:"cap4,omitempty"`
-	Cap5         *float64  `json:"cap5,omitempty"`
-	Cap6         *float64  `json:"cap6,omitempty"`
-	RegisterDate time.Time `json:"registerDate"`
-	SpecieID     string    `json:"specieId"`
+	Portion          string    `json:"portion"`
+	Height           *float64  `json:"height,omitempty"`
+	Cap1             float64   `json:"cap1"`
+	Cap2             *float64  `json:"cap2,omitempty"`
+	Cap3             *float64  `json:"cap3,omitempty"`
+	Cap4             *float64  `json:"cap4,omitempty"`
+	Cap5             *float64  `json:"cap5,omitempty"`
+	Cap6             *float64  `json:"cap6,omitempty"`
+	RegisterDate     time.Time `json:"registerDate"`
+	SpecieID         string    `json:"specieId"`
+	MorphospeciesID  *string   `json:"morphospeciesId,omitempty"`
+	Latitude         *float64  `json:"latitude,omitempty"`
+	Longitude        *float64  `json:"longitude,omitempty"`
+	Tag              *string   `json:"tag,omitempty"`
+	Status           string    `json:"status,omitempty"`
+	Phytosanitary    *string   `json:"phytosanitary,omitempty"`
+	BifurcationNotes *string   `json:"bifurcationNotes,omitempty"`
+	Observations     *string   `json:"observations,omitempty"`
 }
 
 // SpecimenResponse representa a resposta de um specimen
 type SpecimenResponse struct {
-	ID              string    `json:"id"`
-	Portion         string    `json:"portion"`
-	Height          float64   `json:"height"`
-	Cap1            float64   `json:"cap1"`
-	Cap2            *float64  `json:"cap2,omitempty"`
-	Cap3            *float64  `json:"cap3,omitempty"`
-	Cap4            *float64  `json:"cap4,omitempty"`
-	Cap5            *float64  `json:"cap5,omitempty"`
-	Cap6            *float64  `json:"cap6,omitempty"`
-	RegisterDate    time.Time `json:"registerDate"`
-	PhytoAnalysisID string    `json:"phytoAnalysisId"`
-	SpecieID        string    `json:"specieId"`
-	ScientificName  string    `json:"scientificName"`
-	Family          string    `json:"family"`
-	PopularName     *string   `json:"popularName,omitempty"`
-	CreatedAt       time.Time `json:"createdAt"`
-	UpdatedAt       time.Time `json:"updatedAt"`
-	VolumeM3        float64   `json:"volumeM3"`
-	DbhCm           float64   `json:"dbhCm"`
-	BasalAreaM2     float64   `json:"basalAreaM2"`
+	ID               string    `json:"id"`
+	Portion          string    `json:"portion"`
+	Height           *float64  `json:"height"` // null quando não medida
+	Cap1             float64   `json:"cap1"`
+	Cap2             *float64  `json:"cap2,omitempty"`
+	Cap3             *float64  `json:"cap3,omitempty"`
+	Cap4             *float64  `json:"cap4,omitempty"`
+	Cap5             *float64  `json:"cap5,omitempty"`
+	Cap6             *float64  `json:"cap6,omitempty"`
+	RegisterDate     time.Time `json:"registerDate"`
+	PhytoAnalysisID  string    `json:"phytoAnalysisId"`
+	SpecieID         string    `json:"specieId"`
+	MorphospeciesID  *string   `json:"morphospeciesId,omitempty"`
+	ScientificName   string    `json:"scientificName"`
+	Family           string    `json:"family"`
+	PopularName      *string   `json:"popularName,omitempty"`
+	Latitude         *float64  `json:"latitude,omitempty"`
+	Longitude        *float64  `json:"longitude,omitempty"`
+	Tag              *string   `json:"tag,omitempty"`
+	Status           string    `json:"status"`
+	Phytosanitary    *string   `json:"phytosanitary,omitempty"`
+	BifurcationNotes *string   `json:"bifurcationNotes,omitempty"`
+	Observations     *string   `json:"observations,omitempty"`
+	CreatedAt        time.Time `json:"createdAt"`
+	UpdatedAt        time.Time `json:"updatedAt"`
+	VolumeM3         float64   `json:"volumeM3"`
+	DbhCm            float64   `json:"dbhCm"`
+	BasalAreaM2      float64   `json:"basalAreaM2"`
 }
 
 // ToSpecimenResponse converte tipos internos para resposta HTTP
@@ -66,27 +92,41 @@ func ToSpecimenResponse(s *types.SpecimenWithSpecies) *SpecimenResponse {
 	abi := calcABI(s) // cm²
 	dbhCm, basalM2 := calcDbhAndBasalFromABI(abi)
 
+	var height *float64
+	if s.Height > 0 && !s.HeightEstimated {
+		h := s.Height
+		height = &h
+	}
+
 	return &SpecimenResponse{
-		ID:              s.ID,
-		Portion:         s.Portion,
-		Height:          s.Height,
-		Cap1:            s.Cap1,
-		Cap2:            s.Cap2,
-		Cap3:            s.Cap3,
-		Cap4:            s.Cap4,
-		Cap5:            s.Cap5,
-		Cap6:            s.Cap6,
-		RegisterDate:    s.RegisterDate,
-		PhytoAnalysisID: s.PhytoAnalysisID,
-		SpecieID:        s.SpecieID,
-		ScientificName:  s.ScientificName,
-		Family:          s.Family,
-		PopularName:     s.PopularName,
-		CreatedAt:       s.CreatedAt,
-		UpdatedAt:       s.UpdatedAt,
-		VolumeM3:        volumeM3,
-		DbhCm:           dbhCm,
-		BasalAreaM2:     basalM2,
+		ID:               s.ID,
+		Portion:          s.Portion,
+		Height:           height,
+		Cap1:             s.Cap1,
+		Cap2:             s.Cap2,
+		Cap3:             s.Cap3,
+		Cap4:             s.Cap4,
+		Cap5:             s.Cap5,
+		Cap6:             s.Cap6,
+		RegisterDate:     s.RegisterDate,
+		PhytoAnalysisID:  s.PhytoAnalysisID,
+		SpecieID:         s.SpecieID,
+		MorphospeciesID:  s.MorphospeciesID,
+		ScientificName:   s.ScientificName,
+		Family:           s.Family,
+		PopularName:      s.PopularName,
+		Latitude:         s.Latitude,
+		Longitude:        s.Longitude,
+		Tag:              s.Tag,
+		Status:           s.Status,
+		Phytosanitary:    s.Phytosanitary,
+		BifurcationNotes: s.BifurcationNotes,
+		Observations:     s.Observations,
+		CreatedAt:        s.CreatedAt,
+		UpdatedAt:        s.UpdatedAt,
+		VolumeM3:         volumeM3,
+		DbhCm:            dbhCm,
+		BasalAreaM2:      basalM2,
 	}
 }
 
@@ -25,7 +25,23 @@ func Handle(w http.ResponseWriter, r *http.Request, err error) {
 	reqID := chimw.GetReqID(r.Context())
 	status := mapStatus(apperr.CodeOf(err), err)
 
-	// Monta payload padronizado
+	payload := buildPayload(reqID, err)
+	logError("http_error", reqID, status, err)
+
+	writeJSON(w, status, reqID, payload)
+}
+
+// HandleStream trata um erro ocorrido depois do início de uma resposta em streaming: o status
+// já foi enviado, então o erro é apenas logado e o payload padronizado é retornado para que o
+// handler o grave no formato da transmissão.
+func HandleStream(r *http.Request, err error) map[string]any {
+	reqID := chimw.GetReqID(r.Context())
+	logError("http_stream_error", reqID, mapStatus(apperr.CodeOf(err), err), err)
+	return buildPayload(reqID, err)
+}
+
+// buildPayload monta o payload padronizado do erro
+func buildPayload(reqID string, err error) map[string]any {
 	payload := map[string]any{
 		"error": map[string]any{
 			"code":    string(apperr.CodeOf(err)),
@@ -41,21 +57,24 @@ func Handle(w http.ResponseWriter, r *http.Request, err error) {
 	if errors.As(err, &ae) && ae.Fields != nil {
 		payload["error"].(map[string]any)["details"] = ae.Fields
 	}
+	return payload
+}
 
-	// log estruturado (sem vazar sensíveis)
-	if logger != nil {
-		fields := []zap.Field{
-			zap.String("code", string(apperr.CodeOf(err))),
-			zap.Int("status", status),
-			zap.String("request_id", reqID),
-		}
-		if ae != nil && ae.Fields != nil {
-			fields = append(fields, zap.Any("fields", ae.Fields))
-		}
-		logger.Error("http_error", append(fields, zap.Error(err))...)
+// logError registra o erro de forma estruturada (sem vazar sensíveis)
+func logError(msg, reqID string, status int, err error) {
+	if logger == nil {
+		return
 	}
-
-	writeJSON(w, status, reqID, payload)
+	fields := []zap.Field{
+		zap.String("code", string(apperr.CodeOf(err))),
+		zap.Int("status", status),
+		zap.String("request_id", reqID),
+	}
+	var ae *apperr.Error
+	if errors.As(err, &ae) && ae.Fields != nil {
+		fields = append(fields, zap.Any("fields", ae.Fields))
+	}
+	logger.Error(msg, append(fields, zap.Error(err))...)
 }
 
 func mapStatus(code apperr.Code, _ error) int {
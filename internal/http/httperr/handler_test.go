@@ -8,6 +8,8 @@ import (
 
 	"github.com/ESG-Project/suassu-api/internal/apperr"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 func TestHandle(t *testing.T) {
@@ -100,6 +102,24 @@ func TestPublicMessage(t *testing.T) {
 	})
 }
 
+func TestHandleStream(t *testing.T) {
+	core, logs := observer.New(zap.ErrorLevel)
+	SetLogger(zap.New(core))
+	defer SetLogger(nil)
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	payload := HandleStream(req, apperr.New(apperr.CodeInternal, "stream failed"))
+
+	errorObj := payload["error"].(map[string]any)
+	require.Equal(t, "internal", errorObj["code"])
+	require.Equal(t, "stream failed", errorObj["message"])
+	require.Contains(t, payload, "meta")
+
+	entries := logs.FilterMessage("http_stream_error").All()
+	require.Len(t, entries, 1)
+	require.Equal(t, int64(http.StatusInternalServerError), entries[0].ContextMap()["status"])
+}
+
 func TestWriteJSON(t *testing.T) {
 	t.Run("writes JSON response", func(t *testing.T) {
 		w := httptest.NewRecorder()
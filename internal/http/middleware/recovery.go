@@ -20,6 +20,11 @@ func RecoveryWithLogger(logger *zap.Logger) func(http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			defer func() {
 				if rvr := recover(); rvr != nil {
+					// Aborto intencional (ex.: transmissão interrompida): o servidor encerra a conexão
+					if rvr == http.ErrAbortHandler {
+						panic(rvr)
+					}
+
 					// Log estruturado com contexto completo
 					logger.Error("panic_recovered",
 						zap.Any("panic_value", rvr),
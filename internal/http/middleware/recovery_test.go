@@ -118,4 +118,22 @@ func TestRecoveryWithLogger(t *testing.T) {
 		// O middleware deve ter logado o panic com contexto completo
 		// (teste de integração seria necessário para verificar logs)
 	})
+
+	t.Run("re-panics intentional aborts", func(t *testing.T) {
+		logger := zaptest.NewLogger(t)
+		recovery := RecoveryWithLogger(logger)
+
+		abortHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			panic(http.ErrAbortHandler)
+		})
+
+		req := httptest.NewRequest("GET", "/test", nil)
+		w := httptest.NewRecorder()
+
+		// O servidor HTTP trata o ErrAbortHandler encerrando a conexão
+		require.PanicsWithValue(t, http.ErrAbortHandler, func() {
+			recovery(abortHandler).ServeHTTP(w, req)
+		})
+	})
 }
@@ -0,0 +1,109 @@
+package equationhttp
+
+import (
+	"encoding/json"
+	"net/http"
+
+	appequation "github.com/ESG-Project/suassu-api/internal/app/equation"
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+	"github.com/ESG-Project/suassu-api/internal/apperr"
+	equationdto "github.com/ESG-Project/suassu-api/internal/http/dto/equation"
+	"github.com/ESG-Project/suassu-api/internal/http/httperr"
+	"github.com/ESG-Project/suassu-api/internal/http/response"
+	"github.com/go-chi/chi/v5"
+)
+
+// Service define a interface do serviço de equações para a camada HTTP
+type Service = appequation.ServiceInterface
+
+func Routes(svc Service) chi.Router {
+	r := chi.NewRouter()
+
+	// GET /equations?kind=volume&state=MG&vegetationType=...&habit=ARVORE&family=... - Listar equações
+	// Inclui as embutidas no motor; listas de aplicabilidade vazias atendem a qualquer filtro
+	r.Get("/", func(w http.ResponseWriter, req *http.Request) {
+		q := req.URL.Query()
+		f := types.EquationFilter{
+			Kind:           q.Get("kind"),
+			State:          q.Get("state"),
+			VegetationType: q.Get("vegetationType"),
+			Habit:          q.Get("habit"),
+			Family:         q.Get("family"),
+		}
+		if f.Kind != "" && f.Kind != types.EquationKindVolume && f.Kind != types.EquationKindBiomass {
+			httperr.Handle(w, req, apperr.New(apperr.CodeInvalid, "invalid kind"))
+			return
+		}
+
+		list, err := svc.List(req.Context(), f)
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, list, nil)
+	})
+
+	// GET /equations/templates - Modelos usuais (Schumacher-Hall, Spurr...) para cadastro com coeficientes
+	r.Get("/templates", func(w http.ResponseWriter, req *http.Request) {
+		response.JSON(w, http.StatusOK, svc.Templates(), nil)
+	})
+
+	// GET /equations/{id} - Buscar equação por ID
+	r.Get("/{id}", func(w http.ResponseWriter, req *http.Request) {
+		eq, err := svc.GetByID(req.Context(), chi.URLParam(req, "id"))
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, eq, nil)
+	})
+
+	// POST /equations - Cadastrar equação (a expressão é validada na criação)
+	r.Post("/", func(w http.ResponseWriter, req *http.Request) {
+		var in equationdto.EquationRequest
+		if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+			httperr.Handle(w, req, apperr.New(apperr.CodeInvalid, "invalid body"))
+			return
+		}
+
+		eq, err := svc.Create(req.Context(), in.ToInput())
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		response.JSON(w, http.StatusCreated, eq, nil)
+	})
+
+	// PUT /equations/{id} - Alterar equação cadastrada
+	// Análises que já a selecionaram mantêm a definição copiada na seleção
+	r.Put("/{id}", func(w http.ResponseWriter, req *http.Request) {
+		var in equationdto.EquationRequest
+		if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+			httperr.Handle(w, req, apperr.New(apperr.CodeInvalid, "invalid body"))
+			return
+		}
+
+		eq, err := svc.Update(req.Context(), chi.URLParam(req, "id"), in.ToInput())
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, eq, nil)
+	})
+
+	// DELETE /equations/{id} - Remover equação cadastrada
+	r.Delete("/{id}", func(w http.ResponseWriter, req *http.Request) {
+		if err := svc.Delete(req.Context(), chi.URLParam(req, "id")); err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, map[string]string{"message": "deleted"}, nil)
+	})
+
+	return r
+}
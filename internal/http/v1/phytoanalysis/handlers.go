@@ -2,13 +2,21 @@ package phytoanalysishttp
 
 import (
 	"encoding/json"
+	"math"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	appphyto "github.com/ESG-Project/suassu-api/internal/app/phytoanalysis"
+	"github.com/ESG-Project/suassu-api/internal/app/phytometrics"
+	"github.com/ESG-Project/suassu-api/internal/app/types"
 	"github.com/ESG-Project/suassu-api/internal/apperr"
+	domainspecies "github.com/ESG-Project/suassu-api/internal/domain/species"
 	phytodto "github.com/ESG-Project/suassu-api/internal/http/dto/phytoanalysis"
 	"github.com/ESG-Project/suassu-api/internal/http/httperr"
+	httpmw "github.com/ESG-Project/suassu-api/internal/http/middleware"
+	"github.com/ESG-Project/suassu-api/internal/http/pagination"
 	"github.com/ESG-Project/suassu-api/internal/http/response"
 	"github.com/go-chi/chi/v5"
 )
@@ -19,7 +27,7 @@ type Service = appphyto.ServiceInterface
 func Routes(svc Service) chi.Router {
 	r := chi.NewRouter()
 
-	// POST /phyto-analyses - Criar nova análise
+	// POST /phyto-analyses?dryRun=true - Criar nova análise (dryRun apenas valida e retorna o relatório)
 	r.Post("/", func(w http.ResponseWriter, req *http.Request) {
 		var in phytodto.CreatePhytoAnalysisRequest
 		if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
@@ -28,104 +36,858 @@ func Routes(svc Service) chi.Router {
 		}
 
 		// Converter DTO para input do serviço
-		specimens := make([]appphyto.SpecimenInput, 0, len(in.Specimens))
-		for _, s := range in.Specimens {
-			specimens = append(specimens, appphyto.SpecimenInput{
-				Portion:        s.Portion,
-				Height:         s.Height,
-				Cap1:           s.Cap1,
-				Cap2:           s.Cap2,
-				Cap3:           s.Cap3,
-				Cap4:           s.Cap4,
-				Cap5:           s.Cap5,
-				Cap6:           s.Cap6,
-				RegisterDate:   s.RegisterDate,
-				ScientificName: s.ScientificName,
-			})
+		createInput := toCreateInput(in, toSpecimenInputs(in.Specimens))
+
+		if isDryRun(req) {
+			report, err := svc.ValidateCreate(req.Context(), createInput)
+			if err != nil {
+				httperr.Handle(w, req, err)
+				return
+			}
+			response.JSON(w, http.StatusOK, phytodto.ToSpecimenImportReportResponse(report), nil)
+			return
+		}
+
+		id, err := svc.Create(req.Context(), createInput)
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		response.JSON(w, http.StatusCreated, map[string]string{"id": id}, nil)
+	})
+
+	// POST /phyto-analyses/import?dryRun=true - Criar análise a partir do template XLSX
+	// multipart: "data" (JSON com os campos da análise) + "file" (planilha)
+	r.Post("/import", func(w http.ResponseWriter, req *http.Request) {
+		specimens, err := readImportFile(w, req)
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		var in phytodto.CreatePhytoAnalysisRequest
+		if err := json.Unmarshal([]byte(req.FormValue("data")), &in); err != nil {
+			httperr.Handle(w, req, apperr.New(apperr.CodeInvalid, "invalid data field"))
+			return
+		}
+
+		createInput := toCreateInput(in, specimens)
+
+		if isDryRun(req) {
+			report, err := svc.ValidateCreate(req.Context(), createInput)
+			if err != nil {
+				httperr.Handle(w, req, err)
+				return
+			}
+			response.JSON(w, http.StatusOK, phytodto.ToSpecimenImportReportResponse(report), nil)
+			return
+		}
+
+		id, err := svc.Create(req.Context(), createInput)
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		response.JSON(w, http.StatusCreated, map[string]string{"id": id}, nil)
+	})
+
+	// GET /phyto-analyses/project/:projectId - Listar análises por projeto
+	r.Get("/project/{projectId}", func(w http.ResponseWriter, req *http.Request) {
+		projectID := chi.URLParam(req, "projectId")
+
+		list, err := svc.ListByProject(req.Context(), projectID)
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		phytoList := make([]*phytodto.PhytoAnalysisResponse, 0, len(list))
+		for _, p := range list {
+			phytoList = append(phytoList, phytodto.ToPhytoAnalysisResponse(p))
+		}
+
+		response.JSON(w, http.StatusOK, phytoList, nil)
+	})
+
+	// GET /phyto-analyses/enterprise/:enterpriseId - Listar análises por enterprise
+	r.Get("/enterprise/{enterpriseId}", func(w http.ResponseWriter, req *http.Request) {
+		enterpriseID := chi.URLParam(req, "enterpriseId")
+
+		list, err := svc.ListByEnterprise(req.Context(), enterpriseID)
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		phytoList := make([]*phytodto.PhytoAnalysisResponse, 0, len(list))
+		for _, p := range list {
+			phytoList = append(phytoList, phytodto.ToPhytoAnalysisResponse(p))
+		}
+
+		response.JSON(w, http.StatusOK, phytoList, nil)
+	})
+
+	// GET /phyto-analyses/import-template - Download template XLSX
+	r.Get("/import-template", func(w http.ResponseWriter, req *http.Request) {
+		data, err := phytoTemplatesFS.ReadFile("templates/specimens_import_template.xlsx")
+		if err != nil {
+			httperr.Handle(w, req, apperr.New(apperr.CodeNotFound, "import template not found"))
+			return
+		}
+
+		w.Header().Set("Content-Disposition", "attachment; filename=specimens_import_template.xlsx")
+		w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(data)
+	})
+
+	// GET /phyto-analyses/similarity?ids=a,b,c&index=jaccard
+	// Similaridade florística (Jaccard e Sørensen) entre análises da empresa e dendrograma UPGMA
+	r.Get("/similarity", func(w http.ResponseWriter, req *http.Request) {
+		enterpriseID := httpmw.EnterpriseID(req.Context())
+
+		index := req.URL.Query().Get("index")
+		switch index {
+		case "":
+			index = phytodto.SimilarityIndexJaccard
+		case phytodto.SimilarityIndexJaccard, phytodto.SimilarityIndexSorensen:
+		default:
+			httperr.Handle(w, req, apperr.New(apperr.CodeInvalid, "invalid index"))
+			return
+		}
+
+		ids := strings.Split(req.URL.Query().Get("ids"), ",")
+
+		analyses, err := svc.ListWithSpecimens(req.Context(), enterpriseID, ids)
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, phytodto.ToFloristicSimilarityResponse(analyses, index), nil)
+	})
+
+	// GET /phyto-analyses/:id?includeSpecimens=false - Buscar análise por ID
+	// Sem os espécimes, retorna apenas os dados da análise e os indicadores do snapshot
+	r.Get("/{id}", func(w http.ResponseWriter, req *http.Request) {
+		id := chi.URLParam(req, "id")
+
+		includeSpecimens := true
+		if raw := req.URL.Query().Get("includeSpecimens"); raw != "" {
+			v, err := strconv.ParseBool(raw)
+			if err != nil {
+				httperr.Handle(w, req, apperr.New(apperr.CodeInvalid, "invalid includeSpecimens"))
+				return
+			}
+			includeSpecimens = v
+		}
+
+		var phyto *types.PhytoAnalysisComplete
+		var err error
+		if includeSpecimens {
+			phyto, err = svc.GetWithSpecimens(req.Context(), id)
+		} else {
+			phyto, err = svc.GetComplete(req.Context(), id)
+		}
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		snapshot, err := svc.GetIndicators(req.Context(), id)
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		out := phytodto.ToPhytoAnalysisCompleteResponse(phyto, snapshot)
+		response.JSON(w, http.StatusOK, out, nil)
+	})
+
+	// GET /phyto-analyses/:id/indicators - Indicadores do snapshot mais recente
+	r.Get("/{id}/indicators", func(w http.ResponseWriter, req *http.Request) {
+		id := chi.URLParam(req, "id")
+
+		snapshot, err := svc.GetIndicators(req.Context(), id)
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, phytodto.ToIndicatorSnapshotResponse(snapshot), nil)
+	})
+
+	// GET /phyto-analyses/:id/indicators/snapshots - Versões dos indicadores (mais recente primeiro)
+	r.Get("/{id}/indicators/snapshots", func(w http.ResponseWriter, req *http.Request) {
+		id := chi.URLParam(req, "id")
+
+		snapshots, err := svc.ListIndicatorSnapshots(req.Context(), id)
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, phytodto.ToIndicatorSnapshotInfoList(snapshots), nil)
+	})
+
+	// GET /phyto-analyses/:id/indicators/snapshots/:version - Indicadores de uma versão
+	r.Get("/{id}/indicators/snapshots/{version}", func(w http.ResponseWriter, req *http.Request) {
+		id := chi.URLParam(req, "id")
+
+		version, err := strconv.Atoi(chi.URLParam(req, "version"))
+		if err != nil {
+			httperr.Handle(w, req, apperr.New(apperr.CodeInvalid, "invalid version"))
+			return
+		}
+
+		snapshot, err := svc.GetIndicatorSnapshot(req.Context(), id, version)
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, phytodto.ToIndicatorSnapshotResponse(snapshot), nil)
+	})
+
+	// POST /phyto-analyses/:id/indicators/recompute - Recalcula e compara com o snapshot anterior
+	r.Post("/{id}/indicators/recompute", func(w http.ResponseWriter, req *http.Request) {
+		id := chi.URLParam(req, "id")
+
+		rec, err := svc.RecomputeIndicators(req.Context(), id)
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		status := http.StatusOK
+		if rec.Created {
+			status = http.StatusCreated
+		}
+		response.JSON(w, status, phytodto.ToRecomputeIndicatorsResponse(rec), nil)
+	})
+
+	// GET /phyto-analyses/:id/biomass?equation=chave2014&carbonFraction=0.47&rootShootRatio=0.24&woodDensity=0.6
+	// Biomassa e carbono com equação e fatores escolhidos (o snapshot usa a equação selecionada e os padrões)
+	r.Get("/{id}/biomass", func(w http.ResponseWriter, req *http.Request) {
+		id := chi.URLParam(req, "id")
+
+		opts, err := parseBiomassOptions(req, svc)
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		phyto, err := svc.GetForIndicators(req.Context(), id)
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, phytodto.ToBiomassResponse(phyto, opts), nil)
+	})
+
+	// GET /phyto-analyses/:id/equations?vegetationType=... - Equações selecionadas e sugestões do cadastro
+	// (filtradas pelo estado do projeto, das mais específicas para as genéricas)
+	r.Get("/{id}/equations", func(w http.ResponseWriter, req *http.Request) {
+		id := chi.URLParam(req, "id")
+		q := req.URL.Query()
+
+		phyto, err := svc.GetComplete(req.Context(), id)
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		suggestions, err := svc.SuggestEquations(req.Context(), id, types.EquationFilter{
+			Kind:           q.Get("kind"),
+			VegetationType: q.Get("vegetationType"),
+		})
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, phytodto.ToAnalysisEquationsResponse(phyto, suggestions), nil)
+	})
+
+	// PUT /phyto-analyses/:id/equations - Seleciona a equação de volume ou biomassa (pelo tipo da equação)
+	// e recalcula os indicadores
+	r.Put("/{id}/equations", func(w http.ResponseWriter, req *http.Request) {
+		id := chi.URLParam(req, "id")
+
+		var in phytodto.SelectEquationRequest
+		if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+			httperr.Handle(w, req, apperr.New(apperr.CodeInvalid, "invalid body"))
+			return
+		}
+
+		snapshot, err := svc.SelectEquation(req.Context(), id, in.EquationID)
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, phytodto.ToIndicatorSnapshotResponse(snapshot), nil)
+	})
+
+	// DELETE /phyto-analyses/:id/equations/:kind - Volta ao cálculo padrão (volume ou biomass)
+	r.Delete("/{id}/equations/{kind}", func(w http.ResponseWriter, req *http.Request) {
+		id := chi.URLParam(req, "id")
+
+		snapshot, err := svc.ClearEquation(req.Context(), id, chi.URLParam(req, "kind"))
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, phytodto.ToIndicatorSnapshotResponse(snapshot), nil)
+	})
+
+	// GET /phyto-analyses/:id/assortment - Regras de classificação da madeira suprimida (tora e lenha)
+	r.Get("/{id}/assortment", func(w http.ResponseWriter, req *http.Request) {
+		id := chi.URLParam(req, "id")
+
+		phyto, err := svc.GetComplete(req.Context(), id)
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, phytometrics.ResolveAssortment(phyto), nil)
+	})
+
+	// PUT /phyto-analyses/:id/assortment - Define as regras de tora e lenha e recalcula os indicadores
+	r.Put("/{id}/assortment", func(w http.ResponseWriter, req *http.Request) {
+		id := chi.URLParam(req, "id")
+
+		var in phytodto.AssortmentRulesDTO
+		if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+			httperr.Handle(w, req, apperr.New(apperr.CodeInvalid, "invalid body"))
+			return
+		}
+
+		snapshot, err := svc.SetAssortment(req.Context(), id, in)
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, phytodto.ToIndicatorSnapshotResponse(snapshot), nil)
+	})
+
+	// DELETE /phyto-analyses/:id/assortment - Volta às regras padrão do motor
+	r.Delete("/{id}/assortment", func(w http.ResponseWriter, req *http.Request) {
+		id := chi.URLParam(req, "id")
+
+		snapshot, err := svc.ClearAssortment(req.Context(), id)
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, phytodto.ToIndicatorSnapshotResponse(snapshot), nil)
+	})
+
+	// GET /phyto-analyses/:id/suppression - Relatório de supressão (ASV): volume por produto (tora e
+	// lenha, em m³ e mst), por espécie e das espécies protegidas ou ameaçadas, extrapolado para a área total.
+	// Usa todos os espécimes: as árvores mortas também são suprimidas, mesmo fora dos indicadores.
+	r.Get("/{id}/suppression", func(w http.ResponseWriter, req *http.Request) {
+		id := chi.URLParam(req, "id")
+
+		phyto, err := svc.GetWithSpecimens(req.Context(), id)
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, phytodto.ToSuppressionReportResponse(phyto), nil)
+	})
+
+	// GET /phyto-analyses/:id/compensation - Plantio compensatório pelas regras padrão: mudas por
+	// espécie protegida ou ameaçada suprimida e no total, com a regra e a legislação de cada linha
+	r.Get("/{id}/compensation", func(w http.ResponseWriter, req *http.Request) {
+		id := chi.URLParam(req, "id")
+
+		phyto, err := svc.GetWithSpecimens(req.Context(), id)
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, phytodto.ToCompensationResponse(phyto, nil), nil)
+	})
+
+	// POST /phyto-analyses/:id/compensation - Simula o plantio compensatório com as regras informadas
+	r.Post("/{id}/compensation", func(w http.ResponseWriter, req *http.Request) {
+		id := chi.URLParam(req, "id")
+
+		var in phytodto.CompensationRequest
+		if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+			httperr.Handle(w, req, apperr.New(apperr.CodeInvalid, "invalid body"))
+			return
+		}
+		if len(in.Rules) > 0 {
+			if err := phytometrics.ValidateCompensationRules(in.Rules); err != nil {
+				httperr.Handle(w, req, apperr.New(apperr.CodeInvalid, err.Error()))
+				return
+			}
+		}
+
+		phyto, err := svc.GetWithSpecimens(req.Context(), id)
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, phytodto.ToCompensationResponse(phyto, in.Rules), nil)
+	})
+
+	// GET /phyto-analyses/:id/hypsometry - Relação hipsométrica: modelos ajustados com as árvores de
+	// altura medida (Curtis, Henricksen, Prodan, Stoffels), o selecionado e as alturas estimadas
+	r.Get("/{id}/hypsometry", func(w http.ResponseWriter, req *http.Request) {
+		id := chi.URLParam(req, "id")
+
+		h, err := svc.GetHypsometry(req.Context(), id)
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, h, nil)
+	})
+
+	// GET /phyto-analyses/:id/plots - Parcelas da análise com as métricas por parcela
+	// (indivíduos, riqueza, DAP e altura médios, área basal e volume, por parcela e por hectare)
+	r.Get("/{id}/plots", func(w http.ResponseWriter, req *http.Request) {
+		id := chi.URLParam(req, "id")
+
+		phyto, err := svc.GetForIndicators(req.Context(), id)
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, phytodto.ToPlotsResponse(phyto), nil)
+	})
+
+	// POST /phyto-analyses/:id/plots - Cadastra uma parcela (recalcula o número de parcelas e as áreas)
+	r.Post("/{id}/plots", func(w http.ResponseWriter, req *http.Request) {
+		id := chi.URLParam(req, "id")
+
+		var in phytodto.PlotRequest
+		if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+			httperr.Handle(w, req, apperr.New(apperr.CodeInvalid, "invalid body"))
+			return
+		}
+
+		plotID, err := svc.CreatePlot(req.Context(), id, toPlotInput(in))
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		response.JSON(w, http.StatusCreated, map[string]string{"id": plotID}, nil)
+	})
+
+	// PUT /phyto-analyses/:id/plots/:plotId - Atualiza uma parcela (o novo código é aplicado aos espécimes)
+	r.Put("/{id}/plots/{plotId}", func(w http.ResponseWriter, req *http.Request) {
+		id := chi.URLParam(req, "id")
+
+		var in phytodto.PlotRequest
+		if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+			httperr.Handle(w, req, apperr.New(apperr.CodeInvalid, "invalid body"))
+			return
+		}
+
+		if err := svc.UpdatePlot(req.Context(), id, chi.URLParam(req, "plotId"), toPlotInput(in)); err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, map[string]string{"message": "updated"}, nil)
+	})
+
+	// DELETE /phyto-analyses/:id/plots/:plotId - Remove uma parcela sem espécimes
+	r.Delete("/{id}/plots/{plotId}", func(w http.ResponseWriter, req *http.Request) {
+		id := chi.URLParam(req, "id")
+
+		if err := svc.DeletePlot(req.Context(), id, chi.URLParam(req, "plotId")); err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, map[string]string{"message": "deleted"}, nil)
+	})
+
+	// GET /phyto-analyses/:id/strata - Estratos da análise
+	r.Get("/{id}/strata", func(w http.ResponseWriter, req *http.Request) {
+		id := chi.URLParam(req, "id")
+
+		phyto, err := svc.GetComplete(req.Context(), id)
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, phyto.Strata, nil)
+	})
+
+	// POST /phyto-analyses/:id/strata - Cadastra um estrato (a área total passa a ser a soma dos estratos)
+	r.Post("/{id}/strata", func(w http.ResponseWriter, req *http.Request) {
+		id := chi.URLParam(req, "id")
+
+		var in phytodto.StratumRequest
+		if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+			httperr.Handle(w, req, apperr.New(apperr.CodeInvalid, "invalid body"))
+			return
+		}
+
+		stratumID, err := svc.CreateStratum(req.Context(), id, toStratumInput(in))
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		response.JSON(w, http.StatusCreated, map[string]string{"id": stratumID}, nil)
+	})
+
+	// PUT /phyto-analyses/:id/strata/:stratumId - Atualiza um estrato
+	r.Put("/{id}/strata/{stratumId}", func(w http.ResponseWriter, req *http.Request) {
+		id := chi.URLParam(req, "id")
+
+		var in phytodto.StratumRequest
+		if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+			httperr.Handle(w, req, apperr.New(apperr.CodeInvalid, "invalid body"))
+			return
+		}
+
+		if err := svc.UpdateStratum(req.Context(), id, chi.URLParam(req, "stratumId"), toStratumInput(in)); err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, map[string]string{"message": "updated"}, nil)
+	})
+
+	// DELETE /phyto-analyses/:id/strata/:stratumId - Remove um estrato sem parcelas
+	r.Delete("/{id}/strata/{stratumId}", func(w http.ResponseWriter, req *http.Request) {
+		id := chi.URLParam(req, "id")
+
+		if err := svc.DeleteStratum(req.Context(), id, chi.URLParam(req, "stratumId")); err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, map[string]string{"message": "deleted"}, nil)
+	})
+
+	// GET /phyto-analyses/:id/morphospecies - Morfoespécies da análise com a contagem de indivíduos
+	r.Get("/{id}/morphospecies", func(w http.ResponseWriter, req *http.Request) {
+		id := chi.URLParam(req, "id")
+
+		list, err := svc.ListMorphospecies(req.Context(), id)
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, list, nil)
+	})
+
+	// POST /phyto-analyses/:id/morphospecies - Cadastra uma morfoespécie
+	r.Post("/{id}/morphospecies", func(w http.ResponseWriter, req *http.Request) {
+		id := chi.URLParam(req, "id")
+
+		var in phytodto.MorphospeciesRequest
+		if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+			httperr.Handle(w, req, apperr.New(apperr.CodeInvalid, "invalid body"))
+			return
+		}
+
+		morphospeciesID, err := svc.CreateMorphospecies(req.Context(), id, toMorphospeciesInput(in))
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		response.JSON(w, http.StatusCreated, map[string]string{"id": morphospeciesID}, nil)
+	})
+
+	// PUT /phyto-analyses/:id/morphospecies/:morphospeciesId - Atualiza uma morfoespécie
+	r.Put("/{id}/morphospecies/{morphospeciesId}", func(w http.ResponseWriter, req *http.Request) {
+		id := chi.URLParam(req, "id")
+
+		var in phytodto.MorphospeciesRequest
+		if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+			httperr.Handle(w, req, apperr.New(apperr.CodeInvalid, "invalid body"))
+			return
+		}
+
+		if err := svc.UpdateMorphospecies(req.Context(), id, chi.URLParam(req, "morphospeciesId"), toMorphospeciesInput(in)); err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, map[string]string{"message": "updated"}, nil)
+	})
+
+	// DELETE /phyto-analyses/:id/morphospecies/:morphospeciesId - Remove uma morfoespécie sem indivíduos
+	r.Delete("/{id}/morphospecies/{morphospeciesId}", func(w http.ResponseWriter, req *http.Request) {
+		id := chi.URLParam(req, "id")
+
+		if err := svc.DeleteMorphospecies(req.Context(), id, chi.URLParam(req, "morphospeciesId")); err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, map[string]string{"message": "deleted"}, nil)
+	})
+
+	// POST /phyto-analyses/:id/morphospecies/:morphospeciesId/resolve - Determina a morfoespécie como
+	// espécie do catálogo: os indivíduos passam para a espécie e a morfoespécie é removida
+	r.Post("/{id}/morphospecies/{morphospeciesId}/resolve", func(w http.ResponseWriter, req *http.Request) {
+		id := chi.URLParam(req, "id")
+
+		var in phytodto.ResolveMorphospeciesRequest
+		if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+			httperr.Handle(w, req, apperr.New(apperr.CodeInvalid, "invalid body"))
+			return
+		}
+
+		relinked, err := svc.ResolveMorphospecies(req.Context(), id, chi.URLParam(req, "morphospeciesId"), in.SpeciesID)
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, map[string]int64{"relinked": relinked}, nil)
+	})
+
+	// GET /phyto-analyses/:id/campaigns - Campanhas da série de remedições (da primeira à última)
+	r.Get("/{id}/campaigns", func(w http.ResponseWriter, req *http.Request) {
+		id := chi.URLParam(req, "id")
+
+		campaigns, err := svc.ListCampaigns(req.Context(), id)
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		out := make([]*phytodto.PhytoAnalysisResponse, 0, len(campaigns))
+		for _, c := range campaigns {
+			out = append(out, phytodto.ToPhytoAnalysisResponse(c))
+		}
+		response.JSON(w, http.StatusOK, out, nil)
+	})
+
+	// POST /phyto-analyses/:id/campaigns - Cria a remedição das parcelas permanentes da análise
+	// (mesmo protocolo, equações, estratos e parcelas; espécimes importados com a plaqueta)
+	r.Post("/{id}/campaigns", func(w http.ResponseWriter, req *http.Request) {
+		id := chi.URLParam(req, "id")
+
+		var in phytodto.CreateCampaignRequest
+		if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+			httperr.Handle(w, req, apperr.New(apperr.CodeInvalid, "invalid body"))
+			return
 		}
 
-		createInput := appphyto.CreateInput{
-			Title:           in.Title,
-			InitialDate:     in.InitialDate,
-			PortionQuantity: in.PortionQuantity,
-			PortionArea:     in.PortionArea,
-			TotalArea:       in.TotalArea,
-			Description:     in.Description,
-			ProjectID:       in.ProjectID,
-			Specimens:       specimens,
+		campaignID, err := svc.CreateCampaign(req.Context(), id, appphyto.CampaignInput{
+			Title:       in.Title,
+			InitialDate: in.InitialDate,
+			Description: in.Description,
+		})
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
 		}
 
-		id, err := svc.Create(req.Context(), createInput)
+		response.JSON(w, http.StatusCreated, map[string]string{"id": campaignID}, nil)
+	})
+
+	// GET /phyto-analyses/:id/dynamics?previousId= - Dinâmica entre a análise e a campanha anterior:
+	// incremento diamétrico e em área basal, mortalidade, recrutamento e taxas anuais
+	r.Get("/{id}/dynamics", func(w http.ResponseWriter, req *http.Request) {
+		id := chi.URLParam(req, "id")
+
+		dynamics, err := svc.GetDynamics(req.Context(), id, req.URL.Query().Get("previousId"))
 		if err != nil {
 			httperr.Handle(w, req, err)
 			return
 		}
 
-		response.JSON(w, http.StatusCreated, map[string]string{"id": id}, nil)
+		response.JSON(w, http.StatusOK, dynamics, nil)
 	})
 
-	// GET /phyto-analyses/project/:projectId - Listar análises por projeto
-	r.Get("/project/{projectId}", func(w http.ResponseWriter, req *http.Request) {
-		projectID := chi.URLParam(req, "projectId")
+	// GET /phyto-analyses/:id/export?format=geojson|kml - Parcelas (polígono ou centro) e indivíduos
+	// georreferenciados com espécie e atributos dendrométricos, para uso em SIG (ex.: QGIS)
+	r.Get("/{id}/export", func(w http.ResponseWriter, req *http.Request) {
+		id := chi.URLParam(req, "id")
 
-		list, err := svc.ListByProject(req.Context(), projectID)
+		format := strings.ToLower(req.URL.Query().Get("format"))
+		if format == "" {
+			format = geoFormatGeoJSON
+		}
+		if format != geoFormatGeoJSON && format != geoFormatKML {
+			httperr.Handle(w, req, apperr.New(apperr.CodeInvalid, "invalid format"))
+			return
+		}
+
+		phyto, err := svc.GetWithSpecimens(req.Context(), id)
 		if err != nil {
 			httperr.Handle(w, req, err)
 			return
 		}
 
-		phytoList := make([]*phytodto.PhytoAnalysisResponse, 0, len(list))
-		for _, p := range list {
-			phytoList = append(phytoList, phytodto.ToPhytoAnalysisResponse(p))
+		fc := phytodto.ToGeoJSON(phyto)
+		w.Header().Set("Content-Disposition", "attachment; filename=phyto-analysis-"+id+"."+format)
+		if format == geoFormatKML {
+			w.Header().Set("Content-Type", "application/vnd.google-earth.kml+xml")
+			w.WriteHeader(http.StatusOK)
+			_ = phytodto.WriteKML(w, fc)
+			return
 		}
 
-		response.JSON(w, http.StatusOK, phytoList, nil)
+		w.Header().Set("Content-Type", "application/geo+json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(fc)
 	})
 
-	// GET /phyto-analyses/enterprise/:enterpriseId - Listar análises por enterprise
-	r.Get("/enterprise/{enterpriseId}", func(w http.ResponseWriter, req *http.Request) {
-		enterpriseID := chi.URLParam(req, "enterpriseId")
+	// GET /phyto-analyses/:id/distributions?dbhClassWidth=5&dbhMin=5&heightClassWidth=2&heightMin=0
+	// Distribuição diamétrica e de altura (indivíduos e área basal por hectare por classe)
+	r.Get("/{id}/distributions", func(w http.ResponseWriter, req *http.Request) {
+		id := chi.URLParam(req, "id")
 
-		list, err := svc.ListByEnterprise(req.Context(), enterpriseID)
+		opts, err := parseClassDistributionOptions(req)
 		if err != nil {
 			httperr.Handle(w, req, err)
 			return
 		}
 
-		phytoList := make([]*phytodto.PhytoAnalysisResponse, 0, len(list))
-		for _, p := range list {
-			phytoList = append(phytoList, phytodto.ToPhytoAnalysisResponse(p))
+		phyto, err := svc.GetForIndicators(req.Context(), id)
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
 		}
 
-		response.JSON(w, http.StatusOK, phytoList, nil)
+		out, err := phytodto.ToClassDistributionResponse(phyto, opts)
+		if err != nil {
+			httperr.Handle(w, req, apperr.New(apperr.CodeInvalid, err.Error()))
+			return
+		}
+
+		response.JSON(w, http.StatusOK, out, nil)
 	})
 
-	// GET /phyto-analyses/import-template - Download template XLSX
-	r.Get("/import-template", func(w http.ResponseWriter, req *http.Request) {
-		data, err := phytoTemplatesFS.ReadFile("templates/specimens_import_template.xlsx")
+	// GET /phyto-analyses/:id/sampling?probability=0.90&targetError=10
+	// Estatística da amostragem e suficiência amostral
+	r.Get("/{id}/sampling", func(w http.ResponseWriter, req *http.Request) {
+		id := chi.URLParam(req, "id")
+
+		opts, err := parseSamplingOptions(req)
 		if err != nil {
-			httperr.Handle(w, req, apperr.New(apperr.CodeNotFound, "import template not found"))
+			httperr.Handle(w, req, err)
 			return
 		}
 
-		w.Header().Set("Content-Disposition", "attachment; filename=specimens_import_template.xlsx")
-		w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
-		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write(data)
+		phyto, err := svc.GetForIndicators(req.Context(), id)
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, phytodto.ToSamplingStatisticsResponse(phyto, opts), nil)
 	})
 
-	// GET /phyto-analyses/:id - Buscar análise por ID
-	r.Get("/{id}", func(w http.ResponseWriter, req *http.Request) {
+	// GET /phyto-analyses/:id/sampling/stratified?probability=0.90&targetError=10
+	// Estimadores da amostragem estratificada e, por estrato, estatística e indicadores fitossociológicos
+	r.Get("/{id}/sampling/stratified", func(w http.ResponseWriter, req *http.Request) {
 		id := chi.URLParam(req, "id")
 
-		phyto, err := svc.GetWithSpecimens(req.Context(), id)
+		opts, err := parseSamplingOptions(req)
 		if err != nil {
 			httperr.Handle(w, req, err)
 			return
 		}
 
-		out := phytodto.ToPhytoAnalysisCompleteResponse(phyto)
-		response.JSON(w, http.StatusOK, out, nil)
+		phyto, err := svc.GetForIndicators(req.Context(), id)
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+		if len(phyto.Strata) == 0 {
+			httperr.Handle(w, req, apperr.New(apperr.CodeInvalid, "analysis has no strata"))
+			return
+		}
+
+		response.JSON(w, http.StatusOK, phytodto.ToStratifiedSamplingResponse(phyto, opts), nil)
+	})
+
+	// GET /phyto-analyses/:id/species-accumulation?permutations=100&seed=42
+	// Curva de acumulação aleatorizada e estimadores de riqueza (Chao, Jackknife, Bootstrap)
+	r.Get("/{id}/species-accumulation", func(w http.ResponseWriter, req *http.Request) {
+		id := chi.URLParam(req, "id")
+
+		opts, err := parseAccumulationOptions(req)
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		phyto, err := svc.GetForIndicators(req.Context(), id)
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, phytodto.ToSpeciesAccumulationResponse(phyto, opts), nil)
+	})
+
+	// GET /phyto-analyses/:id/diversity?probability=0.95&bootstrap=1000&seed=42
+	// Índices de diversidade com IC do H' (analítico e bootstrap), Margalef, Menhinick e números de Hill
+	r.Get("/{id}/diversity", func(w http.ResponseWriter, req *http.Request) {
+		id := chi.URLParam(req, "id")
+
+		opts, err := parseDiversityOptions(req)
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		phyto, err := svc.GetForIndicators(req.Context(), id)
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, phytodto.ToDiversityResponse(phyto, opts), nil)
+	})
+
+	// GET /phyto-analyses/:id/diversity/compare/:otherId?probability=0.95
+	// Teste t de Hutcheson entre os índices de Shannon de duas análises
+	r.Get("/{id}/diversity/compare/{otherId}", func(w http.ResponseWriter, req *http.Request) {
+		id := chi.URLParam(req, "id")
+		otherID := chi.URLParam(req, "otherId")
+
+		probability, err := parseProbability(req.URL.Query().Get("probability"), phytodto.DefaultDiversityProbability)
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		first, err := svc.GetForIndicators(req.Context(), id)
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+		second, err := svc.GetForIndicators(req.Context(), otherID)
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, phytodto.ToDiversityComparisonResponse(first, second, probability), nil)
 	})
 
 	// GET /phyto-analyses?limit=50&offset=0&projectId=xxx
@@ -179,12 +941,14 @@ func Routes(svc Service) chi.Router {
 		}
 
 		updateInput := appphyto.UpdateInput{
-			Title:           in.Title,
-			InitialDate:     in.InitialDate,
-			PortionQuantity: in.PortionQuantity,
-			PortionArea:     in.PortionArea,
-			TotalArea:       in.TotalArea,
-			Description:     in.Description,
+			Title:             in.Title,
+			InitialDate:       in.InitialDate,
+			PortionQuantity:   in.PortionQuantity,
+			PortionArea:       in.PortionArea,
+			TotalArea:         in.TotalArea,
+			Description:       in.Description,
+			DefaultFormFactor: in.DefaultFormFactor,
+			Measurement:       in.Measurement,
 		}
 
 		if err := svc.Update(req.Context(), id, updateInput); err != nil {
@@ -207,44 +971,407 @@ func Routes(svc Service) chi.Router {
 		response.JSON(w, http.StatusOK, map[string]string{"message": "deleted"}, nil)
 	})
 
-	// GET /phyto-analyses/:id/specimens - Listar specimens de uma análise
+	// GET /phyto-analyses/:id/specimens?limit=100&cursor=...&sort=dbh&order=desc&format=json - Listar specimens de uma análise
+	// Filtros: portion, speciesId, morphospeciesId, tag, status, phytosanitary, scientificName, family, dbhMin, dbhMax,
+	// measuredHeightMin, measuredHeightMax. A altura dos filtros e da ordenação measuredHeight é só a medida em campo:
+	// a altura estimada pela relação hipsométrica é preenchida depois da consulta, na resposta.
+	// format=ndjson|csv transmite todos os espécimes filtrados, sem paginação.
 	r.Get("/{id}/specimens", func(w http.ResponseWriter, req *http.Request) {
 		phytoID := chi.URLParam(req, "id")
 
-		// Buscar análise completa
-		phyto, err := svc.GetWithSpecimens(req.Context(), phytoID)
+		query, err := parseSpecimenListQuery(req)
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		// Dados da análise (sem espécimes) para o fator de forma e a equação volumétrica
+		phyto, err := svc.GetComplete(req.Context(), phytoID)
 		if err != nil {
 			httperr.Handle(w, req, err)
 			return
 		}
+		volumeModel := phytometrics.NewVolumeModel(phyto)
 
-		// Converter specimens
-		specimens := make([]phytodto.SpecimenResponse, 0, len(phyto.Specimens))
-		for _, s := range phyto.Specimens {
-			specimens = append(specimens, phytodto.SpecimenResponse{
-				ID:             s.ID,
-				Portion:        s.Portion,
-				Height:         s.Height,
-				Cap1:           s.Cap1,
-				Cap2:           s.Cap2,
-				Cap3:           s.Cap3,
-				Cap4:           s.Cap4,
-				Cap5:           s.Cap5,
-				Cap6:           s.Cap6,
-				RegisterDate:   s.RegisterDate,
-				SpecieID:       s.SpecieID,
-				ScientificName: s.ScientificName,
-				Family:         s.Family,
-				PopularName:    s.PopularName,
+		if query.Format != specimenFormatJSON {
+			var sw *specimenStreamWriter
+			err := svc.StreamSpecimens(req.Context(), phytoID, query.Filter, func(s *types.SpecimenWithSpecies) error {
+				if sw == nil {
+					sw = newSpecimenStreamWriter(w, query.Format, phytoID)
+				}
+				return sw.Write(phytodto.ToSpecimenResponse(s, volumeModel))
 			})
+			if sw == nil {
+				if err != nil {
+					httperr.Handle(w, req, err)
+					return
+				}
+				sw = newSpecimenStreamWriter(w, query.Format, phytoID)
+			}
+			// erros após o início da transmissão são logados e encerram a resposta
+			if err != nil {
+				sw.Fail(httperr.HandleStream(req, err))
+				return
+			}
+			sw.Flush()
+			return
 		}
 
-		response.JSON(w, http.StatusOK, specimens, nil)
+		specimens, pageInfo, err := svc.ListSpecimens(req.Context(), phytoID, query.Filter, query.Limit, query.After)
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		out := make([]phytodto.SpecimenResponse, 0, len(specimens))
+		for _, s := range specimens {
+			out = append(out, phytodto.ToSpecimenResponse(s, volumeModel))
+		}
+
+		var nextCursor *string
+		if pageInfo.Next != nil {
+			if encoded, err := pagination.Encode(pageInfo.Next); err == nil {
+				nextCursor = &encoded
+			}
+		}
+
+		meta := response.MetaCursor{
+			Limit:      int(query.Limit),
+			NextCursor: nextCursor,
+			HasMore:    pageInfo.HasMore,
+		}
+
+		response.JSON(w, http.StatusOK, out, meta)
+	})
+
+	// POST /phyto-analyses/:id/specimens/import?dryRun=true - Importar espécimes do template XLSX
+	// multipart: "file" (planilha) + "speciesMapping" opcional (JSON nome científico -> speciesID)
+	// + "morphospecies" opcional (JSON com as morfoespécies declaradas)
+	r.Post("/{id}/specimens/import", func(w http.ResponseWriter, req *http.Request) {
+		phytoID := chi.URLParam(req, "id")
+
+		specimens, err := readImportFile(w, req)
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		importInput := appphyto.AddSpecimensInput{Specimens: specimens}
+		if raw := req.FormValue("speciesMapping"); raw != "" {
+			if err := json.Unmarshal([]byte(raw), &importInput.SpeciesMapping); err != nil {
+				httperr.Handle(w, req, apperr.New(apperr.CodeInvalid, "invalid speciesMapping field"))
+				return
+			}
+		}
+		if raw := req.FormValue("morphospecies"); raw != "" {
+			var declared []phytodto.MorphospeciesRequest
+			if err := json.Unmarshal([]byte(raw), &declared); err != nil {
+				httperr.Handle(w, req, apperr.New(apperr.CodeInvalid, "invalid morphospecies field"))
+				return
+			}
+			importInput.Morphospecies = toMorphospeciesInputs(declared)
+		}
+
+		if isDryRun(req) {
+			report, err := svc.ValidateSpecimens(req.Context(), phytoID, importInput)
+			if err != nil {
+				httperr.Handle(w, req, err)
+				return
+			}
+			response.JSON(w, http.StatusOK, phytodto.ToSpecimenImportReportResponse(report), nil)
+			return
+		}
+
+		created, err := svc.AddSpecimens(req.Context(), phytoID, importInput)
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		response.JSON(w, http.StatusCreated, map[string]int{"created": created}, nil)
 	})
 
 	return r
 }
 
+func toSpecimenInputs(in []phytodto.SpecimenInput) []appphyto.SpecimenInput {
+	specimens := make([]appphyto.SpecimenInput, 0, len(in))
+	for _, s := range in {
+		specimens = append(specimens, appphyto.SpecimenInput{
+			Portion:          s.Portion,
+			Height:           s.Height,
+			Cap1:             s.Cap1,
+			Cap2:             s.Cap2,
+			Cap3:             s.Cap3,
+			Cap4:             s.Cap4,
+			Cap5:             s.Cap5,
+			Cap6:             s.Cap6,
+			RegisterDate:     s.RegisterDate,
+			Latitude:         s.Latitude,
+			Longitude:        s.Longitude,
+			Tag:              s.Tag,
+			Status:           s.Status,
+			Phytosanitary:    s.Phytosanitary,
+			BifurcationNotes: s.BifurcationNotes,
+			Observations:     s.Observations,
+			ScientificName:   s.ScientificName,
+		})
+	}
+	return specimens
+}
+
+func toPlotInput(in phytodto.PlotRequest) appphyto.PlotInput {
+	return appphyto.PlotInput{
+		Code:            in.Code,
+		AreaM2:          in.AreaM2,
+		Shape:           in.Shape,
+		WidthM:          in.WidthM,
+		LengthM:         in.LengthM,
+		RadiusM:         in.RadiusM,
+		Center:          in.Center,
+		Vertices:        in.Vertices,
+		VegetationNotes: in.VegetationNotes,
+		StratumID:       in.StratumID,
+	}
+}
+
+func toStratumInput(in phytodto.StratumRequest) appphyto.StratumInput {
+	return appphyto.StratumInput{
+		Code:        in.Code,
+		Name:        in.Name,
+		AreaHa:      in.AreaHa,
+		Description: in.Description,
+	}
+}
+
+func toMorphospeciesInput(in phytodto.MorphospeciesRequest) appphyto.MorphospeciesInput {
+	return appphyto.MorphospeciesInput{
+		Name:   in.Name,
+		Family: in.Family,
+		Genus:  in.Genus,
+	}
+}
+
+func toMorphospeciesInputs(in []phytodto.MorphospeciesRequest) []appphyto.MorphospeciesInput {
+	morphospecies := make([]appphyto.MorphospeciesInput, 0, len(in))
+	for _, m := range in {
+		morphospecies = append(morphospecies, toMorphospeciesInput(m))
+	}
+	return morphospecies
+}
+
+func toPlotInputs(in []phytodto.PlotRequest) []appphyto.PlotInput {
+	plots := make([]appphyto.PlotInput, 0, len(in))
+	for _, p := range in {
+		plots = append(plots, toPlotInput(p))
+	}
+	return plots
+}
+
+func toCreateInput(in phytodto.CreatePhytoAnalysisRequest, specimens []appphyto.SpecimenInput) appphyto.CreateInput {
+	return appphyto.CreateInput{
+		Title:             in.Title,
+		InitialDate:       in.InitialDate,
+		PortionQuantity:   in.PortionQuantity,
+		PortionArea:       in.PortionArea,
+		TotalArea:         in.TotalArea,
+		Description:       in.Description,
+		ProjectID:         in.ProjectID,
+		DefaultFormFactor: in.DefaultFormFactor,
+		Measurement:       in.Measurement,
+		Plots:             toPlotInputs(in.Plots),
+		Specimens:         specimens,
+		SpeciesMapping:    in.SpeciesMapping,
+		Morphospecies:     toMorphospeciesInputs(in.Morphospecies),
+	}
+}
+
+// Formatos da exportação espacial
+const (
+	geoFormatGeoJSON = "geojson"
+	geoFormatKML     = "kml"
+)
+
+// isDryRun indica se a requisição pediu apenas a validação (?dryRun=true)
+func isDryRun(req *http.Request) bool {
+	v, err := strconv.ParseBool(req.URL.Query().Get("dryRun"))
+	return err == nil && v
+}
+
+// parseClassDistributionOptions lê as opções das classes da query, usando os padrões quando ausentes
+func parseClassDistributionOptions(req *http.Request) (phytodto.ClassDistributionOptions, error) {
+	opts := phytodto.DefaultClassDistributionOptions()
+	q := req.URL.Query()
+
+	params := []struct {
+		name     string
+		dst      *float64
+		positive bool
+	}{
+		{"dbhClassWidth", &opts.DbhClassWidth, true},
+		{"dbhMin", &opts.DbhMin, false},
+		{"heightClassWidth", &opts.HeightClassWidth, true},
+		{"heightMin", &opts.HeightMin, false},
+	}
+	for _, p := range params {
+		raw := q.Get(p.name)
+		if raw == "" {
+			continue
+		}
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil || math.IsNaN(v) || math.IsInf(v, 0) || v < 0 || (p.positive && v < phytometrics.MinClassWidth) {
+			return opts, apperr.New(apperr.CodeInvalid, "invalid "+p.name)
+		}
+		*p.dst = v
+	}
+
+	return opts, nil
+}
+
+// parseBiomassOptions lê a equação alométrica (do cadastro), a fração de carbono, a razão
+// raiz:parte aérea e a densidade padrão da madeira (g/cm³)
+func parseBiomassOptions(req *http.Request, svc Service) (phytodto.BiomassOptions, error) {
+	opts := phytodto.DefaultBiomassOptions()
+	q := req.URL.Query()
+
+	if raw := q.Get("equation"); raw != "" {
+		def, err := svc.GetEquation(req.Context(), raw)
+		if err != nil {
+			return opts, err
+		}
+		if def.Kind != types.EquationKindBiomass {
+			return opts, apperr.New(apperr.CodeInvalid, "invalid equation")
+		}
+		eq, err := phytometrics.CompileEquation(*def)
+		if err != nil {
+			return opts, apperr.Wrap(err, apperr.CodeInvalid, "invalid equation")
+		}
+		opts.Equation = eq
+	}
+
+	params := []struct {
+		name string
+		dst  *float64
+		max  float64
+	}{
+		{"carbonFraction", &opts.CarbonFraction, 1},
+		{"rootShootRatio", &opts.RootShootRatio, 10},
+		{"woodDensity", &opts.DefaultWoodDensity, domainspecies.MaxWoodDensity},
+	}
+	for _, p := range params {
+		raw := q.Get(p.name)
+		if raw == "" {
+			continue
+		}
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil || math.IsNaN(v) || !(v > 0 && v <= p.max) {
+			return opts, apperr.New(apperr.CodeInvalid, "invalid "+p.name)
+		}
+		*p.dst = v
+	}
+
+	return opts, nil
+}
+
+// parseSamplingOptions lê o nível de confiança (0-1) e o erro admissível (%) da query
+func parseSamplingOptions(req *http.Request) (phytodto.SamplingOptions, error) {
+	opts := phytodto.DefaultSamplingOptions()
+	q := req.URL.Query()
+
+	probability, err := parseProbability(q.Get("probability"), opts.Probability)
+	if err != nil {
+		return opts, err
+	}
+	opts.Probability = probability
+
+	if raw := q.Get("targetError"); raw != "" {
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil || !(v > 0 && v <= 100) {
+			return opts, apperr.New(apperr.CodeInvalid, "invalid targetError")
+		}
+		opts.TargetErrorPercent = v
+	}
+
+	return opts, nil
+}
+
+// parseAccumulationOptions lê o número de permutações e a semente
+func parseAccumulationOptions(req *http.Request) (phytodto.AccumulationOptions, error) {
+	opts := phytodto.AccumulationOptions{
+		Permutations: phytodto.DefaultAccumulationPermutations,
+	}
+	q := req.URL.Query()
+
+	if raw := q.Get("permutations"); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil || v <= 0 || v > phytodto.MaxAccumulationPermutations {
+			return opts, apperr.New(apperr.CodeInvalid, "invalid permutations")
+		}
+		opts.Permutations = v
+	}
+
+	seed, err := parseSeed(q.Get("seed"))
+	if err != nil {
+		return opts, err
+	}
+	opts.Seed = seed
+
+	return opts, nil
+}
+
+// parseDiversityOptions lê o nível de confiança e o bootstrap do H'
+func parseDiversityOptions(req *http.Request) (phytodto.DiversityOptions, error) {
+	opts := phytodto.DiversityOptions{BootstrapIterations: phytodto.DefaultBootstrapIterations}
+	q := req.URL.Query()
+
+	probability, err := parseProbability(q.Get("probability"), phytodto.DefaultDiversityProbability)
+	if err != nil {
+		return opts, err
+	}
+	opts.Probability = probability
+
+	if raw := q.Get("bootstrap"); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil || v <= 0 || v > phytodto.MaxBootstrapIterations {
+			return opts, apperr.New(apperr.CodeInvalid, "invalid bootstrap")
+		}
+		opts.BootstrapIterations = v
+	}
+
+	seed, err := parseSeed(q.Get("seed"))
+	if err != nil {
+		return opts, err
+	}
+	opts.Seed = seed
+
+	return opts, nil
+}
+
+// parseProbability lê um nível de confiança no intervalo (0, 1)
+func parseProbability(raw string, def float64) (float64, error) {
+	if raw == "" {
+		return def, nil
+	}
+	v, err := strconv.ParseFloat(raw, 64)
+	if err != nil || !(v > 0 && v < 1) {
+		return def, apperr.New(apperr.CodeInvalid, "invalid probability")
+	}
+	return v, nil
+}
+
+// parseSeed lê a semente do gerador aleatório; sem semente, gera uma nova
+// (retornada na resposta para reproduzir o relatório)
+func parseSeed(raw string) (int64, error) {
+	if raw == "" {
+		return time.Now().UnixNano(), nil
+	}
+	v, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return 0, apperr.New(apperr.CodeInvalid, "invalid seed")
+	}
+	return v, nil
+}
+
 func parseInt32(s string, def int32) int32 {
 	if s == "" {
 		return def
@@ -0,0 +1,214 @@
+package phytoanalysishttp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ESG-Project/suassu-api/internal/app/phytometrics"
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSvc implementa apenas os métodos usados nos testes; os demais não são chamados
+type fakeSvc struct {
+	Service
+	phyto     *types.PhytoAnalysisComplete
+	streamErr error
+}
+
+func (f *fakeSvc) GetWithSpecimens(ctx context.Context, id string) (*types.PhytoAnalysisComplete, error) {
+	return f.phyto, nil
+}
+
+func (f *fakeSvc) GetForIndicators(ctx context.Context, id string) (*types.PhytoAnalysisComplete, error) {
+	return phytometrics.WithoutDead(f.phyto), nil
+}
+
+func (f *fakeSvc) GetComplete(ctx context.Context, id string) (*types.PhytoAnalysisComplete, error) {
+	return f.phyto, nil
+}
+
+// StreamSpecimens transmite os espécimes da análise e, se streamErr estiver definido, falha no fim
+func (f *fakeSvc) StreamSpecimens(ctx context.Context, id string, _ types.SpecimenFilter, fn func(*types.SpecimenWithSpecies) error) error {
+	for _, s := range f.phyto.Specimens {
+		if err := fn(s); err != nil {
+			return err
+		}
+	}
+	return f.streamErr
+}
+
+func (f *fakeSvc) GetIndicators(ctx context.Context, id string) (*phytometrics.Snapshot, error) {
+	return nil, nil
+}
+
+// analysisExcludingDead retorna uma análise com protocolo que exclui os mortos dos indicadores
+func analysisExcludingDead() *types.PhytoAnalysisComplete {
+	lat, lng := -15.8, -47.9
+	p := &types.PhytoAnalysisComplete{ID: "phyto-1", Title: "Análise", SampledArea: 0.1, TotalArea: 1}
+	p.Measurement.ExcludeDead = true
+	p.Specimens = []*types.SpecimenWithSpecies{
+		{ID: "live", Portion: "1", Cap1: 20 * math.Pi, Height: 10, ScientificName: "A a", Family: "Fabaceae",
+			Status: types.SpecimenStatusAlive, Latitude: &lat, Longitude: &lng},
+		{ID: "dead", Portion: "1", Cap1: 30 * math.Pi, Height: 12, ScientificName: "A a", Family: "Fabaceae",
+			Status: types.SpecimenStatusDeadStanding, Latitude: &lat, Longitude: &lng},
+	}
+	return p
+}
+
+func TestGetPhytoAnalysis_KeepsDeadSpecimens(t *testing.T) {
+	router := Routes(&fakeSvc{phyto: analysisExcludingDead()})
+
+	req := httptest.NewRequest(http.MethodGet, "/phyto-1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var body struct {
+		Data struct {
+			Specimens []struct {
+				ID     string `json:"id"`
+				Status string `json:"status"`
+			} `json:"specimens"`
+			IndividualsCount int `json:"individualsCount"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.Len(t, body.Data.Specimens, 2)
+	require.Equal(t, "dead", body.Data.Specimens[1].ID)
+	// os agregados seguem o protocolo (sem os mortos)
+	require.Equal(t, 1, body.Data.IndividualsCount)
+}
+
+func TestExportPhytoAnalysis_KeepsDeadSpecimens(t *testing.T) {
+	router := Routes(&fakeSvc{phyto: analysisExcludingDead()})
+
+	req := httptest.NewRequest(http.MethodGet, "/phyto-1/export?format=geojson", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var fc struct {
+		Features []struct {
+			ID         string         `json:"id"`
+			Properties map[string]any `json:"properties"`
+		} `json:"features"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &fc))
+	require.Len(t, fc.Features, 2)
+	require.Equal(t, "dead", fc.Features[1].ID)
+	require.Equal(t, types.SpecimenStatusDeadStanding, fc.Features[1].Properties["status"])
+}
+
+func TestSuppression_IncludesDeadExcludedFromIndicators(t *testing.T) {
+	p := analysisExcludingDead()
+	p.Assortment = &types.AssortmentRules{DeadAsFirewood: true}
+	router := Routes(&fakeSvc{phyto: p})
+
+	req := httptest.NewRequest(http.MethodGet, "/phyto-1/suppression", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var body struct {
+		Data struct {
+			Sampled struct {
+				Log      struct{ Individuals float64 } `json:"log"`
+				Firewood struct{ Individuals float64 } `json:"firewood"`
+			} `json:"sampled"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	// DAP de 30 cm acima do mínimo para tora, mas morta: vai para lenha
+	require.Equal(t, 1.0, body.Data.Sampled.Log.Individuals)
+	require.Equal(t, 1.0, body.Data.Sampled.Firewood.Individuals)
+}
+
+func TestCompensation_CountsDeadExcludedFromIndicators(t *testing.T) {
+	p := analysisExcludingDead()
+	for _, s := range p.Specimens {
+		s.Legislations = []types.SpeciesLegislationStatus{{LawScope: "FEDERAL", ThreatStatus: "EN"}}
+	}
+	router := Routes(&fakeSvc{phyto: p})
+
+	req := httptest.NewRequest(http.MethodGet, "/phyto-1/compensation", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var body struct {
+		Data struct {
+			DeadIgnored      int `json:"deadIgnored"`
+			TotalIndividuals int `json:"totalIndividuals"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.Equal(t, 1, body.Data.DeadIgnored)
+	require.Equal(t, 1, body.Data.TotalIndividuals)
+}
+
+func TestListSpecimensNDJSON_TrailingErrorRecord(t *testing.T) {
+	router := Routes(&fakeSvc{phyto: analysisExcludingDead(), streamErr: errors.New("connection reset")})
+
+	req := httptest.NewRequest(http.MethodGet, "/phyto-1/specimens?format=ndjson", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	lines := make([]map[string]any, 0)
+	scanner := bufio.NewScanner(w.Body)
+	for scanner.Scan() {
+		var line map[string]any
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+	require.Len(t, lines, 3)
+	require.Equal(t, "live", lines[0]["id"])
+	require.Contains(t, lines[2], "error")
+	require.Equal(t, "internal", lines[2]["error"].(map[string]any)["code"])
+}
+
+func TestListSpecimensNDJSON_UsesSelectedVolumeEquation(t *testing.T) {
+	phyto := analysisExcludingDead()
+	phyto.VolumeEquation = &types.EquationData{
+		ID:           "spurr-mg",
+		Name:         "Spurr MG",
+		Kind:         types.EquationKindVolume,
+		Expression:   "b0 + b1 * dap^2 * h",
+		Coefficients: map[string]float64{"b0": 0.01, "b1": 0.00004},
+	}
+	router := Routes(&fakeSvc{phyto: phyto})
+
+	req := httptest.NewRequest(http.MethodGet, "/phyto-1/specimens?format=ndjson", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	scanner := bufio.NewScanner(w.Body)
+	require.True(t, scanner.Scan())
+	var first map[string]any
+	require.NoError(t, json.Unmarshal(scanner.Bytes(), &first))
+	require.Equal(t, "live", first["id"])
+	require.Equal(t, "spurr-mg", first["volumeEquationId"])
+	require.InDelta(t, 0.01+0.00004*400*10, first["volumeM3"].(float64), 1e-9)
+}
+
+func TestListSpecimensCSV_AbortsOnStreamError(t *testing.T) {
+	router := Routes(&fakeSvc{phyto: analysisExcludingDead(), streamErr: errors.New("connection reset")})
+
+	req := httptest.NewRequest(http.MethodGet, "/phyto-1/specimens?format=csv", nil)
+	w := httptest.NewRecorder()
+
+	// o CSV não tem onde sinalizar o erro: a conexão é abortada após as linhas já enviadas
+	require.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		router.ServeHTTP(w, req)
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), "live")
+}
@@ -0,0 +1,348 @@
+package phytoanalysishttp
+
+import (
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	appphyto "github.com/ESG-Project/suassu-api/internal/app/phytoanalysis"
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+	"github.com/ESG-Project/suassu-api/internal/apperr"
+	"github.com/ESG-Project/suassu-api/internal/infra/xlsx"
+)
+
+// maxImportFileSize limita o tamanho da planilha enviada (10 MB)
+const maxImportFileSize = 10 << 20
+
+// maxImportBodySize limita o corpo da requisição: a planilha e os demais campos do formulário
+const maxImportBodySize = maxImportFileSize + 1<<20
+
+// maxImportColumns limita as colunas lidas da planilha: o template tem 17 colunas, e as demais
+// são ignoradas (com folga para colunas extras antes do cabeçalho)
+const maxImportColumns = 64
+
+// colunas do template specimens_import_template.xlsx
+const (
+	colPortion = iota
+	colScientificName
+	colRegisterDate
+	colCap1
+	colCap2
+	colCap3
+	colCap4
+	colCap5
+	colCap6
+	colHeight
+	colLatitude      // opcional: não faz parte do template, pode ser acrescentada
+	colLongitude     // opcional
+	colTag           // opcional: plaqueta da árvore (parcelas permanentes)
+	colStatus        // situação do indivíduo (vazia = viva)
+	colPhytosanitary // condição fitossanitária
+	colBifurcation   // observações sobre a bifurcação
+	colObservations
+)
+
+// prefixos (normalizados) dos cabeçalhos do template, usados para localizar as colunas.
+// As colunas dos fustes aceitam CAP ou DAP; o valor segue o protocolo de medição da análise.
+var importHeaderPrefixes = map[int][]string{
+	colPortion:        {"parcela"},
+	colScientificName: {"especime"},
+	colRegisterDate:   {"data"},
+	colCap1:           {"cap1", "dap1"},
+	colCap2:           {"cap2", "dap2"},
+	colCap3:           {"cap3", "dap3"},
+	colCap4:           {"cap4", "dap4"},
+	colCap5:           {"cap5", "dap5"},
+	colCap6:           {"cap6", "dap6"},
+	colHeight:         {"altura"},
+	colLatitude:       {"latitude"},
+	colLongitude:      {"longitude"},
+	colTag:            {"plaqueta", "tag"},
+	colStatus:         {"situacao", "status"},
+	colPhytosanitary:  {"fitossani", "sanidade"},
+	colBifurcation:    {"bifurca"},
+	colObservations:   {"observa", "obs"},
+}
+
+// valores aceitos (normalizados) na coluna de situação, além dos códigos da API
+var importStatusAliases = map[string]string{
+	"viva":       types.SpecimenStatusAlive,
+	"vivo":       types.SpecimenStatusAlive,
+	"morta":      types.SpecimenStatusDeadStanding,
+	"morto":      types.SpecimenStatusDeadStanding,
+	"mortaempe":  types.SpecimenStatusDeadStanding,
+	"mortoempe":  types.SpecimenStatusDeadStanding,
+	"caida":      types.SpecimenStatusFallen,
+	"caido":      types.SpecimenStatusFallen,
+	"mortacaida": types.SpecimenStatusFallen,
+	"mortocaido": types.SpecimenStatusFallen,
+	"rebrota":    types.SpecimenStatusResprouting,
+	"rebrotando": types.SpecimenStatusResprouting,
+}
+
+// valores aceitos (normalizados) na coluna de fitossanidade, além dos códigos da API
+var importPhytosanitaryAliases = map[string]string{
+	"boa":     types.PhytosanitaryGood,
+	"bom":     types.PhytosanitaryGood,
+	"regular": types.PhytosanitaryFair,
+	"ruim":    types.PhytosanitaryPoor,
+	"ma":      types.PhytosanitaryPoor,
+}
+
+var importDateLayouts = []string{
+	"02/01/2006",
+	"2/1/2006",
+	"02/01/06",
+	"2006-01-02",
+	time.RFC3339,
+}
+
+// readImportFile lê o arquivo XLSX enviado no campo multipart "file"
+func readImportFile(w http.ResponseWriter, req *http.Request) ([]appphyto.SpecimenInput, error) {
+	req.Body = http.MaxBytesReader(w, req.Body, maxImportBodySize)
+	if err := req.ParseMultipartForm(maxImportFileSize); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return nil, apperr.New(apperr.CodeInvalid, "file too large")
+		}
+		return nil, apperr.New(apperr.CodeInvalid, "invalid multipart form")
+	}
+
+	file, header, err := req.FormFile("file")
+	if err != nil {
+		return nil, apperr.New(apperr.CodeInvalid, "file is required")
+	}
+	defer func(f multipart.File) { _ = f.Close() }(file)
+
+	if header.Size > maxImportFileSize {
+		return nil, apperr.New(apperr.CodeInvalid, "file too large")
+	}
+
+	return parseSpecimensSheet(file, header.Size)
+}
+
+// parseSpecimensSheet converte as linhas do template de importação em SpecimenInput,
+// preservando o número da linha da planilha para o relatório de erros.
+func parseSpecimensSheet(r io.ReaderAt, size int64) ([]appphyto.SpecimenInput, error) {
+	rows, err := xlsx.ReadFirstSheet(r, size, maxImportColumns)
+	if err != nil {
+		if errors.Is(err, xlsx.ErrWorkbookTooLarge) {
+			return nil, apperr.Wrap(err, apperr.CodeInvalid, "xlsx file exceeds the import limits")
+		}
+		if errors.Is(err, xlsx.ErrInvalidWorkbook) {
+			return nil, apperr.Wrap(err, apperr.CodeInvalid, "invalid xlsx file")
+		}
+		return nil, err
+	}
+
+	headerIdx, columns := findImportHeader(rows)
+	if headerIdx < 0 {
+		return nil, apperr.New(apperr.CodeInvalid, "import template header not found")
+	}
+
+	specimens := make([]appphyto.SpecimenInput, 0, len(rows)-headerIdx-1)
+	for _, row := range rows[headerIdx+1:] {
+		specimens = append(specimens, specimenFromRow(row, columns))
+	}
+
+	return specimens, nil
+}
+
+// findImportHeader localiza a linha de cabeçalho (coluna "Parcela*") e mapeia as colunas pelo título
+func findImportHeader(rows []xlsx.Row) (int, map[int]int) {
+	for i, row := range rows {
+		columns := make(map[int]int, len(importHeaderPrefixes))
+		for col, cell := range row.Cells {
+			title := normalizeHeader(cell.Value)
+			for field, prefixes := range importHeaderPrefixes {
+				if _, taken := columns[field]; !taken && hasAnyPrefix(title, prefixes) {
+					columns[field] = col
+					break
+				}
+			}
+		}
+
+		_, hasPortion := columns[colPortion]
+		_, hasName := columns[colScientificName]
+		if hasPortion && hasName {
+			return i, columns
+		}
+	}
+	return -1, nil
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func specimenFromRow(row xlsx.Row, columns map[int]int) appphyto.SpecimenInput {
+	cell := func(field int) xlsx.Cell {
+		col, ok := columns[field]
+		if !ok {
+			return xlsx.Cell{}
+		}
+		return row.Cell(col)
+	}
+
+	in := appphyto.SpecimenInput{
+		RowNumber:      row.Number,
+		Portion:        strings.TrimSpace(cell(colPortion).Value),
+		ScientificName: strings.TrimSpace(cell(colScientificName).Value),
+	}
+	if tag := strings.TrimSpace(cell(colTag).Value); tag != "" {
+		in.Tag = &tag
+	}
+	in.Status = importCode(cell(colStatus).Value, importStatusAliases)
+	if v := importCode(cell(colPhytosanitary).Value, importPhytosanitaryAliases); v != "" {
+		in.Phytosanitary = &v
+	}
+	if v := strings.TrimSpace(cell(colBifurcation).Value); v != "" {
+		in.BifurcationNotes = &v
+	}
+	if v := strings.TrimSpace(cell(colObservations).Value); v != "" {
+		in.Observations = &v
+	}
+
+	if v, ok, err := parseNumberCell(cell(colHeight)); err != nil {
+		in.InputErrors = append(in.InputErrors, "height must be a number")
+	} else if ok {
+		in.Height = &v
+	}
+
+	if v, ok, err := parseNumberCell(cell(colCap1)); err != nil {
+		in.InputErrors = append(in.InputErrors, "cap1 must be a number")
+	} else if ok {
+		in.Cap1 = v
+	}
+
+	optionalCaps := []struct {
+		field int
+		name  string
+		dst   **float64
+	}{
+		{colCap2, "cap2", &in.Cap2},
+		{colCap3, "cap3", &in.Cap3},
+		{colCap4, "cap4", &in.Cap4},
+		{colCap5, "cap5", &in.Cap5},
+		{colCap6, "cap6", &in.Cap6},
+	}
+	for _, c := range optionalCaps {
+		v, ok, err := parseNumberCell(cell(c.field))
+		if err != nil {
+			in.InputErrors = append(in.InputErrors, c.name+" must be a number")
+			continue
+		}
+		if ok {
+			value := v
+			*c.dst = &value
+		}
+	}
+
+	coordinates := []struct {
+		field int
+		name  string
+		dst   **float64
+	}{
+		{colLatitude, "latitude", &in.Latitude},
+		{colLongitude, "longitude", &in.Longitude},
+	}
+	for _, c := range coordinates {
+		v, ok, err := parseNumberCell(cell(c.field))
+		if err != nil {
+			in.InputErrors = append(in.InputErrors, c.name+" must be a number")
+			continue
+		}
+		if ok {
+			value := v
+			*c.dst = &value
+		}
+	}
+
+	if d, ok, err := parseDateCell(cell(colRegisterDate)); err != nil {
+		in.InputErrors = append(in.InputErrors, "register date must be a valid date")
+	} else if ok {
+		in.RegisterDate = d
+	}
+
+	return in
+}
+
+// importCode converte o valor da planilha no código da API (termos em português ou o próprio código);
+// valores desconhecidos seguem como informados e são rejeitados na validação
+func importCode(raw string, aliases map[string]string) string {
+	raw = strings.TrimSpace(raw)
+	if code, ok := aliases[normalizeHeader(raw)]; ok {
+		return code
+	}
+	return raw
+}
+
+// parseNumberCell aceita números nativos e textos com vírgula ou ponto decimal
+func parseNumberCell(c xlsx.Cell) (float64, bool, error) {
+	raw := strings.TrimSpace(c.Value)
+	if raw == "" {
+		return 0, false, nil
+	}
+	if !c.Numeric {
+		raw = strings.ReplaceAll(raw, " ", "")
+		if strings.Contains(raw, ",") {
+			raw = strings.ReplaceAll(raw, ".", "")
+			raw = strings.ReplaceAll(raw, ",", ".")
+		}
+	}
+	v, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return 0, false, err
+	}
+	return v, true, nil
+}
+
+// parseDateCell aceita datas seriais do Excel e textos em dd/mm/aaaa ou ISO 8601
+func parseDateCell(c xlsx.Cell) (time.Time, bool, error) {
+	raw := strings.TrimSpace(c.Value)
+	if raw == "" {
+		return time.Time{}, false, nil
+	}
+	if c.Numeric {
+		serial, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return time.Time{}, false, err
+		}
+		return xlsx.SerialToTime(serial), true, nil
+	}
+	for _, layout := range importDateLayouts {
+		if d, err := time.Parse(layout, raw); err == nil {
+			return d, true, nil
+		}
+	}
+	return time.Time{}, false, errors.New("invalid date")
+}
+
+// normalizeHeader remove acentos, espaços e caixa dos títulos das colunas
+func normalizeHeader(s string) string {
+	replacer := strings.NewReplacer(
+		"á", "a", "à", "a", "â", "a", "ã", "a",
+		"é", "e", "ê", "e",
+		"í", "i",
+		"ó", "o", "ô", "o", "õ", "o",
+		"ú", "u",
+		"ç", "c",
+	)
+	s = replacer.Replace(strings.ToLower(s))
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
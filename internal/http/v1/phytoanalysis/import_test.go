@@ -0,0 +1,208 @@
+package phytoanalysishttp
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"mime/multipart"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+	"github.com/ESG-Project/suassu-api/internal/apperr"
+	"github.com/ESG-Project/suassu-api/internal/infra/xlsx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSpecimensSheet_Template(t *testing.T) {
+	t.Parallel()
+
+	data, err := phytoTemplatesFS.ReadFile("templates/specimens_import_template.xlsx")
+	require.NoError(t, err)
+
+	specimens, err := parseSpecimensSheet(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	require.Len(t, specimens, 4)
+
+	first := specimens[0]
+	require.Equal(t, 9, first.RowNumber)
+	require.Equal(t, "2", first.Portion)
+	require.Equal(t, "Allophyllus edulis", first.ScientificName)
+	require.Equal(t, 42.5, first.Cap1)
+	require.Equal(t, 6.5, *first.Height)
+	require.Nil(t, first.Cap2)
+	require.Equal(t, time.Date(2025, time.November, 10, 0, 0, 0, 0, time.UTC), first.RegisterDate)
+	require.Empty(t, first.InputErrors)
+
+	// Linha sem data de registro mantém o número da planilha para o relatório de erros
+	last := specimens[3]
+	require.Equal(t, 12, last.RowNumber)
+	require.True(t, last.RegisterDate.IsZero())
+	require.Equal(t, 41.6, last.Cap1)
+}
+
+func TestParseSpecimensSheet_InvalidFile(t *testing.T) {
+	t.Parallel()
+
+	data := []byte("not a spreadsheet")
+	_, err := parseSpecimensSheet(bytes.NewReader(data), int64(len(data)))
+
+	require.Error(t, err)
+	require.Equal(t, apperr.CodeInvalid, apperr.CodeOf(err))
+}
+
+// sheetWorkbook monta um XLSX mínimo com a planilha informada
+func sheetWorkbook(t *testing.T, sheet string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range map[string]string{
+		"xl/workbook.xml":          `<workbook/>`,
+		"xl/worksheets/sheet1.xml": sheet,
+	} {
+		f, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func TestParseSpecimensSheet_TooManyRows(t *testing.T) {
+	t.Parallel()
+
+	var sheet strings.Builder
+	sheet.WriteString(`<worksheet><sheetData>`)
+	for i := 1; i <= xlsx.MaxRows+1; i++ {
+		fmt.Fprintf(&sheet, `<row r="%d"><c r="A%d"><v>1</v></c></row>`, i, i)
+	}
+	sheet.WriteString(`</sheetData></worksheet>`)
+
+	data := sheetWorkbook(t, sheet.String())
+	_, err := parseSpecimensSheet(bytes.NewReader(data), int64(len(data)))
+	require.ErrorIs(t, err, xlsx.ErrWorkbookTooLarge)
+	require.Equal(t, apperr.CodeInvalid, apperr.CodeOf(err))
+}
+
+func TestReadFirstSheet_FarColumns(t *testing.T) {
+	t.Parallel()
+
+	// linhas com uma única célula na última coluna do Excel (XFD)
+	var sheet strings.Builder
+	sheet.WriteString(`<worksheet><sheetData>`)
+	for i := 1; i <= 200; i++ {
+		fmt.Fprintf(&sheet, `<row r="%d"><c r="XFD%d"><v>1</v></c></row>`, i, i)
+	}
+	sheet.WriteString(`</sheetData></worksheet>`)
+	data := sheetWorkbook(t, sheet.String())
+
+	// células além do limite de colunas da importação são descartadas
+	rows, err := xlsx.ReadFirstSheet(bytes.NewReader(data), int64(len(data)), maxImportColumns)
+	require.NoError(t, err)
+	require.Empty(t, rows)
+
+	// sem limite de colunas, o orçamento de células barra a alocação
+	_, err = xlsx.ReadFirstSheet(bytes.NewReader(data), int64(len(data)), 0)
+	require.ErrorIs(t, err, xlsx.ErrWorkbookTooLarge)
+}
+
+func TestReadImportFile_BodyTooLarge(t *testing.T) {
+	t.Parallel()
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	part, err := mw.CreateFormFile("file", "specimens.xlsx")
+	require.NoError(t, err)
+	_, err = part.Write(make([]byte, maxImportBodySize))
+	require.NoError(t, err)
+	require.NoError(t, mw.Close())
+
+	req := httptest.NewRequest("POST", "/phyto-analyses/import", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	_, err = readImportFile(httptest.NewRecorder(), req)
+	require.ErrorContains(t, err, "file too large")
+	require.Equal(t, apperr.CodeInvalid, apperr.CodeOf(err))
+}
+
+func TestParseSpecimensSheet_OptionalCoordinates(t *testing.T) {
+	t.Parallel()
+
+	rows := []xlsx.Row{
+		{Number: 1, Cells: []xlsx.Cell{{Value: "Parcela*"}, {Value: "Espécime*"}, {Value: "CAP1*"}, {Value: "Latitude"}, {Value: "Longitude"}}},
+		{Number: 2, Cells: []xlsx.Cell{{Value: "1"}, {Value: "A a"}, {Value: "30", Numeric: true}, {Value: "-15,79"}, {Value: "-47.88", Numeric: true}}},
+		{Number: 3, Cells: []xlsx.Cell{{Value: "1"}, {Value: "A a"}, {Value: "30", Numeric: true}, {Value: "x"}}},
+	}
+
+	headerIdx, columns := findImportHeader(rows)
+	require.Equal(t, 0, headerIdx)
+
+	first := specimenFromRow(rows[1], columns)
+	require.Empty(t, first.InputErrors)
+	require.Equal(t, -15.79, *first.Latitude)
+	require.Equal(t, -47.88, *first.Longitude)
+
+	second := specimenFromRow(rows[2], columns)
+	require.Equal(t, []string{"latitude must be a number"}, second.InputErrors)
+	require.Nil(t, second.Longitude)
+}
+
+func TestParseSpecimensSheet_OptionalTag(t *testing.T) {
+	t.Parallel()
+
+	rows := []xlsx.Row{
+		{Number: 1, Cells: []xlsx.Cell{{Value: "Parcela*"}, {Value: "Espécime*"}, {Value: "CAP1*"}, {Value: "Plaqueta"}}},
+		{Number: 2, Cells: []xlsx.Cell{{Value: "1"}, {Value: "A a"}, {Value: "30", Numeric: true}, {Value: " 0012 "}}},
+		{Number: 3, Cells: []xlsx.Cell{{Value: "1"}, {Value: "A a"}, {Value: "30", Numeric: true}}},
+	}
+
+	headerIdx, columns := findImportHeader(rows)
+	require.Equal(t, 0, headerIdx)
+
+	first := specimenFromRow(rows[1], columns)
+	require.Equal(t, "0012", *first.Tag)
+
+	second := specimenFromRow(rows[2], columns)
+	require.Nil(t, second.Tag)
+}
+
+func TestParseSpecimensSheet_OptionalCondition(t *testing.T) {
+	t.Parallel()
+
+	rows := []xlsx.Row{
+		{Number: 1, Cells: []xlsx.Cell{{Value: "Parcela*"}, {Value: "Espécime*"}, {Value: "CAP1*"}, {Value: "Situação"}, {Value: "Fitossanidade"}, {Value: "Bifurcação"}, {Value: "Observações"}}},
+		{Number: 2, Cells: []xlsx.Cell{{Value: "1"}, {Value: "A a"}, {Value: "30", Numeric: true}, {Value: "Morta em pé"}, {Value: "Ruim"}, {Value: "abaixo do DAP"}, {Value: " cupim "}}},
+		{Number: 3, Cells: []xlsx.Cell{{Value: "1"}, {Value: "A a"}, {Value: "30", Numeric: true}, {Value: "resprouting"}}},
+	}
+
+	headerIdx, columns := findImportHeader(rows)
+	require.Equal(t, 0, headerIdx)
+
+	first := specimenFromRow(rows[1], columns)
+	require.Equal(t, types.SpecimenStatusDeadStanding, first.Status)
+	require.Equal(t, types.PhytosanitaryPoor, *first.Phytosanitary)
+	require.Equal(t, "abaixo do DAP", *first.BifurcationNotes)
+	require.Equal(t, "cupim", *first.Observations)
+
+	second := specimenFromRow(rows[2], columns)
+	require.Equal(t, types.SpecimenStatusResprouting, second.Status)
+	require.Nil(t, second.Phytosanitary)
+}
+
+func TestImportTemplate_ConditionColumns(t *testing.T) {
+	t.Parallel()
+
+	data, err := phytoTemplatesFS.ReadFile("templates/specimens_import_template.xlsx")
+	require.NoError(t, err)
+	rows, err := xlsx.ReadFirstSheet(bytes.NewReader(data), int64(len(data)), maxImportColumns)
+	require.NoError(t, err)
+
+	_, columns := findImportHeader(rows)
+	for _, field := range []int{colStatus, colPhytosanitary, colBifurcation, colObservations} {
+		require.Contains(t, columns, field)
+	}
+}
@@ -0,0 +1,209 @@
+package phytoanalysishttp
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	appphyto "github.com/ESG-Project/suassu-api/internal/app/phytoanalysis"
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+	"github.com/ESG-Project/suassu-api/internal/apperr"
+	phytodto "github.com/ESG-Project/suassu-api/internal/http/dto/phytoanalysis"
+	"github.com/ESG-Project/suassu-api/internal/http/pagination"
+)
+
+// Formatos da listagem de espécimes; ndjson e csv são transmitidos sem paginação
+const (
+	specimenFormatJSON   = "json"
+	specimenFormatNDJSON = "ndjson"
+	specimenFormatCSV    = "csv"
+)
+
+// streamFlushEvery define a cada quantas linhas a resposta transmitida é enviada ao cliente
+const streamFlushEvery = 500
+
+// specimenListQuery representa os parâmetros da listagem de espécimes
+type specimenListQuery struct {
+	Filter types.SpecimenFilter
+	Format string
+	Limit  int32
+	After  *types.SpecimenCursorKey
+}
+
+// parseSpecimenListQuery lê filtros, ordenação, paginação e formato da query
+func parseSpecimenListQuery(req *http.Request) (specimenListQuery, error) {
+	q := req.URL.Query()
+	out := specimenListQuery{
+		Filter: types.SpecimenFilter{
+			Portion:         strings.TrimSpace(q.Get("portion")),
+			SpecieID:        strings.TrimSpace(q.Get("speciesId")),
+			MorphospeciesID: strings.TrimSpace(q.Get("morphospeciesId")),
+			Tag:             strings.TrimSpace(q.Get("tag")),
+			Status:          strings.ToLower(strings.TrimSpace(q.Get("status"))),
+			Phytosanitary:   strings.ToLower(strings.TrimSpace(q.Get("phytosanitary"))),
+			ScientificName:  strings.TrimSpace(q.Get("scientificName")),
+			Family:          strings.TrimSpace(q.Get("family")),
+			SortBy:          types.SpecimenSortPortion,
+		},
+		Format: specimenFormatJSON,
+		Limit:  parseInt32(q.Get("limit"), appphyto.DefaultSpecimenPageSize),
+	}
+	if out.Limit <= 0 {
+		out.Limit = appphyto.DefaultSpecimenPageSize
+	}
+	if out.Limit > appphyto.MaxSpecimenPageSize {
+		out.Limit = appphyto.MaxSpecimenPageSize
+	}
+
+	ranges := []struct {
+		name string
+		dst  **float64
+	}{
+		{"dbhMin", &out.Filter.MinDbhCm},
+		{"dbhMax", &out.Filter.MaxDbhCm},
+		{"measuredHeightMin", &out.Filter.MinMeasuredHeight},
+		{"measuredHeightMax", &out.Filter.MaxMeasuredHeight},
+	}
+	for _, p := range ranges {
+		raw := q.Get(p.name)
+		if raw == "" {
+			continue
+		}
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil || math.IsNaN(v) || math.IsInf(v, 0) || v < 0 {
+			return out, apperr.New(apperr.CodeInvalid, "invalid "+p.name)
+		}
+		*p.dst = &v
+	}
+
+	if sort := q.Get("sort"); sort != "" {
+		out.Filter.SortBy = sort
+	}
+
+	switch strings.ToLower(q.Get("order")) {
+	case "", "asc":
+	case "desc":
+		out.Filter.Desc = true
+	default:
+		return out, apperr.New(apperr.CodeInvalid, "invalid order")
+	}
+
+	switch format := strings.ToLower(q.Get("format")); format {
+	case "", specimenFormatJSON:
+	case specimenFormatNDJSON, specimenFormatCSV:
+		out.Format = format
+	default:
+		return out, apperr.New(apperr.CodeInvalid, "invalid format")
+	}
+
+	if raw := q.Get("cursor"); raw != "" {
+		after, err := parseSpecimenCursor(raw, out.Filter)
+		if err != nil {
+			return out, err
+		}
+		out.After = after
+	}
+
+	return out, nil
+}
+
+// specimenCursorTimeLayout é o formato do texto de um timestamp no Postgres
+const specimenCursorTimeLayout = "2006-01-02 15:04:05.999999"
+
+// parseSpecimenCursor decodifica o cursor, exige a mesma ordenação da consulta e converte
+// o valor para o tipo do campo ordenado
+func parseSpecimenCursor(raw string, f types.SpecimenFilter) (*types.SpecimenCursorKey, error) {
+	var after types.SpecimenCursorKey
+	if err := pagination.Decode(raw, &after); err != nil || after.ID == "" {
+		return nil, apperr.New(apperr.CodeInvalid, "invalid cursor format")
+	}
+	if after.SortBy != f.SortBy || after.Desc != f.Desc {
+		return nil, apperr.New(apperr.CodeInvalid, "cursor does not match the requested sort")
+	}
+
+	switch f.SortBy {
+	case types.SpecimenSortDbh, types.SpecimenSortMeasuredHeight:
+		v, err := strconv.ParseFloat(after.Value, 64)
+		if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, apperr.New(apperr.CodeInvalid, "invalid cursor format")
+		}
+		after.Number = &v
+	case types.SpecimenSortRegisterDate:
+		t, err := time.Parse(specimenCursorTimeLayout, after.Value)
+		if err != nil {
+			return nil, apperr.New(apperr.CodeInvalid, "invalid cursor format")
+		}
+		after.Time = &t
+	}
+	return &after, nil
+}
+
+// specimenStreamWriter escreve os espécimes um a um no formato pedido (ndjson ou csv)
+type specimenStreamWriter struct {
+	w       http.ResponseWriter
+	format  string
+	enc     *json.Encoder
+	csv     *csv.Writer
+	written int
+}
+
+func newSpecimenStreamWriter(w http.ResponseWriter, format, phytoID string) *specimenStreamWriter {
+	sw := &specimenStreamWriter{w: w, format: format}
+
+	if format == specimenFormatCSV {
+		w.Header().Set("Content-Disposition", "attachment; filename=specimens-"+phytoID+".csv")
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		sw.csv = csv.NewWriter(w)
+		_ = sw.csv.Write(phytodto.SpecimenCSVHeader)
+		return sw
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	sw.enc = json.NewEncoder(w)
+	return sw
+}
+
+func (sw *specimenStreamWriter) Write(s phytodto.SpecimenResponse) error {
+	var err error
+	if sw.csv != nil {
+		err = sw.csv.Write(phytodto.ToSpecimenCSVRecord(s))
+	} else {
+		err = sw.enc.Encode(s)
+	}
+	if err != nil {
+		return err
+	}
+
+	sw.written++
+	if sw.written%streamFlushEvery == 0 {
+		sw.Flush()
+	}
+	return nil
+}
+
+// Fail encerra a transmissão após um erro. No NDJSON grava o erro como último registro; no CSV,
+// sem onde sinalizar, aborta a conexão para que o cliente não receba um arquivo truncado como completo
+func (sw *specimenStreamWriter) Fail(record map[string]any) {
+	if sw.enc != nil {
+		_ = sw.enc.Encode(record)
+		sw.Flush()
+		return
+	}
+	sw.Flush()
+	panic(http.ErrAbortHandler)
+}
+
+func (sw *specimenStreamWriter) Flush() {
+	if sw.csv != nil {
+		sw.csv.Flush()
+	}
+	if f, ok := sw.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -0,0 +1,89 @@
+package phytoanalysishttp
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	appphyto "github.com/ESG-Project/suassu-api/internal/app/phytoanalysis"
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+	"github.com/ESG-Project/suassu-api/internal/apperr"
+	"github.com/ESG-Project/suassu-api/internal/http/pagination"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSpecimenListQuery(t *testing.T) {
+	t.Parallel()
+
+	cursor, err := pagination.Encode(types.SpecimenCursorKey{SortBy: types.SpecimenSortDbh, Desc: true, Value: "12.5", ID: "sp-9"})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest("GET", "/x/specimens?family=Fabaceae&dbhMin=5&dbhMax=30&sort=dbh&order=desc&limit=20&cursor="+cursor, nil)
+	q, err := parseSpecimenListQuery(req)
+
+	require.NoError(t, err)
+	require.Equal(t, "Fabaceae", q.Filter.Family)
+	require.InDelta(t, 5, *q.Filter.MinDbhCm, 1e-12)
+	require.InDelta(t, 30, *q.Filter.MaxDbhCm, 1e-12)
+	require.Nil(t, q.Filter.MinMeasuredHeight)
+	require.Equal(t, types.SpecimenSortDbh, q.Filter.SortBy)
+	require.True(t, q.Filter.Desc)
+	require.EqualValues(t, 20, q.Limit)
+	require.Equal(t, specimenFormatJSON, q.Format)
+	require.Equal(t, "sp-9", q.After.ID)
+	require.InDelta(t, 12.5, *q.After.Number, 1e-12)
+
+	// limites padrão e máximo da página
+	q, err = parseSpecimenListQuery(httptest.NewRequest("GET", "/x/specimens?format=CSV", nil))
+	require.NoError(t, err)
+	require.EqualValues(t, appphyto.DefaultSpecimenPageSize, q.Limit)
+	require.Equal(t, specimenFormatCSV, q.Format)
+	require.Equal(t, types.SpecimenSortPortion, q.Filter.SortBy)
+
+	q, err = parseSpecimenListQuery(httptest.NewRequest("GET", "/x/specimens?limit=99999", nil))
+	require.NoError(t, err)
+	require.EqualValues(t, appphyto.MaxSpecimenPageSize, q.Limit)
+
+	for _, raw := range []string{"format=xml", "order=up", "measuredHeightMin=-1", "dbhMax=abc", "cursor=bm90LWpzb24"} {
+		_, err := parseSpecimenListQuery(httptest.NewRequest("GET", "/x/specimens?"+raw, nil))
+		require.Error(t, err, raw)
+		require.Equal(t, apperr.CodeInvalid, apperr.CodeOf(err), raw)
+	}
+}
+
+func TestParseSpecimenListQuery_Cursor(t *testing.T) {
+	t.Parallel()
+
+	encode := func(key types.SpecimenCursorKey) string {
+		raw, err := pagination.Encode(key)
+		require.NoError(t, err)
+		return raw
+	}
+
+	// data de registro no formato do Postgres
+	q, err := parseSpecimenListQuery(httptest.NewRequest("GET", "/x/specimens?sort=registerDate&cursor="+
+		encode(types.SpecimenCursorKey{SortBy: types.SpecimenSortRegisterDate, Value: "2024-03-01 10:20:30.5", ID: "sp-1"}), nil))
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2024, 3, 1, 10, 20, 30, 500000000, time.UTC), *q.After.Time)
+
+	// texto segue como texto
+	q, err = parseSpecimenListQuery(httptest.NewRequest("GET", "/x/specimens?cursor="+
+		encode(types.SpecimenCursorKey{SortBy: types.SpecimenSortPortion, Value: "P1", ID: "sp-1"}), nil))
+	require.NoError(t, err)
+	require.Equal(t, "P1", q.After.Value)
+	require.Nil(t, q.After.Number)
+
+	invalid := map[string]types.SpecimenCursorKey{
+		"sort=dbh":                {SortBy: types.SpecimenSortDbh, Value: "abc", ID: "sp-1"},
+		"sort=measuredHeight":     {SortBy: types.SpecimenSortMeasuredHeight, Value: "NaN", ID: "sp-1"},
+		"sort=registerDate":       {SortBy: types.SpecimenSortRegisterDate, Value: "ontem", ID: "sp-1"},
+		"sort=dbh&order=desc":     {SortBy: types.SpecimenSortDbh, Value: "12.5", ID: "sp-1"},
+		"sort=family":             {SortBy: types.SpecimenSortDbh, Value: "12.5", ID: "sp-1"},
+		"sort=portion&order=desc": {SortBy: types.SpecimenSortPortion, Value: "P1", ID: "sp-1"},
+	}
+	for raw, key := range invalid {
+		_, err := parseSpecimenListQuery(httptest.NewRequest("GET", "/x/specimens?"+raw+"&cursor="+encode(key), nil))
+		require.Error(t, err, raw)
+		require.Equal(t, apperr.CodeInvalid, apperr.CodeOf(err), raw)
+	}
+}
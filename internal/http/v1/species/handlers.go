@@ -1,10 +1,12 @@
 package specieshttp
 
 import (
+	"encoding/json"
 	"net/http"
 	"strconv"
 
 	appspecies "github.com/ESG-Project/suassu-api/internal/app/species"
+	"github.com/ESG-Project/suassu-api/internal/apperr"
 	speciesdto "github.com/ESG-Project/suassu-api/internal/http/dto/species"
 	"github.com/ESG-Project/suassu-api/internal/http/httperr"
 	"github.com/ESG-Project/suassu-api/internal/http/response"
@@ -62,6 +64,26 @@ func Routes(svc Service) chi.Router {
 		response.JSON(w, http.StatusOK, out, nil)
 	})
 
+	// PUT /species/{id}/wood-density - Definir densidade básica da madeira (g/cm³)
+	// Snapshots de indicadores já gravados só refletem o novo valor após recálculo da análise
+	r.Put("/{id}/wood-density", func(w http.ResponseWriter, req *http.Request) {
+		id := chi.URLParam(req, "id")
+
+		var in speciesdto.UpdateWoodDensityRequest
+		if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+			httperr.Handle(w, req, apperr.New(apperr.CodeInvalid, "invalid body"))
+			return
+		}
+
+		species, err := svc.UpdateWoodDensity(req.Context(), id, in.WoodDensity)
+		if err != nil {
+			httperr.Handle(w, req, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, speciesdto.ToSpeciesResponse(species), nil)
+	})
+
 	return r
 }
 
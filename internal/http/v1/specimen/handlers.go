@@ -18,7 +18,7 @@ type Service = appspecimen.ServiceInterface
 func Routes(svc Service) chi.Router {
 	r := chi.NewRouter()
 
-	// POST /specimens - Criar novo specimen
+	// POST /specimens - Criar novo specimen (CAP em cm, sem o protocolo de medição da análise)
 	r.Post("/", func(w http.ResponseWriter, req *http.Request) {
 		var in specimendto.CreateSpecimenRequest
 		if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
@@ -27,17 +27,25 @@ func Routes(svc Service) chi.Router {
 		}
 
 		createInput := appspecimen.CreateInput{
-			Portion:         in.Portion,
-			Height:          in.Height,
-			Cap1:            in.Cap1,
-			Cap2:            in.Cap2,
-			Cap3:            in.Cap3,
-			Cap4:            in.Cap4,
-			Cap5:            in.Cap5,
-			Cap6:            in.Cap6,
-			RegisterDate:    in.RegisterDate,
-			PhytoAnalysisID: in.PhytoAnalysisID,
-			SpecieID:        in.SpecieID,
+			Portion:          in.Portion,
+			Height:           in.Height,
+			Cap1:             in.Cap1,
+			Cap2:             in.Cap2,
+			Cap3:             in.Cap3,
+			Cap4:             in.Cap4,
+			Cap5:             in.Cap5,
+			Cap6:             in.Cap6,
+			RegisterDate:     in.RegisterDate,
+			PhytoAnalysisID:  in.PhytoAnalysisID,
+			SpecieID:         in.SpecieID,
+			MorphospeciesID:  in.MorphospeciesID,
+			Latitude:         in.Latitude,
+			Longitude:        in.Longitude,
+			Tag:              in.Tag,
+			Status:           in.Status,
+			Phytosanitary:    in.Phytosanitary,
+			BifurcationNotes: in.BifurcationNotes,
+			Observations:     in.Observations,
 		}
 
 		id, err := svc.Create(req.Context(), createInput)
@@ -74,16 +82,24 @@ func Routes(svc Service) chi.Router {
 		}
 
 		updateInput := appspecimen.UpdateInput{
-			Portion:      in.Portion,
-			Height:       in.Height,
-			Cap1:         in.Cap1,
-			Cap2:         in.Cap2,
-			Cap3:         in.Cap3,
-			Cap4:         in.Cap4,
-			Cap5:         in.Cap5,
-			Cap6:         in.Cap6,
-			RegisterDate: in.RegisterDate,
-			SpecieID:     in.SpecieID,
+			Portion:          in.Portion,
+			Height:           in.Height,
+			Cap1:             in.Cap1,
+			Cap2:             in.Cap2,
+			Cap3:             in.Cap3,
+			Cap4:             in.Cap4,
+			Cap5:             in.Cap5,
+			Cap6:             in.Cap6,
+			RegisterDate:     in.RegisterDate,
+			SpecieID:         in.SpecieID,
+			MorphospeciesID:  in.MorphospeciesID,
+			Latitude:         in.Latitude,
+			Longitude:        in.Longitude,
+			Tag:              in.Tag,
+			Status:           in.Status,
+			Phytosanitary:    in.Phytosanitary,
+			BifurcationNotes: in.BifurcationNotes,
+			Observations:     in.Observations,
 		}
 
 		if err := svc.Update(req.Context(), id, updateInput); err != nil {
@@ -0,0 +1,49 @@
+package specimenhttp_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	appspecimen "github.com/ESG-Project/suassu-api/internal/app/specimen"
+	specimenhttp "github.com/ESG-Project/suassu-api/internal/http/v1/specimen"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSvc guarda as entradas recebidas; os demais métodos não são chamados
+type fakeSvc struct {
+	appspecimen.ServiceInterface
+	created *appspecimen.CreateInput
+	updated *appspecimen.UpdateInput
+}
+
+func (f *fakeSvc) Create(ctx context.Context, in appspecimen.CreateInput) (string, error) {
+	f.created = &in
+	return "sp-1", nil
+}
+
+func (f *fakeSvc) Update(ctx context.Context, id string, in appspecimen.UpdateInput) error {
+	f.updated = &in
+	return nil
+}
+
+// Os fustes da escrita individual são CAP em cm: o protocolo de medição da análise não converte
+func TestSpecimenWrites_TakeCapInCm(t *testing.T) {
+	svc := &fakeSvc{}
+	router := specimenhttp.Routes(svc)
+
+	body := `{"portion":"P1","cap1":31.4,"cap2":15.7,"registerDate":"2024-03-01T00:00:00Z","phytoAnalysisId":"phyto-1","specieId":"sp"}`
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(body)))
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.InDelta(t, 31.4, svc.created.Cap1, 1e-12)
+	require.InDelta(t, 15.7, *svc.created.Cap2, 1e-12)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/sp-1", bytes.NewBufferString(body)))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.InDelta(t, 31.4, svc.updated.Cap1, 1e-12)
+	require.InDelta(t, 15.7, *svc.updated.Cap2, 1e-12)
+}
@@ -0,0 +1,125 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+	"github.com/ESG-Project/suassu-api/internal/apperr"
+	"github.com/ESG-Project/suassu-api/internal/infra/db/postgres/utils"
+	sqlc "github.com/ESG-Project/suassu-api/internal/infra/db/sqlc/gen"
+)
+
+type EquationRepo struct {
+	q *sqlc.Queries
+}
+
+func NewEquationRepo(db *sql.DB) *EquationRepo {
+	return &EquationRepo{q: sqlc.New(db)}
+}
+
+func NewEquationRepoFrom(d dbtx) *EquationRepo {
+	return &EquationRepo{q: sqlc.New(d)}
+}
+
+func (r *EquationRepo) Create(ctx context.Context, eq *types.EquationData) error {
+	coefficients, applicability, err := marshalEquation(eq)
+	if err != nil {
+		return err
+	}
+
+	return r.q.CreateEquation(ctx, sqlc.CreateEquationParams{
+		ID:            eq.ID,
+		Name:          eq.Name,
+		Kind:          eq.Kind,
+		Expression:    eq.Expression,
+		Coefficients:  coefficients,
+		Applicability: applicability,
+		Reference:     utils.ToNullString(eq.Reference),
+		CreatedAt:     eq.CreatedAt,
+		UpdatedAt:     eq.UpdatedAt,
+	})
+}
+
+func (r *EquationRepo) GetByID(ctx context.Context, id string) (*types.EquationData, error) {
+	row, err := r.q.GetEquationByID(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, apperr.New(apperr.CodeNotFound, "equation not found")
+		}
+		return nil, err
+	}
+	return toEquationData(row)
+}
+
+func (r *EquationRepo) List(ctx context.Context) ([]*types.EquationData, error) {
+	rows, err := r.q.ListEquations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*types.EquationData, 0, len(rows))
+	for _, row := range rows {
+		eq, err := toEquationData(row)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, eq)
+	}
+	return result, nil
+}
+
+func (r *EquationRepo) Update(ctx context.Context, eq *types.EquationData) error {
+	coefficients, applicability, err := marshalEquation(eq)
+	if err != nil {
+		return err
+	}
+
+	return r.q.UpdateEquation(ctx, sqlc.UpdateEquationParams{
+		ID:            eq.ID,
+		Name:          eq.Name,
+		Expression:    eq.Expression,
+		Coefficients:  coefficients,
+		Applicability: applicability,
+		Reference:     utils.ToNullString(eq.Reference),
+		UpdatedAt:     eq.UpdatedAt,
+	})
+}
+
+func (r *EquationRepo) Delete(ctx context.Context, id string) error {
+	return r.q.DeleteEquation(ctx, id)
+}
+
+func marshalEquation(eq *types.EquationData) (coefficients, applicability json.RawMessage, err error) {
+	coef := eq.Coefficients
+	if coef == nil {
+		coef = map[string]float64{}
+	}
+	if coefficients, err = json.Marshal(coef); err != nil {
+		return nil, nil, err
+	}
+	if applicability, err = json.Marshal(eq.Applicability); err != nil {
+		return nil, nil, err
+	}
+	return coefficients, applicability, nil
+}
+
+func toEquationData(row sqlc.Equation) (*types.EquationData, error) {
+	eq := &types.EquationData{
+		ID:         row.ID,
+		Name:       row.Name,
+		Kind:       row.Kind,
+		Expression: row.Expression,
+		Reference:  utils.FromNullString(row.Reference),
+		CreatedAt:  row.CreatedAt,
+		UpdatedAt:  row.UpdatedAt,
+	}
+	if err := json.Unmarshal(row.Coefficients, &eq.Coefficients); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(row.Applicability, &eq.Applicability); err != nil {
+		return nil, err
+	}
+	return eq, nil
+}
@@ -0,0 +1,109 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+	"github.com/ESG-Project/suassu-api/internal/apperr"
+	domainmorphospecies "github.com/ESG-Project/suassu-api/internal/domain/morphospecies"
+	"github.com/ESG-Project/suassu-api/internal/infra/db/postgres/utils"
+	sqlc "github.com/ESG-Project/suassu-api/internal/infra/db/sqlc/gen"
+)
+
+type MorphospeciesRepo struct {
+	q *sqlc.Queries
+}
+
+func NewMorphospeciesRepo(db *sql.DB) *MorphospeciesRepo {
+	return &MorphospeciesRepo{q: sqlc.New(db)}
+}
+
+func NewMorphospeciesRepoFrom(d dbtx) *MorphospeciesRepo {
+	return &MorphospeciesRepo{q: sqlc.New(d)}
+}
+
+func (r *MorphospeciesRepo) Create(ctx context.Context, m *domainmorphospecies.Morphospecies) error {
+	return r.q.CreateMorphospecies(ctx, sqlc.CreateMorphospeciesParams{
+		ID:              m.ID,
+		PhytoAnalysisID: m.PhytoAnalysisID,
+		Name:            m.Name,
+		Family:          utils.ToNullString(m.Family),
+		Genus:           utils.ToNullString(m.Genus),
+		CreatedAt:       m.CreatedAt,
+		UpdatedAt:       m.UpdatedAt,
+	})
+}
+
+func (r *MorphospeciesRepo) GetByID(ctx context.Context, phytoAnalysisID, id string) (*types.MorphospeciesData, error) {
+	row, err := r.q.GetMorphospeciesByID(ctx, sqlc.GetMorphospeciesByIDParams{ID: id, PhytoAnalysisID: phytoAnalysisID})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, apperr.New(apperr.CodeNotFound, "morphospecies not found")
+		}
+		return nil, err
+	}
+	return &types.MorphospeciesData{
+		ID:              row.ID,
+		PhytoAnalysisID: row.PhytoAnalysisID,
+		Name:            row.Name,
+		Family:          utils.FromNullString(row.Family),
+		Genus:           utils.FromNullString(row.Genus),
+		CreatedAt:       row.CreatedAt,
+		UpdatedAt:       row.UpdatedAt,
+	}, nil
+}
+
+// ListByPhytoAnalysis lista as morfoespécies da análise com a quantidade de espécimes de cada uma
+func (r *MorphospeciesRepo) ListByPhytoAnalysis(ctx context.Context, phytoAnalysisID string) ([]*types.MorphospeciesData, error) {
+	rows, err := r.q.ListMorphospeciesByPhytoAnalysis(ctx, phytoAnalysisID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*types.MorphospeciesData, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, &types.MorphospeciesData{
+			ID:              row.ID,
+			PhytoAnalysisID: row.PhytoAnalysisID,
+			Name:            row.Name,
+			Family:          utils.FromNullString(row.Family),
+			Genus:           utils.FromNullString(row.Genus),
+			SpecimenCount:   row.SpecimenCount,
+			CreatedAt:       row.CreatedAt,
+			UpdatedAt:       row.UpdatedAt,
+		})
+	}
+	return result, nil
+}
+
+func (r *MorphospeciesRepo) Update(ctx context.Context, m *domainmorphospecies.Morphospecies) error {
+	return r.q.UpdateMorphospecies(ctx, sqlc.UpdateMorphospeciesParams{
+		ID:              m.ID,
+		PhytoAnalysisID: m.PhytoAnalysisID,
+		Name:            m.Name,
+		Family:          utils.ToNullString(m.Family),
+		Genus:           utils.ToNullString(m.Genus),
+		UpdatedAt:       m.UpdatedAt,
+	})
+}
+
+func (r *MorphospeciesRepo) Delete(ctx context.Context, phytoAnalysisID, id string) error {
+	return r.q.DeleteMorphospecies(ctx, sqlc.DeleteMorphospeciesParams{ID: id, PhytoAnalysisID: phytoAnalysisID})
+}
+
+// CountSpecimens conta os espécimes associados à morfoespécie
+func (r *MorphospeciesRepo) CountSpecimens(ctx context.Context, id string) (int64, error) {
+	return r.q.CountSpecimensByMorphospecies(ctx, utils.StringToNullString(id))
+}
+
+// ResolveSpecimens associa os espécimes da morfoespécie à espécie do cadastro e
+// retorna a quantidade de espécimes alterados
+func (r *MorphospeciesRepo) ResolveSpecimens(ctx context.Context, id, speciesID string) (int64, error) {
+	return r.q.ResolveMorphospeciesSpecimens(ctx, sqlc.ResolveMorphospeciesSpecimensParams{
+		MorphospeciesID: utils.StringToNullString(id),
+		SpecieID:        utils.StringToNullString(speciesID),
+		UpdatedAt:       time.Now(),
+	})
+}
@@ -3,7 +3,12 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
 
+	"github.com/ESG-Project/suassu-api/internal/app/phytometrics"
 	"github.com/ESG-Project/suassu-api/internal/app/types"
 	"github.com/ESG-Project/suassu-api/internal/apperr"
 	domainphyto "github.com/ESG-Project/suassu-api/internal/domain/phytoanalysis"
@@ -12,30 +17,37 @@ import (
 )
 
 type PhytoAnalysisRepo struct {
-	q *sqlc.Queries
+	q  *sqlc.Queries
+	db dbtx
 }
 
 func NewPhytoAnalysisRepoFrom(d dbtx) *PhytoAnalysisRepo {
-	return &PhytoAnalysisRepo{q: sqlc.New(d)}
+	return &PhytoAnalysisRepo{q: sqlc.New(d), db: d}
 }
 
 func NewPhytoAnalysisRepo(db *sql.DB) *PhytoAnalysisRepo {
-	return &PhytoAnalysisRepo{q: sqlc.New(db)}
+	return &PhytoAnalysisRepo{q: sqlc.New(db), db: db}
 }
 
 func (r *PhytoAnalysisRepo) Create(ctx context.Context, p *domainphyto.PhytoAnalysis) error {
 	_, err := r.q.CreatePhytoAnalysis(ctx, sqlc.CreatePhytoAnalysisParams{
-		ID:              p.ID,
-		Title:           p.Title,
-		InitialDate:     p.InitialDate,
-		PortionQuantity: int32(p.PortionQuantity),
-		PortionArea:     utils.Float64ToString(p.PortionArea),
-		TotalArea:       utils.Float64ToString(p.TotalArea),
-		SampledArea:     utils.Float64ToString(p.SampledArea),
-		Description:     utils.ToNullString(p.Description),
-		ProjectID:       p.ProjectID,
-		CreatedAt:       p.CreatedAt,
-		UpdatedAt:       p.UpdatedAt,
+		ID:                p.ID,
+		Title:             p.Title,
+		InitialDate:       p.InitialDate,
+		PortionQuantity:   int32(p.PortionQuantity),
+		PortionArea:       utils.Float64ToString(p.PortionArea),
+		TotalArea:         utils.Float64ToString(p.TotalArea),
+		SampledArea:       utils.Float64ToString(p.SampledArea),
+		Description:       utils.ToNullString(p.Description),
+		ProjectID:         p.ProjectID,
+		CreatedAt:         p.CreatedAt,
+		UpdatedAt:         p.UpdatedAt,
+		DefaultFormFactor: utils.Float64PtrToString(p.DefaultFormFactor),
+		MeasurementMode:   p.MeasurementMode,
+		MeasurementUnit:   p.MeasurementUnit,
+		InclusionMin:      utils.Float64PtrToString(p.InclusionMin),
+		InclusionPolicy:   p.InclusionPolicy,
+		ExcludeDead:       p.ExcludeDead,
 	})
 	return err
 }
@@ -177,15 +189,41 @@ func (r *PhytoAnalysisRepo) ListAll(ctx context.Context, limit, offset int32) ([
 
 func (r *PhytoAnalysisRepo) Update(ctx context.Context, p *domainphyto.PhytoAnalysis) error {
 	return r.q.UpdatePhytoAnalysis(ctx, sqlc.UpdatePhytoAnalysisParams{
-		ID:              p.ID,
-		Title:           p.Title,
-		InitialDate:     p.InitialDate,
-		PortionQuantity: int32(p.PortionQuantity),
-		PortionArea:     utils.Float64ToString(p.PortionArea),
-		TotalArea:       utils.Float64ToString(p.TotalArea),
-		SampledArea:     utils.Float64ToString(p.SampledArea),
-		Description:     utils.ToNullString(p.Description),
-		UpdatedAt:       p.UpdatedAt,
+		ID:                p.ID,
+		Title:             p.Title,
+		InitialDate:       p.InitialDate,
+		PortionQuantity:   int32(p.PortionQuantity),
+		PortionArea:       utils.Float64ToString(p.PortionArea),
+		TotalArea:         utils.Float64ToString(p.TotalArea),
+		SampledArea:       utils.Float64ToString(p.SampledArea),
+		Description:       utils.ToNullString(p.Description),
+		UpdatedAt:         p.UpdatedAt,
+		DefaultFormFactor: utils.Float64PtrToString(p.DefaultFormFactor),
+		MeasurementMode:   p.MeasurementMode,
+		MeasurementUnit:   p.MeasurementUnit,
+		InclusionMin:      utils.Float64PtrToString(p.InclusionMin),
+		InclusionPolicy:   p.InclusionPolicy,
+		ExcludeDead:       p.ExcludeDead,
+	})
+}
+
+// UpdateSampling atualiza o número de parcelas e as áreas derivadas das parcelas cadastradas
+func (r *PhytoAnalysisRepo) UpdateSampling(ctx context.Context, id string, portionQuantity int, portionArea, sampledArea float64) error {
+	return r.q.UpdatePhytoAnalysisSampling(ctx, sqlc.UpdatePhytoAnalysisSamplingParams{
+		ID:              id,
+		PortionQuantity: int32(portionQuantity),
+		PortionArea:     utils.Float64ToString(portionArea),
+		SampledArea:     utils.Float64ToString(sampledArea),
+		UpdatedAt:       time.Now(),
+	})
+}
+
+// UpdateTotalArea grava a área total (ha), derivada dos estratos cadastrados
+func (r *PhytoAnalysisRepo) UpdateTotalArea(ctx context.Context, id string, totalArea float64) error {
+	return r.q.UpdatePhytoAnalysisTotalArea(ctx, sqlc.UpdatePhytoAnalysisTotalAreaParams{
+		ID:        id,
+		TotalArea: utils.Float64ToString(totalArea),
+		UpdatedAt: time.Now(),
 	})
 }
 
@@ -239,7 +277,15 @@ func (r *PhytoAnalysisRepo) GetWithSpecimens(ctx context.Context, id string) (*t
 		ProjectLatitude:     utils.FromNullString(firstRow.ProjectLatitude),
 		ProjectLongitude:    utils.FromNullString(firstRow.ProjectLongitude),
 		ProjectAddInfo:      utils.FromNullString(firstRow.ProjectAddInfo),
-		Specimens:           make([]*types.SpecimenWithSpecies, 0),
+		DefaultFormFactor:   utils.NullStringToNullFloat64(firstRow.DefaultFormFactor),
+		Measurement: types.MeasurementProtocol{
+			Mode:            firstRow.MeasurementMode,
+			Unit:            firstRow.MeasurementUnit,
+			InclusionMin:    utils.NullStringToNullFloat64(firstRow.InclusionMin),
+			InclusionPolicy: firstRow.InclusionPolicy,
+			ExcludeDead:     firstRow.ExcludeDead,
+		},
+		Specimens: make([]*types.SpecimenWithSpecies, 0),
 	}
 
 	// Agregar specimens
@@ -249,29 +295,678 @@ func (r *PhytoAnalysisRepo) GetWithSpecimens(ctx context.Context, id string) (*t
 			continue
 		}
 
-		height := utils.NullStringToNullFloat64(row.Height)
+		height, _ := utils.StringToFloat64(row.Height.String) // 0 quando não medida
 		cap1 := utils.NullStringToNullFloat64(row.Cap1)
 
 		specimen := &types.SpecimenWithSpecies{
-			ID:              row.SpecimenID.String,
-			Portion:         row.Portion.String,
-			Height:          *height,
-			Cap1:            *cap1,
-			Cap2:            utils.NullStringToNullFloat64(row.Cap2),
-			Cap3:            utils.NullStringToNullFloat64(row.Cap3),
-			Cap4:            utils.NullStringToNullFloat64(row.Cap4),
-			Cap5:            utils.NullStringToNullFloat64(row.Cap5),
-			Cap6:            utils.NullStringToNullFloat64(row.Cap6),
-			RegisterDate:    row.RegisterDate.Time,
-			PhytoAnalysisID: firstRow.PhytoID,
-			SpecieID:        row.SpecieID.String,
-			ScientificName:  row.ScientificName.String,
-			Family:          row.Family.String,
-			PopularName:     utils.FromNullString(row.PopularName),
+			ID:               row.SpecimenID.String,
+			Portion:          row.Portion.String,
+			Height:           height,
+			Cap1:             *cap1,
+			Cap2:             utils.NullStringToNullFloat64(row.Cap2),
+			Cap3:             utils.NullStringToNullFloat64(row.Cap3),
+			Cap4:             utils.NullStringToNullFloat64(row.Cap4),
+			Cap5:             utils.NullStringToNullFloat64(row.Cap5),
+			Cap6:             utils.NullStringToNullFloat64(row.Cap6),
+			RegisterDate:     row.RegisterDate.Time,
+			PhytoAnalysisID:  firstRow.PhytoID,
+			SpecieID:         row.SpecieID.String,
+			MorphospeciesID:  utils.FromNullString(row.MorphospeciesID),
+			Latitude:         utils.NullStringToNullFloat64(row.Latitude),
+			Longitude:        utils.NullStringToNullFloat64(row.Longitude),
+			Tag:              utils.FromNullString(row.Tag),
+			Status:           row.Status.String,
+			Phytosanitary:    utils.FromNullString(row.Phytosanitary),
+			BifurcationNotes: utils.FromNullString(row.BifurcationNotes),
+			Observations:     utils.FromNullString(row.Observations),
+			ScientificName:   row.ScientificName.String,
+			Family:           row.Family.String,
+			PopularName:      utils.FromNullString(row.PopularName),
 		}
 
 		result.Specimens = append(result.Specimens, specimen)
 	}
 
+	// Fator de forma da legislação aplicável, densidade da madeira, hábito e proteção de cada espécie
+	speciesIDs := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, s := range result.Specimens {
+		if s.SpecieID != "" && !seen[s.SpecieID] {
+			seen[s.SpecieID] = true
+			speciesIDs = append(speciesIDs, s.SpecieID)
+		}
+	}
+
+	formFactors, err := getFormFactorsBySpeciesIDs(ctx, r.db, speciesIDs)
+	if err != nil {
+		return nil, err
+	}
+	traits, err := getSpeciesTraitsBySpeciesIDs(ctx, r.db, speciesIDs)
+	if err != nil {
+		return nil, err
+	}
+	legislations, err := getActiveLegislationsBySpeciesIDs(ctx, r.db, speciesIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range result.Specimens {
+		if ff, ok := formFactors[s.SpecieID]; ok {
+			s.FormFactor = &ff
+		}
+		if t, ok := traits[s.SpecieID]; ok {
+			s.WoodDensity = t.WoodDensity
+			s.Habit = t.Habit
+		}
+		if l, ok := legislations[s.SpecieID]; ok {
+			applyLegislations(s, l)
+		}
+	}
+
+	if err := r.loadEquations(ctx, result); err != nil {
+		return nil, err
+	}
+	if err := r.loadPlots(ctx, result); err != nil {
+		return nil, err
+	}
+	if err := r.loadStrata(ctx, result); err != nil {
+		return nil, err
+	}
+	if err := r.loadCampaign(ctx, result); err != nil {
+		return nil, err
+	}
+	if err := r.loadAssortment(ctx, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FilterIDsByEnterprise retorna, dentre os IDs informados, as análises cujos projetos pertencem à empresa
+func (r *PhytoAnalysisRepo) FilterIDsByEnterprise(ctx context.Context, enterpriseID string, ids []string) ([]string, error) {
+	result := make([]string, 0, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	placeholders := make([]string, 0, len(ids))
+	args := make([]interface{}, 0, len(ids)+1)
+	args = append(args, enterpriseID)
+	for i, id := range ids {
+		placeholders = append(placeholders, fmt.Sprintf("$%d", i+2))
+		args = append(args, id)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT pa.id
+		FROM public.phyto_analysis pa
+		INNER JOIN public."Project" p ON pa.project_id = p.id
+		INNER JOIN public."Client" c ON p."clientId" = c.id
+		INNER JOIN public."User" u ON c."userId" = u.id
+		WHERE u."enterpriseId" = $1 AND pa.id IN (%s)`,
+		strings.Join(placeholders, ", "),
+	)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		result = append(result, id)
+	}
+
+	return result, rows.Err()
+}
+
+// LockForSnapshot bloqueia a linha da análise até o fim da transação, serializando a numeração
+// das versões dos snapshots da análise
+func (r *PhytoAnalysisRepo) LockForSnapshot(ctx context.Context, phytoAnalysisID string) error {
+	if _, err := r.q.LockPhytoAnalysisForSnapshot(ctx, phytoAnalysisID); err != nil {
+		if err == sql.ErrNoRows {
+			return apperr.New(apperr.CodeNotFound, "phyto analysis not found")
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *PhytoAnalysisRepo) CreateIndicatorSnapshot(ctx context.Context, s *phytometrics.Snapshot) error {
+	payload, err := json.Marshal(s.Result)
+	if err != nil {
+		return err
+	}
+
+	return r.q.CreatePhytoIndicatorSnapshot(ctx, sqlc.CreatePhytoIndicatorSnapshotParams{
+		ID:              s.ID,
+		PhytoAnalysisID: s.PhytoAnalysisID,
+		Version:         int32(s.Version),
+		EngineVersion:   int32(s.EngineVersion),
+		Reason:          s.Reason,
+		SpecimensCount:  int32(s.SpecimensCount),
+		Payload:         payload,
+		CreatedAt:       s.CreatedAt,
+	})
+}
+
+func (r *PhytoAnalysisRepo) GetLatestIndicatorSnapshot(ctx context.Context, phytoAnalysisID string) (*phytometrics.Snapshot, error) {
+	row, err := r.q.GetLatestPhytoIndicatorSnapshot(ctx, phytoAnalysisID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, apperr.New(apperr.CodeNotFound, "indicator snapshot not found")
+		}
+		return nil, err
+	}
+	return toIndicatorSnapshot(row)
+}
+
+func (r *PhytoAnalysisRepo) GetIndicatorSnapshot(ctx context.Context, phytoAnalysisID string, version int) (*phytometrics.Snapshot, error) {
+	row, err := r.q.GetPhytoIndicatorSnapshotByVersion(ctx, sqlc.GetPhytoIndicatorSnapshotByVersionParams{
+		PhytoAnalysisID: phytoAnalysisID,
+		Version:         int32(version),
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, apperr.New(apperr.CodeNotFound, "indicator snapshot not found")
+		}
+		return nil, err
+	}
+	return toIndicatorSnapshot(row)
+}
+
+// ListIndicatorSnapshots lista as versões da análise (mais recente primeiro), sem o resultado
+func (r *PhytoAnalysisRepo) ListIndicatorSnapshots(ctx context.Context, phytoAnalysisID string) ([]*phytometrics.Snapshot, error) {
+	rows, err := r.q.ListPhytoIndicatorSnapshots(ctx, phytoAnalysisID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*phytometrics.Snapshot, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, &phytometrics.Snapshot{
+			ID:              row.ID,
+			PhytoAnalysisID: row.PhytoAnalysisID,
+			Version:         int(row.Version),
+			EngineVersion:   int(row.EngineVersion),
+			Reason:          row.Reason,
+			SpecimensCount:  int(row.SpecimensCount),
+			CreatedAt:       row.CreatedAt,
+		})
+	}
+
+	return result, nil
+}
+
+func (r *PhytoAnalysisRepo) DeleteIndicatorSnapshots(ctx context.Context, phytoAnalysisID string) error {
+	return r.q.DeletePhytoIndicatorSnapshotsByAnalysis(ctx, phytoAnalysisID)
+}
+
+// SetEquation grava a equação selecionada para a análise (uma por tipo), com cópia da definição
+func (r *PhytoAnalysisRepo) SetEquation(ctx context.Context, phytoAnalysisID string, eq *types.EquationData) error {
+	definition, err := json.Marshal(eq)
+	if err != nil {
+		return err
+	}
+
+	return r.q.UpsertPhytoAnalysisEquation(ctx, sqlc.UpsertPhytoAnalysisEquationParams{
+		PhytoAnalysisID: phytoAnalysisID,
+		Kind:            eq.Kind,
+		EquationID:      eq.ID,
+		Definition:      definition,
+		SelectedAt:      time.Now(),
+	})
+}
+
+// DeleteEquation remove a seleção do tipo informado (a análise volta ao padrão do motor)
+func (r *PhytoAnalysisRepo) DeleteEquation(ctx context.Context, phytoAnalysisID string, kind string) error {
+	return r.q.DeletePhytoAnalysisEquation(ctx, sqlc.DeletePhytoAnalysisEquationParams{
+		PhytoAnalysisID: phytoAnalysisID,
+		Kind:            kind,
+	})
+}
+
+// loadEquations preenche as equações selecionadas da análise
+func (r *PhytoAnalysisRepo) loadEquations(ctx context.Context, p *types.PhytoAnalysisComplete) error {
+	rows, err := r.q.ListPhytoAnalysisEquations(ctx, p.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		var eq types.EquationData
+		if err := json.Unmarshal(row.Definition, &eq); err != nil {
+			return err
+		}
+		switch row.Kind {
+		case types.EquationKindVolume:
+			p.VolumeEquation = &eq
+		case types.EquationKindBiomass:
+			p.BiomassEquation = &eq
+		}
+	}
+
+	return nil
+}
+
+// loadPlots preenche as parcelas cadastradas da análise
+func (r *PhytoAnalysisRepo) loadPlots(ctx context.Context, p *types.PhytoAnalysisComplete) error {
+	plots, err := NewPlotRepoFrom(r.db).ListByPhytoAnalysis(ctx, p.ID)
+	if err != nil {
+		return err
+	}
+	p.Plots = plots
+	return nil
+}
+
+// loadStrata preenche os estratos cadastrados da análise
+func (r *PhytoAnalysisRepo) loadStrata(ctx context.Context, p *types.PhytoAnalysisComplete) error {
+	strata, err := NewStratumRepoFrom(r.db).ListByPhytoAnalysis(ctx, p.ID)
+	if err != nil {
+		return err
+	}
+	p.Strata = strata
+	return nil
+}
+
+// loadCampaign preenche a campanha anterior remedida pela análise
+func (r *PhytoAnalysisRepo) loadCampaign(ctx context.Context, p *types.PhytoAnalysisComplete) error {
+	previousID, err := r.GetPreviousCampaignID(ctx, p.ID)
+	if err != nil {
+		return err
+	}
+	p.PreviousAnalysisID = previousID
+	return nil
+}
+
+// SetAssortment grava as regras de classificação da madeira suprimida da análise
+func (r *PhytoAnalysisRepo) SetAssortment(ctx context.Context, phytoAnalysisID string, rules types.AssortmentRules) error {
+	return r.q.UpsertPhytoAssortment(ctx, sqlc.UpsertPhytoAssortmentParams{
+		PhytoAnalysisID: phytoAnalysisID,
+		LogMinDbhCm:     utils.Float64ToString(rules.LogMinDbhCm),
+		StackingFactor:  utils.Float64ToString(rules.StackingFactor),
+		DeadAsFirewood:  rules.DeadAsFirewood,
+		UpdatedAt:       time.Now(),
+	})
+}
+
+// DeleteAssortment remove as regras da análise (a análise volta às regras padrão do motor)
+func (r *PhytoAnalysisRepo) DeleteAssortment(ctx context.Context, phytoAnalysisID string) error {
+	return r.q.DeletePhytoAssortment(ctx, phytoAnalysisID)
+}
+
+// loadAssortment preenche as regras de classificação da madeira suprimida da análise
+func (r *PhytoAnalysisRepo) loadAssortment(ctx context.Context, p *types.PhytoAnalysisComplete) error {
+	row, err := r.q.GetPhytoAssortment(ctx, p.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		return err
+	}
+
+	logMinDbh, _ := utils.StringToFloat64(row.LogMinDbhCm)
+	stackingFactor, _ := utils.StringToFloat64(row.StackingFactor)
+	p.Assortment = &types.AssortmentRules{
+		LogMinDbhCm:    logMinDbh,
+		StackingFactor: stackingFactor,
+		DeadAsFirewood: row.DeadAsFirewood,
+	}
+	return nil
+}
+
+// CreateCampaign registra a análise como remedição da campanha anterior
+func (r *PhytoAnalysisRepo) CreateCampaign(ctx context.Context, phytoAnalysisID, previousAnalysisID string) error {
+	return r.q.CreatePhytoCampaign(ctx, sqlc.CreatePhytoCampaignParams{
+		PhytoAnalysisID:    phytoAnalysisID,
+		PreviousAnalysisID: previousAnalysisID,
+		CreatedAt:          time.Now(),
+	})
+}
+
+// GetPreviousCampaignID retorna a campanha remedida pela análise (nil = primeira campanha)
+func (r *PhytoAnalysisRepo) GetPreviousCampaignID(ctx context.Context, phytoAnalysisID string) (*string, error) {
+	row, err := r.q.GetPhytoCampaign(ctx, phytoAnalysisID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &row.PreviousAnalysisID, nil
+}
+
+// GetNextCampaignID retorna a campanha que remede a análise (nil = última campanha)
+func (r *PhytoAnalysisRepo) GetNextCampaignID(ctx context.Context, phytoAnalysisID string) (*string, error) {
+	row, err := r.q.GetPhytoCampaignByPrevious(ctx, phytoAnalysisID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &row.PhytoAnalysisID, nil
+}
+
+// ListMorphospecies lista as morfoespécies da análise com a quantidade de espécimes
+func (r *PhytoAnalysisRepo) ListMorphospecies(ctx context.Context, phytoAnalysisID string) ([]*types.MorphospeciesData, error) {
+	return NewMorphospeciesRepoFrom(r.db).ListByPhytoAnalysis(ctx, phytoAnalysisID)
+}
+
+func toIndicatorSnapshot(row sqlc.PhytoIndicatorSnapshot) (*phytometrics.Snapshot, error) {
+	var result phytometrics.Result
+	if err := json.Unmarshal(row.Payload, &result); err != nil {
+		return nil, err
+	}
+
+	return &phytometrics.Snapshot{
+		ID:              row.ID,
+		PhytoAnalysisID: row.PhytoAnalysisID,
+		Version:         int(row.Version),
+		EngineVersion:   int(row.EngineVersion),
+		Reason:          row.Reason,
+		SpecimensCount:  int(row.SpecimensCount),
+		CreatedAt:       row.CreatedAt,
+		Result:          &result,
+	}, nil
+}
+
+// GetComplete retorna a análise com projeto e endereço, sem os espécimes
+func (r *PhytoAnalysisRepo) GetComplete(ctx context.Context, id string) (*types.PhytoAnalysisComplete, error) {
+	row, err := r.q.GetPhytoAnalysisComplete(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, apperr.New(apperr.CodeNotFound, "phyto analysis not found")
+		}
+		return nil, err
+	}
+
+	portionArea, _ := utils.StringToFloat64(row.PortionArea)
+	totalArea, _ := utils.StringToFloat64(row.TotalArea)
+	sampledArea, _ := utils.StringToFloat64(row.SampledArea)
+
+	result := &types.PhytoAnalysisComplete{
+		ID:              row.PhytoID,
+		Title:           row.PhytoTitle,
+		InitialDate:     row.InitialDate,
+		PortionQuantity: int(row.PortionQuantity),
+		PortionArea:     portionArea,
+		TotalArea:       totalArea,
+		SampledArea:     sampledArea,
+		Description:     utils.FromNullString(row.PhytoDescription),
+		ProjectID:       row.ProjectID,
+		CreatedAt:       row.PhytoCreatedAt,
+		UpdatedAt:       row.PhytoUpdatedAt,
+		ProjectTitle:    row.ProjectTitle,
+		ProjectCNPJ:     utils.FromNullString(row.ProjectCnpj),
+		ProjectActivity: row.ProjectActivity,
+		ProjectClientID: row.ProjectClientID,
+		// Endereço do projeto
+		ProjectZipCode:      utils.FromNullString(row.ProjectZipCode),
+		ProjectState:        utils.FromNullString(row.ProjectState),
+		ProjectCity:         utils.FromNullString(row.ProjectCity),
+		ProjectNeighborhood: utils.FromNullString(row.ProjectNeighborhood),
+		ProjectStreet:       utils.FromNullString(row.ProjectStreet),
+		ProjectNum:          utils.FromNullString(row.ProjectNum),
+		ProjectLatitude:     utils.FromNullString(row.ProjectLatitude),
+		ProjectLongitude:    utils.FromNullString(row.ProjectLongitude),
+		ProjectAddInfo:      utils.FromNullString(row.ProjectAddInfo),
+		DefaultFormFactor:   utils.NullStringToNullFloat64(row.DefaultFormFactor),
+		Measurement: types.MeasurementProtocol{
+			Mode:            row.MeasurementMode,
+			Unit:            row.MeasurementUnit,
+			InclusionMin:    utils.NullStringToNullFloat64(row.InclusionMin),
+			InclusionPolicy: row.InclusionPolicy,
+			ExcludeDead:     row.ExcludeDead,
+		},
+		Specimens: make([]*types.SpecimenWithSpecies, 0),
+	}
+
+	if err := r.loadEquations(ctx, result); err != nil {
+		return nil, err
+	}
+	if err := r.loadPlots(ctx, result); err != nil {
+		return nil, err
+	}
+	if err := r.loadStrata(ctx, result); err != nil {
+		return nil, err
+	}
+	if err := r.loadCampaign(ctx, result); err != nil {
+		return nil, err
+	}
+	if err := r.loadAssortment(ctx, result); err != nil {
+		return nil, err
+	}
+
 	return result, nil
 }
+
+// specimenDbhExpr calcula o DAP (cm) a partir dos CAPs: √(Σ CAP²) / π
+const specimenDbhExpr = `(sqrt(power(sp.cap1::float8, 2)
+	+ power(coalesce(sp.cap2, 0)::float8, 2) + power(coalesce(sp.cap3, 0)::float8, 2)
+	+ power(coalesce(sp.cap4, 0)::float8, 2) + power(coalesce(sp.cap5, 0)::float8, 2)
+	+ power(coalesce(sp.cap6, 0)::float8, 2)) / pi())`
+
+// nome e família do indivíduo: espécie do cadastro ou morfoespécie da análise
+const (
+	specimenNameExpr   = "coalesce(s.scientific_name, m.name)"
+	specimenFamilyExpr = "coalesce(s.family, m.family, '')"
+)
+
+// specimenSortColumns mapeia o campo de ordenação para a expressão SQL e o tipo do valor no cursor
+var specimenSortColumns = map[string]struct{ expr, cast string }{
+	types.SpecimenSortPortion:        {"sp.portion", "varchar"},
+	types.SpecimenSortScientificName: {specimenNameExpr, "varchar"},
+	types.SpecimenSortFamily:         {specimenFamilyExpr, "varchar"},
+	types.SpecimenSortDbh:            {specimenDbhExpr, "float8"},
+	types.SpecimenSortMeasuredHeight: {"coalesce(sp.height, 0)", "numeric"}, // sem altura medida = 0
+	types.SpecimenSortRegisterDate:   {"sp.register_date", "timestamp"},
+}
+
+// buildSpecimenListQuery monta a consulta filtrada dos espécimes da análise, com o fator de forma
+// da legislação aplicável e o valor de ordenação (texto) usado no cursor. limit <= 0 = sem limite.
+func buildSpecimenListQuery(phytoID string, f types.SpecimenFilter, after *types.SpecimenCursorKey, limit int32) (string, []interface{}) {
+	args := []interface{}{phytoID}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	column, ok := specimenSortColumns[f.SortBy]
+	if !ok {
+		column = specimenSortColumns[types.SpecimenSortPortion]
+	}
+	direction, comparison := "ASC", ">"
+	if f.Desc {
+		direction, comparison = "DESC", "<"
+	}
+
+	where := []string{"sp.phyto_analysis_id = $1"}
+	if f.Portion != "" {
+		where = append(where, "sp.portion = "+arg(f.Portion))
+	}
+	if f.SpecieID != "" {
+		where = append(where, "sp.specie_id = "+arg(f.SpecieID))
+	}
+	if f.MorphospeciesID != "" {
+		where = append(where, "sp.morphospecies_id = "+arg(f.MorphospeciesID))
+	}
+	if f.Tag != "" {
+		where = append(where, "sp.tag = "+arg(f.Tag))
+	}
+	if f.Status != "" {
+		where = append(where, "sp.status = "+arg(f.Status))
+	}
+	if f.Phytosanitary != "" {
+		where = append(where, "sp.phytosanitary = "+arg(f.Phytosanitary))
+	}
+	if f.ScientificName != "" {
+		where = append(where, "lower("+specimenNameExpr+") = lower("+arg(f.ScientificName)+")")
+	}
+	if f.Family != "" {
+		where = append(where, "lower("+specimenFamilyExpr+") = lower("+arg(f.Family)+")")
+	}
+	if f.MinDbhCm != nil {
+		where = append(where, specimenDbhExpr+" >= "+arg(*f.MinDbhCm))
+	}
+	if f.MaxDbhCm != nil {
+		where = append(where, specimenDbhExpr+" <= "+arg(*f.MaxDbhCm))
+	}
+	// altura medida: espécimes sem medição (altura estimada na resposta) ficam de fora
+	if f.MinMeasuredHeight != nil || f.MaxMeasuredHeight != nil {
+		where = append(where, "sp.height > 0")
+	}
+	if f.MinMeasuredHeight != nil {
+		where = append(where, "sp.height >= "+arg(*f.MinMeasuredHeight))
+	}
+	if f.MaxMeasuredHeight != nil {
+		where = append(where, "sp.height <= "+arg(*f.MaxMeasuredHeight))
+	}
+	if after != nil {
+		var value interface{} = after.Value
+		switch {
+		case after.Number != nil:
+			value = *after.Number
+		case after.Time != nil:
+			value = *after.Time
+		}
+		where = append(where, fmt.Sprintf("(%s, sp.id) %s (%s::%s, %s)",
+			column.expr, comparison, arg(value), column.cast, arg(after.ID)))
+	}
+
+	query := fmt.Sprintf(`
+		SELECT sp.id, sp.portion, sp.height, sp.cap1, sp.cap2, sp.cap3, sp.cap4, sp.cap5, sp.cap6,
+			sp.register_date, sp.phyto_analysis_id, sp.specie_id, sp.created_at, sp.updated_at,
+			sp.latitude, sp.longitude, sp.tag,
+			sp.status, sp.phytosanitary, sp.bifurcation_notes, sp.observations, sp.morphospecies_id,
+			%s, %s, s.popular_name, ff.species_form_factor,
+			s.wood_density::text, s.habit::text,
+			(%s)::text AS sort_key
+		FROM public.specimen sp
+		LEFT JOIN public.species s ON sp.specie_id = s.id
+		LEFT JOIN public.morphospecies m ON sp.morphospecies_id = m.id
+		LEFT JOIN LATERAL (
+			SELECT sl.species_form_factor
+			FROM public.species_legislations sl
+			WHERE sl.species_id = sp.specie_id AND sl.is_law_active = true
+			ORDER BY CASE sl.law_scope WHEN 'MUNICIPAL' THEN 0 WHEN 'STATE' THEN 1 ELSE 2 END,
+				sl.updated_at DESC
+			LIMIT 1
+		) ff ON true
+		WHERE %s
+		ORDER BY %s %s, sp.id %s`,
+		specimenNameExpr, specimenFamilyExpr, column.expr,
+		strings.Join(where, " AND "), column.expr, direction, direction,
+	)
+	if limit > 0 {
+		query += " LIMIT " + arg(limit)
+	}
+
+	return query, args
+}
+
+func scanSpecimenListRow(rows *sql.Rows) (*types.SpecimenWithSpecies, string, error) {
+	var (
+		s                                    types.SpecimenWithSpecies
+		cap1                                 string
+		height, cap2, cap3, cap4, cap5, cap6 sql.NullString
+		latitude, longitude, tag             sql.NullString
+		phytosanitary, bifurcation, notes    sql.NullString
+		specieID, morphospeciesID            sql.NullString
+		formFactor                           sql.NullString
+		popularName, woodDensity, habit      sql.NullString
+		sortKey                              string
+	)
+	if err := rows.Scan(
+		&s.ID, &s.Portion, &height, &cap1, &cap2, &cap3, &cap4, &cap5, &cap6,
+		&s.RegisterDate, &s.PhytoAnalysisID, &specieID, &s.CreatedAt, &s.UpdatedAt,
+		&latitude, &longitude, &tag,
+		&s.Status, &phytosanitary, &bifurcation, &notes, &morphospeciesID,
+		&s.ScientificName, &s.Family, &popularName, &formFactor,
+		&woodDensity, &habit,
+		&sortKey,
+	); err != nil {
+		return nil, "", err
+	}
+
+	s.SpecieID = specieID.String
+	s.MorphospeciesID = utils.FromNullString(morphospeciesID)
+	s.Height, _ = utils.StringToFloat64(height.String)
+	s.Cap1, _ = utils.StringToFloat64(cap1)
+	s.Cap2 = utils.NullStringToNullFloat64(cap2)
+	s.Cap3 = utils.NullStringToNullFloat64(cap3)
+	s.Cap4 = utils.NullStringToNullFloat64(cap4)
+	s.Cap5 = utils.NullStringToNullFloat64(cap5)
+	s.Cap6 = utils.NullStringToNullFloat64(cap6)
+	s.Latitude = utils.NullStringToNullFloat64(latitude)
+	s.Longitude = utils.NullStringToNullFloat64(longitude)
+	s.Tag = utils.FromNullString(tag)
+	s.Phytosanitary = utils.FromNullString(phytosanitary)
+	s.BifurcationNotes = utils.FromNullString(bifurcation)
+	s.Observations = utils.FromNullString(notes)
+	s.PopularName = utils.FromNullString(popularName)
+	s.FormFactor = utils.NullStringToNullFloat64(formFactor)
+	s.WoodDensity = utils.NullStringToNullFloat64(woodDensity)
+	s.Habit = utils.FromNullString(habit)
+
+	return &s, sortKey, nil
+}
+
+// ListSpecimens lista uma página de espécimes da análise (paginação por cursor: valor de ordenação + id)
+func (r *PhytoAnalysisRepo) ListSpecimens(ctx context.Context, phytoAnalysisID string, f types.SpecimenFilter, limit int32, after *types.SpecimenCursorKey) ([]*types.SpecimenWithSpecies, types.SpecimenPageInfo, error) {
+	query, args := buildSpecimenListQuery(phytoAnalysisID, f, after, limit+1)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, types.SpecimenPageInfo{}, err
+	}
+	defer rows.Close()
+
+	out := make([]*types.SpecimenWithSpecies, 0, limit)
+	var pageInfo types.SpecimenPageInfo
+	for rows.Next() {
+		s, sortKey, err := scanSpecimenListRow(rows)
+		if err != nil {
+			return nil, types.SpecimenPageInfo{}, err
+		}
+		if int32(len(out)) == limit {
+			pageInfo.HasMore = true
+			break
+		}
+		out = append(out, s)
+		pageInfo.Next = &types.SpecimenCursorKey{SortBy: f.SortBy, Desc: f.Desc, Value: sortKey, ID: s.ID}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, types.SpecimenPageInfo{}, err
+	}
+
+	if !pageInfo.HasMore {
+		pageInfo.Next = nil
+	}
+	return out, pageInfo, nil
+}
+
+// StreamSpecimens percorre todos os espécimes filtrados sem carregá-los em memória
+func (r *PhytoAnalysisRepo) StreamSpecimens(ctx context.Context, phytoAnalysisID string, f types.SpecimenFilter, fn func(*types.SpecimenWithSpecies) error) error {
+	query, args := buildSpecimenListQuery(phytoAnalysisID, f, nil, 0)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		s, _, err := scanSpecimenListRow(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(s); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,31 @@
+package postgres
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildSpecimenListQuery_MeasuredHeight(t *testing.T) {
+	t.Parallel()
+
+	// Sem filtro de altura, os espécimes sem medição continuam na listagem
+	query, args := buildSpecimenListQuery("phyto-1", types.SpecimenFilter{}, nil, 0)
+	require.NotContains(t, query, "sp.height >")
+	require.Equal(t, []interface{}{"phyto-1"}, args)
+
+	// O filtro usa só a altura medida: altura nula ou zero (estimada na resposta) fica de fora
+	minHeight, maxHeight := 0.0, 12.0
+	query, args = buildSpecimenListQuery("phyto-1", types.SpecimenFilter{
+		MinMeasuredHeight: &minHeight,
+		MaxMeasuredHeight: &maxHeight,
+		SortBy:            types.SpecimenSortMeasuredHeight,
+	}, nil, 10)
+	require.Contains(t, query, "sp.height > 0 AND sp.height >= $2 AND sp.height <= $3")
+	require.Equal(t, []interface{}{"phyto-1", 0.0, 12.0, int32(10)}, args)
+
+	// Na ordenação, os sem medição valem 0
+	require.True(t, strings.Contains(query, "ORDER BY coalesce(sp.height, 0) ASC, sp.id ASC"), query)
+}
@@ -0,0 +1,172 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+	"github.com/ESG-Project/suassu-api/internal/apperr"
+	domainplot "github.com/ESG-Project/suassu-api/internal/domain/plot"
+	"github.com/ESG-Project/suassu-api/internal/infra/db/postgres/utils"
+	sqlc "github.com/ESG-Project/suassu-api/internal/infra/db/sqlc/gen"
+)
+
+type PlotRepo struct {
+	q *sqlc.Queries
+}
+
+func NewPlotRepo(db *sql.DB) *PlotRepo {
+	return &PlotRepo{q: sqlc.New(db)}
+}
+
+func NewPlotRepoFrom(d dbtx) *PlotRepo {
+	return &PlotRepo{q: sqlc.New(d)}
+}
+
+func (r *PlotRepo) Create(ctx context.Context, p *domainplot.Plot) error {
+	vertices, err := marshalVertices(p.Vertices)
+	if err != nil {
+		return err
+	}
+	lat, lng := centerToNullStrings(p.Center)
+
+	return r.q.CreatePlot(ctx, sqlc.CreatePlotParams{
+		ID:              p.ID,
+		PhytoAnalysisID: p.PhytoAnalysisID,
+		Code:            p.Code,
+		Area:            utils.Float64ToString(p.AreaM2),
+		Shape:           p.Shape,
+		Width:           utils.Float64PtrToString(p.WidthM),
+		Length:          utils.Float64PtrToString(p.LengthM),
+		Radius:          utils.Float64PtrToString(p.RadiusM),
+		CenterLatitude:  lat,
+		CenterLongitude: lng,
+		Vertices:        vertices,
+		VegetationNotes: utils.ToNullString(p.VegetationNotes),
+		CreatedAt:       p.CreatedAt,
+		UpdatedAt:       p.UpdatedAt,
+		StratumID:       utils.ToNullString(p.StratumID),
+	})
+}
+
+func (r *PlotRepo) GetByID(ctx context.Context, phytoAnalysisID, id string) (*types.PlotData, error) {
+	row, err := r.q.GetPlotByID(ctx, sqlc.GetPlotByIDParams{ID: id, PhytoAnalysisID: phytoAnalysisID})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, apperr.New(apperr.CodeNotFound, "plot not found")
+		}
+		return nil, err
+	}
+	return toPlotData(row)
+}
+
+func (r *PlotRepo) ListByPhytoAnalysis(ctx context.Context, phytoAnalysisID string) ([]*types.PlotData, error) {
+	rows, err := r.q.ListPlotsByPhytoAnalysis(ctx, phytoAnalysisID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*types.PlotData, 0, len(rows))
+	for _, row := range rows {
+		p, err := toPlotData(row)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, p)
+	}
+	return result, nil
+}
+
+func (r *PlotRepo) Update(ctx context.Context, p *domainplot.Plot) error {
+	vertices, err := marshalVertices(p.Vertices)
+	if err != nil {
+		return err
+	}
+	lat, lng := centerToNullStrings(p.Center)
+
+	return r.q.UpdatePlot(ctx, sqlc.UpdatePlotParams{
+		ID:              p.ID,
+		PhytoAnalysisID: p.PhytoAnalysisID,
+		Code:            p.Code,
+		Area:            utils.Float64ToString(p.AreaM2),
+		Shape:           p.Shape,
+		Width:           utils.Float64PtrToString(p.WidthM),
+		Length:          utils.Float64PtrToString(p.LengthM),
+		Radius:          utils.Float64PtrToString(p.RadiusM),
+		CenterLatitude:  lat,
+		CenterLongitude: lng,
+		Vertices:        vertices,
+		VegetationNotes: utils.ToNullString(p.VegetationNotes),
+		UpdatedAt:       p.UpdatedAt,
+		StratumID:       utils.ToNullString(p.StratumID),
+	})
+}
+
+func (r *PlotRepo) Delete(ctx context.Context, phytoAnalysisID, id string) error {
+	return r.q.DeletePlot(ctx, sqlc.DeletePlotParams{ID: id, PhytoAnalysisID: phytoAnalysisID})
+}
+
+// CountSpecimens conta os espécimes da análise que referenciam o código da parcela
+func (r *PlotRepo) CountSpecimens(ctx context.Context, phytoAnalysisID, code string) (int64, error) {
+	return r.q.CountSpecimensByPortion(ctx, sqlc.CountSpecimensByPortionParams{
+		PhytoAnalysisID: phytoAnalysisID,
+		Portion:         code,
+	})
+}
+
+// RenameSpecimensPortion atualiza o código da parcela nos espécimes da análise
+func (r *PlotRepo) RenameSpecimensPortion(ctx context.Context, phytoAnalysisID, oldCode, newCode string) error {
+	return r.q.RenameSpecimensPortion(ctx, sqlc.RenameSpecimensPortionParams{
+		PhytoAnalysisID: phytoAnalysisID,
+		Portion:         oldCode,
+		Portion_2:       newCode,
+	})
+}
+
+func marshalVertices(vertices []domainplot.Coordinate) (json.RawMessage, error) {
+	if vertices == nil {
+		vertices = []domainplot.Coordinate{}
+	}
+	return json.Marshal(vertices)
+}
+
+func centerToNullStrings(c *domainplot.Coordinate) (sql.NullString, sql.NullString) {
+	if c == nil {
+		return sql.NullString{}, sql.NullString{}
+	}
+	return utils.StringToNullString(utils.Float64ToString(c.Latitude)),
+		utils.StringToNullString(utils.Float64ToString(c.Longitude))
+}
+
+func toPlotData(row sqlc.Plot) (*types.PlotData, error) {
+	area, _ := utils.StringToFloat64(row.Area)
+
+	p := &types.PlotData{
+		ID:              row.ID,
+		PhytoAnalysisID: row.PhytoAnalysisID,
+		Code:            row.Code,
+		AreaM2:          area,
+		Shape:           row.Shape,
+		WidthM:          utils.NullStringToNullFloat64(row.Width),
+		LengthM:         utils.NullStringToNullFloat64(row.Length),
+		RadiusM:         utils.NullStringToNullFloat64(row.Radius),
+		VegetationNotes: utils.FromNullString(row.VegetationNotes),
+		StratumID:       utils.FromNullString(row.StratumID),
+		CreatedAt:       row.CreatedAt,
+		UpdatedAt:       row.UpdatedAt,
+	}
+
+	lat := utils.NullStringToNullFloat64(row.CenterLatitude)
+	lng := utils.NullStringToNullFloat64(row.CenterLongitude)
+	if lat != nil && lng != nil {
+		p.Center = &types.Coordinate{Latitude: *lat, Longitude: *lng}
+	}
+
+	if len(row.Vertices) > 0 {
+		if err := json.Unmarshal(row.Vertices, &p.Vertices); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
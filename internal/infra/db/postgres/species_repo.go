@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/ESG-Project/suassu-api/internal/app/types"
@@ -35,6 +36,7 @@ func (r *SpeciesRepo) CreateSpecies(ctx context.Context, s *domainspecies.Specie
 		Habit:          utils.ToNullSpeciesHabit(s.Habit),
 		CreatedAt:      s.CreatedAt,
 		UpdatedAt:      s.UpdatedAt,
+		WoodDensity:    utils.Float64PtrToString(s.WoodDensity),
 	})
 	return err
 }
@@ -98,6 +100,7 @@ func (r *SpeciesRepo) GetByID(ctx context.Context, id string) (*types.SpeciesWit
 		Family:         row.Family,
 		PopularName:    utils.FromNullString(row.PopularName),
 		Habit:          utils.FromNullSpeciesHabit(row.Habit),
+		WoodDensity:    utils.NullStringToNullFloat64(row.WoodDensity),
 		CreatedAt:      row.CreatedAt,
 		UpdatedAt:      row.UpdatedAt,
 		Legislations:   legislationData,
@@ -160,6 +163,347 @@ func (r *SpeciesRepo) GetMapByScientificNames(ctx context.Context, names []strin
 	return result, rows.Err()
 }
 
+// speciesMatchMinScore é a similaridade mínima para um nome do catálogo ser sugerido
+const speciesMatchMinScore = 0.7
+
+// speciesMatchPrefixLen é o tamanho do prefixo do gênero usado para filtrar o catálogo no banco
+const speciesMatchPrefixLen = 3
+
+// MatchScientificNames compara os nomes informados, após normalização (caixa, acentos, autoria),
+// com as espécies do catálogo que começam pelo mesmo prefixo do gênero, e retorna, por nome, os
+// candidatos ordenados por similaridade. Erros de grafia nas primeiras letras do gênero não geram
+// sugestão. Nomes sem candidato acima de speciesMatchMinScore não aparecem no mapa.
+func (r *SpeciesRepo) MatchScientificNames(ctx context.Context, names []string, limit int) (map[string][]types.SpeciesMatch, error) {
+	result := make(map[string][]types.SpeciesMatch, len(names))
+	if len(names) == 0 || limit <= 0 {
+		return result, nil
+	}
+
+	prefixes := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, name := range names {
+		prefix := speciesMatchPrefix(domainspecies.NormalizeScientificName(name))
+		if prefix != "" && !seen[prefix] {
+			seen[prefix] = true
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	if len(prefixes) == 0 {
+		return result, nil
+	}
+
+	conditions := make([]string, 0, len(prefixes))
+	args := make([]interface{}, 0, len(prefixes))
+	for i, prefix := range prefixes {
+		conditions = append(conditions, fmt.Sprintf(`lower(trim(both from scientific_name)) LIKE $%d`, i+1))
+		args = append(args, escapeLike(prefix)+"%")
+	}
+	query := `SELECT id, scientific_name, family FROM public.species WHERE ` + strings.Join(conditions, " OR ")
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type catalogEntry struct {
+		match      types.SpeciesMatch
+		normalized string
+	}
+	catalog := make(map[string][]catalogEntry, len(prefixes))
+	for rows.Next() {
+		var id, scientificName, family string
+		if err := rows.Scan(&id, &scientificName, &family); err != nil {
+			return nil, err
+		}
+		normalized := domainspecies.NormalizeScientificName(scientificName)
+		prefix := speciesMatchPrefix(normalized)
+		catalog[prefix] = append(catalog[prefix], catalogEntry{
+			match: types.SpeciesMatch{
+				SpeciesID:      id,
+				ScientificName: strings.TrimSpace(scientificName),
+				Family:         family,
+			},
+			normalized: normalized,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		normalized := domainspecies.NormalizeScientificName(name)
+		if normalized == "" {
+			continue
+		}
+		if _, done := result[name]; done {
+			continue
+		}
+
+		nameLen := len([]rune(normalized))
+		matches := make([]types.SpeciesMatch, 0)
+		for _, entry := range catalog[speciesMatchPrefix(normalized)] {
+			// Descarta pela diferença de tamanho antes de calcular a distância de edição
+			entryLen := len([]rune(entry.normalized))
+			if diff := float64(abs(nameLen - entryLen)); diff > (1-speciesMatchMinScore)*float64(max(nameLen, entryLen)) {
+				continue
+			}
+
+			score := domainspecies.NameSimilarity(normalized, entry.normalized)
+			if score < speciesMatchMinScore {
+				continue
+			}
+			m := entry.match
+			m.Score = score
+			m.Exact = entry.normalized == normalized
+			matches = append(matches, m)
+		}
+		if len(matches) == 0 {
+			continue
+		}
+
+		sort.SliceStable(matches, func(i, j int) bool {
+			if matches[i].Score != matches[j].Score {
+				return matches[i].Score > matches[j].Score
+			}
+			return matches[i].ScientificName < matches[j].ScientificName
+		})
+		if len(matches) > limit {
+			matches = matches[:limit]
+		}
+		result[name] = matches
+	}
+
+	return result, nil
+}
+
+// speciesMatchPrefix retorna o prefixo do gênero de um nome normalizado
+func speciesMatchPrefix(normalized string) string {
+	runes := []rune(normalized)
+	if len(runes) > speciesMatchPrefixLen {
+		runes = runes[:speciesMatchPrefixLen]
+	}
+	return strings.TrimSpace(string(runes))
+}
+
+// escapeLike escapa os curingas do LIKE
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// GetNamesByIDs retorna um mapa speciesID -> scientificName para os IDs existentes
+func (r *SpeciesRepo) GetNamesByIDs(ctx context.Context, ids []string) (map[string]string, error) {
+	result := make(map[string]string, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	placeholders := make([]string, 0, len(ids))
+	args := make([]interface{}, 0, len(ids))
+	for i, id := range ids {
+		placeholders = append(placeholders, fmt.Sprintf("$%d", i+1))
+		args = append(args, id)
+	}
+
+	query := fmt.Sprintf(
+		`SELECT id, scientific_name FROM public.species WHERE id IN (%s)`,
+		strings.Join(placeholders, ", "),
+	)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id, scientificName string
+		if err := rows.Scan(&id, &scientificName); err != nil {
+			return nil, err
+		}
+		result[id] = strings.TrimSpace(scientificName)
+	}
+
+	return result, rows.Err()
+}
+
+// GetFormFactorsBySpeciesIDs retorna o fator de forma da legislação aplicável a cada espécie
+func (r *SpeciesRepo) GetFormFactorsBySpeciesIDs(ctx context.Context, speciesIDs []string) (map[string]float64, error) {
+	return getFormFactorsBySpeciesIDs(ctx, r.db, speciesIDs)
+}
+
+// getFormFactorsBySpeciesIDs resolve, por espécie, a legislação aplicável: apenas leis ativas,
+// priorizando a esfera mais específica (municipal > estadual > federal) e a mais recente.
+// Espécies sem legislação ativa não aparecem no mapa.
+func getFormFactorsBySpeciesIDs(ctx context.Context, db dbtx, speciesIDs []string) (map[string]float64, error) {
+	result := make(map[string]float64, len(speciesIDs))
+	if len(speciesIDs) == 0 {
+		return result, nil
+	}
+
+	placeholders := make([]string, 0, len(speciesIDs))
+	args := make([]interface{}, 0, len(speciesIDs))
+	for i, id := range speciesIDs {
+		placeholders = append(placeholders, fmt.Sprintf("$%d", i+1))
+		args = append(args, id)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT DISTINCT ON (species_id) species_id, species_form_factor
+		FROM public.species_legislations
+		WHERE species_id IN (%s) AND is_law_active = true
+		ORDER BY species_id,
+			CASE law_scope WHEN 'MUNICIPAL' THEN 0 WHEN 'STATE' THEN 1 ELSE 2 END,
+			updated_at DESC`,
+		strings.Join(placeholders, ", "),
+	)
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var speciesID, formFactor string
+		if err := rows.Scan(&speciesID, &formFactor); err != nil {
+			return nil, err
+		}
+		ff, err := utils.StringToFloat64(formFactor)
+		if err != nil || ff <= 0 {
+			continue
+		}
+		result[speciesID] = ff
+	}
+
+	return result, rows.Err()
+}
+
+// speciesTraits representa atributos da espécie usados nos cálculos da análise
+type speciesTraits struct {
+	WoodDensity *float64
+	Habit       *string
+}
+
+// getSpeciesTraitsBySpeciesIDs retorna densidade da madeira e hábito de cada espécie.
+// Espécies sem nenhum dos dois cadastrados não aparecem no mapa.
+func getSpeciesTraitsBySpeciesIDs(ctx context.Context, db dbtx, speciesIDs []string) (map[string]speciesTraits, error) {
+	result := make(map[string]speciesTraits, len(speciesIDs))
+	if len(speciesIDs) == 0 {
+		return result, nil
+	}
+
+	placeholders := make([]string, 0, len(speciesIDs))
+	args := make([]interface{}, 0, len(speciesIDs))
+	for i, id := range speciesIDs {
+		placeholders = append(placeholders, fmt.Sprintf("$%d", i+1))
+		args = append(args, id)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, wood_density::text, habit::text
+		FROM public.species
+		WHERE id IN (%s) AND (wood_density IS NOT NULL OR habit IS NOT NULL)`,
+		strings.Join(placeholders, ", "),
+	)
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var speciesID string
+		var woodDensity, habit sql.NullString
+		if err := rows.Scan(&speciesID, &woodDensity, &habit); err != nil {
+			return nil, err
+		}
+		traits := speciesTraits{Habit: utils.FromNullString(habit)}
+		if wd := utils.NullStringToNullFloat64(woodDensity); wd != nil && *wd > 0 {
+			traits.WoodDensity = wd
+		}
+		result[speciesID] = traits
+	}
+
+	return result, rows.Err()
+}
+
+// getActiveLegislationsBySpeciesIDs retorna as legislações vigentes de cada espécie (proteção, grau
+// de ameaça, origem e grupo ecológico), da mais específica (municipal) para a mais geral (federal)
+func getActiveLegislationsBySpeciesIDs(ctx context.Context, db dbtx, speciesIDs []string) (map[string][]types.SpeciesLegislationStatus, error) {
+	result := make(map[string][]types.SpeciesLegislationStatus, len(speciesIDs))
+	if len(speciesIDs) == 0 {
+		return result, nil
+	}
+
+	placeholders := make([]string, 0, len(speciesIDs))
+	args := make([]interface{}, 0, len(speciesIDs))
+	for i, id := range speciesIDs {
+		placeholders = append(placeholders, fmt.Sprintf("$%d", i+1))
+		args = append(args, id)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT species_id, law_scope::text, law_id, is_species_protected, species_threat_status::text,
+			species_origin::text, successional_ecology::text
+		FROM public.species_legislations
+		WHERE species_id IN (%s) AND is_law_active = true
+		ORDER BY species_id,
+			CASE law_scope WHEN 'MUNICIPAL' THEN 0 WHEN 'STATE' THEN 1 ELSE 2 END,
+			updated_at DESC`,
+		strings.Join(placeholders, ", "),
+	)
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var speciesID string
+		var l types.SpeciesLegislationStatus
+		var lawID sql.NullString
+		if err := rows.Scan(&speciesID, &l.LawScope, &lawID, &l.Protected, &l.ThreatStatus, &l.Origin, &l.SuccessionalEcology); err != nil {
+			return nil, err
+		}
+		l.LawID = utils.FromNullString(lawID)
+		result[speciesID] = append(result[speciesID], l)
+	}
+
+	return result, rows.Err()
+}
+
+// threatSeverity ordena os graus de ameaça do mais restritivo (CR) ao menos restritivo (LC)
+var threatSeverity = map[string]int{"CR": 0, "EN": 1, "VU": 2, "NT": 3, "LC": 4}
+
+// applyLegislations preenche as legislações vigentes do espécime, a proteção (alguma legislação
+// protege a espécie) e o grau de ameaça mais restritivo entre elas
+func applyLegislations(s *types.SpecimenWithSpecies, legislations []types.SpeciesLegislationStatus) {
+	s.Legislations = legislations
+	s.Protected = false
+	s.ThreatStatus = nil
+	for i, l := range legislations {
+		s.Protected = s.Protected || l.Protected
+		sev, ok := threatSeverity[l.ThreatStatus]
+		if !ok {
+			continue
+		}
+		if s.ThreatStatus == nil || sev < threatSeverity[*s.ThreatStatus] {
+			s.ThreatStatus = &legislations[i].ThreatStatus
+		}
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
 func (r *SpeciesRepo) GetByScientificName(ctx context.Context, scientificName string) (*types.SpeciesWithLegislation, error) {
 	row, err := r.q.GetSpeciesByScientificName(ctx, scientificName)
 	if err != nil {
@@ -201,6 +545,7 @@ func (r *SpeciesRepo) GetByScientificName(ctx context.Context, scientificName st
 		Family:         row.Family,
 		PopularName:    utils.FromNullString(row.PopularName),
 		Habit:          utils.FromNullSpeciesHabit(row.Habit),
+		WoodDensity:    utils.NullStringToNullFloat64(row.WoodDensity),
 		CreatedAt:      row.CreatedAt,
 		UpdatedAt:      row.UpdatedAt,
 		Legislations:   legislationData,
@@ -250,6 +595,7 @@ func (r *SpeciesRepo) List(ctx context.Context, limit, offset int32) ([]*types.S
 			Family:         row.Family,
 			PopularName:    utils.FromNullString(row.PopularName),
 			Habit:          utils.FromNullSpeciesHabit(row.Habit),
+			WoodDensity:    utils.NullStringToNullFloat64(row.WoodDensity),
 			CreatedAt:      row.CreatedAt,
 			UpdatedAt:      row.UpdatedAt,
 			Legislations:   legislationData,
@@ -267,6 +613,7 @@ func (r *SpeciesRepo) UpdateSpecies(ctx context.Context, s *domainspecies.Specie
 		PopularName:    utils.ToNullString(s.PopularName),
 		Habit:          utils.ToNullSpeciesHabit(s.Habit),
 		UpdatedAt:      s.UpdatedAt,
+		WoodDensity:    utils.Float64PtrToString(s.WoodDensity),
 	})
 }
 
@@ -28,20 +28,28 @@ func NewSpecimenRepo(db *sql.DB) *SpecimenRepo {
 
 func (r *SpecimenRepo) Create(ctx context.Context, s *domainspecimen.Specimen) error {
 	_, err := r.q.CreateSpecimen(ctx, sqlc.CreateSpecimenParams{
-		ID:              s.ID,
-		Portion:         s.Portion,
-		Height:          utils.Float64ToString(s.Height),
-		Cap1:            utils.Float64ToString(s.Cap1),
-		Cap2:            utils.Float64PtrToString(s.Cap2),
-		Cap3:            utils.Float64PtrToString(s.Cap3),
-		Cap4:            utils.Float64PtrToString(s.Cap4),
-		Cap5:            utils.Float64PtrToString(s.Cap5),
-		Cap6:            utils.Float64PtrToString(s.Cap6),
-		RegisterDate:    s.RegisterDate,
-		PhytoAnalysisID: s.PhytoAnalysisID,
-		SpecieID:        s.SpecieID,
-		CreatedAt:       s.CreatedAt,
-		UpdatedAt:       s.UpdatedAt,
+		ID:               s.ID,
+		Portion:          s.Portion,
+		Height:           utils.Float64PtrToString(s.Height),
+		Cap1:             utils.Float64ToString(s.Cap1),
+		Cap2:             utils.Float64PtrToString(s.Cap2),
+		Cap3:             utils.Float64PtrToString(s.Cap3),
+		Cap4:             utils.Float64PtrToString(s.Cap4),
+		Cap5:             utils.Float64PtrToString(s.Cap5),
+		Cap6:             utils.Float64PtrToString(s.Cap6),
+		RegisterDate:     s.RegisterDate,
+		PhytoAnalysisID:  s.PhytoAnalysisID,
+		SpecieID:         utils.NonEmptyToNullString(s.SpecieID),
+		CreatedAt:        s.CreatedAt,
+		UpdatedAt:        s.UpdatedAt,
+		Latitude:         utils.Float64PtrToString(s.Latitude),
+		Longitude:        utils.Float64PtrToString(s.Longitude),
+		Tag:              utils.ToNullString(s.Tag),
+		Status:           s.Status,
+		Phytosanitary:    utils.ToNullString(s.Phytosanitary),
+		BifurcationNotes: utils.ToNullString(s.BifurcationNotes),
+		Observations:     utils.ToNullString(s.Observations),
+		MorphospeciesID:  utils.ToNullString(s.MorphospeciesID),
 	})
 	return err
 }
@@ -55,27 +63,35 @@ func (r *SpecimenRepo) GetByID(ctx context.Context, id string) (*types.SpecimenW
 		return nil, err
 	}
 
-	height, _ := utils.StringToFloat64(row.Height)
+	height, _ := utils.StringToFloat64(row.Height.String) // 0 quando não medida
 	cap1, _ := utils.StringToFloat64(row.Cap1)
 
 	return &types.SpecimenWithSpecies{
-		ID:              row.ID,
-		Portion:         row.Portion,
-		Height:          height,
-		Cap1:            cap1,
-		Cap2:            utils.NullStringToNullFloat64(row.Cap2),
-		Cap3:            utils.NullStringToNullFloat64(row.Cap3),
-		Cap4:            utils.NullStringToNullFloat64(row.Cap4),
-		Cap5:            utils.NullStringToNullFloat64(row.Cap5),
-		Cap6:            utils.NullStringToNullFloat64(row.Cap6),
-		RegisterDate:    row.RegisterDate,
-		PhytoAnalysisID: row.PhytoAnalysisID,
-		SpecieID:        row.SpecieID,
-		CreatedAt:       row.CreatedAt,
-		UpdatedAt:       row.UpdatedAt,
-		ScientificName:  row.ScientificName,
-		Family:          row.Family,
-		PopularName:     utils.FromNullString(row.PopularName),
+		ID:               row.ID,
+		Portion:          row.Portion,
+		Height:           height,
+		Cap1:             cap1,
+		Cap2:             utils.NullStringToNullFloat64(row.Cap2),
+		Cap3:             utils.NullStringToNullFloat64(row.Cap3),
+		Cap4:             utils.NullStringToNullFloat64(row.Cap4),
+		Cap5:             utils.NullStringToNullFloat64(row.Cap5),
+		Cap6:             utils.NullStringToNullFloat64(row.Cap6),
+		RegisterDate:     row.RegisterDate,
+		PhytoAnalysisID:  row.PhytoAnalysisID,
+		SpecieID:         row.SpecieID.String,
+		MorphospeciesID:  utils.FromNullString(row.MorphospeciesID),
+		CreatedAt:        row.CreatedAt,
+		UpdatedAt:        row.UpdatedAt,
+		Latitude:         utils.NullStringToNullFloat64(row.Latitude),
+		Longitude:        utils.NullStringToNullFloat64(row.Longitude),
+		Tag:              utils.FromNullString(row.Tag),
+		Status:           row.Status,
+		Phytosanitary:    utils.FromNullString(row.Phytosanitary),
+		BifurcationNotes: utils.FromNullString(row.BifurcationNotes),
+		Observations:     utils.FromNullString(row.Observations),
+		ScientificName:   row.ScientificName,
+		Family:           row.Family,
+		PopularName:      utils.FromNullString(row.PopularName),
 	}, nil
 }
 
@@ -87,27 +103,35 @@ func (r *SpecimenRepo) ListByPhytoAnalysis(ctx context.Context, phytoAnalysisID
 
 	result := make([]*types.SpecimenWithSpecies, 0, len(rows))
 	for _, row := range rows {
-		height, _ := utils.StringToFloat64(row.Height)
+		height, _ := utils.StringToFloat64(row.Height.String) // 0 quando não medida
 		cap1, _ := utils.StringToFloat64(row.Cap1)
 
 		result = append(result, &types.SpecimenWithSpecies{
-			ID:              row.ID,
-			Portion:         row.Portion,
-			Height:          height,
-			Cap1:            cap1,
-			Cap2:            utils.NullStringToNullFloat64(row.Cap2),
-			Cap3:            utils.NullStringToNullFloat64(row.Cap3),
-			Cap4:            utils.NullStringToNullFloat64(row.Cap4),
-			Cap5:            utils.NullStringToNullFloat64(row.Cap5),
-			Cap6:            utils.NullStringToNullFloat64(row.Cap6),
-			RegisterDate:    row.RegisterDate,
-			PhytoAnalysisID: row.PhytoAnalysisID,
-			SpecieID:        row.SpecieID,
-			CreatedAt:       row.CreatedAt,
-			UpdatedAt:       row.UpdatedAt,
-			ScientificName:  row.ScientificName,
-			Family:          row.Family,
-			PopularName:     utils.FromNullString(row.PopularName),
+			ID:               row.ID,
+			Portion:          row.Portion,
+			Height:           height,
+			Cap1:             cap1,
+			Cap2:             utils.NullStringToNullFloat64(row.Cap2),
+			Cap3:             utils.NullStringToNullFloat64(row.Cap3),
+			Cap4:             utils.NullStringToNullFloat64(row.Cap4),
+			Cap5:             utils.NullStringToNullFloat64(row.Cap5),
+			Cap6:             utils.NullStringToNullFloat64(row.Cap6),
+			RegisterDate:     row.RegisterDate,
+			PhytoAnalysisID:  row.PhytoAnalysisID,
+			SpecieID:         row.SpecieID.String,
+			MorphospeciesID:  utils.FromNullString(row.MorphospeciesID),
+			CreatedAt:        row.CreatedAt,
+			UpdatedAt:        row.UpdatedAt,
+			Latitude:         utils.NullStringToNullFloat64(row.Latitude),
+			Longitude:        utils.NullStringToNullFloat64(row.Longitude),
+			Tag:              utils.FromNullString(row.Tag),
+			Status:           row.Status,
+			Phytosanitary:    utils.FromNullString(row.Phytosanitary),
+			BifurcationNotes: utils.FromNullString(row.BifurcationNotes),
+			Observations:     utils.FromNullString(row.Observations),
+			ScientificName:   row.ScientificName,
+			Family:           row.Family,
+			PopularName:      utils.FromNullString(row.PopularName),
 		})
 	}
 
@@ -116,18 +140,26 @@ func (r *SpecimenRepo) ListByPhytoAnalysis(ctx context.Context, phytoAnalysisID
 
 func (r *SpecimenRepo) Update(ctx context.Context, s *domainspecimen.Specimen) error {
 	return r.q.UpdateSpecimen(ctx, sqlc.UpdateSpecimenParams{
-		ID:           s.ID,
-		Portion:      s.Portion,
-		Height:       utils.Float64ToString(s.Height),
-		Cap1:         utils.Float64ToString(s.Cap1),
-		Cap2:         utils.Float64PtrToString(s.Cap2),
-		Cap3:         utils.Float64PtrToString(s.Cap3),
-		Cap4:         utils.Float64PtrToString(s.Cap4),
-		Cap5:         utils.Float64PtrToString(s.Cap5),
-		Cap6:         utils.Float64PtrToString(s.Cap6),
-		RegisterDate: s.RegisterDate,
-		SpecieID:     s.SpecieID,
-		UpdatedAt:    s.UpdatedAt,
+		ID:               s.ID,
+		Portion:          s.Portion,
+		Height:           utils.Float64PtrToString(s.Height),
+		Cap1:             utils.Float64ToString(s.Cap1),
+		Cap2:             utils.Float64PtrToString(s.Cap2),
+		Cap3:             utils.Float64PtrToString(s.Cap3),
+		Cap4:             utils.Float64PtrToString(s.Cap4),
+		Cap5:             utils.Float64PtrToString(s.Cap5),
+		Cap6:             utils.Float64PtrToString(s.Cap6),
+		RegisterDate:     s.RegisterDate,
+		SpecieID:         utils.NonEmptyToNullString(s.SpecieID),
+		UpdatedAt:        s.UpdatedAt,
+		Latitude:         utils.Float64PtrToString(s.Latitude),
+		Longitude:        utils.Float64PtrToString(s.Longitude),
+		Tag:              utils.ToNullString(s.Tag),
+		Status:           s.Status,
+		Phytosanitary:    utils.ToNullString(s.Phytosanitary),
+		BifurcationNotes: utils.ToNullString(s.BifurcationNotes),
+		Observations:     utils.ToNullString(s.Observations),
+		MorphospeciesID:  utils.ToNullString(s.MorphospeciesID),
 	})
 }
 
@@ -140,6 +172,22 @@ func (r *SpecimenRepo) DeleteByPhytoAnalysis(ctx context.Context, phytoAnalysisI
 	return err
 }
 
+// ListTagsByPhytoAnalysis retorna as plaquetas cadastradas na análise
+func (r *SpecimenRepo) ListTagsByPhytoAnalysis(ctx context.Context, phytoAnalysisID string) ([]string, error) {
+	return r.q.ListSpecimenTagsByPhytoAnalysis(ctx, phytoAnalysisID)
+}
+
+// TagInUse informa se a plaqueta já está em outro espécime da análise (exceptID = o próprio espécime)
+func (r *SpecimenRepo) TagInUse(ctx context.Context, phytoAnalysisID, tag, exceptID string) (bool, error) {
+	var used bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM public.specimen
+			WHERE phyto_analysis_id = $1 AND tag = $2 AND id <> $3
+		)`, phytoAnalysisID, tag, exceptID).Scan(&used)
+	return used, err
+}
+
 func (r *SpecimenRepo) CountByPhytoAnalysis(ctx context.Context, phytoAnalysisID string) (int64, error) {
 	count, err := r.q.CountSpecimensByPhytoAnalysis(ctx, phytoAnalysisID)
 	if err != nil {
@@ -154,21 +202,22 @@ func (r *SpecimenRepo) CreateBatch(ctx context.Context, specimens []*domainspeci
 		return nil
 	}
 
-	const colsPerRow = 14
+	const colsPerRow = 22
 	valueGroups := make([]string, 0, len(specimens))
 	args := make([]interface{}, 0, len(specimens)*colsPerRow)
 
 	for i, s := range specimens {
 		base := i * colsPerRow
 		valueGroups = append(valueGroups, fmt.Sprintf(
-			"($%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d)",
-			base+1, base+2, base+3, base+4, base+5, base+6, base+7,
-			base+8, base+9, base+10, base+11, base+12, base+13, base+14,
+			"($%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d)",
+			base+1, base+2, base+3, base+4, base+5, base+6, base+7, base+8,
+			base+9, base+10, base+11, base+12, base+13, base+14, base+15, base+16,
+			base+17, base+18, base+19, base+20, base+21, base+22,
 		))
 		args = append(args,
 			s.ID,
 			s.Portion,
-			utils.Float64ToString(s.Height),
+			utils.Float64PtrToString(s.Height),
 			utils.Float64ToString(s.Cap1),
 			utils.Float64PtrToString(s.Cap2),
 			utils.Float64PtrToString(s.Cap3),
@@ -177,15 +226,24 @@ func (r *SpecimenRepo) CreateBatch(ctx context.Context, specimens []*domainspeci
 			utils.Float64PtrToString(s.Cap6),
 			s.RegisterDate,
 			s.PhytoAnalysisID,
-			s.SpecieID,
+			utils.NonEmptyToNullString(s.SpecieID),
 			s.CreatedAt,
 			s.UpdatedAt,
+			utils.Float64PtrToString(s.Latitude),
+			utils.Float64PtrToString(s.Longitude),
+			utils.ToNullString(s.Tag),
+			s.Status,
+			utils.ToNullString(s.Phytosanitary),
+			utils.ToNullString(s.BifurcationNotes),
+			utils.ToNullString(s.Observations),
+			utils.ToNullString(s.MorphospeciesID),
 		)
 	}
 
 	query := `INSERT INTO public.specimen (
 		id, portion, height, cap1, cap2, cap3, cap4, cap5, cap6,
-		register_date, phyto_analysis_id, specie_id, created_at, updated_at
+		register_date, phyto_analysis_id, specie_id, created_at, updated_at,
+		latitude, longitude, tag, status, phytosanitary, bifurcation_notes, observations, morphospecies_id
 	) VALUES ` + strings.Join(valueGroups, ", ")
 
 	_, err := r.db.ExecContext(ctx, query, args...)
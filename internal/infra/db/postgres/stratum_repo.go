@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/ESG-Project/suassu-api/internal/app/types"
+	"github.com/ESG-Project/suassu-api/internal/apperr"
+	domainstratum "github.com/ESG-Project/suassu-api/internal/domain/stratum"
+	"github.com/ESG-Project/suassu-api/internal/infra/db/postgres/utils"
+	sqlc "github.com/ESG-Project/suassu-api/internal/infra/db/sqlc/gen"
+)
+
+type StratumRepo struct {
+	q *sqlc.Queries
+}
+
+func NewStratumRepo(db *sql.DB) *StratumRepo {
+	return &StratumRepo{q: sqlc.New(db)}
+}
+
+func NewStratumRepoFrom(d dbtx) *StratumRepo {
+	return &StratumRepo{q: sqlc.New(d)}
+}
+
+func (r *StratumRepo) Create(ctx context.Context, s *domainstratum.Stratum) error {
+	return r.q.CreateStratum(ctx, sqlc.CreateStratumParams{
+		ID:              s.ID,
+		PhytoAnalysisID: s.PhytoAnalysisID,
+		Code:            s.Code,
+		Name:            s.Name,
+		Area:            utils.Float64ToString(s.AreaHa),
+		Description:     utils.ToNullString(s.Description),
+		CreatedAt:       s.CreatedAt,
+		UpdatedAt:       s.UpdatedAt,
+	})
+}
+
+func (r *StratumRepo) GetByID(ctx context.Context, phytoAnalysisID, id string) (*types.StratumData, error) {
+	row, err := r.q.GetStratumByID(ctx, sqlc.GetStratumByIDParams{ID: id, PhytoAnalysisID: phytoAnalysisID})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, apperr.New(apperr.CodeNotFound, "stratum not found")
+		}
+		return nil, err
+	}
+	return toStratumData(row), nil
+}
+
+func (r *StratumRepo) ListByPhytoAnalysis(ctx context.Context, phytoAnalysisID string) ([]*types.StratumData, error) {
+	rows, err := r.q.ListStrataByPhytoAnalysis(ctx, phytoAnalysisID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*types.StratumData, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, toStratumData(row))
+	}
+	return result, nil
+}
+
+func (r *StratumRepo) Update(ctx context.Context, s *domainstratum.Stratum) error {
+	return r.q.UpdateStratum(ctx, sqlc.UpdateStratumParams{
+		ID:              s.ID,
+		PhytoAnalysisID: s.PhytoAnalysisID,
+		Code:            s.Code,
+		Name:            s.Name,
+		Area:            utils.Float64ToString(s.AreaHa),
+		Description:     utils.ToNullString(s.Description),
+		UpdatedAt:       s.UpdatedAt,
+	})
+}
+
+func (r *StratumRepo) Delete(ctx context.Context, phytoAnalysisID, id string) error {
+	return r.q.DeleteStratum(ctx, sqlc.DeleteStratumParams{ID: id, PhytoAnalysisID: phytoAnalysisID})
+}
+
+// CountPlots conta as parcelas associadas ao estrato
+func (r *StratumRepo) CountPlots(ctx context.Context, id string) (int64, error) {
+	return r.q.CountPlotsByStratum(ctx, utils.StringToNullString(id))
+}
+
+func toStratumData(row sqlc.Stratum) *types.StratumData {
+	area, _ := utils.StringToFloat64(row.Area)
+	return &types.StratumData{
+		ID:              row.ID,
+		PhytoAnalysisID: row.PhytoAnalysisID,
+		Code:            row.Code,
+		Name:            row.Name,
+		AreaHa:          area,
+		Description:     utils.FromNullString(row.Description),
+		CreatedAt:       row.CreatedAt,
+		UpdatedAt:       row.UpdatedAt,
+	}
+}
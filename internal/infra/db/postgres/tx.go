@@ -29,6 +29,9 @@ type Repos struct {
 	PhytoAnalyses func() *PhytoAnalysisRepo
 	Specimens     func() *SpecimenRepo
 	Species       func() *SpeciesRepo
+	Plots         func() *PlotRepo
+	Strata        func() *StratumRepo
+	Morphospecies func() *MorphospeciesRepo
 }
 
 func (m *TxManager) RunInTx(ctx context.Context, fn func(r Repos) error) error {
@@ -46,6 +49,9 @@ func (m *TxManager) RunInTx(ctx context.Context, fn func(r Repos) error) error {
 		PhytoAnalyses: func() *PhytoAnalysisRepo { return NewPhytoAnalysisRepoFrom(tx) },
 		Specimens:     func() *SpecimenRepo { return NewSpecimenRepoFrom(tx) },
 		Species:       func() *SpeciesRepo { return NewSpeciesRepoFrom(tx) },
+		Plots:         func() *PlotRepo { return NewPlotRepoFrom(tx) },
+		Strata:        func() *StratumRepo { return NewStratumRepoFrom(tx) },
+		Morphospecies: func() *MorphospeciesRepo { return NewMorphospeciesRepoFrom(tx) },
 	}
 
 	if err := fn(r); err != nil {
@@ -108,3 +108,8 @@ func FromNullSpeciesHabit(nh sqlc.NullSpeciesHabit) *string {
 func StringToNullString(s string) sql.NullString {
 	return sql.NullString{String: s, Valid: true}
 }
+
+// NonEmptyToNullString converte string para sql.NullString (vazia = NULL)
+func NonEmptyToNullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -7,6 +7,7 @@ package sqlcgen
 import (
 	"database/sql"
 	"database/sql/driver"
+	"encoding/json"
 	"fmt"
 	"time"
 )
@@ -310,11 +311,33 @@ type Enterprise struct {
 	Phone       sql.NullString `json:"phone"`
 }
 
+type Equation struct {
+	ID            string          `json:"id"`
+	Name          string          `json:"name"`
+	Kind          string          `json:"kind"`
+	Expression    string          `json:"expression"`
+	Coefficients  json.RawMessage `json:"coefficients"`
+	Applicability json.RawMessage `json:"applicability"`
+	Reference     sql.NullString  `json:"reference"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
 type Feature struct {
 	ID   string `json:"id"`
 	Name string `json:"name"`
 }
 
+type Morphospecy struct {
+	ID              string         `json:"id"`
+	PhytoAnalysisID string         `json:"phyto_analysis_id"`
+	Name            string         `json:"name"`
+	Family          sql.NullString `json:"family"`
+	Genus           sql.NullString `json:"genus"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+}
+
 type Permission struct {
 	ID        string `json:"id"`
 	FeatureId string `json:"featureId"`
@@ -326,17 +349,74 @@ type Permission struct {
 }
 
 type PhytoAnalysis struct {
-	ID              string         `json:"id"`
-	Title           string         `json:"title"`
-	InitialDate     time.Time      `json:"initial_date"`
-	PortionQuantity int32          `json:"portion_quantity"`
-	PortionArea     string         `json:"portion_area"`
-	TotalArea       string         `json:"total_area"`
-	SampledArea     string         `json:"sampled_area"`
-	Description     sql.NullString `json:"description"`
-	ProjectID       string         `json:"project_id"`
-	CreatedAt       time.Time      `json:"created_at"`
-	UpdatedAt       time.Time      `json:"updated_at"`
+	ID                string         `json:"id"`
+	Title             string         `json:"title"`
+	InitialDate       time.Time      `json:"initial_date"`
+	PortionQuantity   int32          `json:"portion_quantity"`
+	PortionArea       string         `json:"portion_area"`
+	TotalArea         string         `json:"total_area"`
+	SampledArea       string         `json:"sampled_area"`
+	Description       sql.NullString `json:"description"`
+	ProjectID         string         `json:"project_id"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+	DefaultFormFactor sql.NullString `json:"default_form_factor"`
+	MeasurementMode   string         `json:"measurement_mode"`
+	MeasurementUnit   string         `json:"measurement_unit"`
+	InclusionMin      sql.NullString `json:"inclusion_min"`
+	InclusionPolicy   string         `json:"inclusion_policy"`
+	ExcludeDead       bool           `json:"exclude_dead"`
+}
+
+type PhytoAnalysisEquation struct {
+	PhytoAnalysisID string          `json:"phyto_analysis_id"`
+	Kind            string          `json:"kind"`
+	EquationID      string          `json:"equation_id"`
+	Definition      json.RawMessage `json:"definition"`
+	SelectedAt      time.Time       `json:"selected_at"`
+}
+
+type PhytoAssortment struct {
+	PhytoAnalysisID string    `json:"phyto_analysis_id"`
+	LogMinDbhCm     string    `json:"log_min_dbh_cm"`
+	StackingFactor  string    `json:"stacking_factor"`
+	DeadAsFirewood  bool      `json:"dead_as_firewood"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+type PhytoCampaign struct {
+	PhytoAnalysisID    string    `json:"phyto_analysis_id"`
+	PreviousAnalysisID string    `json:"previous_analysis_id"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+type PhytoIndicatorSnapshot struct {
+	ID              string          `json:"id"`
+	PhytoAnalysisID string          `json:"phyto_analysis_id"`
+	Version         int32           `json:"version"`
+	EngineVersion   int32           `json:"engine_version"`
+	Reason          string          `json:"reason"`
+	SpecimensCount  int32           `json:"specimens_count"`
+	Payload         json.RawMessage `json:"payload"`
+	CreatedAt       time.Time       `json:"created_at"`
+}
+
+type Plot struct {
+	ID              string          `json:"id"`
+	PhytoAnalysisID string          `json:"phyto_analysis_id"`
+	Code            string          `json:"code"`
+	Area            string          `json:"area"`
+	Shape           string          `json:"shape"`
+	Width           sql.NullString  `json:"width"`
+	Length          sql.NullString  `json:"length"`
+	Radius          sql.NullString  `json:"radius"`
+	CenterLatitude  sql.NullString  `json:"center_latitude"`
+	CenterLongitude sql.NullString  `json:"center_longitude"`
+	Vertices        json.RawMessage `json:"vertices"`
+	VegetationNotes sql.NullString  `json:"vegetation_notes"`
+	CreatedAt       time.Time       `json:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at"`
+	StratumID       sql.NullString  `json:"stratum_id"`
 }
 
 type Project struct {
@@ -378,6 +458,7 @@ type Species struct {
 	Habit          NullSpeciesHabit `json:"habit"`
 	CreatedAt      time.Time        `json:"created_at"`
 	UpdatedAt      time.Time        `json:"updated_at"`
+	WoodDensity    sql.NullString   `json:"wood_density"`
 }
 
 type SpeciesChange struct {
@@ -411,18 +492,37 @@ type SpeciesLegislation struct {
 }
 
 type Speciman struct {
+	ID               string         `json:"id"`
+	Portion          string         `json:"portion"`
+	Height           sql.NullString `json:"height"`
+	Cap1             string         `json:"cap1"`
+	Cap2             sql.NullString `json:"cap2"`
+	Cap3             sql.NullString `json:"cap3"`
+	Cap4             sql.NullString `json:"cap4"`
+	Cap5             sql.NullString `json:"cap5"`
+	Cap6             sql.NullString `json:"cap6"`
+	RegisterDate     time.Time      `json:"register_date"`
+	PhytoAnalysisID  string         `json:"phyto_analysis_id"`
+	SpecieID         sql.NullString `json:"specie_id"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	Latitude         sql.NullString `json:"latitude"`
+	Longitude        sql.NullString `json:"longitude"`
+	Tag              sql.NullString `json:"tag"`
+	Status           string         `json:"status"`
+	Phytosanitary    sql.NullString `json:"phytosanitary"`
+	BifurcationNotes sql.NullString `json:"bifurcation_notes"`
+	Observations     sql.NullString `json:"observations"`
+	MorphospeciesID  sql.NullString `json:"morphospecies_id"`
+}
+
+type Stratum struct {
 	ID              string         `json:"id"`
-	Portion         string         `json:"portion"`
-	Height          string         `json:"height"`
-	Cap1            string         `json:"cap1"`
-	Cap2            sql.NullString `json:"cap2"`
-	Cap3            sql.NullString `json:"cap3"`
-	Cap4            sql.NullString `json:"cap4"`
-	Cap5            sql.NullString `json:"cap5"`
-	Cap6            sql.NullString `json:"cap6"`
-	RegisterDate    time.Time      `json:"register_date"`
 	PhytoAnalysisID string         `json:"phyto_analysis_id"`
-	SpecieID        string         `json:"specie_id"`
+	Code            string         `json:"code"`
+	Name            string         `json:"name"`
+	Area            string         `json:"area"`
+	Description     sql.NullString `json:"description"`
 	CreatedAt       time.Time      `json:"created_at"`
 	UpdatedAt       time.Time      `json:"updated_at"`
 }
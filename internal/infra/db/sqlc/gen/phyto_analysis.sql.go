@@ -23,24 +23,36 @@ INSERT INTO public.phyto_analysis (
     description,
     project_id,
     created_at,
-    updated_at
+    updated_at,
+    default_form_factor,
+    measurement_mode,
+    measurement_unit,
+    inclusion_min,
+    inclusion_policy,
+    exclude_dead
 )
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
-RETURNING id, title, initial_date, portion_quantity, portion_area, total_area, sampled_area, description, project_id, created_at, updated_at
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+RETURNING id, title, initial_date, portion_quantity, portion_area, total_area, sampled_area, description, project_id, created_at, updated_at, default_form_factor, measurement_mode, measurement_unit, inclusion_min, inclusion_policy, exclude_dead
 `
 
 type CreatePhytoAnalysisParams struct {
-	ID              string         `json:"id"`
-	Title           string         `json:"title"`
-	InitialDate     time.Time      `json:"initial_date"`
-	PortionQuantity int32          `json:"portion_quantity"`
-	PortionArea     string         `json:"portion_area"`
-	TotalArea       string         `json:"total_area"`
-	SampledArea     string         `json:"sampled_area"`
-	Description     sql.NullString `json:"description"`
-	ProjectID       string         `json:"project_id"`
-	CreatedAt       time.Time      `json:"created_at"`
-	UpdatedAt       time.Time      `json:"updated_at"`
+	ID                string         `json:"id"`
+	Title             string         `json:"title"`
+	InitialDate       time.Time      `json:"initial_date"`
+	PortionQuantity   int32          `json:"portion_quantity"`
+	PortionArea       string         `json:"portion_area"`
+	TotalArea         string         `json:"total_area"`
+	SampledArea       string         `json:"sampled_area"`
+	Description       sql.NullString `json:"description"`
+	ProjectID         string         `json:"project_id"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+	DefaultFormFactor sql.NullString `json:"default_form_factor"`
+	MeasurementMode   string         `json:"measurement_mode"`
+	MeasurementUnit   string         `json:"measurement_unit"`
+	InclusionMin      sql.NullString `json:"inclusion_min"`
+	InclusionPolicy   string         `json:"inclusion_policy"`
+	ExcludeDead       bool           `json:"exclude_dead"`
 }
 
 func (q *Queries) CreatePhytoAnalysis(ctx context.Context, arg CreatePhytoAnalysisParams) (PhytoAnalysis, error) {
@@ -56,6 +68,12 @@ func (q *Queries) CreatePhytoAnalysis(ctx context.Context, arg CreatePhytoAnalys
 		arg.ProjectID,
 		arg.CreatedAt,
 		arg.UpdatedAt,
+		arg.DefaultFormFactor,
+		arg.MeasurementMode,
+		arg.MeasurementUnit,
+		arg.InclusionMin,
+		arg.InclusionPolicy,
+		arg.ExcludeDead,
 	)
 	var i PhytoAnalysis
 	err := row.Scan(
@@ -70,6 +88,12 @@ func (q *Queries) CreatePhytoAnalysis(ctx context.Context, arg CreatePhytoAnalys
 		&i.ProjectID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DefaultFormFactor,
+		&i.MeasurementMode,
+		&i.MeasurementUnit,
+		&i.InclusionMin,
+		&i.InclusionPolicy,
+		&i.ExcludeDead,
 	)
 	return i, err
 }
@@ -148,6 +172,115 @@ func (q *Queries) GetPhytoAnalysisByID(ctx context.Context, id string) (GetPhyto
 	return i, err
 }
 
+const getPhytoAnalysisComplete = `-- name: GetPhytoAnalysisComplete :one
+SELECT 
+    pa.id AS phyto_id,
+    pa.title AS phyto_title,
+    pa.initial_date,
+    pa.portion_quantity,
+    pa.portion_area,
+    pa.total_area,
+    pa.sampled_area,
+    pa.description AS phyto_description,
+    pa.project_id,
+    pa.created_at AS phyto_created_at,
+    pa.updated_at AS phyto_updated_at,
+    pa.default_form_factor,
+    pa.measurement_mode,
+    pa.measurement_unit,
+    pa.inclusion_min,
+    pa.inclusion_policy,
+    pa.exclude_dead,
+    p.title AS project_title,
+    p.cnpj AS project_cnpj,
+    p.activity AS project_activity,
+    p."clientId" AS project_client_id,
+    a."zipCode" AS project_zip_code,
+    a.state AS project_state,
+    a.city AS project_city,
+    a.neighborhood AS project_neighborhood,
+    a.street AS project_street,
+    a.num AS project_num,
+    a.latitude AS project_latitude,
+    a.longitude AS project_longitude,
+    a."addInfo" AS project_add_info
+FROM public.phyto_analysis pa
+INNER JOIN public."Project" p ON pa.project_id = p.id
+LEFT JOIN public."Address" a ON p."addressId" = a.id
+WHERE pa.id = $1
+`
+
+type GetPhytoAnalysisCompleteRow struct {
+	PhytoID             string         `json:"phyto_id"`
+	PhytoTitle          string         `json:"phyto_title"`
+	InitialDate         time.Time      `json:"initial_date"`
+	PortionQuantity     int32          `json:"portion_quantity"`
+	PortionArea         string         `json:"portion_area"`
+	TotalArea           string         `json:"total_area"`
+	SampledArea         string         `json:"sampled_area"`
+	PhytoDescription    sql.NullString `json:"phyto_description"`
+	ProjectID           string         `json:"project_id"`
+	PhytoCreatedAt      time.Time      `json:"phyto_created_at"`
+	PhytoUpdatedAt      time.Time      `json:"phyto_updated_at"`
+	DefaultFormFactor   sql.NullString `json:"default_form_factor"`
+	MeasurementMode     string         `json:"measurement_mode"`
+	MeasurementUnit     string         `json:"measurement_unit"`
+	InclusionMin        sql.NullString `json:"inclusion_min"`
+	InclusionPolicy     string         `json:"inclusion_policy"`
+	ExcludeDead         bool           `json:"exclude_dead"`
+	ProjectTitle        string         `json:"project_title"`
+	ProjectCnpj         sql.NullString `json:"project_cnpj"`
+	ProjectActivity     string         `json:"project_activity"`
+	ProjectClientID     string         `json:"project_client_id"`
+	ProjectZipCode      sql.NullString `json:"project_zip_code"`
+	ProjectState        sql.NullString `json:"project_state"`
+	ProjectCity         sql.NullString `json:"project_city"`
+	ProjectNeighborhood sql.NullString `json:"project_neighborhood"`
+	ProjectStreet       sql.NullString `json:"project_street"`
+	ProjectNum          sql.NullString `json:"project_num"`
+	ProjectLatitude     sql.NullString `json:"project_latitude"`
+	ProjectLongitude    sql.NullString `json:"project_longitude"`
+	ProjectAddInfo      sql.NullString `json:"project_add_info"`
+}
+
+func (q *Queries) GetPhytoAnalysisComplete(ctx context.Context, id string) (GetPhytoAnalysisCompleteRow, error) {
+	row := q.db.QueryRowContext(ctx, getPhytoAnalysisComplete, id)
+	var i GetPhytoAnalysisCompleteRow
+	err := row.Scan(
+		&i.PhytoID,
+		&i.PhytoTitle,
+		&i.InitialDate,
+		&i.PortionQuantity,
+		&i.PortionArea,
+		&i.TotalArea,
+		&i.SampledArea,
+		&i.PhytoDescription,
+		&i.ProjectID,
+		&i.PhytoCreatedAt,
+		&i.PhytoUpdatedAt,
+		&i.DefaultFormFactor,
+		&i.MeasurementMode,
+		&i.MeasurementUnit,
+		&i.InclusionMin,
+		&i.InclusionPolicy,
+		&i.ExcludeDead,
+		&i.ProjectTitle,
+		&i.ProjectCnpj,
+		&i.ProjectActivity,
+		&i.ProjectClientID,
+		&i.ProjectZipCode,
+		&i.ProjectState,
+		&i.ProjectCity,
+		&i.ProjectNeighborhood,
+		&i.ProjectStreet,
+		&i.ProjectNum,
+		&i.ProjectLatitude,
+		&i.ProjectLongitude,
+		&i.ProjectAddInfo,
+	)
+	return i, err
+}
+
 const getPhytoAnalysisWithSpecimens = `-- name: GetPhytoAnalysisWithSpecimens :many
 SELECT 
     pa.id AS phyto_id,
@@ -161,6 +294,12 @@ SELECT
     pa.project_id,
     pa.created_at AS phyto_created_at,
     pa.updated_at AS phyto_updated_at,
+    pa.default_form_factor,
+    pa.measurement_mode,
+    pa.measurement_unit,
+    pa.inclusion_min,
+    pa.inclusion_policy,
+    pa.exclude_dead,
     p.title AS project_title,
     p.cnpj AS project_cnpj,
     p.activity AS project_activity,
@@ -185,14 +324,23 @@ SELECT
     sp.cap6,
     sp.register_date,
     sp.specie_id,
-    s.scientific_name,
-    s.family,
+    sp.latitude,
+    sp.longitude,
+    sp.tag,
+    sp.status,
+    sp.phytosanitary,
+    sp.bifurcation_notes,
+    sp.observations,
+    sp.morphospecies_id,
+    COALESCE(s.scientific_name, m.name) AS scientific_name,
+    COALESCE(s.family, m.family) AS family,
     s.popular_name
 FROM public.phyto_analysis pa
 INNER JOIN public."Project" p ON pa.project_id = p.id
 LEFT JOIN public."Address" a ON p."addressId" = a.id
 LEFT JOIN public.specimen sp ON sp.phyto_analysis_id = pa.id
 LEFT JOIN public.species s ON sp.specie_id = s.id
+LEFT JOIN public.morphospecies m ON sp.morphospecies_id = m.id
 WHERE pa.id = $1
 ORDER BY sp.portion ASC, sp.created_at ASC
 `
@@ -209,6 +357,12 @@ type GetPhytoAnalysisWithSpecimensRow struct {
 	ProjectID           string         `json:"project_id"`
 	PhytoCreatedAt      time.Time      `json:"phyto_created_at"`
 	PhytoUpdatedAt      time.Time      `json:"phyto_updated_at"`
+	DefaultFormFactor   sql.NullString `json:"default_form_factor"`
+	MeasurementMode     string         `json:"measurement_mode"`
+	MeasurementUnit     string         `json:"measurement_unit"`
+	InclusionMin        sql.NullString `json:"inclusion_min"`
+	InclusionPolicy     string         `json:"inclusion_policy"`
+	ExcludeDead         bool           `json:"exclude_dead"`
 	ProjectTitle        string         `json:"project_title"`
 	ProjectCnpj         sql.NullString `json:"project_cnpj"`
 	ProjectActivity     string         `json:"project_activity"`
@@ -233,6 +387,14 @@ type GetPhytoAnalysisWithSpecimensRow struct {
 	Cap6                sql.NullString `json:"cap6"`
 	RegisterDate        sql.NullTime   `json:"register_date"`
 	SpecieID            sql.NullString `json:"specie_id"`
+	Latitude            sql.NullString `json:"latitude"`
+	Longitude           sql.NullString `json:"longitude"`
+	Tag                 sql.NullString `json:"tag"`
+	Status              sql.NullString `json:"status"`
+	Phytosanitary       sql.NullString `json:"phytosanitary"`
+	BifurcationNotes    sql.NullString `json:"bifurcation_notes"`
+	Observations        sql.NullString `json:"observations"`
+	MorphospeciesID     sql.NullString `json:"morphospecies_id"`
 	ScientificName      sql.NullString `json:"scientific_name"`
 	Family              sql.NullString `json:"family"`
 	PopularName         sql.NullString `json:"popular_name"`
@@ -259,6 +421,12 @@ func (q *Queries) GetPhytoAnalysisWithSpecimens(ctx context.Context, id string)
 			&i.ProjectID,
 			&i.PhytoCreatedAt,
 			&i.PhytoUpdatedAt,
+			&i.DefaultFormFactor,
+			&i.MeasurementMode,
+			&i.MeasurementUnit,
+			&i.InclusionMin,
+			&i.InclusionPolicy,
+			&i.ExcludeDead,
 			&i.ProjectTitle,
 			&i.ProjectCnpj,
 			&i.ProjectActivity,
@@ -283,6 +451,14 @@ func (q *Queries) GetPhytoAnalysisWithSpecimens(ctx context.Context, id string)
 			&i.Cap6,
 			&i.RegisterDate,
 			&i.SpecieID,
+			&i.Latitude,
+			&i.Longitude,
+			&i.Tag,
+			&i.Status,
+			&i.Phytosanitary,
+			&i.BifurcationNotes,
+			&i.Observations,
+			&i.MorphospeciesID,
 			&i.ScientificName,
 			&i.Family,
 			&i.PopularName,
@@ -554,20 +730,32 @@ SET
     total_area = $6,
     sampled_area = $7,
     description = $8,
-    updated_at = $9
+    updated_at = $9,
+    default_form_factor = $10,
+    measurement_mode = $11,
+    measurement_unit = $12,
+    inclusion_min = $13,
+    inclusion_policy = $14,
+    exclude_dead = $15
 WHERE id = $1
 `
 
 type UpdatePhytoAnalysisParams struct {
-	ID              string         `json:"id"`
-	Title           string         `json:"title"`
-	InitialDate     time.Time      `json:"initial_date"`
-	PortionQuantity int32          `json:"portion_quantity"`
-	PortionArea     string         `json:"portion_area"`
-	TotalArea       string         `json:"total_area"`
-	SampledArea     string         `json:"sampled_area"`
-	Description     sql.NullString `json:"description"`
-	UpdatedAt       time.Time      `json:"updated_at"`
+	ID                string         `json:"id"`
+	Title             string         `json:"title"`
+	InitialDate       time.Time      `json:"initial_date"`
+	PortionQuantity   int32          `json:"portion_quantity"`
+	PortionArea       string         `json:"portion_area"`
+	TotalArea         string         `json:"total_area"`
+	SampledArea       string         `json:"sampled_area"`
+	Description       sql.NullString `json:"description"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+	DefaultFormFactor sql.NullString `json:"default_form_factor"`
+	MeasurementMode   string         `json:"measurement_mode"`
+	MeasurementUnit   string         `json:"measurement_unit"`
+	InclusionMin      sql.NullString `json:"inclusion_min"`
+	InclusionPolicy   string         `json:"inclusion_policy"`
+	ExcludeDead       bool           `json:"exclude_dead"`
 }
 
 func (q *Queries) UpdatePhytoAnalysis(ctx context.Context, arg UpdatePhytoAnalysisParams) error {
@@ -581,6 +769,60 @@ func (q *Queries) UpdatePhytoAnalysis(ctx context.Context, arg UpdatePhytoAnalys
 		arg.SampledArea,
 		arg.Description,
 		arg.UpdatedAt,
+		arg.DefaultFormFactor,
+		arg.MeasurementMode,
+		arg.MeasurementUnit,
+		arg.InclusionMin,
+		arg.InclusionPolicy,
+		arg.ExcludeDead,
 	)
 	return err
 }
+
+const updatePhytoAnalysisSampling = `-- name: UpdatePhytoAnalysisSampling :exec
+UPDATE public.phyto_analysis
+SET
+    portion_quantity = $2,
+    portion_area = $3,
+    sampled_area = $4,
+    updated_at = $5
+WHERE id = $1
+`
+
+type UpdatePhytoAnalysisSamplingParams struct {
+	ID              string    `json:"id"`
+	PortionQuantity int32     `json:"portion_quantity"`
+	PortionArea     string    `json:"portion_area"`
+	SampledArea     string    `json:"sampled_area"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+func (q *Queries) UpdatePhytoAnalysisSampling(ctx context.Context, arg UpdatePhytoAnalysisSamplingParams) error {
+	_, err := q.db.ExecContext(ctx, updatePhytoAnalysisSampling,
+		arg.ID,
+		arg.PortionQuantity,
+		arg.PortionArea,
+		arg.SampledArea,
+		arg.UpdatedAt,
+	)
+	return err
+}
+
+const updatePhytoAnalysisTotalArea = `-- name: UpdatePhytoAnalysisTotalArea :exec
+UPDATE public.phyto_analysis
+SET
+    total_area = $2,
+    updated_at = $3
+WHERE id = $1
+`
+
+type UpdatePhytoAnalysisTotalAreaParams struct {
+	ID        string    `json:"id"`
+	TotalArea string    `json:"total_area"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (q *Queries) UpdatePhytoAnalysisTotalArea(ctx context.Context, arg UpdatePhytoAnalysisTotalAreaParams) error {
+	_, err := q.db.ExecContext(ctx, updatePhytoAnalysisTotalArea, arg.ID, arg.TotalArea, arg.UpdatedAt)
+	return err
+}
@@ -19,10 +19,11 @@ INSERT INTO public.species (
     popular_name,
     habit,
     created_at,
-    updated_at
+    updated_at,
+    wood_density
 )
-VALUES ($1, $2, $3, $4, $5, $6, $7)
-RETURNING id, scientific_name, family, popular_name, habit, created_at, updated_at
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, scientific_name, family, popular_name, habit, created_at, updated_at, wood_density
 `
 
 type CreateSpeciesParams struct {
@@ -33,6 +34,7 @@ type CreateSpeciesParams struct {
 	Habit          NullSpeciesHabit `json:"habit"`
 	CreatedAt      time.Time        `json:"created_at"`
 	UpdatedAt      time.Time        `json:"updated_at"`
+	WoodDensity    sql.NullString   `json:"wood_density"`
 }
 
 func (q *Queries) CreateSpecies(ctx context.Context, arg CreateSpeciesParams) (Species, error) {
@@ -44,6 +46,7 @@ func (q *Queries) CreateSpecies(ctx context.Context, arg CreateSpeciesParams) (S
 		arg.Habit,
 		arg.CreatedAt,
 		arg.UpdatedAt,
+		arg.WoodDensity,
 	)
 	var i Species
 	err := row.Scan(
@@ -54,6 +57,7 @@ func (q *Queries) CreateSpecies(ctx context.Context, arg CreateSpeciesParams) (S
 		&i.Habit,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.WoodDensity,
 	)
 	return i, err
 }
@@ -143,7 +147,8 @@ SELECT
     s.popular_name,
     s.habit,
     s.created_at,
-    s.updated_at
+    s.updated_at,
+    s.wood_density
 FROM public.species s
 WHERE s.id = $1
 LIMIT 1
@@ -160,6 +165,7 @@ func (q *Queries) GetSpeciesByID(ctx context.Context, id string) (Species, error
 		&i.Habit,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.WoodDensity,
 	)
 	return i, err
 }
@@ -172,7 +178,8 @@ SELECT
     s.popular_name,
     s.habit,
     s.created_at,
-    s.updated_at
+    s.updated_at,
+    s.wood_density
 FROM public.species s
 WHERE s.scientific_name = $1
 LIMIT 1
@@ -189,6 +196,7 @@ func (q *Queries) GetSpeciesByScientificName(ctx context.Context, scientificName
 		&i.Habit,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.WoodDensity,
 	)
 	return i, err
 }
@@ -256,7 +264,8 @@ SELECT
     s.popular_name,
     s.habit,
     s.created_at,
-    s.updated_at
+    s.updated_at,
+    s.wood_density
 FROM public.species s
 ORDER BY s.scientific_name ASC
 LIMIT $1 OFFSET $2
@@ -284,6 +293,7 @@ func (q *Queries) ListSpecies(ctx context.Context, arg ListSpeciesParams) ([]Spe
 			&i.Habit,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.WoodDensity,
 		); err != nil {
 			return nil, err
 		}
@@ -305,7 +315,8 @@ SET
     family = $3,
     popular_name = $4,
     habit = $5,
-    updated_at = $6
+    updated_at = $6,
+    wood_density = $7
 WHERE id = $1
 `
 
@@ -316,6 +327,7 @@ type UpdateSpeciesParams struct {
 	PopularName    sql.NullString   `json:"popular_name"`
 	Habit          NullSpeciesHabit `json:"habit"`
 	UpdatedAt      time.Time        `json:"updated_at"`
+	WoodDensity    sql.NullString   `json:"wood_density"`
 }
 
 func (q *Queries) UpdateSpecies(ctx context.Context, arg UpdateSpeciesParams) error {
@@ -326,6 +338,7 @@ func (q *Queries) UpdateSpecies(ctx context.Context, arg UpdateSpeciesParams) er
 		arg.PopularName,
 		arg.Habit,
 		arg.UpdatedAt,
+		arg.WoodDensity,
 	)
 	return err
 }
@@ -39,27 +39,43 @@ INSERT INTO public.specimen (
     phyto_analysis_id,
     specie_id,
     created_at,
-    updated_at
+    updated_at,
+    latitude,
+    longitude,
+    tag,
+    status,
+    phytosanitary,
+    bifurcation_notes,
+    observations,
+    morphospecies_id
 )
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
-RETURNING id, portion, height, cap1, cap2, cap3, cap4, cap5, cap6, register_date, phyto_analysis_id, specie_id, created_at, updated_at
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22)
+RETURNING id, portion, height, cap1, cap2, cap3, cap4, cap5, cap6, register_date, phyto_analysis_id, specie_id, created_at, updated_at, latitude, longitude, tag, status, phytosanitary, bifurcation_notes, observations, morphospecies_id
 `
 
 type CreateSpecimenParams struct {
-	ID              string         `json:"id"`
-	Portion         string         `json:"portion"`
-	Height          string         `json:"height"`
-	Cap1            string         `json:"cap1"`
-	Cap2            sql.NullString `json:"cap2"`
-	Cap3            sql.NullString `json:"cap3"`
-	Cap4            sql.NullString `json:"cap4"`
-	Cap5            sql.NullString `json:"cap5"`
-	Cap6            sql.NullString `json:"cap6"`
-	RegisterDate    time.Time      `json:"register_date"`
-	PhytoAnalysisID string         `json:"phyto_analysis_id"`
-	SpecieID        string         `json:"specie_id"`
-	CreatedAt       time.Time      `json:"created_at"`
-	UpdatedAt       time.Time      `json:"updated_at"`
+	ID               string         `json:"id"`
+	Portion          string         `json:"portion"`
+	Height           sql.NullString `json:"height"`
+	Cap1             string         `json:"cap1"`
+	Cap2             sql.NullString `json:"cap2"`
+	Cap3             sql.NullString `json:"cap3"`
+	Cap4             sql.NullString `json:"cap4"`
+	Cap5             sql.NullString `json:"cap5"`
+	Cap6             sql.NullString `json:"cap6"`
+	RegisterDate     time.Time      `json:"register_date"`
+	PhytoAnalysisID  string         `json:"phyto_analysis_id"`
+	SpecieID         sql.NullString `json:"specie_id"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	Latitude         sql.NullString `json:"latitude"`
+	Longitude        sql.NullString `json:"longitude"`
+	Tag              sql.NullString `json:"tag"`
+	Status           string         `json:"status"`
+	Phytosanitary    sql.NullString `json:"phytosanitary"`
+	BifurcationNotes sql.NullString `json:"bifurcation_notes"`
+	Observations     sql.NullString `json:"observations"`
+	MorphospeciesID  sql.NullString `json:"morphospecies_id"`
 }
 
 func (q *Queries) CreateSpecimen(ctx context.Context, arg CreateSpecimenParams) (Speciman, error) {
@@ -78,6 +94,14 @@ func (q *Queries) CreateSpecimen(ctx context.Context, arg CreateSpecimenParams)
 		arg.SpecieID,
 		arg.CreatedAt,
 		arg.UpdatedAt,
+		arg.Latitude,
+		arg.Longitude,
+		arg.Tag,
+		arg.Status,
+		arg.Phytosanitary,
+		arg.BifurcationNotes,
+		arg.Observations,
+		arg.MorphospeciesID,
 	)
 	var i Speciman
 	err := row.Scan(
@@ -95,6 +119,14 @@ func (q *Queries) CreateSpecimen(ctx context.Context, arg CreateSpecimenParams)
 		&i.SpecieID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Latitude,
+		&i.Longitude,
+		&i.Tag,
+		&i.Status,
+		&i.Phytosanitary,
+		&i.BifurcationNotes,
+		&i.Observations,
+		&i.MorphospeciesID,
 	)
 	return i, err
 }
@@ -125,33 +157,50 @@ SELECT
     sp.specie_id,
     sp.created_at,
     sp.updated_at,
-    s.scientific_name,
-    s.family,
+    sp.latitude,
+    sp.longitude,
+    sp.tag,
+    sp.status,
+    sp.phytosanitary,
+    sp.bifurcation_notes,
+    sp.observations,
+    sp.morphospecies_id,
+    COALESCE(s.scientific_name, m.name)::text AS scientific_name,
+    COALESCE(s.family, m.family, '')::text AS family,
     s.popular_name
 FROM public.specimen sp
-INNER JOIN public.species s ON sp.specie_id = s.id
+LEFT JOIN public.species s ON sp.specie_id = s.id
+LEFT JOIN public.morphospecies m ON sp.morphospecies_id = m.id
 WHERE sp.id = $1
 LIMIT 1
 `
 
 type GetSpecimenByIDRow struct {
-	ID              string         `json:"id"`
-	Portion         string         `json:"portion"`
-	Height          string         `json:"height"`
-	Cap1            string         `json:"cap1"`
-	Cap2            sql.NullString `json:"cap2"`
-	Cap3            sql.NullString `json:"cap3"`
-	Cap4            sql.NullString `json:"cap4"`
-	Cap5            sql.NullString `json:"cap5"`
-	Cap6            sql.NullString `json:"cap6"`
-	RegisterDate    time.Time      `json:"register_date"`
-	PhytoAnalysisID string         `json:"phyto_analysis_id"`
-	SpecieID        string         `json:"specie_id"`
-	CreatedAt       time.Time      `json:"created_at"`
-	UpdatedAt       time.Time      `json:"updated_at"`
-	ScientificName  string         `json:"scientific_name"`
-	Family          string         `json:"family"`
-	PopularName     sql.NullString `json:"popular_name"`
+	ID               string         `json:"id"`
+	Portion          string         `json:"portion"`
+	Height           sql.NullString `json:"height"`
+	Cap1             string         `json:"cap1"`
+	Cap2             sql.NullString `json:"cap2"`
+	Cap3             sql.NullString `json:"cap3"`
+	Cap4             sql.NullString `json:"cap4"`
+	Cap5             sql.NullString `json:"cap5"`
+	Cap6             sql.NullString `json:"cap6"`
+	RegisterDate     time.Time      `json:"register_date"`
+	PhytoAnalysisID  string         `json:"phyto_analysis_id"`
+	SpecieID         sql.NullString `json:"specie_id"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	Latitude         sql.NullString `json:"latitude"`
+	Longitude        sql.NullString `json:"longitude"`
+	Tag              sql.NullString `json:"tag"`
+	Status           string         `json:"status"`
+	Phytosanitary    sql.NullString `json:"phytosanitary"`
+	BifurcationNotes sql.NullString `json:"bifurcation_notes"`
+	Observations     sql.NullString `json:"observations"`
+	MorphospeciesID  sql.NullString `json:"morphospecies_id"`
+	ScientificName   string         `json:"scientific_name"`
+	Family           string         `json:"family"`
+	PopularName      sql.NullString `json:"popular_name"`
 }
 
 func (q *Queries) GetSpecimenByID(ctx context.Context, id string) (GetSpecimenByIDRow, error) {
@@ -172,6 +221,14 @@ func (q *Queries) GetSpecimenByID(ctx context.Context, id string) (GetSpecimenBy
 		&i.SpecieID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Latitude,
+		&i.Longitude,
+		&i.Tag,
+		&i.Status,
+		&i.Phytosanitary,
+		&i.BifurcationNotes,
+		&i.Observations,
+		&i.MorphospeciesID,
 		&i.ScientificName,
 		&i.Family,
 		&i.PopularName,
@@ -179,6 +236,36 @@ func (q *Queries) GetSpecimenByID(ctx context.Context, id string) (GetSpecimenBy
 	return i, err
 }
 
+const listSpecimenTagsByPhytoAnalysis = `-- name: ListSpecimenTagsByPhytoAnalysis :many
+SELECT tag::text
+FROM public.specimen
+WHERE phyto_analysis_id = $1 AND tag IS NOT NULL
+ORDER BY tag ASC
+`
+
+func (q *Queries) ListSpecimenTagsByPhytoAnalysis(ctx context.Context, phytoAnalysisID string) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, listSpecimenTagsByPhytoAnalysis, phytoAnalysisID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, err
+		}
+		items = append(items, tag)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listSpecimensByPhytoAnalysis = `-- name: ListSpecimensByPhytoAnalysis :many
 SELECT 
     sp.id,
@@ -195,33 +282,50 @@ SELECT
     sp.specie_id,
     sp.created_at,
     sp.updated_at,
-    s.scientific_name,
-    s.family,
+    sp.latitude,
+    sp.longitude,
+    sp.tag,
+    sp.status,
+    sp.phytosanitary,
+    sp.bifurcation_notes,
+    sp.observations,
+    sp.morphospecies_id,
+    COALESCE(s.scientific_name, m.name)::text AS scientific_name,
+    COALESCE(s.family, m.family, '')::text AS family,
     s.popular_name
 FROM public.specimen sp
-INNER JOIN public.species s ON sp.specie_id = s.id
+LEFT JOIN public.species s ON sp.specie_id = s.id
+LEFT JOIN public.morphospecies m ON sp.morphospecies_id = m.id
 WHERE sp.phyto_analysis_id = $1
 ORDER BY sp.portion ASC, sp.created_at ASC
 `
 
 type ListSpecimensByPhytoAnalysisRow struct {
-	ID              string         `json:"id"`
-	Portion         string         `json:"portion"`
-	Height          string         `json:"height"`
-	Cap1            string         `json:"cap1"`
-	Cap2            sql.NullString `json:"cap2"`
-	Cap3            sql.NullString `json:"cap3"`
-	Cap4            sql.NullString `json:"cap4"`
-	Cap5            sql.NullString `json:"cap5"`
-	Cap6            sql.NullString `json:"cap6"`
-	RegisterDate    time.Time      `json:"register_date"`
-	PhytoAnalysisID string         `json:"phyto_analysis_id"`
-	SpecieID        string         `json:"specie_id"`
-	CreatedAt       time.Time      `json:"created_at"`
-	UpdatedAt       time.Time      `json:"updated_at"`
-	ScientificName  string         `json:"scientific_name"`
-	Family          string         `json:"family"`
-	PopularName     sql.NullString `json:"popular_name"`
+	ID               string         `json:"id"`
+	Portion          string         `json:"portion"`
+	Height           sql.NullString `json:"height"`
+	Cap1             string         `json:"cap1"`
+	Cap2             sql.NullString `json:"cap2"`
+	Cap3             sql.NullString `json:"cap3"`
+	Cap4             sql.NullString `json:"cap4"`
+	Cap5             sql.NullString `json:"cap5"`
+	Cap6             sql.NullString `json:"cap6"`
+	RegisterDate     time.Time      `json:"register_date"`
+	PhytoAnalysisID  string         `json:"phyto_analysis_id"`
+	SpecieID         sql.NullString `json:"specie_id"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	Latitude         sql.NullString `json:"latitude"`
+	Longitude        sql.NullString `json:"longitude"`
+	Tag              sql.NullString `json:"tag"`
+	Status           string         `json:"status"`
+	Phytosanitary    sql.NullString `json:"phytosanitary"`
+	BifurcationNotes sql.NullString `json:"bifurcation_notes"`
+	Observations     sql.NullString `json:"observations"`
+	MorphospeciesID  sql.NullString `json:"morphospecies_id"`
+	ScientificName   string         `json:"scientific_name"`
+	Family           string         `json:"family"`
+	PopularName      sql.NullString `json:"popular_name"`
 }
 
 func (q *Queries) ListSpecimensByPhytoAnalysis(ctx context.Context, phytoAnalysisID string) ([]ListSpecimensByPhytoAnalysisRow, error) {
@@ -248,6 +352,14 @@ func (q *Queries) ListSpecimensByPhytoAnalysis(ctx context.Context, phytoAnalysi
 			&i.SpecieID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Latitude,
+			&i.Longitude,
+			&i.Tag,
+			&i.Status,
+			&i.Phytosanitary,
+			&i.BifurcationNotes,
+			&i.Observations,
+			&i.MorphospeciesID,
 			&i.ScientificName,
 			&i.Family,
 			&i.PopularName,
@@ -278,23 +390,39 @@ SET
     cap6 = $9,
     register_date = $10,
     specie_id = $11,
-    updated_at = $12
+    updated_at = $12,
+    latitude = $13,
+    longitude = $14,
+    tag = $15,
+    status = $16,
+    phytosanitary = $17,
+    bifurcation_notes = $18,
+    observations = $19,
+    morphospecies_id = $20
 WHERE id = $1
 `
 
 type UpdateSpecimenParams struct {
-	ID           string         `json:"id"`
-	Portion      string         `json:"portion"`
-	Height       string         `json:"height"`
-	Cap1         string         `json:"cap1"`
-	Cap2         sql.NullString `json:"cap2"`
-	Cap3         sql.NullString `json:"cap3"`
-	Cap4         sql.NullString `json:"cap4"`
-	Cap5         sql.NullString `json:"cap5"`
-	Cap6         sql.NullString `json:"cap6"`
-	RegisterDate time.Time      `json:"register_date"`
-	SpecieID     string         `json:"specie_id"`
-	UpdatedAt    time.Time      `json:"updated_at"`
+	ID               string         `json:"id"`
+	Portion          string         `json:"portion"`
+	Height           sql.NullString `json:"height"`
+	Cap1             string         `json:"cap1"`
+	Cap2             sql.NullString `json:"cap2"`
+	Cap3             sql.NullString `json:"cap3"`
+	Cap4             sql.NullString `json:"cap4"`
+	Cap5             sql.NullString `json:"cap5"`
+	Cap6             sql.NullString `json:"cap6"`
+	RegisterDate     time.Time      `json:"register_date"`
+	SpecieID         sql.NullString `json:"specie_id"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	Latitude         sql.NullString `json:"latitude"`
+	Longitude        sql.NullString `json:"longitude"`
+	Tag              sql.NullString `json:"tag"`
+	Status           string         `json:"status"`
+	Phytosanitary    sql.NullString `json:"phytosanitary"`
+	BifurcationNotes sql.NullString `json:"bifurcation_notes"`
+	Observations     sql.NullString `json:"observations"`
+	MorphospeciesID  sql.NullString `json:"morphospecies_id"`
 }
 
 func (q *Queries) UpdateSpecimen(ctx context.Context, arg UpdateSpecimenParams) error {
@@ -311,6 +439,14 @@ func (q *Queries) UpdateSpecimen(ctx context.Context, arg UpdateSpecimenParams)
 		arg.RegisterDate,
 		arg.SpecieID,
 		arg.UpdatedAt,
+		arg.Latitude,
+		arg.Longitude,
+		arg.Tag,
+		arg.Status,
+		arg.Phytosanitary,
+		arg.BifurcationNotes,
+		arg.Observations,
+		arg.MorphospeciesID,
 	)
 	return err
 }
@@ -0,0 +1,29 @@
+-- name: CreateEquation :exec
+INSERT INTO equation (
+  id, name, kind, expression, coefficients, applicability, reference, created_at, updated_at
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);
+
+-- name: GetEquationByID :one
+SELECT id, name, kind, expression, coefficients, applicability, reference, created_at, updated_at
+FROM equation
+WHERE id = $1;
+
+-- name: ListEquations :many
+SELECT id, name, kind, expression, coefficients, applicability, reference, created_at, updated_at
+FROM equation
+ORDER BY kind ASC, name ASC;
+
+-- name: UpdateEquation :exec
+UPDATE equation
+SET
+  name = $2,
+  expression = $3,
+  coefficients = $4,
+  applicability = $5,
+  reference = $6,
+  updated_at = $7
+WHERE id = $1;
+
+-- name: DeleteEquation :exec
+DELETE FROM equation
+WHERE id = $1;
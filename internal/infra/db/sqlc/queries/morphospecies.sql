@@ -0,0 +1,43 @@
+-- name: CreateMorphospecies :exec
+INSERT INTO morphospecies (
+  id, phyto_analysis_id, name, family, genus, created_at, updated_at
+) VALUES ($1, $2, $3, $4, $5, $6, $7);
+
+-- name: GetMorphospeciesByID :one
+SELECT id, phyto_analysis_id, name, family, genus, created_at, updated_at
+FROM morphospecies
+WHERE id = $1 AND phyto_analysis_id = $2
+LIMIT 1;
+
+-- name: ListMorphospeciesByPhytoAnalysis :many
+SELECT m.id, m.phyto_analysis_id, m.name, m.family, m.genus, m.created_at, m.updated_at,
+       COUNT(sp.id) AS specimen_count
+FROM morphospecies m
+LEFT JOIN specimen sp ON sp.morphospecies_id = m.id
+WHERE m.phyto_analysis_id = $1
+GROUP BY m.id
+ORDER BY m.name ASC;
+
+-- name: UpdateMorphospecies :exec
+UPDATE morphospecies
+SET name = $3,
+    family = $4,
+    genus = $5,
+    updated_at = $6
+WHERE id = $1 AND phyto_analysis_id = $2;
+
+-- name: DeleteMorphospecies :exec
+DELETE FROM morphospecies
+WHERE id = $1 AND phyto_analysis_id = $2;
+
+-- name: CountSpecimensByMorphospecies :one
+SELECT COUNT(*) AS total
+FROM specimen
+WHERE morphospecies_id = $1;
+
+-- name: ResolveMorphospeciesSpecimens :execrows
+UPDATE specimen
+SET specie_id = $2,
+    morphospecies_id = NULL,
+    updated_at = $3
+WHERE morphospecies_id = $1;
@@ -10,9 +10,15 @@ INSERT INTO public.phyto_analysis (
     description,
     project_id,
     created_at,
-    updated_at
+    updated_at,
+    default_form_factor,
+    measurement_mode,
+    measurement_unit,
+    inclusion_min,
+    inclusion_policy,
+    exclude_dead
 )
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
 RETURNING *;
 
 -- name: GetPhytoAnalysisByID :one
@@ -114,13 +120,56 @@ SET
     total_area = $6,
     sampled_area = $7,
     description = $8,
-    updated_at = $9
+    updated_at = $9,
+    default_form_factor = $10,
+    measurement_mode = $11,
+    measurement_unit = $12,
+    inclusion_min = $13,
+    inclusion_policy = $14,
+    exclude_dead = $15
 WHERE id = $1;
 
 -- name: DeletePhytoAnalysis :exec
 DELETE FROM public.phyto_analysis
 WHERE id = $1;
 
+-- name: GetPhytoAnalysisComplete :one
+SELECT 
+    pa.id AS phyto_id,
+    pa.title AS phyto_title,
+    pa.initial_date,
+    pa.portion_quantity,
+    pa.portion_area,
+    pa.total_area,
+    pa.sampled_area,
+    pa.description AS phyto_description,
+    pa.project_id,
+    pa.created_at AS phyto_created_at,
+    pa.updated_at AS phyto_updated_at,
+    pa.default_form_factor,
+    pa.measurement_mode,
+    pa.measurement_unit,
+    pa.inclusion_min,
+    pa.inclusion_policy,
+    pa.exclude_dead,
+    p.title AS project_title,
+    p.cnpj AS project_cnpj,
+    p.activity AS project_activity,
+    p."clientId" AS project_client_id,
+    a."zipCode" AS project_zip_code,
+    a.state AS project_state,
+    a.city AS project_city,
+    a.neighborhood AS project_neighborhood,
+    a.street AS project_street,
+    a.num AS project_num,
+    a.latitude AS project_latitude,
+    a.longitude AS project_longitude,
+    a."addInfo" AS project_add_info
+FROM public.phyto_analysis pa
+INNER JOIN public."Project" p ON pa.project_id = p.id
+LEFT JOIN public."Address" a ON p."addressId" = a.id
+WHERE pa.id = $1;
+
 -- name: GetPhytoAnalysisWithSpecimens :many
 SELECT 
     pa.id AS phyto_id,
@@ -134,6 +183,12 @@ SELECT
     pa.project_id,
     pa.created_at AS phyto_created_at,
     pa.updated_at AS phyto_updated_at,
+    pa.default_form_factor,
+    pa.measurement_mode,
+    pa.measurement_unit,
+    pa.inclusion_min,
+    pa.inclusion_policy,
+    pa.exclude_dead,
     p.title AS project_title,
     p.cnpj AS project_cnpj,
     p.activity AS project_activity,
@@ -158,14 +213,39 @@ SELECT
     sp.cap6,
     sp.register_date,
     sp.specie_id,
-    s.scientific_name,
-    s.family,
+    sp.latitude,
+    sp.longitude,
+    sp.tag,
+    sp.status,
+    sp.phytosanitary,
+    sp.bifurcation_notes,
+    sp.observations,
+    sp.morphospecies_id,
+    COALESCE(s.scientific_name, m.name) AS scientific_name,
+    COALESCE(s.family, m.family) AS family,
     s.popular_name
 FROM public.phyto_analysis pa
 INNER JOIN public."Project" p ON pa.project_id = p.id
 LEFT JOIN public."Address" a ON p."addressId" = a.id
 LEFT JOIN public.specimen sp ON sp.phyto_analysis_id = pa.id
 LEFT JOIN public.species s ON sp.specie_id = s.id
+LEFT JOIN public.morphospecies m ON sp.morphospecies_id = m.id
 WHERE pa.id = $1
 ORDER BY sp.portion ASC, sp.created_at ASC;
 
+
+-- name: UpdatePhytoAnalysisSampling :exec
+UPDATE public.phyto_analysis
+SET
+    portion_quantity = $2,
+    portion_area = $3,
+    sampled_area = $4,
+    updated_at = $5
+WHERE id = $1;
+
+-- name: UpdatePhytoAnalysisTotalArea :exec
+UPDATE public.phyto_analysis
+SET
+    total_area = $2,
+    updated_at = $3
+WHERE id = $1;
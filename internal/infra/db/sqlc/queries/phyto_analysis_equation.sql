@@ -0,0 +1,18 @@
+-- name: UpsertPhytoAnalysisEquation :exec
+INSERT INTO phyto_analysis_equation (
+  phyto_analysis_id, kind, equation_id, definition, selected_at
+) VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (phyto_analysis_id, kind) DO UPDATE
+SET equation_id = EXCLUDED.equation_id,
+    definition = EXCLUDED.definition,
+    selected_at = EXCLUDED.selected_at;
+
+-- name: ListPhytoAnalysisEquations :many
+SELECT phyto_analysis_id, kind, equation_id, definition, selected_at
+FROM phyto_analysis_equation
+WHERE phyto_analysis_id = $1
+ORDER BY kind ASC;
+
+-- name: DeletePhytoAnalysisEquation :exec
+DELETE FROM phyto_analysis_equation
+WHERE phyto_analysis_id = $1 AND kind = $2;
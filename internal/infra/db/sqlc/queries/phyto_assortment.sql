@@ -0,0 +1,18 @@
+-- name: UpsertPhytoAssortment :exec
+INSERT INTO phyto_assortment (
+  phyto_analysis_id, log_min_dbh_cm, stacking_factor, dead_as_firewood, updated_at
+) VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (phyto_analysis_id) DO UPDATE
+SET log_min_dbh_cm = EXCLUDED.log_min_dbh_cm,
+    stacking_factor = EXCLUDED.stacking_factor,
+    dead_as_firewood = EXCLUDED.dead_as_firewood,
+    updated_at = EXCLUDED.updated_at;
+
+-- name: GetPhytoAssortment :one
+SELECT phyto_analysis_id, log_min_dbh_cm, stacking_factor, dead_as_firewood, updated_at
+FROM phyto_assortment
+WHERE phyto_analysis_id = $1;
+
+-- name: DeletePhytoAssortment :exec
+DELETE FROM phyto_assortment
+WHERE phyto_analysis_id = $1;
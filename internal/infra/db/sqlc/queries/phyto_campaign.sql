@@ -0,0 +1,14 @@
+-- name: CreatePhytoCampaign :exec
+INSERT INTO phyto_campaign (
+  phyto_analysis_id, previous_analysis_id, created_at
+) VALUES ($1, $2, $3);
+
+-- name: GetPhytoCampaign :one
+SELECT phyto_analysis_id, previous_analysis_id, created_at
+FROM phyto_campaign
+WHERE phyto_analysis_id = $1;
+
+-- name: GetPhytoCampaignByPrevious :one
+SELECT phyto_analysis_id, previous_analysis_id, created_at
+FROM phyto_campaign
+WHERE previous_analysis_id = $1;
@@ -0,0 +1,31 @@
+-- name: CreatePhytoIndicatorSnapshot :exec
+INSERT INTO phyto_indicator_snapshot (
+  id, phyto_analysis_id, version, engine_version, reason, specimens_count, payload, created_at
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+
+-- name: GetLatestPhytoIndicatorSnapshot :one
+SELECT id, phyto_analysis_id, version, engine_version, reason, specimens_count, payload, created_at
+FROM phyto_indicator_snapshot
+WHERE phyto_analysis_id = $1
+ORDER BY version DESC
+LIMIT 1;
+
+-- name: GetPhytoIndicatorSnapshotByVersion :one
+SELECT id, phyto_analysis_id, version, engine_version, reason, specimens_count, payload, created_at
+FROM phyto_indicator_snapshot
+WHERE phyto_analysis_id = $1 AND version = $2;
+
+-- name: ListPhytoIndicatorSnapshots :many
+SELECT id, phyto_analysis_id, version, engine_version, reason, specimens_count, created_at
+FROM phyto_indicator_snapshot
+WHERE phyto_analysis_id = $1
+ORDER BY version DESC;
+
+-- name: DeletePhytoIndicatorSnapshotsByAnalysis :exec
+DELETE FROM phyto_indicator_snapshot
+WHERE phyto_analysis_id = $1;
+
+-- name: LockPhytoAnalysisForSnapshot :one
+SELECT id FROM public.phyto_analysis
+WHERE id = $1
+FOR UPDATE;
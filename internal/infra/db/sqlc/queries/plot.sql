@@ -0,0 +1,49 @@
+-- name: CreatePlot :exec
+INSERT INTO plot (
+  id, phyto_analysis_id, code, area, shape, width, length, radius,
+  center_latitude, center_longitude, vertices, vegetation_notes, created_at, updated_at, stratum_id
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15);
+
+-- name: GetPlotByID :one
+SELECT id, phyto_analysis_id, code, area, shape, width, length, radius,
+  center_latitude, center_longitude, vertices, vegetation_notes, created_at, updated_at, stratum_id
+FROM plot
+WHERE id = $1 AND phyto_analysis_id = $2
+LIMIT 1;
+
+-- name: ListPlotsByPhytoAnalysis :many
+SELECT id, phyto_analysis_id, code, area, shape, width, length, radius,
+  center_latitude, center_longitude, vertices, vegetation_notes, created_at, updated_at, stratum_id
+FROM plot
+WHERE phyto_analysis_id = $1
+ORDER BY code ASC;
+
+-- name: UpdatePlot :exec
+UPDATE plot
+SET code = $3,
+    area = $4,
+    shape = $5,
+    width = $6,
+    length = $7,
+    radius = $8,
+    center_latitude = $9,
+    center_longitude = $10,
+    vertices = $11,
+    vegetation_notes = $12,
+    updated_at = $13,
+    stratum_id = $14
+WHERE id = $1 AND phyto_analysis_id = $2;
+
+-- name: DeletePlot :exec
+DELETE FROM plot
+WHERE id = $1 AND phyto_analysis_id = $2;
+
+-- name: CountSpecimensByPortion :one
+SELECT COUNT(*) AS total
+FROM specimen
+WHERE phyto_analysis_id = $1 AND portion = $2;
+
+-- name: RenameSpecimensPortion :exec
+UPDATE specimen
+SET portion = $3
+WHERE phyto_analysis_id = $1 AND portion = $2;
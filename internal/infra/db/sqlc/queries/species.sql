@@ -6,9 +6,10 @@ INSERT INTO public.species (
     popular_name,
     habit,
     created_at,
-    updated_at
+    updated_at,
+    wood_density
 )
-VALUES ($1, $2, $3, $4, $5, $6, $7)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 RETURNING *;
 
 -- name: CreateSpeciesLegislation :one
@@ -37,7 +38,8 @@ SELECT
     s.popular_name,
     s.habit,
     s.created_at,
-    s.updated_at
+    s.updated_at,
+    s.wood_density
 FROM public.species s
 WHERE s.id = $1
 LIMIT 1;
@@ -50,7 +52,8 @@ SELECT
     s.popular_name,
     s.habit,
     s.created_at,
-    s.updated_at
+    s.updated_at,
+    s.wood_density
 FROM public.species s
 WHERE s.scientific_name = $1
 LIMIT 1;
@@ -63,7 +66,8 @@ SELECT
     s.popular_name,
     s.habit,
     s.created_at,
-    s.updated_at
+    s.updated_at,
+    s.wood_density
 FROM public.species s
 ORDER BY s.scientific_name ASC
 LIMIT $1 OFFSET $2;
@@ -93,7 +97,8 @@ SET
     family = $3,
     popular_name = $4,
     habit = $5,
-    updated_at = $6
+    updated_at = $6,
+    wood_density = $7
 WHERE id = $1;
 
 -- name: UpdateSpeciesLegislation :exec
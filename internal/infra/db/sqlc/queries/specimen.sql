@@ -13,10 +13,18 @@ INSERT INTO public.specimen (
     phyto_analysis_id,
     specie_id,
     created_at,
-    updated_at
+    updated_at,
+    latitude,
+    longitude,
+    tag,
+    status,
+    phytosanitary,
+    bifurcation_notes,
+    observations,
+    morphospecies_id
 )
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
-RETURNING id, portion, height, cap1, cap2, cap3, cap4, cap5, cap6, register_date, phyto_analysis_id, specie_id, created_at, updated_at;
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22)
+RETURNING id, portion, height, cap1, cap2, cap3, cap4, cap5, cap6, register_date, phyto_analysis_id, specie_id, created_at, updated_at, latitude, longitude, tag, status, phytosanitary, bifurcation_notes, observations, morphospecies_id;
 
 -- name: GetSpecimenByID :one
 SELECT 
@@ -34,11 +42,20 @@ SELECT
     sp.specie_id,
     sp.created_at,
     sp.updated_at,
-    s.scientific_name,
-    s.family,
+    sp.latitude,
+    sp.longitude,
+    sp.tag,
+    sp.status,
+    sp.phytosanitary,
+    sp.bifurcation_notes,
+    sp.observations,
+    sp.morphospecies_id,
+    COALESCE(s.scientific_name, m.name)::text AS scientific_name,
+    COALESCE(s.family, m.family, '')::text AS family,
     s.popular_name
 FROM public.specimen sp
-INNER JOIN public.species s ON sp.specie_id = s.id
+LEFT JOIN public.species s ON sp.specie_id = s.id
+LEFT JOIN public.morphospecies m ON sp.morphospecies_id = m.id
 WHERE sp.id = $1
 LIMIT 1;
 
@@ -58,11 +75,20 @@ SELECT
     sp.specie_id,
     sp.created_at,
     sp.updated_at,
-    s.scientific_name,
-    s.family,
+    sp.latitude,
+    sp.longitude,
+    sp.tag,
+    sp.status,
+    sp.phytosanitary,
+    sp.bifurcation_notes,
+    sp.observations,
+    sp.morphospecies_id,
+    COALESCE(s.scientific_name, m.name)::text AS scientific_name,
+    COALESCE(s.family, m.family, '')::text AS family,
     s.popular_name
 FROM public.specimen sp
-INNER JOIN public.species s ON sp.specie_id = s.id
+LEFT JOIN public.species s ON sp.specie_id = s.id
+LEFT JOIN public.morphospecies m ON sp.morphospecies_id = m.id
 WHERE sp.phyto_analysis_id = $1
 ORDER BY sp.portion ASC, sp.created_at ASC;
 
@@ -79,7 +105,15 @@ SET
     cap6 = $9,
     register_date = $10,
     specie_id = $11,
-    updated_at = $12
+    updated_at = $12,
+    latitude = $13,
+    longitude = $14,
+    tag = $15,
+    status = $16,
+    phytosanitary = $17,
+    bifurcation_notes = $18,
+    observations = $19,
+    morphospecies_id = $20
 WHERE id = $1;
 
 -- name: DeleteSpecimen :exec
@@ -91,3 +125,9 @@ SELECT COUNT(*) as total
 FROM public.specimen
 WHERE phyto_analysis_id = $1;
 
+
+-- name: ListSpecimenTagsByPhytoAnalysis :many
+SELECT tag::text
+FROM public.specimen
+WHERE phyto_analysis_id = $1 AND tag IS NOT NULL
+ORDER BY tag ASC;
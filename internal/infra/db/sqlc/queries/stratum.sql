@@ -0,0 +1,34 @@
+-- name: CreateStratum :exec
+INSERT INTO stratum (
+  id, phyto_analysis_id, code, name, area, description, created_at, updated_at
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+
+-- name: GetStratumByID :one
+SELECT id, phyto_analysis_id, code, name, area, description, created_at, updated_at
+FROM stratum
+WHERE id = $1 AND phyto_analysis_id = $2
+LIMIT 1;
+
+-- name: ListStrataByPhytoAnalysis :many
+SELECT id, phyto_analysis_id, code, name, area, description, created_at, updated_at
+FROM stratum
+WHERE phyto_analysis_id = $1
+ORDER BY code ASC;
+
+-- name: UpdateStratum :exec
+UPDATE stratum
+SET code = $3,
+    name = $4,
+    area = $5,
+    description = $6,
+    updated_at = $7
+WHERE id = $1 AND phyto_analysis_id = $2;
+
+-- name: DeleteStratum :exec
+DELETE FROM stratum
+WHERE id = $1 AND phyto_analysis_id = $2;
+
+-- name: CountPlotsByStratum :one
+SELECT COUNT(*) AS total
+FROM plot
+WHERE stratum_id = $1;
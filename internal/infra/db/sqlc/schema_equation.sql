@@ -0,0 +1,27 @@
+-- Apenas para o sqlc entender tipos (não roda no banco).
+-- Cadastro de equações volumétricas e alométricas (as embutidas ficam em internal/app/phytometrics)
+CREATE TABLE equation (
+  id varchar(36) PRIMARY KEY,
+  name varchar(255) NOT NULL,
+  kind varchar(20) NOT NULL,
+  expression text NOT NULL,
+  coefficients jsonb NOT NULL,
+  applicability jsonb NOT NULL,
+  reference text,
+  created_at timestamp NOT NULL DEFAULT now(),
+  updated_at timestamp NOT NULL
+);
+
+CREATE INDEX idx_equation_kind ON equation (kind);
+
+-- Equação selecionada por análise e tipo; a definição é copiada na seleção para que
+-- alterações no cadastro não mudem análises já calculadas
+CREATE TABLE phyto_analysis_equation (
+  phyto_analysis_id varchar(36) NOT NULL,
+  kind varchar(20) NOT NULL,
+  equation_id varchar(36) NOT NULL,
+  definition jsonb NOT NULL,
+  selected_at timestamp NOT NULL DEFAULT now(),
+  PRIMARY KEY (phyto_analysis_id, kind),
+  FOREIGN KEY (phyto_analysis_id) REFERENCES phyto_analysis (id) ON DELETE CASCADE
+);
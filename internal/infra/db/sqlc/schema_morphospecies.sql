@@ -0,0 +1,17 @@
+-- Apenas para o sqlc entender tipos (não roda no banco).
+-- Morfoespécies da análise: indivíduos ainda sem determinação botânica ("Indet. 1", "Myrcia sp.").
+-- Resolvidas para uma espécie do cadastro, seus espécimes passam a apontar para specimen.specie_id
+CREATE TABLE morphospecies (
+  id varchar(36) PRIMARY KEY,
+  phyto_analysis_id varchar(36) NOT NULL,
+  name varchar(255) NOT NULL,
+  family varchar(255),
+  genus varchar(255),
+  created_at timestamp NOT NULL DEFAULT now(),
+  updated_at timestamp NOT NULL,
+  FOREIGN KEY (phyto_analysis_id) REFERENCES phyto_analysis (id) ON DELETE CASCADE,
+  UNIQUE (phyto_analysis_id, name),
+  UNIQUE (id, phyto_analysis_id)
+);
+
+CREATE INDEX idx_morphospecies_phyto_analysis_id ON morphospecies (phyto_analysis_id);
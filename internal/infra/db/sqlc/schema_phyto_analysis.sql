@@ -11,6 +11,18 @@ CREATE TABLE phyto_analysis (
   project_id varchar(36) NOT NULL,
   created_at timestamp NOT NULL DEFAULT now(),
   updated_at timestamp NOT NULL,
+  -- Fator de forma padrão para espécies sem legislação aplicável (NULL = cilíndrico, 1.0)
+  default_form_factor numeric,
+  -- Protocolo de medição: fustes medidos em CAP ou DAP ('cap' | 'dap'), na unidade 'cm' ou 'mm'.
+  -- Os espécimes são sempre gravados como CAP em cm (convertidos na importação).
+  measurement_mode varchar(3) NOT NULL DEFAULT 'cap',
+  measurement_unit varchar(2) NOT NULL DEFAULT 'cm',
+  -- Critério de inclusão por fuste, no modo e unidade do protocolo (NULL = sem critério)
+  inclusion_min numeric,
+  -- Fustes abaixo do critério: 'reject' (linha inválida) ou 'flag' (aviso no relatório)
+  inclusion_policy varchar(10) NOT NULL DEFAULT 'reject',
+  -- Indivíduos mortos (em pé ou caídos) fora dos indicadores
+  exclude_dead boolean NOT NULL DEFAULT false,
   FOREIGN KEY (project_id) REFERENCES "Project" (id)
 );
 
@@ -0,0 +1,16 @@
+-- Apenas para o sqlc entender tipos (não roda no banco).
+-- Regras de classificação da madeira suprimida em produtos (tora e lenha), uma por análise.
+-- Sem registro, a análise usa as regras padrão do motor de métricas.
+CREATE TABLE phyto_assortment (
+  phyto_analysis_id varchar(36) PRIMARY KEY,
+  -- DAP mínimo (cm) para tora; abaixo dele o indivíduo vira lenha
+  log_min_dbh_cm numeric NOT NULL,
+  -- Fator de empilhamento: mst = m³ / fator
+  stacking_factor numeric NOT NULL,
+  -- Indivíduos mortos vão para lenha independentemente do DAP
+  dead_as_firewood boolean NOT NULL DEFAULT false,
+  updated_at timestamp NOT NULL DEFAULT now(),
+  FOREIGN KEY (phyto_analysis_id) REFERENCES phyto_analysis (id) ON DELETE CASCADE,
+  CHECK (log_min_dbh_cm > 0),
+  CHECK (stacking_factor > 0 AND stacking_factor <= 1)
+);
@@ -0,0 +1,11 @@
+-- Apenas para o sqlc entender tipos (não roda no banco).
+-- Campanhas de remedição de parcelas permanentes: cada análise remede as parcelas da campanha
+-- anterior; as árvores são identificadas entre campanhas pela plaqueta (specimen.tag)
+CREATE TABLE phyto_campaign (
+  phyto_analysis_id varchar(36) PRIMARY KEY,
+  previous_analysis_id varchar(36) NOT NULL,
+  created_at timestamp NOT NULL DEFAULT now(),
+  FOREIGN KEY (phyto_analysis_id) REFERENCES phyto_analysis (id) ON DELETE CASCADE,
+  FOREIGN KEY (previous_analysis_id) REFERENCES phyto_analysis (id) ON DELETE CASCADE,
+  UNIQUE (previous_analysis_id)
+);
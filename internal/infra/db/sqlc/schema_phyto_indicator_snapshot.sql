@@ -0,0 +1,16 @@
+-- Apenas para o sqlc entender tipos (não roda no banco).
+-- Snapshots versionados dos indicadores calculados pelo motor (internal/app/phytometrics)
+CREATE TABLE phyto_indicator_snapshot (
+  id varchar(36) PRIMARY KEY,
+  phyto_analysis_id varchar(36) NOT NULL,
+  version integer NOT NULL,
+  engine_version integer NOT NULL,
+  reason varchar(50) NOT NULL,
+  specimens_count integer NOT NULL,
+  payload jsonb NOT NULL,
+  created_at timestamp NOT NULL DEFAULT now(),
+  FOREIGN KEY (phyto_analysis_id) REFERENCES phyto_analysis (id) ON DELETE CASCADE,
+  UNIQUE (phyto_analysis_id, version)
+);
+
+CREATE INDEX idx_phyto_indicator_snapshot_analysis_id ON phyto_indicator_snapshot (phyto_analysis_id);
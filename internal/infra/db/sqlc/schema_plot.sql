@@ -0,0 +1,28 @@
+-- Apenas para o sqlc entender tipos (não roda no banco).
+-- Parcelas da análise; os espécimes referenciam a parcela pelo código (specimen.portion = plot.code)
+CREATE TABLE plot (
+  id varchar(36) PRIMARY KEY,
+  phyto_analysis_id varchar(36) NOT NULL,
+  code varchar(255) NOT NULL,
+  area numeric NOT NULL,
+  -- 'rectangle' (width × length), 'circle' (radius) ou 'irregular'
+  shape varchar(20) NOT NULL,
+  width numeric,
+  length numeric,
+  radius numeric,
+  center_latitude numeric,
+  center_longitude numeric,
+  -- Vértices do polígono: [{"latitude": ..., "longitude": ...}]
+  vertices jsonb NOT NULL DEFAULT '[]',
+  vegetation_notes varchar(1000),
+  created_at timestamp NOT NULL DEFAULT now(),
+  updated_at timestamp NOT NULL,
+  -- Estrato da parcela (opcional, amostragem estratificada)
+  stratum_id varchar(36),
+  FOREIGN KEY (phyto_analysis_id) REFERENCES phyto_analysis (id) ON DELETE CASCADE,
+  FOREIGN KEY (stratum_id) REFERENCES stratum (id),
+  UNIQUE (phyto_analysis_id, code)
+);
+
+CREATE INDEX idx_plot_phyto_analysis_id ON plot (phyto_analysis_id);
+CREATE INDEX idx_plot_stratum_id ON plot (stratum_id);
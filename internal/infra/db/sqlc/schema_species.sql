@@ -51,7 +51,9 @@ CREATE TABLE species (
   popular_name varchar(255),
   habit "SpeciesHabit",
   created_at timestamp NOT NULL DEFAULT now(),
-  updated_at timestamp NOT NULL
+  updated_at timestamp NOT NULL,
+  -- Densidade básica da madeira (g/cm³), usada na estimativa de biomassa
+  wood_density numeric
 );
 
 -- Tabela SpeciesLegislation (species_legislations)
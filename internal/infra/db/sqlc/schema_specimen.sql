@@ -2,7 +2,7 @@
 CREATE TABLE specimen (
   id varchar(36) PRIMARY KEY,
   portion varchar(50) NOT NULL,
-  height numeric NOT NULL,
+  height numeric,
   cap1 numeric NOT NULL,
   cap2 numeric,
   cap3 numeric,
@@ -11,10 +11,27 @@ CREATE TABLE specimen (
   cap6 numeric,
   register_date timestamp NOT NULL,
   phyto_analysis_id varchar(36) NOT NULL,
-  specie_id varchar(36) NOT NULL,
+  -- Espécie do cadastro ou morfoespécie da análise (exatamente uma das duas)
+  specie_id varchar(36),
   created_at timestamp NOT NULL DEFAULT now(),
   updated_at timestamp NOT NULL,
+  latitude numeric,
+  longitude numeric,
+  -- Plaqueta do indivíduo: identidade da árvore entre campanhas de remedição
+  tag varchar(50),
+  -- Situação (alive, dead_standing, fallen, resprouting) e observações de campo
+  status varchar(20) NOT NULL DEFAULT 'alive',
+  phytosanitary varchar(10),
+  bifurcation_notes text,
+  observations text,
+  morphospecies_id varchar(36),
   FOREIGN KEY (phyto_analysis_id) REFERENCES phyto_analysis (id),
-  FOREIGN KEY (specie_id) REFERENCES species (id)
+  FOREIGN KEY (specie_id) REFERENCES species (id),
+  -- a morfoespécie deve ser da mesma análise do indivíduo
+  FOREIGN KEY (morphospecies_id, phyto_analysis_id) REFERENCES morphospecies (id, phyto_analysis_id),
+  CHECK ((specie_id IS NULL) <> (morphospecies_id IS NULL))
 );
 
+CREATE UNIQUE INDEX idx_specimen_analysis_tag ON specimen (phyto_analysis_id, tag) WHERE tag IS NOT NULL;
+
+CREATE INDEX idx_specimen_morphospecies_id ON specimen (morphospecies_id) WHERE morphospecies_id IS NOT NULL;
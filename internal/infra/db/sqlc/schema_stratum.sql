@@ -0,0 +1,17 @@
+-- Apenas para o sqlc entender tipos (não roda no banco).
+-- Estratos da análise (amostragem estratificada); as parcelas indicam o estrato em plot.stratum_id
+CREATE TABLE stratum (
+  id varchar(36) PRIMARY KEY,
+  phyto_analysis_id varchar(36) NOT NULL,
+  code varchar(255) NOT NULL,
+  name varchar(255) NOT NULL,
+  -- Área do estrato (ha)
+  area numeric NOT NULL,
+  description varchar(1000),
+  created_at timestamp NOT NULL DEFAULT now(),
+  updated_at timestamp NOT NULL,
+  FOREIGN KEY (phyto_analysis_id) REFERENCES phyto_analysis (id) ON DELETE CASCADE,
+  UNIQUE (phyto_analysis_id, code)
+);
+
+CREATE INDEX idx_stratum_phyto_analysis_id ON stratum (phyto_analysis_id);
@@ -0,0 +1,323 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidWorkbook indica que o arquivo não é uma planilha XLSX legível
+var ErrInvalidWorkbook = errors.New("invalid xlsx workbook")
+
+// ErrWorkbookTooLarge indica que a planilha passa dos limites de leitura
+var ErrWorkbookTooLarge = errors.New("xlsx workbook too large")
+
+// Limites de leitura: protegem contra arquivos compactados que se expandem demais (zip bombs)
+const (
+	MaxEntrySize = 64 << 20 // tamanho descompactado de cada parte do arquivo (64 MB)
+	MaxRows      = 100000   // linhas da planilha
+	MaxCells     = 2000000  // células alocadas no total (cada linha vai até a última coluna preenchida)
+	excelColumns = 16384    // colunas do Excel (A até XFD)
+)
+
+// Cell representa o valor bruto de uma célula
+type Cell struct {
+	Value   string // valor textual (strings compartilhadas já resolvidas)
+	Numeric bool   // true quando a célula armazena um número (inclui datas seriais)
+}
+
+// Row representa uma linha não vazia da planilha
+type Row struct {
+	Number int    // número da linha na planilha (1-based, como exibido no Excel)
+	Cells  []Cell // células indexadas pela coluna (A=0, B=1, ...)
+}
+
+// Cell retorna a célula da coluna informada (0-based) ou uma célula vazia
+func (r Row) Cell(col int) Cell {
+	if col < 0 || col >= len(r.Cells) {
+		return Cell{}
+	}
+	return r.Cells[col]
+}
+
+// ReadFirstSheet lê as linhas da primeira planilha visível do arquivo XLSX.
+// Apenas valores são extraídos; estilos, fórmulas e desenhos são ignorados. Células a partir
+// da coluna maxColumns (0-based) são descartadas; maxColumns <= 0 lê até a última coluna do Excel.
+func ReadFirstSheet(r io.ReaderAt, size int64, maxColumns int) ([]Row, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWorkbook, err)
+	}
+
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	sheetPath, err := firstSheetPath(files)
+	if err != nil {
+		return nil, err
+	}
+
+	var shared []string
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		shared, err = readSharedStrings(f)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	f, ok := files[sheetPath]
+	if !ok {
+		return nil, fmt.Errorf("%w: worksheet %s not found", ErrInvalidWorkbook, sheetPath)
+	}
+
+	if maxColumns <= 0 || maxColumns > excelColumns {
+		maxColumns = excelColumns
+	}
+	return readSheet(f, shared, maxColumns)
+}
+
+// SerialToTime converte uma data serial do Excel (sistema 1900) em time.Time (UTC)
+func SerialToTime(serial float64) time.Time {
+	epoch := time.Date(1899, time.December, 30, 0, 0, 0, 0, time.UTC)
+	days := int(serial)
+	frac := serial - float64(days)
+	return epoch.AddDate(0, 0, days).Add(time.Duration(frac * float64(24*time.Hour)))
+}
+
+type workbookXML struct {
+	Sheets []struct {
+		State string `xml:"state,attr"`
+		RID   string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type relationshipsXML struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type sharedStringsXML struct {
+	Items []struct {
+		T    string `xml:"t"`
+		Runs []struct {
+			T string `xml:"t"`
+		} `xml:"r"`
+	} `xml:"si"`
+}
+
+type worksheetXML struct {
+	Rows []struct {
+		R     int `xml:"r,attr"`
+		Cells []struct {
+			R      string `xml:"r,attr"`
+			T      string `xml:"t,attr"`
+			V      string `xml:"v"`
+			Inline struct {
+				T    string `xml:"t"`
+				Runs []struct {
+					T string `xml:"t"`
+				} `xml:"r"`
+			} `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+func decodeFile(f *zip.File, v any) error {
+	if f.UncompressedSize64 > MaxEntrySize {
+		return fmt.Errorf("%w: %s exceeds %d bytes", ErrWorkbookTooLarge, f.Name, MaxEntrySize)
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidWorkbook, err)
+	}
+	defer rc.Close()
+
+	// o tamanho declarado no zip pode ser falso: a leitura é limitada de qualquer forma
+	lr := &io.LimitedReader{R: rc, N: MaxEntrySize + 1}
+	if err := xml.NewDecoder(lr).Decode(v); err != nil {
+		if lr.N <= 0 {
+			return fmt.Errorf("%w: %s exceeds %d bytes", ErrWorkbookTooLarge, f.Name, MaxEntrySize)
+		}
+		return fmt.Errorf("%w: %s: %v", ErrInvalidWorkbook, f.Name, err)
+	}
+	return nil
+}
+
+// firstSheetPath resolve o caminho da primeira planilha visível via workbook.xml e seus relacionamentos
+func firstSheetPath(files map[string]*zip.File) (string, error) {
+	const fallback = "xl/worksheets/sheet1.xml"
+
+	wbFile, ok := files["xl/workbook.xml"]
+	if !ok {
+		return "", fmt.Errorf("%w: workbook.xml not found", ErrInvalidWorkbook)
+	}
+	relsFile, ok := files["xl/_rels/workbook.xml.rels"]
+	if !ok {
+		return fallback, nil
+	}
+
+	var wb workbookXML
+	if err := decodeFile(wbFile, &wb); err != nil {
+		return "", err
+	}
+	var rels relationshipsXML
+	if err := decodeFile(relsFile, &rels); err != nil {
+		return "", err
+	}
+
+	targets := make(map[string]string, len(rels.Relationships))
+	for _, rel := range rels.Relationships {
+		targets[rel.ID] = rel.Target
+	}
+
+	for _, s := range wb.Sheets {
+		if s.State == "hidden" || s.State == "veryHidden" {
+			continue
+		}
+		target, ok := targets[s.RID]
+		if !ok {
+			continue
+		}
+		if strings.HasPrefix(target, "/") {
+			return strings.TrimPrefix(target, "/"), nil
+		}
+		return path.Join("xl", target), nil
+	}
+
+	return fallback, nil
+}
+
+func readSharedStrings(f *zip.File) ([]string, error) {
+	var sst sharedStringsXML
+	if err := decodeFile(f, &sst); err != nil {
+		return nil, err
+	}
+
+	out := make([]string, 0, len(sst.Items))
+	for _, si := range sst.Items {
+		if len(si.Runs) == 0 {
+			out = append(out, si.T)
+			continue
+		}
+		var b strings.Builder
+		b.WriteString(si.T)
+		for _, run := range si.Runs {
+			b.WriteString(run.T)
+		}
+		out = append(out, b.String())
+	}
+	return out, nil
+}
+
+func readSheet(f *zip.File, shared []string, maxColumns int) ([]Row, error) {
+	var ws worksheetXML
+	if err := decodeFile(f, &ws); err != nil {
+		return nil, err
+	}
+
+	if len(ws.Rows) > MaxRows {
+		return nil, fmt.Errorf("%w: more than %d rows", ErrWorkbookTooLarge, MaxRows)
+	}
+
+	rows := make([]Row, 0, len(ws.Rows))
+	cells := 0
+	for i, xr := range ws.Rows {
+		rowNumber := xr.R
+		if rowNumber == 0 {
+			rowNumber = i + 1
+		}
+
+		row := Row{Number: rowNumber}
+		for j, xc := range xr.Cells {
+			col := j
+			if xc.R != "" {
+				c, err := columnIndex(xc.R)
+				if err != nil {
+					return nil, err
+				}
+				col = c
+			}
+			if col >= maxColumns {
+				continue
+			}
+
+			var cell Cell
+			switch xc.T {
+			case "s":
+				idx, err := strconv.Atoi(strings.TrimSpace(xc.V))
+				if err != nil || idx < 0 || idx >= len(shared) {
+					return nil, fmt.Errorf("%w: invalid shared string reference in %s", ErrInvalidWorkbook, xc.R)
+				}
+				cell = Cell{Value: shared[idx]}
+			case "inlineStr":
+				var b strings.Builder
+				b.WriteString(xc.Inline.T)
+				for _, run := range xc.Inline.Runs {
+					b.WriteString(run.T)
+				}
+				cell = Cell{Value: b.String()}
+			case "str", "b", "e":
+				cell = Cell{Value: xc.V}
+			default:
+				cell = Cell{Value: xc.V, Numeric: xc.V != ""}
+			}
+
+			if cell.Value == "" {
+				continue
+			}
+			if grow := col + 1 - len(row.Cells); grow > 0 {
+				cells += grow
+				if cells > MaxCells {
+					return nil, fmt.Errorf("%w: more than %d cells", ErrWorkbookTooLarge, MaxCells)
+				}
+			}
+			for len(row.Cells) <= col {
+				row.Cells = append(row.Cells, Cell{})
+			}
+			row.Cells[col] = cell
+		}
+
+		if len(row.Cells) > 0 {
+			rows = append(rows, row)
+		}
+	}
+
+	return rows, nil
+}
+
+// columnIndex converte a referência de célula (ex.: "AB12") no índice 0-based da coluna
+func columnIndex(ref string) (int, error) {
+	col := 0
+	n := 0
+	for _, ch := range ref {
+		if col > excelColumns {
+			break // evita overflow em referências longas demais
+		}
+		if ch >= 'A' && ch <= 'Z' {
+			col = col*26 + int(ch-'A'+1)
+			n++
+			continue
+		}
+		if ch >= 'a' && ch <= 'z' {
+			col = col*26 + int(ch-'a'+1)
+			n++
+			continue
+		}
+		break
+	}
+	if n == 0 || col > excelColumns {
+		return 0, fmt.Errorf("%w: invalid cell reference %q", ErrInvalidWorkbook, ref)
+	}
+	return col - 1, nil
+}